@@ -18,22 +18,29 @@ package cmd
 
 import (
 	"encoding/xml"
+	"io"
 	"net/http"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/policy"
 )
 
-// Data types used for returning dummy access control
+// Maximum size of an access control policy XML.
+const maxACLSize = 64 * humanize.KiByte
+
+// Data types used for returning and parsing access control
 // policy XML, these variables shouldn't be used elsewhere
 // they are only defined to be used in this file alone.
 type grantee struct {
-	XMLNS       string `xml:"xmlns:xsi,attr"`
-	XMLXSI      string `xml:"xsi:type,attr"`
-	Type        string `xml:"Type"`
-	ID          string `xml:"ID,omitempty"`
-	DisplayName string `xml:"DisplayName,omitempty"`
-	URI         string `xml:"URI,omitempty"`
+	XMLNS        string `xml:"xmlns:xsi,attr"`
+	XMLXSI       string `xml:"xsi:type,attr"`
+	Type         string `xml:"Type"`
+	ID           string `xml:"ID,omitempty"`
+	DisplayName  string `xml:"DisplayName,omitempty"`
+	EmailAddress string `xml:"EmailAddress,omitempty"`
+	URI          string `xml:"URI,omitempty"`
 }
 
 type grant struct {
@@ -49,6 +56,87 @@ type accessControlPolicy struct {
 	} `xml:"AccessControlList"`
 }
 
+// newAccessControlPolicy - returns access control policy equivalent to canned ACL.
+func newAccessControlPolicy(acl policy.CannedACL) *accessControlPolicy {
+	acp := &accessControlPolicy{
+		Owner: Owner{ID: globalMinioDefaultOwnerID},
+	}
+	acp.AccessControlList.Grants = append(acp.AccessControlList.Grants, grant{
+		Grantee: grantee{
+			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
+			XMLXSI: "CanonicalUser",
+			Type:   "CanonicalUser",
+			ID:     globalMinioDefaultOwnerID,
+		},
+		Permission: string(policy.FullControlPermission),
+	})
+	for _, g := range acl.Grants() {
+		acp.AccessControlList.Grants = append(acp.AccessControlList.Grants, grant{
+			Grantee: grantee{
+				XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
+				XMLXSI: "Group",
+				Type:   "Group",
+				URI:    string(g.Group),
+			},
+			Permission: string(g.Permission),
+		})
+	}
+
+	return acp
+}
+
+// parseAccessControlPolicy - returns canned ACL equivalent to access control policy XML.
+func parseAccessControlPolicy(reader io.Reader) (policy.CannedACL, APIErrorCode) {
+	var acp accessControlPolicy
+	if err := xml.NewDecoder(reader).Decode(&acp); err != nil {
+		return "", ErrMalformedACLError
+	}
+
+	var grants []policy.Grant
+	for _, g := range acp.AccessControlList.Grants {
+		switch {
+		case g.Grantee.URI != "":
+			grants = append(grants, policy.Grant{
+				Group:      policy.Group(g.Grantee.URI),
+				Permission: policy.Permission(g.Permission),
+			})
+		case g.Grantee.ID == globalMinioDefaultOwnerID && g.Permission == string(policy.FullControlPermission):
+			// Owner always has full control.
+		default:
+			return "", ErrUnsupportedACL
+		}
+	}
+
+	acl, found := policy.CannedACLFromGrants(grants)
+	if !found {
+		return "", ErrUnsupportedACL
+	}
+
+	return acl, ErrNone
+}
+
+// getACLFromRequest - returns canned ACL requested either by x-amz-acl
+// header or by access control policy XML in request body.
+func getACLFromRequest(r *http.Request) (policy.CannedACL, APIErrorCode) {
+	if r.Header.Get(amzACLHeader) != "" {
+		return getRequestCannedACL(r.Header)
+	}
+
+	if acl, s3Error := getRequestCannedACL(r.Header); s3Error != ErrNone {
+		return acl, s3Error
+	}
+
+	if r.ContentLength <= 0 {
+		return "", ErrMalformedACLError
+	}
+
+	if r.ContentLength > maxACLSize {
+		return "", ErrEntityTooLarge
+	}
+
+	return parseAccessControlPolicy(io.LimitReader(r.Body, r.ContentLength))
+}
+
 // GetBucketACLHandler - GET Bucket ACL
 // -----------------
 // This operation uses the ACL
@@ -65,9 +153,7 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getBucketACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketACLAction, bucket, ""); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
@@ -79,21 +165,63 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	acl := &accessControlPolicy{}
-	acl.AccessControlList.Grants = append(acl.AccessControlList.Grants, grant{
-		Grantee: grantee{
-			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
-			XMLXSI: "CanonicalUser",
-			Type:   "CanonicalUser",
-		},
-		Permission: "FULL_CONTROL",
-	})
-	if err := xml.NewEncoder(w).Encode(acl); err != nil {
+	acl := policy.PrivateACL
+	// globalACLSys is not initialized in gateway mode.
+	if globalACLSys != nil {
+		if acl, err = readBucketACL(ctx, objAPI, bucket); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	writeSuccessResponseXML(w, encodeResponse(newAccessControlPolicy(acl)))
+}
+
+// PutBucketACLHandler - PUT Bucket ACL
+// -----------------
+// This operation uses the ACL subresource to set canned ACL
+// of a specified bucket.
+func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketACL")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalACLSys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketACLAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Before proceeding validate if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	acl, s3Error := getACLFromRequest(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	if err := setBucketACL(ctx, objAPI, bucket, acl); err != nil {
+		logger.LogIf(ctx, err)
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	w.(http.Flusher).Flush()
+	writeSuccessResponseHeadersOnly(w)
 }
 
 // GetObjectACLHandler - GET Object ACL
@@ -113,33 +241,68 @@ func (api objectAPIHandlers) GetObjectACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getObjectACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectACLAction, bucket, object); s3Error != ErrNone {
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
 
 	// Before proceeding validate if object exists.
-	_, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
 	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	acl := &accessControlPolicy{}
-	acl.AccessControlList.Grants = append(acl.AccessControlList.Grants, grant{
-		Grantee: grantee{
-			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
-			XMLXSI: "CanonicalUser",
-			Type:   "CanonicalUser",
-		},
-		Permission: "FULL_CONTROL",
-	})
-	if err := xml.NewEncoder(w).Encode(acl); err != nil {
+	writeSuccessResponseXML(w, encodeResponse(newAccessControlPolicy(getObjectACL(objInfo.UserDefined))))
+}
+
+// PutObjectACLHandler - PUT Object ACL
+// -----------------
+// This operation uses the ACL subresource to set canned ACL
+// of a specified object.
+func (api objectAPIHandlers) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutObjectACL")
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalACLSys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectACLAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Before proceeding validate if object exists.
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
 		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 		return
 	}
 
-	w.(http.Flusher).Flush()
+	acl, s3Error := getACLFromRequest(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Nothing to update if ACL is unchanged.
+	if getObjectACL(objInfo.UserDefined) != acl {
+		if err = setObjectACLMetadata(ctx, objAPI, bucket, object, objInfo, acl); err != nil {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+	}
+
+	writeSuccessResponseHeadersOnly(w)
 }
@@ -0,0 +1,191 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// newTestACLRequest - returns request with given canned ACL header, signed
+// with Signature V4 if credentials are given.
+func newTestACLRequest(method, urlStr string, body []byte, acl, accessKey, secretKey string) (*http.Request, error) {
+	req, err := newTestRequest(method, urlStr, int64(len(body)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if acl != "" {
+		req.Header.Set("x-amz-acl", acl)
+	}
+
+	// Anonymous request return early.
+	if accessKey == "" || secretKey == "" {
+		return req, nil
+	}
+
+	if err = signRequestV4(req, accessKey, secretKey); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// Wrapper for calling ACL handler tests for both XL multiple disks and single node setup.
+func TestACLHandlers(t *testing.T) {
+	// Object level ACL handlers are registered first, as bucket level
+	// handlers match object paths too.
+	ExecObjectLayerAPITest(t, testACLHandlers, []string{
+		"GetObjectACL",
+		"PutObjectACL",
+		"GetBucketACL",
+		"PutBucketACL",
+		"HeadBucket",
+		"GetObject",
+		"PutObject",
+	})
+}
+
+func testACLHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+
+	globalPolicySys = NewPolicySys()
+	globalACLSys = NewACLSys()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+
+	objectName := "private-object"
+	data := []byte("hello")
+	if _, err := obj.PutObject(context.Background(), bucketName, objectName, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	publicReadWriteACP := `<AccessControlPolicy><Owner><ID>` + globalMinioDefaultOwnerID + `</ID></Owner><AccessControlList>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>` + globalMinioDefaultOwnerID + `</ID></Grantee><Permission>FULL_CONTROL</Permission></Grant>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>WRITE</Permission></Grant>` +
+		`</AccessControlList></AccessControlPolicy>`
+
+	bucketACLURL := makeTestTargetURL("", bucketName, "", url.Values{"acl": []string{""}})
+	objectACLURL := makeTestTargetURL("", bucketName, objectName, url.Values{"acl": []string{""}})
+	bucketURL := makeTestTargetURL("", bucketName, "", nil)
+	objectURL := makeTestTargetURL("", bucketName, objectName, nil)
+
+	testCases := []struct {
+		method             string
+		url                string
+		body               string
+		acl                string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+		expectedPermission policy.Permission
+	}{
+		// Test case - 1.
+		// Anonymous read of private object.
+		{"GET", objectURL, "", "", "", "", http.StatusForbidden, ""},
+		// Test case - 2.
+		{"GET", objectACLURL, "", "", credentials.AccessKey, credentials.SecretKey, http.StatusOK, policy.FullControlPermission},
+		// Test case - 3.
+		// Set public-read canned ACL on object.
+		{"PUT", objectACLURL, "", "public-read", credentials.AccessKey, credentials.SecretKey, http.StatusOK, ""},
+		// Test case - 4.
+		{"GET", objectACLURL, "", "", credentials.AccessKey, credentials.SecretKey, http.StatusOK, policy.ReadPermission},
+		// Test case - 5.
+		// Anonymous read of public-read object.
+		{"GET", objectURL, "", "", "", "", http.StatusOK, ""},
+		// Test case - 6.
+		// Anonymous request is not allowed to change ACL.
+		{"PUT", objectACLURL, "", "private", "", "", http.StatusForbidden, ""},
+		// Test case - 7.
+		// Anonymous listing of private bucket.
+		{"HEAD", bucketURL, "", "", "", "", http.StatusForbidden, ""},
+		// Test case - 8.
+		// Anonymous upload to private bucket.
+		{"PUT", makeTestTargetURL("", bucketName, "anonymous-object", nil), "hello", "", "", "", http.StatusForbidden, ""},
+		// Test case - 9.
+		// Set public-read-write canned ACL on bucket using access control policy.
+		{"PUT", bucketACLURL, publicReadWriteACP, "", credentials.AccessKey, credentials.SecretKey, http.StatusOK, ""},
+		// Test case - 10.
+		{"GET", bucketACLURL, "", "", credentials.AccessKey, credentials.SecretKey, http.StatusOK, policy.WritePermission},
+		// Test case - 11.
+		{"HEAD", bucketURL, "", "", "", "", http.StatusOK, ""},
+		// Test case - 12.
+		{"PUT", makeTestTargetURL("", bucketName, "anonymous-object", nil), "hello", "", "", "", http.StatusOK, ""},
+		// Test case - 13.
+		// Invalid canned ACL.
+		{"PUT", bucketACLURL, "", "log-delivery-write", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest, ""},
+		// Test case - 14.
+		// Access control policy not equivalent to a canned ACL.
+		{"PUT", bucketACLURL, strings.Replace(publicReadWriteACP, "READ", "READ_ACP", 1), "", credentials.AccessKey, credentials.SecretKey, http.StatusNotImplemented, ""},
+		// Test case - 15.
+		// Malformed access control policy.
+		{"PUT", bucketACLURL, "<AccessControlPolicy>", "", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest, ""},
+		// Test case - 16.
+		// Reset bucket ACL to private.
+		{"PUT", bucketACLURL, "", "private", credentials.AccessKey, credentials.SecretKey, http.StatusOK, ""},
+		// Test case - 17.
+		{"HEAD", bucketURL, "", "", "", "", http.StatusForbidden, ""},
+		// Test case - 18.
+		// Upload object with public-read canned ACL.
+		{"PUT", makeTestTargetURL("", bucketName, "public-object", nil), "hello", "public-read", credentials.AccessKey, credentials.SecretKey, http.StatusOK, ""},
+		// Test case - 19.
+		{"GET", makeTestTargetURL("", bucketName, "public-object", nil), "", "", "", "", http.StatusOK, ""},
+		// Test case - 20.
+		// Upload object with invalid canned ACL.
+		{"PUT", makeTestTargetURL("", bucketName, "public-object", nil), "hello", "public", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest, ""},
+		// Test case - 21.
+		// Non-existent object.
+		{"PUT", makeTestTargetURL("", bucketName, "non-existent", url.Values{"acl": []string{""}}), "", "public-read", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound, ""},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		req, err := newTestACLRequest(testCase.method, testCase.url, []byte(testCase.body), testCase.acl, testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code, rec.Body.String())
+		}
+
+		if testCase.expectedPermission == "" {
+			continue
+		}
+
+		var acp accessControlPolicy
+		if err = xml.Unmarshal(rec.Body.Bytes(), &acp); err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse response: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		grants := acp.AccessControlList.Grants
+		if permission := grants[len(grants)-1].Permission; permission != string(testCase.expectedPermission) {
+			t.Fatalf("Test %d: %s: Expected permission %s, got %s", i+1, instanceType, testCase.expectedPermission, permission)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// Bucket canned ACL file name.
+	bucketACLConfig = "acl.json"
+
+	// Metadata key of canned ACL set on an object.
+	objectACLMetadataKey = ReservedMetadataPrefix + "Acl"
+
+	// Request header carrying canned ACL.
+	amzACLHeader = "X-Amz-Acl"
+
+	// Prefix of request headers carrying explicit grants.
+	amzGrantHeaderPrefix = "X-Amz-Grant-"
+
+	// Duration for which canned ACL read from object metadata is cached.
+	objectACLCacheTTL = 5 * time.Second
+
+	// Maximum number of cached object canned ACLs.
+	objectACLCacheMaxEntries = 10000
+)
+
+// bucketACL - bucket canned ACL stored in acl.json.
+type bucketACL struct {
+	ACL policy.CannedACL `json:"acl"`
+}
+
+// cachedObjectACL - canned ACL of an object along with its expiry.
+type cachedObjectACL struct {
+	acl    policy.CannedACL
+	expiry time.Time
+}
+
+// ACLSys - bucket and object canned ACL subsystem.
+type ACLSys struct {
+	sync.RWMutex
+	bucketACLMap map[string]policy.CannedACL
+
+	// Object canned ACLs recently read from object metadata, so that
+	// denied anonymous requests do not look up the object every time.
+	objectACLMu  sync.Mutex
+	objectACLMap map[string]cachedObjectACL
+}
+
+// removeDeletedBuckets - removes cached ACLs of buckets not present anymore.
+func (sys *ACLSys) removeDeletedBuckets(bucketInfos []BucketInfo) {
+	buckets := set.NewStringSet()
+	for _, info := range bucketInfos {
+		buckets.Add(info.Name)
+	}
+	sys.Lock()
+	defer sys.Unlock()
+
+	for bucket := range sys.bucketACLMap {
+		if !buckets.Contains(bucket) {
+			delete(sys.bucketACLMap, bucket)
+		}
+	}
+}
+
+// Set - sets canned ACL to given bucket name. If ACL is private, existing ACL is removed.
+func (sys *ACLSys) Set(bucketName string, acl policy.CannedACL) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if len(acl.Grants()) == 0 {
+		delete(sys.bucketACLMap, bucketName)
+	} else {
+		sys.bucketACLMap[bucketName] = acl
+	}
+}
+
+// Remove - removes canned ACL for given bucket name.
+func (sys *ACLSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketACLMap, bucketName)
+}
+
+// Get - returns canned ACL of given bucket name.
+func (sys *ACLSys) Get(bucketName string) policy.CannedACL {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if acl, found := sys.bucketACLMap[bucketName]; found {
+		return acl
+	}
+
+	return policy.PrivateACL
+}
+
+// IsAllowed - checks given policy args is allowed by canned ACL of the bucket
+// or, for object read, by canned ACL of the object.
+func (sys *ACLSys) IsAllowed(ctx context.Context, args policy.Args) bool {
+	if sys.Get(args.BucketName).IsBucketAllowed(args) {
+		return true
+	}
+
+	// Only object read is granted by object ACLs.
+	if args.ObjectName == "" || args.Action != policy.GetObjectAction {
+		return false
+	}
+
+	acl, ok := sys.getObjectACL(ctx, args.BucketName, args.ObjectName)
+	return ok && acl.IsObjectAllowed(args)
+}
+
+// getObjectACL - returns canned ACL of given object, from cache if it was
+// read recently.
+func (sys *ACLSys) getObjectACL(ctx context.Context, bucketName, objectName string) (policy.CannedACL, bool) {
+	key := pathJoin(bucketName, objectName)
+	now := UTCNow()
+
+	sys.objectACLMu.Lock()
+	cached, found := sys.objectACLMap[key]
+	sys.objectACLMu.Unlock()
+	if found && now.Before(cached.expiry) {
+		return cached.acl, true
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return "", false
+	}
+
+	acl := policy.PrivateACL
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucketName, objectName)
+	switch {
+	case err == nil:
+		acl = getObjectACL(objInfo.UserDefined)
+	case isErrObjectNotFound(err):
+		// Missing objects are cached as private.
+	default:
+		return "", false
+	}
+
+	sys.objectACLMu.Lock()
+	defer sys.objectACLMu.Unlock()
+
+	if len(sys.objectACLMap) >= objectACLCacheMaxEntries {
+		for k, v := range sys.objectACLMap {
+			if !now.Before(v.expiry) {
+				delete(sys.objectACLMap, k)
+			}
+		}
+		if len(sys.objectACLMap) >= objectACLCacheMaxEntries {
+			sys.objectACLMap = make(map[string]cachedObjectACL)
+		}
+	}
+	sys.objectACLMap[key] = cachedObjectACL{acl: acl, expiry: now.Add(objectACLCacheTTL)}
+
+	return acl, true
+}
+
+// RemoveObject - removes cached canned ACL of given object.
+func (sys *ACLSys) RemoveObject(bucketName, objectName string) {
+	sys.objectACLMu.Lock()
+	defer sys.objectACLMu.Unlock()
+
+	delete(sys.objectACLMap, pathJoin(bucketName, objectName))
+}
+
+// Refresh ACLSys.
+func (sys *ACLSys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+	sys.removeDeletedBuckets(buckets)
+	for _, bucket := range buckets {
+		acl, err := readBucketACL(context.Background(), objAPI, bucket.Name)
+		if err != nil {
+			continue
+		}
+		sys.Set(bucket.Name, acl)
+	}
+	return nil
+}
+
+// Init - initializes ACL system from acl.json of all buckets.
+func (sys *ACLSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load ACLSys once during boot.
+	if err := sys.refresh(objAPI); err != nil {
+		return err
+	}
+
+	// Refresh ACLSys in background.
+	go func() {
+		ticker := time.NewTicker(globalRefreshBucketPolicyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-ticker.C:
+				sys.refresh(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// NewACLSys - creates new ACL system.
+func NewACLSys() *ACLSys {
+	return &ACLSys{
+		bucketACLMap: make(map[string]policy.CannedACL),
+		objectACLMap: make(map[string]cachedObjectACL),
+	}
+}
+
+// getObjectACL - returns canned ACL stored in object metadata.
+func getObjectACL(metadata map[string]string) policy.CannedACL {
+	if acl, err := policy.ParseCannedACL(metadata[objectACLMetadataKey]); err == nil {
+		return acl
+	}
+
+	return policy.PrivateACL
+}
+
+// setObjectACL - stores canned ACL in object metadata. Private ACL is
+// the default, hence it is not stored.
+func setObjectACL(metadata map[string]string, acl policy.CannedACL) {
+	if len(acl.Grants()) == 0 {
+		delete(metadata, objectACLMetadataKey)
+	} else {
+		metadata[objectACLMetadataKey] = string(acl)
+	}
+}
+
+// getRequestCannedACL - returns canned ACL set in x-amz-acl header. Private
+// ACL is returned if the header is not set.
+func getRequestCannedACL(header http.Header) (policy.CannedACL, APIErrorCode) {
+	// Explicit grants are not supported.
+	for key := range header {
+		if strings.HasPrefix(key, amzGrantHeaderPrefix) {
+			return "", ErrUnsupportedACL
+		}
+	}
+
+	value := header.Get(amzACLHeader)
+	if value == "" {
+		return policy.PrivateACL, ErrNone
+	}
+
+	acl, err := policy.ParseCannedACL(value)
+	if err != nil {
+		return "", ErrInvalidCannedACL
+	}
+
+	return acl, ErrNone
+}
+
+// setRequestObjectACL - stores canned ACL requested by x-amz-acl header
+// in object metadata.
+func setRequestObjectACL(header http.Header, metadata map[string]string) APIErrorCode {
+	// globalACLSys is not initialized in gateway mode, ACLs are ignored there.
+	if globalACLSys == nil {
+		return ErrNone
+	}
+
+	acl, s3Error := getRequestCannedACL(header)
+	if s3Error != ErrNone {
+		return s3Error
+	}
+
+	setObjectACL(metadata, acl)
+	return ErrNone
+}
+
+// readBucketACL - reads canned ACL of given bucket name. Private ACL is
+// returned if no ACL is set.
+func readBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string) (policy.CannedACL, error) {
+	// Construct path to acl.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketACLConfig)
+	reader, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			return policy.PrivateACL, nil
+		}
+		return "", err
+	}
+
+	var config bucketACL
+	if err = json.NewDecoder(reader).Decode(&config); err != nil {
+		return "", err
+	}
+
+	if !config.ACL.IsValid() {
+		return "", errInvalidArgument
+	}
+
+	return config.ACL, nil
+}
+
+// saveBucketACL - saves canned ACL of given bucket name. Private ACL
+// is the default, hence acl.json is removed instead.
+func saveBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string, acl policy.CannedACL) error {
+	if len(acl.Grants()) == 0 {
+		err := removeBucketACL(ctx, objAPI, bucketName)
+		if err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(bucketACL{ACL: acl})
+	if err != nil {
+		return err
+	}
+
+	// Construct path to acl.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketACLConfig)
+	return saveConfig(objAPI, configFile, data)
+}
+
+// removeBucketACL - removes acl.json of given bucket name.
+func removeBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	// Construct path to acl.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketACLConfig)
+	return objAPI.DeleteObject(ctx, minioMetaBucket, configFile)
+}
+
+// setBucketACL - saves canned ACL of given bucket name and propagates it to all peers.
+func setBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string, acl policy.CannedACL) error {
+	if err := saveBucketACL(ctx, objAPI, bucketName, acl); err != nil {
+		return err
+	}
+
+	globalACLSys.Set(bucketName, acl)
+	globalNotificationSys.SetBucketACL(ctx, bucketName, acl)
+	return nil
+}
+
+// initBucketACL - sets canned ACL requested while creating the bucket.
+func initBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string, acl policy.CannedACL) error {
+	// globalACLSys is not initialized in gateway mode, ACLs are ignored there.
+	if globalACLSys == nil || len(acl.Grants()) == 0 {
+		return nil
+	}
+
+	return setBucketACL(ctx, objAPI, bucketName, acl)
+}
+
+// setObjectACLMetadata - updates canned ACL in metadata of given object.
+func setObjectACLMetadata(ctx context.Context, objAPI ObjectLayer, bucketName, objectName string, objInfo ObjectInfo, acl policy.CannedACL) error {
+	metadata := make(map[string]string, len(objInfo.UserDefined))
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	setObjectACL(metadata, acl)
+
+	// Only metadata is updated, object data is not read.
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+
+	reader, err := hash.NewReader(pipeReader, objInfo.Size, "", "")
+	if err != nil {
+		pipeWriter.CloseWithError(err)
+		return err
+	}
+
+	objInfo.UserDefined = metadata
+	objInfo.Reader = reader
+	objInfo.Writer = pipeWriter
+	objInfo.metadataOnly = true
+
+	if _, err = objAPI.CopyObject(ctx, bucketName, objectName, bucketName, objectName, objInfo); err != nil {
+		return err
+	}
+
+	if globalACLSys != nil {
+		globalACLSys.RemoveObject(bucketName, objectName)
+	}
+	return nil
+}
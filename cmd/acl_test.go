@@ -0,0 +1,152 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+func TestGetRequestCannedACL(t *testing.T) {
+	testCases := []struct {
+		header         http.Header
+		expectedResult policy.CannedACL
+		expectedErr    APIErrorCode
+	}{
+		{http.Header{}, policy.PrivateACL, ErrNone},
+		{http.Header{"X-Amz-Acl": []string{"public-read"}}, policy.PublicReadACL, ErrNone},
+		{http.Header{"X-Amz-Acl": []string{"authenticated-read"}}, policy.AuthenticatedReadACL, ErrNone},
+		{http.Header{"X-Amz-Acl": []string{"public"}}, "", ErrInvalidCannedACL},
+		{http.Header{"X-Amz-Grant-Read": []string{"uri=http://acs.amazonaws.com/groups/global/AllUsers"}}, "", ErrUnsupportedACL},
+	}
+
+	for i, testCase := range testCases {
+		result, s3Error := getRequestCannedACL(testCase.header)
+		if s3Error != testCase.expectedErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, s3Error)
+		}
+
+		if result != testCase.expectedResult {
+			t.Fatalf("test %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestObjectACLMetadata(t *testing.T) {
+	metadata := map[string]string{"content-type": "text/plain"}
+	if acl := getObjectACL(metadata); acl != policy.PrivateACL {
+		t.Fatalf("expected: %v, got: %v", policy.PrivateACL, acl)
+	}
+
+	setObjectACL(metadata, policy.PublicReadACL)
+	if acl := getObjectACL(metadata); acl != policy.PublicReadACL {
+		t.Fatalf("expected: %v, got: %v", policy.PublicReadACL, acl)
+	}
+
+	setObjectACL(metadata, policy.BucketOwnerFullControlACL)
+	if _, found := metadata[objectACLMetadataKey]; found {
+		t.Fatalf("expected: ACL to be removed from metadata")
+	}
+}
+
+func TestBucketACLConfig(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	initNSLock(false)
+
+	ctx := context.Background()
+	if err = obj.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, acl := range []policy.CannedACL{policy.PrivateACL, policy.PublicReadACL, policy.PublicReadWriteACL, policy.PrivateACL} {
+		if err = saveBucketACL(ctx, obj, "bucket", acl); err != nil {
+			t.Fatalf("test %v: unexpected error: %v", i+1, err)
+		}
+
+		result, err := readBucketACL(ctx, obj, "bucket")
+		if err != nil {
+			t.Fatalf("test %v: unexpected error: %v", i+1, err)
+		}
+
+		if result != acl {
+			t.Fatalf("test %v: expected: %v, got: %v", i+1, acl, result)
+		}
+	}
+
+	sys := NewACLSys()
+	if err = saveBucketACL(ctx, obj, "bucket", policy.PublicReadACL); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = sys.refresh(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	if acl := sys.Get("bucket"); acl != policy.PublicReadACL {
+		t.Fatalf("expected: %v, got: %v", policy.PublicReadACL, acl)
+	}
+}
+
+func TestIsRequestAllowedExplicitDeny(t *testing.T) {
+	savedPolicySys, savedACLSys := globalPolicySys, globalACLSys
+	defer func() {
+		globalPolicySys, globalACLSys = savedPolicySys, savedACLSys
+	}()
+
+	globalPolicySys = NewPolicySys()
+	globalACLSys = NewACLSys()
+	globalACLSys.Set("bucket", policy.PublicReadWriteACL)
+
+	anonListBucket := policy.Args{Action: policy.ListBucketAction, BucketName: "bucket", ConditionValues: map[string][]string{}}
+	ownerListBucket := policy.Args{AccountName: "owner", Action: policy.ListBucketAction, BucketName: "bucket", ConditionValues: map[string][]string{}, IsOwner: true}
+
+	if !isRequestAllowed(context.Background(), anonListBucket) {
+		t.Fatalf("expected: anonymous listing to be allowed by bucket ACL")
+	}
+
+	globalPolicySys.Set("bucket", policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{policy.NewStatement(
+			policy.Deny,
+			policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.ListBucketAction),
+			policy.NewResourceSet(policy.NewResource("bucket", "")),
+			condition.NewFunctions(),
+		)},
+	})
+
+	if isRequestAllowed(context.Background(), anonListBucket) {
+		t.Fatalf("expected: explicit deny not to be overridden by bucket ACL")
+	}
+
+	if isRequestAllowed(context.Background(), ownerListBucket) {
+		t.Fatalf("expected: explicit deny to apply to bucket owner")
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/handlers"
 	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
 	"github.com/minio/minio/pkg/quick"
 )
 
@@ -47,6 +49,9 @@ const (
 	mgmtPrefix      mgmtQueryKey = "prefix"
 	mgmtClientToken mgmtQueryKey = "clientToken"
 	mgmtForceStart  mgmtQueryKey = "forceStart"
+	mgmtPolicyName  mgmtQueryKey = "policyName"
+	mgmtGroup       mgmtQueryKey = "group"
+	mgmtPool        mgmtQueryKey = "pool"
 )
 
 var (
@@ -270,6 +275,116 @@ func (a adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Reque
 	writeSuccessResponseJSON(w, jsonBytes)
 }
 
+// ServerDrivesHandler - GET /minio/admin/v1/drives
+// ----------
+// Returns details of all drives of all servers, such as their
+// position in the cluster, usage and recent latency and errors.
+func (a adminAPIHandlers) ServerDrivesHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return
+	}
+
+	if !globalIsXL {
+		writeErrorResponseJSON(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	reply := make([]madmin.ServerDrivesInfo, len(globalAdminPeers))
+
+	var wg sync.WaitGroup
+	for i, p := range globalAdminPeers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+
+			reply[idx] = madmin.ServerDrivesInfo{Addr: peer.addr}
+
+			drives, err := peer.cmdRunner.ServerDrives()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", peer.addr)
+				ctx := logger.SetReqInfo(context.Background(), reqInfo)
+				logger.LogIf(ctx, err)
+				reply[idx].Error = err.Error()
+				return
+			}
+
+			reply[idx].Drives = drives
+		}(i, p)
+	}
+	wg.Wait()
+
+	jsonBytes, err := json.Marshal(reply)
+	if err != nil {
+		writeErrorResponseJSON(w, ErrInternalError, r.URL)
+		logger.LogIf(context.Background(), err)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
+// DriveSpeedtestHandler - POST /minio/admin/v1/drives/speedtest?size=<bytes>
+// ----------
+// Writes and syncs a temporary file on all drives of all servers
+// and returns the measured write throughputs.
+func (a adminAPIHandlers) DriveSpeedtestHandler(w http.ResponseWriter, r *http.Request) {
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return
+	}
+
+	if !globalIsXL {
+		writeErrorResponseJSON(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	size := int64(driveSpeedtestDefaultSize)
+	if sizeStr := r.URL.Query().Get("size"); sizeStr != "" {
+		var err error
+		size, err = strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil || size <= 0 || size > driveSpeedtestMaxSize {
+			writeErrorResponseJSON(w, ErrAdminInvalidSpeedtestSize, r.URL)
+			return
+		}
+	}
+
+	reply := make([]madmin.ServerDriveSpeedtest, len(globalAdminPeers))
+
+	var wg sync.WaitGroup
+	for i, p := range globalAdminPeers {
+		wg.Add(1)
+		go func(idx int, peer adminPeer) {
+			defer wg.Done()
+
+			reply[idx] = madmin.ServerDriveSpeedtest{Addr: peer.addr}
+
+			results, err := peer.cmdRunner.DriveSpeedtest(size)
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", peer.addr)
+				ctx := logger.SetReqInfo(context.Background(), reqInfo)
+				logger.LogIf(ctx, err)
+				reply[idx].Error = err.Error()
+				return
+			}
+
+			reply[idx].Drives = results
+		}(i, p)
+	}
+	wg.Wait()
+
+	jsonBytes, err := json.Marshal(reply)
+	if err != nil {
+		writeErrorResponseJSON(w, ErrInternalError, r.URL)
+		logger.LogIf(context.Background(), err)
+		return
+	}
+
+	writeSuccessResponseJSON(w, jsonBytes)
+}
+
 // extractHealInitParams - Validates params for heal init API.
 func extractHealInitParams(r *http.Request) (bucket, objPrefix string,
 	hs madmin.HealOpts, clientToken string, forceStart bool,
@@ -484,6 +599,16 @@ func toAdminAPIErrCode(err error) APIErrorCode {
 	switch err {
 	case errXLWriteQuorum:
 		return ErrAdminConfigNoQuorum
+	case errNoSuchPool:
+		return ErrAdminNoSuchPool
+	case errDecommissionAlreadyRunning, errDecommissionNotRunning, errDecommissionLastPool:
+		return ErrAdminDecommissionNotAllowed
+	case errInvalidConfigKey:
+		return ErrAdminInvalidConfigKey
+	case errNoSuchConfigKey:
+		return ErrAdminNoSuchConfigKey
+	case errNoSuchConfigHistory:
+		return ErrAdminNoSuchConfigHistory
 	default:
 		return toAPIErrorCode(err)
 	}
@@ -561,17 +686,295 @@ func (a adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	oldConfig, rerr := readServerConfig(ctx, objectAPI)
+
+	// Changes limited to subsystems which can be reconfigured at
+	// runtime, such as notification targets, are applied without
+	// restarting the servers.
+	if rerr == nil && oldConfig.Credential.Equal(config.Credential) {
+		applied, cerr := commitConfig(ctx, objectAPI, oldConfig, &config)
+		if cerr != nil {
+			writeErrorResponseJSON(w, toAdminAPIErrCode(cerr), r.URL)
+			return
+		}
+
+		writeConfigAppliedResponse(w, applied)
+		if !applied {
+			sendServiceCmd(globalAdminPeers, serviceRestart)
+		}
+		return
+	}
+
+	// Keep the configuration being replaced in history.
+	if rerr == nil {
+		logger.LogIf(ctx, saveConfigHistory(ctx, objectAPI, oldConfig))
+	}
+
 	if err = saveServerConfig(objectAPI, &config); err != nil {
 		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
 		return
 	}
 
 	// Reply to the client before restarting minio server.
-	writeSuccessResponseHeadersOnly(w)
+	writeConfigAppliedResponse(w, false)
 
 	sendServiceCmd(globalAdminPeers, serviceRestart)
 }
 
+// validateAdminConfigKVReq - validates a config KV admin request and
+// returns the object layer on which config.json is stored.
+func validateAdminConfigKVReq(w http.ResponseWriter, r *http.Request) ObjectLayer {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return nil
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return nil
+	}
+
+	return objectAPI
+}
+
+// writeConfigKVErrorResponse - writes the error of changing a config
+// key, errors not related to the key itself are validation errors.
+func writeConfigKVErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case errInvalidConfigKey, errNoSuchConfigKey, errNoSuchConfigHistory:
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+	default:
+		writeCustomErrorResponseJSON(w, ErrAdminConfigBadJSON, err.Error(), r.URL)
+	}
+}
+
+// writeConfigAppliedResponse - replies to a config change, telling
+// the client whether the change was applied without a restart.
+func writeConfigAppliedResponse(w http.ResponseWriter, applied bool) {
+	w.Header().Set(madmin.ConfigAppliedHeader, strconv.FormatBool(applied))
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetConfigKVHandler - GET /minio/admin/v1/config-kv?key=<key>
+// ----------
+// Returns the encrypted JSON value of a config key such as
+// 'region' or 'notify.webhook.1'.
+func (a adminAPIHandlers) GetConfigKVHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetConfigKV")
+
+	objectAPI := validateAdminConfigKVReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	config, err := readServerConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	value, err := getConfigKV(config, r.URL.Query().Get("key"))
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	evalue, err := madmin.EncryptServerConfigData(password, value)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, evalue)
+}
+
+// SetConfigKVHandler - PUT /minio/admin/v1/config-kv?key=<key>
+// ----------
+// Sets a config key to the encrypted JSON value in the request body.
+// The resulting configuration is validated before it is saved, the
+// previous configuration is kept in history.
+func (a adminAPIHandlers) SetConfigKVHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetConfigKV")
+
+	objectAPI := validateAdminConfigKVReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Read the value from request body.
+	valueBuf := make([]byte, maxConfigJSONSize+1)
+	n, err := io.ReadFull(r.Body, valueBuf)
+	if err == nil {
+		// More than maxConfigSize bytes were available
+		writeErrorResponseJSON(w, ErrAdminConfigTooLarge, r.URL)
+		return
+	}
+	if err != io.ErrUnexpectedEOF {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	password := globalServerConfig.GetCredential().SecretKey
+	value, err := madmin.DecryptServerConfigData(password, bytes.NewReader(valueBuf[:n]))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, ErrAdminConfigBadJSON, r.URL)
+		return
+	}
+
+	configLock := newConfigTransactionLock()
+	if err = configLock.GetLock(globalOperationTimeout); err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+	defer configLock.Unlock()
+
+	oldConfig, err := readServerConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	config, err := setConfigKV(oldConfig, r.URL.Query().Get("key"), value)
+	if err != nil {
+		writeConfigKVErrorResponse(w, r, err)
+		return
+	}
+
+	applied, err := commitConfig(ctx, objectAPI, oldConfig, config)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeConfigAppliedResponse(w, applied)
+}
+
+// DelConfigKVHandler - DELETE /minio/admin/v1/config-kv?key=<key>
+// ----------
+// Removes a config key, removing a whole subsystem resets it to its
+// default value. The previous configuration is kept in history.
+func (a adminAPIHandlers) DelConfigKVHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DelConfigKV")
+
+	objectAPI := validateAdminConfigKVReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	configLock := newConfigTransactionLock()
+	if err := configLock.GetLock(globalOperationTimeout); err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+	defer configLock.Unlock()
+
+	oldConfig, err := readServerConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	config, err := delConfigKV(oldConfig, r.URL.Query().Get("key"))
+	if err != nil {
+		writeConfigKVErrorResponse(w, r, err)
+		return
+	}
+
+	applied, err := commitConfig(ctx, objectAPI, oldConfig, config)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeConfigAppliedResponse(w, applied)
+}
+
+// ListConfigHistoryHandler - GET /minio/admin/v1/config-history
+// ----------
+// Returns the previous configurations which can be restored.
+func (a adminAPIHandlers) ListConfigHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListConfigHistory")
+
+	objectAPI := validateAdminConfigKVReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	entries, err := listConfigHistory(ctx, objectAPI)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// RestoreConfigHistoryHandler - POST /minio/admin/v1/config-history/restore?restoreId=<id>
+// ----------
+// Restores a previous configuration, credentials are not restored.
+// The current configuration is kept in history.
+func (a adminAPIHandlers) RestoreConfigHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RestoreConfigHistory")
+
+	objectAPI := validateAdminConfigKVReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	configLock := newConfigTransactionLock()
+	if err := configLock.GetLock(globalOperationTimeout); err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+	defer configLock.Unlock()
+
+	oldConfig, err := readServerConfig(ctx, objectAPI)
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	config, err := readConfigHistory(ctx, objectAPI, r.URL.Query().Get("restoreId"))
+	if err != nil {
+		writeConfigKVErrorResponse(w, r, err)
+		return
+	}
+
+	// Credentials are managed separately.
+	config.Credential = oldConfig.Credential
+	if err = config.Validate(); err != nil {
+		writeConfigKVErrorResponse(w, r, err)
+		return
+	}
+
+	applied, err := commitConfig(ctx, objectAPI, oldConfig, config)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeConfigAppliedResponse(w, applied)
+}
+
 // UpdateCredsHandler - POST /minio/admin/v1/config/credential
 // ----------
 // Update credentials in a minio server. In a distributed setup,
@@ -660,3 +1063,273 @@ func (a adminAPIHandlers) UpdateCredentialsHandler(w http.ResponseWriter,
 	// Reply to the client before restarting minio server.
 	writeSuccessResponseHeadersOnly(w)
 }
+
+// validateAdminIAMReq - validates an IAM admin request and returns the
+// object layer to persist IAM configuration on.
+func validateAdminIAMReq(w http.ResponseWriter, r *http.Request) ObjectLayer {
+	// Get current object layer instance. globalIAMSys is not
+	// initialized in gateway mode.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil || globalIAMSys == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return nil
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return nil
+	}
+
+	return objectAPI
+}
+
+// ListCannedPoliciesHandler - GET /minio/admin/v1/list-canned-policies
+// ----------
+// Returns all canned policies as a JSON map of policy name to policy.
+func (a adminAPIHandlers) ListCannedPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListCannedPolicies")
+
+	if objectAPI := validateAdminIAMReq(w, r); objectAPI == nil {
+		return
+	}
+
+	data, err := json.Marshal(globalIAMSys.ListPolicies())
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// AddCannedPolicyHandler - PUT /minio/admin/v1/add-canned-policy?policyName=<name>
+// ----------
+// Adds or replaces canned policy of given name, policy is sent in the
+// request body.
+func (a adminAPIHandlers) AddCannedPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AddCannedPolicy")
+
+	objectAPI := validateAdminIAMReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	policyName := r.URL.Query().Get(string(mgmtPolicyName))
+	if !isValidIAMName(policyName) {
+		writeErrorResponseJSON(w, ErrAdminInvalidPolicyName, r.URL)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		writeErrorResponseJSON(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	// Error out if Content-Length is beyond allowed size.
+	if r.ContentLength > maxBucketPolicySize {
+		writeErrorResponseJSON(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	var p policy.Policy
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&p); err != nil || p.Version == "" {
+		writeErrorResponseJSON(w, ErrMalformedPolicy, r.URL)
+		return
+	}
+
+	if err := setIAMPolicy(ctx, objectAPI, policyName, p); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// RemoveCannedPolicyHandler - DELETE /minio/admin/v1/remove-canned-policy?policyName=<name>
+func (a adminAPIHandlers) RemoveCannedPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RemoveCannedPolicy")
+
+	objectAPI := validateAdminIAMReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	policyName := r.URL.Query().Get(string(mgmtPolicyName))
+	if !isValidIAMName(policyName) {
+		writeErrorResponseJSON(w, ErrAdminInvalidPolicyName, r.URL)
+		return
+	}
+
+	if _, ok := globalIAMSys.GetPolicy(policyName); !ok {
+		writeErrorResponseJSON(w, ErrAdminNoSuchPolicy, r.URL)
+		return
+	}
+
+	if err := removeIAMPolicy(ctx, objectAPI, policyName); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetGroupPolicyHandler - PUT /minio/admin/v1/set-group-policy?group=<group>&policyName=<name>
+// ----------
+// Maps canned policy to an identity provider group, empty policy name
+// removes the mapping.
+func (a adminAPIHandlers) SetGroupPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetGroupPolicy")
+
+	objectAPI := validateAdminIAMReq(w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	group := r.URL.Query().Get(string(mgmtGroup))
+	policyName := r.URL.Query().Get(string(mgmtPolicyName))
+	if !isValidIAMName(group) || (policyName != "" && !isValidIAMName(policyName)) {
+		writeErrorResponseJSON(w, ErrAdminInvalidPolicyName, r.URL)
+		return
+	}
+
+	if policyName != "" {
+		if _, ok := globalIAMSys.GetPolicy(policyName); !ok {
+			writeErrorResponseJSON(w, ErrAdminNoSuchPolicy, r.URL)
+			return
+		}
+	}
+
+	if err := setIAMGroupPolicy(ctx, objectAPI, group, policyName); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// validateAdminPoolsReq - validates admin request on server pools,
+// returns nil for deployments without multiple pools.
+func validateAdminPoolsReq(w http.ResponseWriter, r *http.Request) *xlZones {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		writeErrorResponseJSON(w, ErrServerNotInitialized, r.URL)
+		return nil
+	}
+
+	// Validate request signature.
+	adminAPIErr := checkAdminRequestAuthType(r, "")
+	if adminAPIErr != ErrNone {
+		writeErrorResponseJSON(w, adminAPIErr, r.URL)
+		return nil
+	}
+
+	z, ok := objectAPI.(*xlZones)
+	if !ok {
+		writeErrorResponseJSON(w, ErrNotImplemented, r.URL)
+		return nil
+	}
+
+	return z
+}
+
+// ListPoolsHandler - GET /minio/admin/v1/pools/list
+// ----------
+// Returns status of all server pools as a JSON list.
+func (a adminAPIHandlers) ListPoolsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListPools")
+
+	z := validateAdminPoolsReq(w, r)
+	if z == nil {
+		return
+	}
+
+	pools, err := z.ListPools(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(pools)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// StatusPoolHandler - GET /minio/admin/v1/pools/status?pool=<cmdline>
+// ----------
+// Returns status of the server pool defined by its command line argument.
+func (a adminAPIHandlers) StatusPoolHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StatusPool")
+
+	z := validateAdminPoolsReq(w, r)
+	if z == nil {
+		return
+	}
+
+	status, err := z.StatusPool(ctx, r.URL.Query().Get(string(mgmtPool)))
+	if err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// StartDecommissionHandler - POST /minio/admin/v1/pools/decommission?pool=<cmdline>
+// ----------
+// Stops writing new objects to the server pool and starts moving its
+// objects to the remaining pools in the background.
+func (a adminAPIHandlers) StartDecommissionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartDecommission")
+
+	z := validateAdminPoolsReq(w, r)
+	if z == nil {
+		return
+	}
+
+	if err := z.StartDecommission(ctx, r.URL.Query().Get(string(mgmtPool))); err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// CancelDecommissionHandler - POST /minio/admin/v1/pools/cancel?pool=<cmdline>
+// ----------
+// Cancels an ongoing decommission, the server pool receives new objects again.
+func (a adminAPIHandlers) CancelDecommissionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelDecommission")
+
+	z := validateAdminPoolsReq(w, r)
+	if z == nil {
+		return
+	}
+
+	if err := z.CancelDecommission(ctx, r.URL.Query().Get(string(mgmtPool))); err != nil {
+		writeErrorResponseJSON(w, toAdminAPIErrCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
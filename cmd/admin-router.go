@@ -53,6 +53,10 @@ func registerAdminRouter(router *mux.Router) {
 	// Info operations
 	adminV1Router.Methods(http.MethodGet).Path("/info").HandlerFunc(httpTraceAll(adminAPI.ServerInfoHandler))
 
+	// Drive details and drive speedtest
+	adminV1Router.Methods(http.MethodGet).Path("/drives").HandlerFunc(httpTraceAll(adminAPI.ServerDrivesHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/drives/speedtest").HandlerFunc(httpTraceAll(adminAPI.DriveSpeedtestHandler))
+
 	/// Heal operations
 
 	// Heal processing endpoint.
@@ -68,4 +72,33 @@ func registerAdminRouter(router *mux.Router) {
 	adminV1Router.Methods(http.MethodGet).Path("/config").HandlerFunc(httpTraceHdrs(adminAPI.GetConfigHandler))
 	// Set config
 	adminV1Router.Methods(http.MethodPut).Path("/config").HandlerFunc(httpTraceHdrs(adminAPI.SetConfigHandler))
+	// Get, set and delete a config key
+	adminV1Router.Methods(http.MethodGet).Path("/config-kv").HandlerFunc(httpTraceHdrs(adminAPI.GetConfigKVHandler))
+	adminV1Router.Methods(http.MethodPut).Path("/config-kv").HandlerFunc(httpTraceHdrs(adminAPI.SetConfigKVHandler))
+	adminV1Router.Methods(http.MethodDelete).Path("/config-kv").HandlerFunc(httpTraceHdrs(adminAPI.DelConfigKVHandler))
+	// List and restore previous configurations
+	adminV1Router.Methods(http.MethodGet).Path("/config-history").HandlerFunc(httpTraceAll(adminAPI.ListConfigHistoryHandler))
+	adminV1Router.Methods(http.MethodPost).Path("/config-history/restore").HandlerFunc(httpTraceAll(adminAPI.RestoreConfigHistoryHandler))
+
+	/// IAM operations
+
+	// List canned policies
+	adminV1Router.Methods(http.MethodGet).Path("/list-canned-policies").HandlerFunc(httpTraceAll(adminAPI.ListCannedPoliciesHandler))
+	// Add canned policy
+	adminV1Router.Methods(http.MethodPut).Path("/add-canned-policy").HandlerFunc(httpTraceAll(adminAPI.AddCannedPolicyHandler))
+	// Remove canned policy
+	adminV1Router.Methods(http.MethodDelete).Path("/remove-canned-policy").HandlerFunc(httpTraceAll(adminAPI.RemoveCannedPolicyHandler))
+	// Map canned policy to group
+	adminV1Router.Methods(http.MethodPut).Path("/set-group-policy").HandlerFunc(httpTraceAll(adminAPI.SetGroupPolicyHandler))
+
+	/// Pool operations
+
+	// List status of all server pools
+	adminV1Router.Methods(http.MethodGet).Path("/pools/list").HandlerFunc(httpTraceAll(adminAPI.ListPoolsHandler))
+	// Status of a server pool
+	adminV1Router.Methods(http.MethodGet).Path("/pools/status").HandlerFunc(httpTraceAll(adminAPI.StatusPoolHandler))
+	// Start decommission of a server pool
+	adminV1Router.Methods(http.MethodPost).Path("/pools/decommission").HandlerFunc(httpTraceAll(adminAPI.StartDecommissionHandler))
+	// Cancel decommission of a server pool
+	adminV1Router.Methods(http.MethodPost).Path("/pools/cancel").HandlerFunc(httpTraceAll(adminAPI.CancelDecommissionHandler))
 }
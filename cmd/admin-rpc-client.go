@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
 	xnet "github.com/minio/minio/pkg/net"
 )
 
@@ -68,6 +69,24 @@ func (rpcClient *AdminRPCClient) GetConfig() ([]byte, error) {
 	return reply, err
 }
 
+// ServerDrives - returns details of the drives of the remote server.
+func (rpcClient *AdminRPCClient) ServerDrives() ([]madmin.ServerDrive, error) {
+	args := AuthArgs{}
+	var reply []madmin.ServerDrive
+
+	err := rpcClient.Call(adminServiceName+".ServerDrives", &args, &reply)
+	return reply, err
+}
+
+// DriveSpeedtest - runs a speedtest on the drives of the remote server.
+func (rpcClient *AdminRPCClient) DriveSpeedtest(size int64) ([]madmin.DriveSpeedtestResult, error) {
+	args := DriveSpeedtestArgs{Size: size}
+	var reply []madmin.DriveSpeedtestResult
+
+	err := rpcClient.Call(adminServiceName+".DriveSpeedtest", &args, &reply)
+	return reply, err
+}
+
 // NewAdminRPCClient - returns new admin RPC client.
 func NewAdminRPCClient(host *xnet.Host) (*AdminRPCClient, error) {
 	scheme := "http"
@@ -112,6 +131,8 @@ type adminCmdRunner interface {
 	ReInitFormat(dryRun bool) error
 	ServerInfo() (ServerInfoData, error)
 	GetConfig() ([]byte, error)
+	ServerDrives() ([]madmin.ServerDrive, error)
+	DriveSpeedtest(size int64) ([]madmin.DriveSpeedtestResult, error)
 }
 
 // adminPeer - represents an entity that implements admin API RPCs.
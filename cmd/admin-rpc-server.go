@@ -23,6 +23,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/logger"
 	xrpc "github.com/minio/minio/cmd/rpc"
+	"github.com/minio/minio/pkg/madmin"
 )
 
 const adminServiceName = "Admin"
@@ -58,6 +59,24 @@ func (receiver *adminRPCReceiver) GetConfig(args *AuthArgs, reply *[]byte) (err
 	return err
 }
 
+// ServerDrives - returns details of the drives of this server.
+func (receiver *adminRPCReceiver) ServerDrives(args *AuthArgs, reply *[]madmin.ServerDrive) (err error) {
+	*reply, err = receiver.local.ServerDrives()
+	return err
+}
+
+// DriveSpeedtestArgs - provides the size of the file written by DriveSpeedtest RPC
+type DriveSpeedtestArgs struct {
+	AuthArgs
+	Size int64
+}
+
+// DriveSpeedtest - runs a speedtest on the drives of this server.
+func (receiver *adminRPCReceiver) DriveSpeedtest(args *DriveSpeedtestArgs, reply *[]madmin.DriveSpeedtestResult) (err error) {
+	*reply, err = receiver.local.DriveSpeedtest(args.Size)
+	return err
+}
+
 // ReInitFormatArgs - provides dry-run information to re-initialize format.json
 type ReInitFormatArgs struct {
 	AuthArgs
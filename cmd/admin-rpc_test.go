@@ -167,6 +167,83 @@ func testAdminCmdRunnerGetConfig(t *testing.T, client adminCmdRunner) {
 	}
 }
 
+func testAdminCmdRunnerServerDrives(t *testing.T, client adminCmdRunner) {
+	tmpGlobalObjectAPI := globalObjectAPI
+	defer func() {
+		globalObjectAPI = tmpGlobalObjectAPI
+	}()
+
+	objLayer, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	testCases := []struct {
+		objectAPI ObjectLayer
+		drives    int
+		expectErr bool
+	}{
+		{objLayer, len(fsDirs), false},
+		{&DummyObjectLayer{}, 0, true},
+		{nil, 0, true},
+	}
+
+	for i, testCase := range testCases {
+		globalObjectAPI = testCase.objectAPI
+		drives, err := client.ServerDrives()
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+		if len(drives) != testCase.drives {
+			t.Fatalf("case %v: expected %d drives, got %d", i+1, testCase.drives, len(drives))
+		}
+	}
+}
+
+func testAdminCmdRunnerDriveSpeedtest(t *testing.T, client adminCmdRunner) {
+	tmpGlobalObjectAPI := globalObjectAPI
+	defer func() {
+		globalObjectAPI = tmpGlobalObjectAPI
+	}()
+
+	objLayer, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	testCases := []struct {
+		objectAPI ObjectLayer
+		drives    int
+		expectErr bool
+	}{
+		{objLayer, len(fsDirs), false},
+		{&DummyObjectLayer{}, 0, true},
+		{nil, 0, true},
+	}
+
+	for i, testCase := range testCases {
+		globalObjectAPI = testCase.objectAPI
+		results, err := client.DriveSpeedtest(1024)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+		if len(results) != testCase.drives {
+			t.Fatalf("case %v: expected %d results, got %d", i+1, testCase.drives, len(results))
+		}
+		for _, result := range results {
+			if result.Error != "" {
+				t.Fatalf("case %v: unexpected error %s", i+1, result.Error)
+			}
+		}
+	}
+}
+
 func newAdminRPCHTTPServerClient(t *testing.T) (*httptest.Server, *AdminRPCClient, *serverConfig) {
 	rpcServer, err := NewAdminRPCServer()
 	if err != nil {
@@ -237,3 +314,23 @@ func TestAdminRPCClientGetConfig(t *testing.T) {
 
 	testAdminCmdRunnerGetConfig(t, rpcClient)
 }
+
+func TestAdminRPCClientServerDrives(t *testing.T) {
+	httpServer, rpcClient, prevGlobalServerConfig := newAdminRPCHTTPServerClient(t)
+	defer httpServer.Close()
+	defer func() {
+		globalServerConfig = prevGlobalServerConfig
+	}()
+
+	testAdminCmdRunnerServerDrives(t, rpcClient)
+}
+
+func TestAdminRPCClientDriveSpeedtest(t *testing.T) {
+	httpServer, rpcClient, prevGlobalServerConfig := newAdminRPCHTTPServerClient(t)
+	defer httpServer.Close()
+	defer func() {
+		globalServerConfig = prevGlobalServerConfig
+	}()
+
+	testAdminCmdRunnerDriveSpeedtest(t, rpcClient)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/minio/minio/pkg/dns"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/inventory"
 	"github.com/minio/minio/pkg/s3select"
 )
 
@@ -162,6 +163,21 @@ const (
 	ErrOverlappingConfigs
 	ErrUnsupportedNotification
 
+	// Bucket inventory related errors.
+	ErrNoSuchInventoryConfiguration
+	ErrInventoryInvalidID
+	ErrInventoryInvalidDestination
+	ErrInventoryInvalidConfiguration
+	ErrInventoryUnsupportedFormat
+	ErrInventoryTooManyConfigurations
+	ErrInvalidCannedACL
+	ErrMalformedACLError
+	ErrUnsupportedACL
+
+	// Temporary credentials related errors.
+	ErrInvalidToken
+	ErrExpiredToken
+
 	// S3 extended errors.
 	ErrContentSHA256Mismatch
 
@@ -193,6 +209,14 @@ const (
 	ErrAdminConfigTooLarge
 	ErrAdminConfigBadJSON
 	ErrAdminCredentialsMismatch
+	ErrAdminNoSuchPolicy
+	ErrAdminInvalidPolicyName
+	ErrAdminNoSuchPool
+	ErrAdminDecommissionNotAllowed
+	ErrAdminInvalidSpeedtestSize
+	ErrAdminInvalidConfigKey
+	ErrAdminNoSuchConfigKey
+	ErrAdminNoSuchConfigHistory
 	ErrInsecureClientRequest
 	ErrObjectTampered
 
@@ -802,6 +826,63 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		HTTPStatusCode: http.StatusBadRequest,
 	},
 
+	/// Bucket inventory related errors.
+	ErrNoSuchInventoryConfiguration: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified inventory configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInventoryInvalidID: {
+		Code:           "InvalidArgument",
+		Description:    "The inventory configuration id is invalid or does not match the id in the request.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInventoryInvalidDestination: {
+		Code:           "InvalidArgument",
+		Description:    "The inventory destination bucket must be specified as a valid bucket ARN.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInventoryInvalidConfiguration: {
+		Code:           "InvalidArgument",
+		Description:    "The inventory configuration has an invalid format, frequency, optional field or included object versions.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInventoryUnsupportedFormat: {
+		Code:           "NotImplemented",
+		Description:    "Only CSV inventory format is supported.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrInventoryTooManyConfigurations: {
+		Code:           "TooManyConfigurations",
+		Description:    "You are attempting to create a new configuration but have already reached the 1,000-configuration limit.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCannedACL: {
+		Code:           "InvalidArgument",
+		Description:    "The canned ACL specified is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrMalformedACLError: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrUnsupportedACL: {
+		Code:           "NotImplemented",
+		Description:    "Only grants equivalent to a canned ACL are supported.",
+		HTTPStatusCode: http.StatusNotImplemented,
+	},
+	ErrInvalidToken: {
+		Code:           "InvalidToken",
+		Description:    "The provided token is malformed or otherwise invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrExpiredToken: {
+		Code:           "ExpiredToken",
+		Description:    "The provided token has expired.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+
 	/// S3 extensions.
 	ErrContentSHA256Mismatch: {
 		Code:           "XAmzContentSHA256Mismatch",
@@ -891,6 +972,46 @@ var errorCodeResponse = map[APIErrorCode]APIError{
 		Description:    "Credentials in config mismatch with server environment variables",
 		HTTPStatusCode: http.StatusServiceUnavailable,
 	},
+	ErrAdminNoSuchPolicy: {
+		Code:           "XMinioAdminNoSuchPolicy",
+		Description:    "The canned policy does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminInvalidPolicyName: {
+		Code:           "XMinioAdminInvalidPolicyName",
+		Description:    "The canned policy or group name is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminNoSuchPool: {
+		Code:           "XMinioAdminNoSuchPool",
+		Description:    "The specified server pool does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminDecommissionNotAllowed: {
+		Code:           "XMinioAdminDecommissionNotAllowed",
+		Description:    "The server pool is already being decommissioned, is not being decommissioned or is the last active pool.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidSpeedtestSize: {
+		Code:           "XMinioAdminInvalidSpeedtestSize",
+		Description:    "The drive speedtest size must be a positive number of bytes not larger than 256MiB.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminInvalidConfigKey: {
+		Code:           "XMinioAdminInvalidConfigKey",
+		Description:    "The config key is invalid or does not address a configurable subsystem.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrAdminNoSuchConfigKey: {
+		Code:           "XMinioAdminNoSuchConfigKey",
+		Description:    "The specified config key does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrAdminNoSuchConfigHistory: {
+		Code:           "XMinioAdminNoSuchConfigHistory",
+		Description:    "The specified config history entry does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
 	ErrInsecureClientRequest: {
 		Code:           "XMinioInsecureClientRequest",
 		Description:    "Cannot respond to plain-text request from TLS-encrypted server",
@@ -1441,6 +1562,8 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrIncompatibleEncryptionMethod
 	case errKMSNotConfigured:
 		apiErr = ErrKMSNotConfigured
+	case errTooManyInventoryConfigs:
+		apiErr = ErrInventoryTooManyConfigurations
 	case crypto.ErrKMSAuthLogin:
 		apiErr = ErrKMSAuthFailure
 	case context.Canceled, context.DeadlineExceeded:
@@ -1620,6 +1743,10 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 	if err == dns.ErrNoEntriesFound {
 		return ErrNoSuchBucket
 	}
+	// Entries of another cluster exist for the bucket.
+	if err == dns.ErrBucketConflict {
+		return ErrBucketAlreadyExists
+	}
 
 	switch err.(type) {
 	case StorageFull:
@@ -1708,6 +1835,18 @@ func toAPIErrorCode(err error) (apiErr APIErrorCode) {
 		apiErr = ErrOverlappingFilterNotification
 	case *event.ErrUnsupportedConfiguration:
 		apiErr = ErrUnsupportedNotification
+	case BucketInventoryConfigNotFound:
+		apiErr = ErrNoSuchInventoryConfiguration
+	case *inventory.ErrInvalidID, *inventory.ErrIDMismatch:
+		apiErr = ErrInventoryInvalidID
+	case *inventory.ErrInvalidDestination:
+		apiErr = ErrInventoryInvalidDestination
+	case *inventory.ErrUnsupportedFormat:
+		apiErr = ErrInventoryUnsupportedFormat
+	case *inventory.ErrInvalidFormat, *inventory.ErrInvalidFrequency, *inventory.ErrInvalidField:
+		apiErr = ErrInventoryInvalidConfiguration
+	case *inventory.ErrDuplicateField, *inventory.ErrInvalidObjectVersions:
+		apiErr = ErrInventoryInvalidConfiguration
 	case BackendDown:
 		apiErr = ErrBackendDown
 	case crypto.Error:
@@ -60,8 +60,10 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.NewMultipartUploadHandler)).Queries("uploads", "")
 		// AbortMultipartUpload
 		bucket.Methods("DELETE").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.AbortMultipartUploadHandler)).Queries("uploadId", "{uploadId:.*}")
-		// GetObjectACL - this is a dummy call.
+		// GetObjectACL
 		bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.GetObjectACLHandler)).Queries("acl", "")
+		// PutObjectACL
+		bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(httpTraceAll(api.PutObjectACLHandler)).Queries("acl", "")
 		// SelectObjectContent
 		bucket.Methods("POST").Path("/{object:.+}").HandlerFunc(httpTraceHdrs(api.SelectObjectContentHandler)).Queries("select", "").Queries("select-type", "2")
 		// GetObject
@@ -79,9 +81,13 @@ func registerAPIRouter(router *mux.Router) {
 		// GetBucketPolicy
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketPolicyHandler)).Queries("policy", "")
 
-		// GetBucketACL -- this is a dummy call.
+		// GetBucketACL
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketACLHandler)).Queries("acl", "")
 
+		// GetBucketInventoryConfiguration
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketInventoryConfigurationHandler)).Queries("inventory", "", "id", "{id:.*}")
+		// ListBucketInventoryConfigurations
+		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.ListBucketInventoryConfigurationsHandler)).Queries("inventory", "")
 		// GetBucketNotification
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.GetBucketNotificationHandler)).Queries("notification", "")
 		// ListenBucketNotification
@@ -92,10 +98,14 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.ListObjectsV2Handler)).Queries("list-type", "2")
 		// ListObjectsV1 (Legacy)
 		bucket.Methods("GET").HandlerFunc(httpTraceAll(api.ListObjectsV1Handler))
+		// PutBucketACL
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketACLHandler)).Queries("acl", "")
 		// PutBucketPolicy
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketPolicyHandler)).Queries("policy", "")
 		// PutBucketNotification
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketNotificationHandler)).Queries("notification", "")
+		// PutBucketInventoryConfiguration
+		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketInventoryConfigurationHandler)).Queries("inventory", "", "id", "{id:.*}")
 		// PutBucket
 		bucket.Methods("PUT").HandlerFunc(httpTraceAll(api.PutBucketHandler))
 		// HeadBucket
@@ -106,6 +116,8 @@ func registerAPIRouter(router *mux.Router) {
 		bucket.Methods("POST").HandlerFunc(httpTraceAll(api.DeleteMultipleObjectsHandler)).Queries("delete", "")
 		// DeleteBucketPolicy
 		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketPolicyHandler)).Queries("policy", "")
+		// DeleteBucketInventoryConfiguration
+		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketInventoryConfigurationHandler)).Queries("inventory", "", "id", "{id:.*}")
 		// DeleteBucket
 		bucket.Methods("DELETE").HandlerFunc(httpTraceAll(api.DeleteBucketHandler))
 	}
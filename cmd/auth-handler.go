@@ -117,6 +117,14 @@ func checkAdminRequestAuthType(r *http.Request, region string) APIErrorCode {
 	if _, ok := r.Header["X-Amz-Content-Sha256"]; ok && getRequestAuthType(r) == authTypeSigned && !skipContentSha256Cksum(r) { // we only support V4 (no presign) with auth. body
 		s3Err = isReqAuthenticated(r, region)
 	}
+	if s3Err == ErrNone {
+		// Temporary credentials are not allowed to perform admin operations.
+		if _, owner, errCode := getReqAccessKeyV4(r, region); errCode != ErrNone {
+			s3Err = errCode
+		} else if !owner {
+			s3Err = ErrAccessDenied
+		}
+	}
 	if s3Err != ErrNone {
 		reqInfo := (&logger.ReqInfo{}).AppendTags("requestHeaders", dumpRequest(r))
 		ctx := logger.SetReqInfo(context.Background(), reqInfo)
@@ -146,6 +154,15 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 		if errorCode := isReqAuthenticated(r, region); errorCode != ErrNone {
 			return errorCode
 		}
+
+		cred, owner, errorCode := getReqAccessKeyV4(r, region)
+		if errorCode != ErrNone {
+			return errorCode
+		}
+		if !owner {
+			isOwner = false
+			accountName = cred.AccessKey
+		}
 	default:
 		isOwner = false
 		accountName = ""
@@ -174,13 +191,42 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
 	}
 
-	if globalPolicySys.IsAllowed(policy.Args{
+	args := policy.Args{
 		AccountName:     accountName,
 		Action:          action,
 		BucketName:      bucketName,
 		ConditionValues: getConditionValues(r, locationConstraint),
 		IsOwner:         isOwner,
 		ObjectName:      objectName,
+	}
+
+	if isRequestAllowed(ctx, args) {
+		return ErrNone
+	}
+
+	return ErrAccessDenied
+}
+
+// isPutAllowed - checks whether signature V4 signed PutObject request is
+// allowed. Temporary credentials are allowed only by their session policy,
+// bucket policy or canned ACLs.
+func isPutAllowed(ctx context.Context, r *http.Request, bucket, object string) APIErrorCode {
+	cred, owner, s3Err := getReqAccessKeyV4(r, globalServerConfig.GetRegion())
+	if s3Err != ErrNone {
+		return s3Err
+	}
+
+	if owner {
+		return ErrNone
+	}
+
+	if isRequestAllowed(ctx, policy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          policy.PutObjectAction,
+		BucketName:      bucket,
+		ConditionValues: getConditionValues(r, ""),
+		IsOwner:         false,
+		ObjectName:      object,
 	}) {
 		return ErrNone
 	}
@@ -188,6 +234,32 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 	return ErrAccessDenied
 }
 
+// isRequestAllowed - checks given policy args is allowed by session policy
+// of temporary credentials, by bucket policy or by canned ACLs. Canned ACLs
+// are evaluated only when bucket policy neither allows nor explicitly denies.
+func isRequestAllowed(ctx context.Context, args policy.Args) bool {
+	if args.AccountName != "" && args.ConditionValues != nil {
+		args.ConditionValues["username"] = []string{args.AccountName}
+	}
+
+	// globalIAMSys is not initialized in gateway mode.
+	if !args.IsOwner && args.AccountName != "" && globalIAMSys != nil && globalIAMSys.IsAllowed(args) {
+		return true
+	}
+
+	if globalPolicySys.IsAllowed(args) {
+		return true
+	}
+
+	// Explicit deny in bucket policy is never overridden by canned ACLs.
+	if globalPolicySys.IsDenied(args) {
+		return false
+	}
+
+	// globalACLSys is not initialized in gateway mode.
+	return globalACLSys != nil && globalACLSys.IsAllowed(ctx, args)
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -287,8 +359,9 @@ func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		a.handler.ServeHTTP(w, r)
 		return
 	} else if aType == authTypeJWT {
-		// Validate Authorization header if its valid for JWT request.
-		if !isHTTPRequestValid(r) {
+		// Validate Authorization header if its valid for JWT request,
+		// handlers check further whether the user is the server owner.
+		if _, _, authErr := webRequestAuthenticateUser(r); authErr != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
@@ -27,6 +27,7 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -42,6 +43,43 @@ import (
 	"github.com/minio/minio/pkg/sync/errgroup"
 )
 
+// listFederatedBuckets - lists the buckets of all clusters of a federated
+// deployment from the DNS entries, merged with the local buckets which
+// are not registered yet.
+func listFederatedBuckets(ctx context.Context, dnsConfig dns.Config, listBuckets func(context.Context) ([]BucketInfo, error)) ([]BucketInfo, error) {
+	dnsBuckets, err := dnsConfig.List()
+	if err != nil && err != dns.ErrNoEntriesFound {
+		return nil, err
+	}
+	localBuckets, err := listBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketSet := set.NewStringSet()
+	var bucketsInfo []BucketInfo
+	for _, dnsRecord := range dnsBuckets {
+		bucket := strings.Trim(dnsRecord.Key, slashSeparator)
+		if bucketSet.Contains(bucket) {
+			continue
+		}
+		bucketsInfo = append(bucketsInfo, BucketInfo{
+			Name:    bucket,
+			Created: dnsRecord.CreationDate,
+		})
+		bucketSet.Add(bucket)
+	}
+	for _, bucketInfo := range localBuckets {
+		if !bucketSet.Contains(bucketInfo.Name) {
+			bucketsInfo = append(bucketsInfo, bucketInfo)
+		}
+	}
+	sort.Slice(bucketsInfo, func(i, j int) bool {
+		return bucketsInfo[i].Name < bucketsInfo[j].Name
+	})
+	return bucketsInfo, nil
+}
+
 // Check if there are buckets on server without corresponding entry in etcd backend and
 // make entries. Here is the general flow
 // - Range over all the available buckets
@@ -199,35 +237,21 @@ func (api objectAPIHandlers) ListBucketsHandler(w http.ResponseWriter, r *http.R
 		writeErrorResponse(w, s3Error, r.URL)
 		return
 	}
-	// If etcd, dns federation configured list buckets from etcd.
-	var bucketsInfo []BucketInfo
+	// If etcd, dns federation configured list buckets of all clusters.
 	if globalDNSConfig != nil {
-		dnsBuckets, err := globalDNSConfig.List()
-		if err != nil && err != dns.ErrNoEntriesFound {
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-			return
-		}
-		bucketSet := set.NewStringSet()
-		for _, dnsRecord := range dnsBuckets {
-			if bucketSet.Contains(dnsRecord.Key) {
-				continue
-			}
-			bucketsInfo = append(bucketsInfo, BucketInfo{
-				Name:    strings.Trim(dnsRecord.Key, slashSeparator),
-				Created: dnsRecord.CreationDate,
-			})
-			bucketSet.Add(dnsRecord.Key)
-		}
-	} else {
-		// Invoke the list buckets.
-		var err error
-		bucketsInfo, err = listBuckets(ctx)
-		if err != nil {
-			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
-			return
+		listLocalBuckets := listBuckets
+		listBuckets = func(ctx context.Context) ([]BucketInfo, error) {
+			return listFederatedBuckets(ctx, globalDNSConfig, listLocalBuckets)
 		}
 	}
 
+	// Invoke the list buckets.
+	bucketsInfo, err := listBuckets(ctx)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	// Generate response.
 	response := generateListBucketsResponse(bucketsInfo)
 	encodedSuccessResponse := encodeResponse(response)
@@ -398,6 +422,13 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Parse incoming canned ACL.
+	acl, s3Error := getRequestCannedACL(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// Parse incoming location constraint.
 	location, s3Error := parseLocationConstraint(r)
 	if s3Error != ErrNone {
@@ -413,7 +444,8 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	if globalDNSConfig != nil {
-		if _, err := globalDNSConfig.Get(bucket); err != nil {
+		sr, err := globalDNSConfig.Get(bucket)
+		if err != nil {
 			if err == dns.ErrNoEntriesFound {
 				// Proceed to creating a bucket.
 				if err = objectAPI.MakeBucketWithLocation(ctx, bucket, location); err != nil {
@@ -425,6 +457,10 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 					writeErrorResponse(w, toAPIErrorCode(err), r.URL)
 					return
 				}
+				if err = initBucketACL(ctx, objectAPI, bucket, acl); err != nil {
+					writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+					return
+				}
 
 				// Make sure to add Location information here only for bucket
 				w.Header().Set("Location", getObjectLocation(r, globalDomainName, bucket, ""))
@@ -436,6 +472,11 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 			return
 
 		}
+		if globalDomainIPs.Intersection(set.CreateStringSet(getHostsSlice(sr)...)).IsEmpty() {
+			// The bucket belongs to another cluster.
+			writeErrorResponse(w, ErrBucketAlreadyExists, r.URL)
+			return
+		}
 		writeErrorResponse(w, ErrBucketAlreadyOwnedByYou, r.URL)
 		return
 	}
@@ -447,6 +488,11 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if err = initBucketACL(ctx, objectAPI, bucket, acl); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set("Location", path.Clean(r.URL.Path)) // Clean any trailing slashes.
 
@@ -590,6 +636,12 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	// Canned ACL is set in "acl" form field.
+	if s3Error := setRequestObjectACL(http.Header{amzACLHeader: formValues["Acl"]}, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	hashReader, err := hash.NewReader(fileBody, fileSize, "", "")
 	if err != nil {
 		logger.LogIf(ctx, err)
@@ -738,6 +790,13 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 
 	globalNotificationSys.RemoveNotification(bucket)
 	globalPolicySys.Remove(bucket)
+	// globalInventorySys and globalACLSys are not initialized in gateway mode.
+	if globalInventorySys != nil {
+		globalInventorySys.Remove(bucket)
+	}
+	if globalACLSys != nil {
+		globalACLSys.Remove(bucket)
+	}
 	globalNotificationSys.DeleteBucket(ctx, bucket)
 
 	if globalDNSConfig != nil {
@@ -23,12 +23,70 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/dns"
 )
 
+// dnsConfigStub - dns.Config returning a fixed list of records.
+type dnsConfigStub struct {
+	records []dns.SrvRecord
+	err     error
+}
+
+func (d dnsConfigStub) Put(bucket string) error                    { return d.err }
+func (d dnsConfigStub) List() ([]dns.SrvRecord, error)             { return d.records, d.err }
+func (d dnsConfigStub) Get(bucket string) ([]dns.SrvRecord, error) { return d.records, d.err }
+func (d dnsConfigStub) Delete(bucket string) error                 { return d.err }
+
+// Tests merging the buckets of all clusters of a federated deployment.
+func TestListFederatedBuckets(t *testing.T) {
+	created := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	localBuckets := func(ctx context.Context) ([]BucketInfo, error) {
+		return []BucketInfo{{Name: "local"}, {Name: "remote"}}, nil
+	}
+
+	testCases := []struct {
+		dnsConfig dnsConfigStub
+		buckets   []BucketInfo
+		err       error
+	}{
+		{
+			dnsConfig: dnsConfigStub{records: []dns.SrvRecord{
+				{Host: "10.0.0.1", Key: "/remote/", CreationDate: created},
+				{Host: "10.0.0.2", Key: "/remote/", CreationDate: created},
+				{Host: "10.0.0.2", Key: "/another.remote/", CreationDate: created},
+			}},
+			buckets: []BucketInfo{
+				{Name: "another.remote", Created: created},
+				{Name: "local"},
+				{Name: "remote", Created: created},
+			},
+		},
+		{
+			dnsConfig: dnsConfigStub{err: dns.ErrNoEntriesFound},
+			buckets:   []BucketInfo{{Name: "local"}, {Name: "remote"}},
+		},
+		{
+			dnsConfig: dnsConfigStub{err: errServerNotInitialized},
+			err:       errServerNotInitialized,
+		},
+	}
+	for i, testCase := range testCases {
+		buckets, err := listFederatedBuckets(context.Background(), testCase.dnsConfig, localBuckets)
+		if err != testCase.err {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.err, err)
+		}
+		if !reflect.DeepEqual(buckets, testCase.buckets) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.buckets, buckets)
+		}
+	}
+}
+
 // Wrapper for calling GetBucketPolicy HTTP handler tests for both XL multiple disks and single node setup.
 func TestGetBucketLocationHandler(t *testing.T) {
 	ExecObjectLayerAPITest(t, testGetBucketLocationHandler, []string{"GetBucketLocation"})
@@ -0,0 +1,273 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"path"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/inventory"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// Maximum size of an inventory configuration XML.
+	maxInventoryConfigSize = 1 * humanize.MiByte
+
+	// Maximum number of inventory configurations per bucket as per AWS S3 specification.
+	maxInventoryConfigsPerBucket = 1000
+)
+
+// PutBucketInventoryConfigurationHandler - This HTTP handler stores given inventory configuration as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTInventoryConfig.html
+func (api objectAPIHandlers) PutBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketInventoryConfiguration")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalInventorySys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	// PutBucketInventoryConfiguration always needs a Content-Length.
+	if r.ContentLength <= 0 {
+		writeErrorResponse(w, ErrMissingContentLength, r.URL)
+		return
+	}
+
+	if r.ContentLength > maxInventoryConfigSize {
+		writeErrorResponse(w, ErrEntityTooLarge, r.URL)
+		return
+	}
+
+	config, err := inventory.ParseConfig(io.LimitReader(r.Body, r.ContentLength), id)
+	if err != nil {
+		apiErr := ErrMalformedXML
+		if inventory.IsInventoryError(err) {
+			apiErr = toAPIErrorCode(err)
+		}
+
+		writeErrorResponse(w, apiErr, r.URL)
+		return
+	}
+
+	// Reports are written by the server, the caller has to be allowed
+	// to write them into the destination bucket.
+	dest := config.Destination.S3BucketDestination
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectAction, dest.BucketName(), path.Join(dest.Prefix, bucket, config.ID)); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Destination bucket must exist to receive reports.
+	if _, err = objAPI.GetBucketInfo(ctx, dest.BucketName()); err != nil {
+		writeErrorResponse(w, ErrInventoryInvalidDestination, r.URL)
+		return
+	}
+
+	configs, err := updateInventoryConfig(ctx, objAPI, bucket, func(configs *inventory.Configs) error {
+		if _, found := configs.Get(config.ID); !found && len(configs.Configs) >= maxInventoryConfigsPerBucket {
+			return errTooManyInventoryConfigs
+		}
+
+		configs.Set(*config)
+		return nil
+	})
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	globalInventorySys.Set(bucket, *configs)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketInventoryConfigurationHandler - This HTTP handler returns inventory configuration of given id.
+func (api objectAPIHandlers) GetBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketInventoryConfiguration")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalInventorySys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configs, err := readInventoryConfig(ctx, objAPI, bucket)
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	config, found := configs.Get(id)
+	if !found {
+		writeErrorResponse(w, ErrNoSuchInventoryConfiguration, r.URL)
+		return
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseXML(w, configData)
+}
+
+// ListBucketInventoryConfigurationsHandler - This HTTP handler returns all inventory configurations of a bucket.
+func (api objectAPIHandlers) ListBucketInventoryConfigurationsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBucketInventoryConfigurations")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalInventorySys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configs, err := readInventoryConfig(ctx, objAPI, bucket)
+	if err != nil {
+		// Return empty list if no configuration is set, to comply with AWS S3.
+		if _, ok := err.(BucketInventoryConfigNotFound); !ok {
+			writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+			return
+		}
+
+		configs = &inventory.Configs{}
+	}
+
+	configsData, err := xml.Marshal(configs)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	writeSuccessResponseXML(w, configsData)
+}
+
+// DeleteBucketInventoryConfigurationHandler - This HTTP handler removes inventory configuration of given id.
+func (api objectAPIHandlers) DeleteBucketInventoryConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketInventoryConfiguration")
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(w, ErrServerNotInitialized, r.URL)
+		return
+	}
+
+	if globalInventorySys == nil {
+		writeErrorResponse(w, ErrNotImplemented, r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	configs, err := updateInventoryConfig(ctx, objAPI, bucket, func(configs *inventory.Configs) error {
+		if !configs.Remove(id) {
+			return BucketInventoryConfigNotFound{Bucket: bucket}
+		}
+
+		return nil
+	})
+	if err != nil {
+		writeErrorResponse(w, toAPIErrorCode(err), r.URL)
+		return
+	}
+
+	globalInventorySys.Set(bucket, *configs)
+
+	writeSuccessNoContent(w)
+}
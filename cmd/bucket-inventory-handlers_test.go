@@ -0,0 +1,202 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/inventory"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+// return URL for inventory configuration operations.
+func getInventoryURL(endPoint, bucketName, id string) string {
+	queryValue := url.Values{}
+	queryValue.Set("inventory", "")
+	if id != "" {
+		queryValue.Set("id", id)
+	}
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
+// Wrapper for calling bucket inventory handler tests for both XL multiple disks and single node setup.
+func TestBucketInventoryHandlers(t *testing.T) {
+	ExecObjectLayerAPITest(t, testBucketInventoryHandlers, []string{
+		"GetBucketInventoryConfiguration",
+		"ListBucketInventoryConfigurations",
+		"PutBucketInventoryConfiguration",
+		"DeleteBucketInventoryConfiguration",
+	})
+}
+
+func testBucketInventoryHandlers(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+
+	globalPolicySys = NewPolicySys()
+	globalInventorySys = NewInventorySys()
+
+	destBucket := getRandomBucketName()
+	if err := obj.MakeBucketWithLocation(context.Background(), destBucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	configTemplate := `<InventoryConfiguration><Destination><S3BucketDestination><Bucket>arn:aws:s3:::%s</Bucket><Format>%s</Format></S3BucketDestination></Destination><IsEnabled>true</IsEnabled><Id>%s</Id><IncludedObjectVersions>Current</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`
+
+	testCases := []struct {
+		method             string
+		bucketName         string
+		id                 string
+		body               string
+		accessKey          string
+		secretKey          string
+		expectedRespStatus int
+	}{
+		// Test case - 1.
+		// No configuration is set yet.
+		{"GET", bucketName, "report1", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Test case - 2.
+		// Valid configuration.
+		{"PUT", bucketName, "report1", fmt.Sprintf(configTemplate, destBucket, "CSV", "report1"), credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Test case - 3.
+		// Configuration ID mismatches with requested ID.
+		{"PUT", bucketName, "report2", fmt.Sprintf(configTemplate, destBucket, "CSV", "report1"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 4.
+		// Unsupported format.
+		{"PUT", bucketName, "report2", fmt.Sprintf(configTemplate, destBucket, "ORC", "report2"), credentials.AccessKey, credentials.SecretKey, http.StatusNotImplemented},
+		// Test case - 5.
+		// Non-existent destination bucket.
+		{"PUT", bucketName, "report2", fmt.Sprintf(configTemplate, "non-existent", "CSV", "report2"), credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 6.
+		// Malformed XML.
+		{"PUT", bucketName, "report2", "<InventoryConfiguration>", credentials.AccessKey, credentials.SecretKey, http.StatusBadRequest},
+		// Test case - 7.
+		// Anonymous request.
+		{"PUT", bucketName, "report2", fmt.Sprintf(configTemplate, destBucket, "CSV", "report2"), "", "", http.StatusForbidden},
+		// Test case - 8.
+		// Non-existent bucket.
+		{"PUT", "non-existent", "report2", fmt.Sprintf(configTemplate, destBucket, "CSV", "report2"), credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Test case - 9.
+		{"GET", bucketName, "report1", "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Test case - 10.
+		{"GET", bucketName, "report2", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Test case - 11.
+		{"GET", bucketName, "", "", credentials.AccessKey, credentials.SecretKey, http.StatusOK},
+		// Test case - 12.
+		{"DELETE", bucketName, "report2", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+		// Test case - 13.
+		{"DELETE", bucketName, "report1", "", credentials.AccessKey, credentials.SecretKey, http.StatusNoContent},
+		// Test case - 14.
+		{"GET", bucketName, "report1", "", credentials.AccessKey, credentials.SecretKey, http.StatusNotFound},
+	}
+
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		body := []byte(testCase.body)
+		req, err := newTestSignedRequestV4(testCase.method, getInventoryURL("", testCase.bucketName, testCase.id),
+			int64(len(body)), bytes.NewReader(body), testCase.accessKey, testCase.secretKey)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, testCase.expectedRespStatus, rec.Code, rec.Body.String())
+		}
+
+		if testCase.method != "GET" || rec.Code != http.StatusOK {
+			continue
+		}
+
+		if testCase.id == "" {
+			var configs inventory.Configs
+			if err = xml.Unmarshal(rec.Body.Bytes(), &configs); err != nil {
+				t.Fatalf("Test %d: %s: Unable to parse response: <ERROR> %v", i+1, instanceType, err)
+			}
+			if len(configs.Configs) != 1 {
+				t.Fatalf("Test %d: %s: Expected 1 configuration, got %d", i+1, instanceType, len(configs.Configs))
+			}
+			continue
+		}
+
+		config, err := inventory.ParseConfig(rec.Body, testCase.id)
+		if err != nil {
+			t.Fatalf("Test %d: %s: Unable to parse response: <ERROR> %v", i+1, instanceType, err)
+		}
+		if config.Destination.S3BucketDestination.BucketName() != destBucket {
+			t.Fatalf("Test %d: %s: Expected destination %s, got %s", i+1, instanceType, destBucket,
+				config.Destination.S3BucketDestination.BucketName())
+		}
+	}
+
+	// Deleting the last configuration must clear the cached entry.
+	if configs := globalInventorySys.Get(bucketName); len(configs.Configs) != 0 {
+		t.Fatalf("%s: Expected no cached configurations, got %d", instanceType, len(configs.Configs))
+	}
+
+	// Callers allowed to configure inventory need write access to the
+	// destination bucket as well.
+	globalPolicySys.Set(bucketName, policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{policy.NewStatement(
+			policy.Allow,
+			policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.PutInventoryConfigurationAction),
+			policy.NewResourceSet(policy.NewResource(bucketName, "")),
+			condition.NewFunctions(),
+		)},
+	})
+	defer globalPolicySys.Remove(bucketName)
+
+	for i, expectedRespStatus := range []int{http.StatusForbidden, http.StatusOK} {
+		if expectedRespStatus == http.StatusOK {
+			globalPolicySys.Set(destBucket, policy.Policy{
+				Version: policy.DefaultVersion,
+				Statements: []policy.Statement{policy.NewStatement(
+					policy.Allow,
+					policy.NewPrincipal("*"),
+					policy.NewActionSet(policy.PutObjectAction),
+					policy.NewResourceSet(policy.NewResource(destBucket, "*")),
+					condition.NewFunctions(),
+				)},
+			})
+			defer globalPolicySys.Remove(destBucket)
+		}
+
+		rec := httptest.NewRecorder()
+		body := []byte(fmt.Sprintf(configTemplate, destBucket, "CSV", "report3"))
+		req, err := newTestRequest("PUT", getInventoryURL("", bucketName, "report3"), int64(len(body)), bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Test %d: %s: Failed to create HTTP request: <ERROR> %v", i+1, instanceType, err)
+		}
+
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != expectedRespStatus {
+			t.Fatalf("Test %d: %s: Expected the response status to be `%d`, but instead found `%d`: %s",
+				i+1, instanceType, expectedRespStatus, rec.Code, rec.Body.String())
+		}
+	}
+}
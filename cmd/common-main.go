@@ -31,6 +31,9 @@ import (
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/dns"
+	"github.com/minio/minio/pkg/iam/ldap"
+	"github.com/minio/minio/pkg/iam/validator"
+	xnet "github.com/minio/minio/pkg/net"
 
 	"github.com/minio/minio-go/pkg/set"
 )
@@ -163,9 +166,14 @@ func handleCommonEnvVars() {
 			globalDomainIPs.Add(ip)
 		}
 	}
-	if globalDomainName != "" && !globalDomainIPs.IsEmpty() && globalEtcdClient != nil {
+	dnsWebhookEndpoint := os.Getenv("MINIO_DNS_WEBHOOK_ENDPOINT")
+	if globalDomainName != "" && !globalDomainIPs.IsEmpty() {
 		var err error
-		globalDNSConfig, err = dns.NewCoreDNS(globalDomainName, globalDomainIPs, globalMinioPort, globalEtcdClient)
+		if globalEtcdClient != nil {
+			globalDNSConfig, err = dns.NewCoreDNS(globalDomainName, globalDomainIPs, globalMinioPort, globalEtcdClient)
+		} else if dnsWebhookEndpoint != "" {
+			globalDNSConfig, err = dns.NewWebhookDNS(globalDomainName, globalDomainIPs, globalMinioPort, dnsWebhookEndpoint)
+		}
 		logger.FatalIf(err, "Unable to initialize DNS config for %s.", globalDomainName)
 	}
 
@@ -204,6 +212,89 @@ func handleCommonEnvVars() {
 			globalCacheMaxUse = maxUse
 		}
 	}
+
+	if commit := os.Getenv("MINIO_CACHE_COMMIT"); commit != "" {
+		commitMode, err := parseCacheCommit(commit)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_COMMIT value (`%s`)", commit)
+		}
+		globalCacheCommit = commitMode
+	}
+
+	if cacheRange := os.Getenv("MINIO_CACHE_RANGE"); cacheRange != "" {
+		rangeFlag, err := ParseBoolFlag(cacheRange)
+		if err != nil {
+			logger.Fatal(uiErrInvalidCacheRangeValue(nil).Msg("Unknown value `%s`", cacheRange), "Unable to validate MINIO_CACHE_RANGE environment variable")
+		}
+		globalCacheRange = bool(rangeFlag)
+	}
+
+	if policy := os.Getenv("MINIO_CACHE_POLICY"); policy != "" {
+		cachePolicy, err := parseCachePolicy(policy)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_POLICY value (`%s`)", policy)
+		}
+		globalCachePolicy = cachePolicy
+	}
+
+	if lowStr := os.Getenv("MINIO_CACHE_WATERMARK_LOW"); lowStr != "" {
+		low, err := strconv.Atoi(lowStr)
+		if err != nil {
+			logger.Fatal(uiErrInvalidCacheWatermarkValue(err), "Unable to parse MINIO_CACHE_WATERMARK_LOW value (`%s`)", lowStr)
+		}
+		globalCacheWatermarkLow = low
+	}
+
+	if highStr := os.Getenv("MINIO_CACHE_WATERMARK_HIGH"); highStr != "" {
+		high, err := strconv.Atoi(highStr)
+		if err != nil {
+			logger.Fatal(uiErrInvalidCacheWatermarkValue(err), "Unable to parse MINIO_CACHE_WATERMARK_HIGH value (`%s`)", highStr)
+		}
+		globalCacheWatermarkHigh = high
+	}
+
+	if err := validateCacheWatermarks(globalCacheWatermarkLow, globalCacheWatermarkHigh); err != nil {
+		logger.Fatal(err, "Invalid MINIO_CACHE_WATERMARK_LOW and MINIO_CACHE_WATERMARK_HIGH values")
+	}
+
+	if includes := os.Getenv("MINIO_CACHE_INCLUDE"); includes != "" {
+		includeList, err := parseCacheIncludes(strings.Split(includes, cacheEnvDelimiter))
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_INCLUDE value (`%s`)", includes)
+		}
+		globalCacheIncludes = includeList
+	}
+
+	if quota := os.Getenv("MINIO_CACHE_QUOTA"); quota != "" {
+		quotas, err := parseCacheQuotaEntries(strings.Split(quota, cacheEnvDelimiter))
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_QUOTA value (`%s`)", quota)
+		}
+		globalCacheQuota = quotas
+	}
+
+	globalCacheMinSize = os.Getenv("MINIO_CACHE_MIN_SIZE")
+	globalCacheMaxSize = os.Getenv("MINIO_CACHE_MAX_SIZE")
+	if _, _, err := parseCacheSizes(globalCacheMinSize, globalCacheMaxSize); err != nil {
+		logger.Fatal(err, "Unable to parse MINIO_CACHE_MIN_SIZE and MINIO_CACHE_MAX_SIZE values")
+	}
+
+	if afterHitsStr := os.Getenv("MINIO_CACHE_AFTER_HITS"); afterHitsStr != "" {
+		afterHits, err := strconv.Atoi(afterHitsStr)
+		if err != nil || afterHits < 0 {
+			logger.Fatal(uiErrInvalidCacheAfterHitsValue(err), "Unable to parse MINIO_CACHE_AFTER_HITS value (`%s`)", afterHitsStr)
+		}
+		globalCacheAfterHits = afterHits
+	}
+
+	if masterKey := os.Getenv("MINIO_CACHE_ENCRYPTION_MASTER_KEY"); masterKey != "" {
+		keyID, key, err := parseCacheEncryptionKey(masterKey)
+		if err != nil {
+			logger.Fatal(err, "Unable to parse MINIO_CACHE_ENCRYPTION_MASTER_KEY value")
+		}
+		globalCacheKMS = crypto.NewKMS(key)
+		globalCacheKMSKeyID = keyID
+	}
 	// In place update is true by default if the MINIO_UPDATE is not set
 	// or is not set to 'off', if MINIO_UPDATE is set to 'off' then
 	// in-place update is off.
@@ -224,19 +315,30 @@ func handleCommonEnvVars() {
 			logger.FatalIf(err, "Invalid value set in environment variable %s", reducedRedundancyStorageClassEnv)
 		}
 
+		if csc := os.Getenv(customStorageClassesEnv); csc != "" {
+			globalCustomStorageClasses, err = parseCustomStorageClasses(csc)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", customStorageClassesEnv)
+		}
+
 		// Validation is done after parsing both the storage classes. This is needed because we need one
 		// storage class value to deduce the correct value of the other storage class.
 		if globalRRStorageClass.Scheme != "" {
-			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity)
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, globalCustomStorageClasses)
 			logger.FatalIf(err, "Invalid value set in environment variable %s", reducedRedundancyStorageClassEnv)
 			globalIsStorageClass = true
 		}
 
 		if globalStandardStorageClass.Scheme != "" {
-			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity)
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, globalCustomStorageClasses)
 			logger.FatalIf(err, "Invalid value set in environment variable %s", standardStorageClassEnv)
 			globalIsStorageClass = true
 		}
+
+		if len(globalCustomStorageClasses) > 0 {
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, globalCustomStorageClasses)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", customStorageClassesEnv)
+			globalIsStorageClass = true
+		}
 	}
 
 	// Get WORM environment variable.
@@ -252,6 +354,61 @@ func handleCommonEnvVars() {
 		globalWORMEnabled = bool(wormFlag)
 	}
 
+	// Get FS listing index environment variable.
+	if listIndex := os.Getenv("MINIO_FS_LIST_INDEX"); listIndex != "" {
+		listIndexFlag, err := ParseBoolFlag(listIndex)
+		logger.FatalIf(err, "Unable to validate MINIO_FS_LIST_INDEX environment variable")
+		globalFSListIndexEnabled = bool(listIndexFlag)
+	}
+
+	if jwksURL, ok := os.LookupEnv("MINIO_IAM_JWKS_URL"); ok {
+		u, err := xnet.ParseURL(jwksURL)
+		logger.FatalIf(err, "Unable to parse MINIO_IAM_JWKS_URL value (`%s`)", jwksURL)
+
+		jwks := validator.JWKSArgs{
+			URL:      u,
+			Audience: os.Getenv("MINIO_IAM_JWKS_AUDIENCE"),
+			Issuer:   os.Getenv("MINIO_IAM_JWKS_ISSUER"),
+		}
+		logger.FatalIf(jwks.Validate(), "Invalid value set in environment variable MINIO_IAM_JWKS_URL")
+
+		globalOpenIDConfig = openIDConfig{JWKS: jwks}
+		globalIsEnvOpenID = true
+	}
+
+	if ldapServerAddr := os.Getenv("MINIO_IDENTITY_LDAP_SERVER_ADDR"); ldapServerAddr != "" {
+		globalLDAPConfig = ldap.Config{
+			ServerAddr:         ldapServerAddr,
+			BindDN:             os.Getenv("MINIO_IDENTITY_LDAP_BIND_DN"),
+			BindPassword:       os.Getenv("MINIO_IDENTITY_LDAP_BIND_PASSWORD"),
+			UserSearchBaseDN:   os.Getenv("MINIO_IDENTITY_LDAP_USER_SEARCH_BASE_DN"),
+			UserSearchFilter:   os.Getenv("MINIO_IDENTITY_LDAP_USER_SEARCH_FILTER"),
+			GroupSearchBaseDN:  os.Getenv("MINIO_IDENTITY_LDAP_GROUP_SEARCH_BASE_DN"),
+			GroupSearchFilter:  os.Getenv("MINIO_IDENTITY_LDAP_GROUP_SEARCH_FILTER"),
+			GroupNameAttribute: os.Getenv("MINIO_IDENTITY_LDAP_GROUP_NAME_ATTRIBUTE"),
+		}
+
+		if insecure := os.Getenv("MINIO_IDENTITY_LDAP_SERVER_INSECURE"); insecure != "" {
+			insecureFlag, err := ParseBoolFlag(insecure)
+			logger.FatalIf(err, "Unable to parse MINIO_IDENTITY_LDAP_SERVER_INSECURE value (`%s`)", insecure)
+			globalLDAPConfig.Insecure = bool(insecureFlag)
+		}
+
+		if skipVerify := os.Getenv("MINIO_IDENTITY_LDAP_TLS_SKIP_VERIFY"); skipVerify != "" {
+			skipVerifyFlag, err := ParseBoolFlag(skipVerify)
+			logger.FatalIf(err, "Unable to parse MINIO_IDENTITY_LDAP_TLS_SKIP_VERIFY value (`%s`)", skipVerify)
+			globalLDAPConfig.SkipTLSVerify = bool(skipVerifyFlag)
+		}
+
+		if expiry := os.Getenv("MINIO_IDENTITY_LDAP_STS_EXPIRY"); expiry != "" {
+			stsExpiry, err := time.ParseDuration(expiry)
+			logger.FatalIf(err, "Unable to parse MINIO_IDENTITY_LDAP_STS_EXPIRY value (`%s`)", expiry)
+			globalLDAPConfig.STSExpiry = stsExpiry
+		}
+
+		logger.FatalIf(globalLDAPConfig.Validate(), "Invalid LDAP identity configuration set in MINIO_IDENTITY_LDAP_* environment variables")
+	}
+
 	kmsConf, err := crypto.NewVaultConfig()
 	if err != nil {
 		logger.Fatal(err, "Unable to initialize hashicorp vault")
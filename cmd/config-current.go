@@ -30,6 +30,7 @@ import (
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/event/target"
+	"github.com/minio/minio/pkg/iam/validator"
 )
 
 // Steps to move from version N to version N+1
@@ -41,9 +42,9 @@ import (
 // 6. Make changes in config-current_test.go for any test change
 
 // Config version
-const serverConfigVersion = "28"
+const serverConfigVersion = "29"
 
-type serverConfig = serverConfigV28
+type serverConfig = serverConfigV29
 
 var (
 	// globalServerConfig server config.
@@ -109,21 +110,22 @@ func (s *serverConfig) SetWorm(b bool) {
 	s.Worm = BoolFlag(b)
 }
 
-func (s *serverConfig) SetStorageClass(standardClass, rrsClass storageClass) {
+func (s *serverConfig) SetStorageClass(standardClass, rrsClass storageClass, customClasses map[string]storageClass) {
 	s.StorageClass.Standard = standardClass
 	s.StorageClass.RRS = rrsClass
+	s.StorageClass.Custom = customClasses
 }
 
 // GetStorageClass reads storage class fields from current config.
-// It returns the standard and reduced redundancy storage class struct
-func (s *serverConfig) GetStorageClass() (storageClass, storageClass) {
+// It returns the standard, reduced redundancy and custom storage classes
+func (s *serverConfig) GetStorageClass() (storageClass, storageClass, map[string]storageClass) {
 	if globalIsStorageClass {
-		return globalStandardStorageClass, globalRRStorageClass
+		return globalStandardStorageClass, globalRRStorageClass, globalCustomStorageClasses
 	}
 	if s == nil {
-		return storageClass{}, storageClass{}
+		return storageClass{}, storageClass{}, nil
 	}
-	return s.StorageClass.Standard, s.StorageClass.RRS
+	return s.StorageClass.Standard, s.StorageClass.RRS, s.StorageClass.Custom
 }
 
 // GetBrowser get current credentials.
@@ -149,11 +151,8 @@ func (s *serverConfig) GetWorm() bool {
 }
 
 // SetCacheConfig sets the current cache config
-func (s *serverConfig) SetCacheConfig(drives, exclude []string, expiry int, maxuse int) {
-	s.Cache.Drives = drives
-	s.Cache.Exclude = exclude
-	s.Cache.Expiry = expiry
-	s.Cache.MaxUse = maxuse
+func (s *serverConfig) SetCacheConfig(cacheConf CacheConfig) {
+	s.Cache = cacheConf
 }
 
 // GetCacheConfig gets the current cache config
@@ -164,6 +163,18 @@ func (s *serverConfig) GetCacheConfig() CacheConfig {
 			Exclude: globalCacheExcludes,
 			Expiry:  globalCacheExpiry,
 			MaxUse:  globalCacheMaxUse,
+			Commit:  globalCacheCommit,
+			Range:   BoolFlag(globalCacheRange),
+
+			Policy:        globalCachePolicy,
+			WatermarkLow:  globalCacheWatermarkLow,
+			WatermarkHigh: globalCacheWatermarkHigh,
+
+			Include:   globalCacheIncludes,
+			Quota:     globalCacheQuota,
+			MinSize:   globalCacheMinSize,
+			MaxSize:   globalCacheMaxSize,
+			AfterHits: globalCacheAfterHits,
 		}
 	}
 	if s == nil {
@@ -196,6 +207,10 @@ func (s *serverConfig) Validate() error {
 		}
 	}
 
+	if err := s.OpenID.JWKS.Validate(); err != nil {
+		return fmt.Errorf("openid: %s", err.Error())
+	}
+
 	for _, v := range s.Notify.AMQP {
 		if err := v.Validate(); err != nil {
 			return fmt.Errorf("amqp: %s", err.Error())
@@ -276,16 +291,22 @@ func (s *serverConfig) loadFromEnvs() {
 	}
 
 	if globalIsStorageClass {
-		s.SetStorageClass(globalStandardStorageClass, globalRRStorageClass)
+		s.SetStorageClass(globalStandardStorageClass, globalRRStorageClass, globalCustomStorageClasses)
 	}
 
 	if globalIsDiskCacheEnabled {
-		s.SetCacheConfig(globalCacheDrives, globalCacheExcludes, globalCacheExpiry, globalCacheMaxUse)
+		// Cache config set through the environment is returned
+		// by GetCacheConfig().
+		s.SetCacheConfig(s.GetCacheConfig())
 	}
 
 	if globalKMS != nil {
 		s.KMS = globalKMSConfig
 	}
+
+	if globalIsEnvOpenID {
+		s.OpenID = globalOpenIDConfig
+	}
 }
 
 // Returns the string describing a difference with the given
@@ -303,7 +324,7 @@ func (s *serverConfig) ConfigDiff(t *serverConfig) string {
 		return "Browser configuration differs"
 	case s.Domain != t.Domain:
 		return "Domain configuration differs"
-	case s.StorageClass != t.StorageClass:
+	case !reflect.DeepEqual(s.StorageClass, t.StorageClass):
 		return "StorageClass configuration differs"
 	case !reflect.DeepEqual(s.Cache, t.Cache):
 		return "Cache configuration differs"
@@ -329,6 +350,8 @@ func (s *serverConfig) ConfigDiff(t *serverConfig) string {
 		return "Logger configuration differs"
 	case !reflect.DeepEqual(s.KMS, t.KMS):
 		return "KMS configuration differs"
+	case !reflect.DeepEqual(s.OpenID, t.OpenID):
+		return "OpenID configuration differs"
 	case reflect.DeepEqual(s, t):
 		return ""
 	default:
@@ -359,6 +382,7 @@ func newServerConfig() *serverConfig {
 		},
 		KMS:    crypto.KMSConfig{},
 		Notify: notifier{},
+		OpenID: openIDConfig{},
 	}
 
 	// Make sure to initialize notification configs.
@@ -412,7 +436,7 @@ func (s *serverConfig) loadToCachedConfigs() {
 		globalDomainName = s.Domain
 	}
 	if !globalIsStorageClass {
-		globalStandardStorageClass, globalRRStorageClass = s.GetStorageClass()
+		globalStandardStorageClass, globalRRStorageClass, globalCustomStorageClasses = s.GetStorageClass()
 	}
 	if !globalIsDiskCacheEnabled {
 		cacheConf := s.GetCacheConfig()
@@ -420,6 +444,16 @@ func (s *serverConfig) loadToCachedConfigs() {
 		globalCacheExcludes = cacheConf.Exclude
 		globalCacheExpiry = cacheConf.Expiry
 		globalCacheMaxUse = cacheConf.MaxUse
+		globalCacheCommit = cacheConf.Commit
+		globalCacheRange = bool(cacheConf.Range)
+		globalCachePolicy = cacheConf.Policy
+		globalCacheWatermarkLow = cacheConf.WatermarkLow
+		globalCacheWatermarkHigh = cacheConf.WatermarkHigh
+		globalCacheIncludes = cacheConf.Include
+		globalCacheQuota = cacheConf.Quota
+		globalCacheMinSize = cacheConf.MinSize
+		globalCacheMaxSize = cacheConf.MaxSize
+		globalCacheAfterHits = cacheConf.AfterHits
 	}
 	if globalKMS == nil {
 		globalKMSConfig = s.KMS
@@ -428,6 +462,9 @@ func (s *serverConfig) loadToCachedConfigs() {
 			globalKMSKeyID = globalKMSConfig.Vault.Key.Name
 		}
 	}
+	if !globalIsEnvOpenID {
+		globalOpenIDConfig = s.OpenID
+	}
 }
 
 // newConfig - initialize a new server config, saves env parameters if
@@ -483,137 +520,118 @@ func loadConfig(objAPI ObjectLayer) error {
 	return nil
 }
 
-// getNotificationTargets - returns TargetList which contains enabled targets in serverConfig.
-// A new notification target is added like below
-// * Add a new target in pkg/event/target package.
-// * Add newly added target configuration to serverConfig.Notify.<TARGET_NAME>.
-// * Handle the configuration in this function to create/add into TargetList.
-func getNotificationTargets(config *serverConfig) *event.TargetList {
-	targetList := event.NewTargetList()
+// getAuthValidators - returns Validators which contains enabled
+// authentication providers in serverConfig.
+func getAuthValidators(config *serverConfig) *validator.Validators {
+	validators := validator.NewValidators()
+	if config == nil || config.OpenID.JWKS.IsEmpty() {
+		return validators
+	}
+
+	if err := validators.Add(validator.NewJWT(config.OpenID.JWKS)); err != nil {
+		logger.LogIf(context.Background(), err)
+	}
+
+	return validators
+}
+
+// getNotificationTargetArgs - returns arguments of enabled targets in
+// serverConfig indexed by their target ID.
+func getNotificationTargetArgs(config *serverConfig) map[event.TargetID]interface{} {
+	targetArgs := make(map[event.TargetID]interface{})
 	if config == nil {
-		return targetList
+		return targetArgs
 	}
 	for id, args := range config.Notify.AMQP {
 		if args.Enable {
-			newTarget, err := target.NewAMQPTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "amqp"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.Elasticsearch {
 		if args.Enable {
-			newTarget, err := target.NewElasticsearchTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-
-			}
+			targetArgs[event.TargetID{ID: id, Name: "elasticsearch"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.Kafka {
 		if args.Enable {
-			newTarget, err := target.NewKafkaTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "kafka"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.MQTT {
 		if args.Enable {
-			newTarget, err := target.NewMQTTTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "mqtt"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.MySQL {
 		if args.Enable {
-			newTarget, err := target.NewMySQLTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "mysql"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.NATS {
 		if args.Enable {
-			newTarget, err := target.NewNATSTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "nats"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.PostgreSQL {
 		if args.Enable {
-			newTarget, err := target.NewPostgreSQLTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "postgresql"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.Redis {
 		if args.Enable {
-			newTarget, err := target.NewRedisTarget(id, args)
-			if err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
-			if err = targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "redis"}] = args
 		}
 	}
-
 	for id, args := range config.Notify.Webhook {
 		if args.Enable {
-			newTarget := target.NewWebhookTarget(id, args)
-			if err := targetList.Add(newTarget); err != nil {
-				logger.LogIf(context.Background(), err)
-				continue
-			}
+			targetArgs[event.TargetID{ID: id, Name: "webhook"}] = args
+		}
+	}
+	return targetArgs
+}
+
+// newNotificationTarget - creates the notification target identified
+// by targetID from its configuration arguments.
+func newNotificationTarget(targetID event.TargetID, args interface{}) (event.Target, error) {
+	switch args := args.(type) {
+	case target.AMQPArgs:
+		return target.NewAMQPTarget(targetID.ID, args)
+	case target.ElasticsearchArgs:
+		return target.NewElasticsearchTarget(targetID.ID, args)
+	case target.KafkaArgs:
+		return target.NewKafkaTarget(targetID.ID, args)
+	case target.MQTTArgs:
+		return target.NewMQTTTarget(targetID.ID, args)
+	case target.MySQLArgs:
+		return target.NewMySQLTarget(targetID.ID, args)
+	case target.NATSArgs:
+		return target.NewNATSTarget(targetID.ID, args)
+	case target.PostgreSQLArgs:
+		return target.NewPostgreSQLTarget(targetID.ID, args)
+	case target.RedisArgs:
+		return target.NewRedisTarget(targetID.ID, args)
+	case target.WebhookArgs:
+		return target.NewWebhookTarget(targetID.ID, args), nil
+	}
+	return nil, fmt.Errorf("unsupported notification target %v", targetID)
+}
+
+// getNotificationTargets - returns TargetList which contains enabled targets in serverConfig.
+// A new notification target is added like below
+// * Add a new target in pkg/event/target package.
+// * Add newly added target configuration to serverConfig.Notify.<TARGET_NAME>.
+// * Handle the configuration in getNotificationTargetArgs and newNotificationTarget.
+func getNotificationTargets(config *serverConfig) *event.TargetList {
+	targetList := event.NewTargetList()
+	for targetID, args := range getNotificationTargetArgs(config) {
+		newTarget, err := newNotificationTarget(targetID, args)
+		if err != nil {
+			logger.LogIf(context.Background(), err)
+			continue
+		}
+		if err = targetList.Add(newTarget); err != nil {
+			logger.LogIf(context.Background(), err)
+			continue
 		}
 	}
 
@@ -259,8 +259,8 @@ func TestConfigDiff(t *testing.T) {
 		{&serverConfig{}, nil, "Given configuration is empty"},
 		// 2
 		{
-			&serverConfig{Credential: auth.Credentials{"u1", "p1"}},
-			&serverConfig{Credential: auth.Credentials{"u1", "p2"}},
+			&serverConfig{Credential: auth.Credentials{AccessKey: "u1", SecretKey: "p1"}},
+			&serverConfig{Credential: auth.Credentials{AccessKey: "u1", SecretKey: "p2"}},
 			"Credential configuration differs",
 		},
 		// 3
@@ -271,8 +271,8 @@ func TestConfigDiff(t *testing.T) {
 		{&serverConfig{Domain: "domain1"}, &serverConfig{Domain: "domain2"}, "Domain configuration differs"},
 		// 6
 		{
-			&serverConfig{StorageClass: storageClassConfig{storageClass{"1", 8}, storageClass{"2", 6}}},
-			&serverConfig{StorageClass: storageClassConfig{storageClass{"1", 8}, storageClass{"2", 4}}},
+			&serverConfig{StorageClass: storageClassConfig{storageClass{"1", 8}, storageClass{"2", 6}, nil}},
+			&serverConfig{StorageClass: storageClassConfig{storageClass{"1", 8}, storageClass{"2", 4}, nil}},
 			"StorageClass configuration differs",
 		},
 		// 7
@@ -0,0 +1,382 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/quick"
+)
+
+const (
+	// Previous configurations are saved under this prefix
+	// in minioMetaBucket, i.e. '.minio.sys/config/history'.
+	configHistoryPrefix = minioConfigPrefix + "/history"
+
+	// Number of previous configurations kept in history.
+	configHistoryMaxEntries = 10
+
+	// Format of the restore ID of history entries, sortable
+	// and valid as a file name on all platforms.
+	configHistoryTimeFormat = "20060102T150405.000000000Z"
+)
+
+// Config subsystems which can be addressed by the config KV admin API.
+var configSubSystems = set.CreateStringSet(
+	"region", "browser", "worm", "domain", "storageclass",
+	"cache", "kms", "notify", "logger", "openid",
+)
+
+// Config subsystems whose changes are applied without a server restart.
+var dynamicConfigSubSystems = set.CreateStringSet(
+	"region", "browser", "worm", "domain", "storageclass", "notify",
+)
+
+var (
+	errInvalidConfigKey     = errors.New("invalid config key")
+	errNoSuchConfigKey      = errors.New("config key not found")
+	errNoSuchConfigHistory  = errors.New("config history entry not found")
+	errInvalidConfigKVValue = errors.New("config value is not valid JSON")
+)
+
+// parseConfigKey - splits a dotted config key such as
+// 'notify.webhook.1' into its components, the first
+// component must be a known config subsystem.
+func parseConfigKey(key string) ([]string, error) {
+	components := strings.Split(key, ".")
+	for _, c := range components {
+		if c == "" {
+			return nil, errInvalidConfigKey
+		}
+	}
+	if !configSubSystems.Contains(components[0]) {
+		return nil, errInvalidConfigKey
+	}
+	return components, nil
+}
+
+// configToMap - converts config to its generic JSON representation.
+func configToMap(config *serverConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var m map[string]interface{}
+	if err = decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// configFromMap - converts the generic JSON representation back to
+// a server config, validating the result.
+func configFromMap(m map[string]interface{}) (*serverConfig, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &serverConfig{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	if err = quick.CheckData(config); err != nil {
+		return nil, err
+	}
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// lookupConfigKey - returns the JSON object holding the last component
+// of key, intermediate objects are created when create is set.
+func lookupConfigKey(m map[string]interface{}, components []string, create bool) (map[string]interface{}, error) {
+	for _, c := range components[:len(components)-1] {
+		v, ok := m[c]
+		if !ok || v == nil {
+			if !create {
+				return nil, errNoSuchConfigKey
+			}
+			v = map[string]interface{}{}
+			m[c] = v
+		}
+		child, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errInvalidConfigKey
+		}
+		m = child
+	}
+	return m, nil
+}
+
+// getConfigKV - returns the JSON value of key in config.
+func getConfigKV(config *serverConfig, key string) ([]byte, error) {
+	components, err := parseConfigKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := configToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := lookupConfigKey(m, components, false)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := parent[components[len(components)-1]]
+	if !ok {
+		return nil, errNoSuchConfigKey
+	}
+	return json.Marshal(v)
+}
+
+// setConfigKV - returns a copy of config with key set to the JSON value.
+func setConfigKV(config *serverConfig, key string, value []byte) (*serverConfig, error) {
+	components, err := parseConfigKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(value) {
+		return nil, errInvalidConfigKVValue
+	}
+	if err = quick.CheckDuplicateKeys(string(value)); err != nil {
+		return nil, err
+	}
+
+	m, err := configToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := lookupConfigKey(m, components, true)
+	if err != nil {
+		return nil, err
+	}
+	parent[components[len(components)-1]] = json.RawMessage(value)
+
+	return configFromMap(m)
+}
+
+// delConfigKV - returns a copy of config with key removed, removing a
+// whole subsystem resets it to its default value.
+func delConfigKV(config *serverConfig, key string) (*serverConfig, error) {
+	components, err := parseConfigKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := configToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := lookupConfigKey(m, components, false)
+	if err != nil {
+		return nil, err
+	}
+	last := components[len(components)-1]
+	if _, ok := parent[last]; !ok {
+		return nil, errNoSuchConfigKey
+	}
+
+	if len(components) == 1 {
+		defaults, err := configToMap(newServerConfig())
+		if err != nil {
+			return nil, err
+		}
+		m[last] = defaults[last]
+	} else {
+		delete(parent, last)
+	}
+
+	return configFromMap(m)
+}
+
+// changedConfigSubSystems - returns the config subsystems which
+// differ between old and config.
+func changedConfigSubSystems(old, config *serverConfig) (set.StringSet, error) {
+	oldMap, err := configToMap(old)
+	if err != nil {
+		return nil, err
+	}
+	m, err := configToMap(config)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := set.NewStringSet()
+	for subSys := range configSubSystems {
+		if !reflect.DeepEqual(oldMap[subSys], m[subSys]) {
+			changed.Add(subSys)
+		}
+	}
+	return changed, nil
+}
+
+// configHistoryPath - returns the path of the history entry restoreID.
+func configHistoryPath(restoreID string) string {
+	return path.Join(configHistoryPrefix, restoreID+".json")
+}
+
+// saveConfigHistory - saves config as a new history entry, removing
+// the oldest entries beyond the limit.
+func saveConfigHistory(ctx context.Context, objAPI ObjectLayer, config *serverConfig) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	restoreID := UTCNow().Format(configHistoryTimeFormat)
+	if err = saveConfig(objAPI, configHistoryPath(restoreID), data); err != nil {
+		return err
+	}
+
+	entries, err := listConfigHistory(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	for len(entries) > configHistoryMaxEntries {
+		if err = objAPI.DeleteObject(ctx, minioMetaBucket, configHistoryPath(entries[0].RestoreID)); err != nil {
+			return err
+		}
+		entries = entries[1:]
+	}
+	return nil
+}
+
+// listConfigHistory - returns all history entries, oldest first.
+func listConfigHistory(ctx context.Context, objAPI ObjectLayer) ([]madmin.ConfigHistoryEntry, error) {
+	entries := []madmin.ConfigHistoryEntry{}
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, minioMetaBucket, configHistoryPrefix+"/", marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, objInfo := range result.Objects {
+			restoreID := strings.TrimSuffix(path.Base(objInfo.Name), ".json")
+			createTime, err := time.Parse(configHistoryTimeFormat, restoreID)
+			if err != nil {
+				// Ignore unrelated files.
+				continue
+			}
+			entries = append(entries, madmin.ConfigHistoryEntry{
+				RestoreID:  restoreID,
+				CreateTime: createTime,
+			})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreateTime.Before(entries[j].CreateTime)
+	})
+	return entries, nil
+}
+
+// readConfigHistory - returns the configuration saved as restoreID.
+func readConfigHistory(ctx context.Context, objAPI ObjectLayer, restoreID string) (*serverConfig, error) {
+	if _, err := time.Parse(configHistoryTimeFormat, restoreID); err != nil {
+		return nil, errNoSuchConfigHistory
+	}
+
+	buffer, err := readConfig(ctx, objAPI, configHistoryPath(restoreID))
+	if err != nil {
+		if err == errConfigNotFound {
+			return nil, errNoSuchConfigHistory
+		}
+		return nil, err
+	}
+
+	config := &serverConfig{}
+	if err = json.Unmarshal(buffer.Bytes(), config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// newConfigTransactionLock - returns the lock serializing changes
+// made to config.json through the admin API across all servers.
+func newConfigTransactionLock() RWLocker {
+	return globalNSMutex.NewNSLock(minioMetaBucket, path.Join(minioConfigPrefix, minioConfigFile)+".transaction")
+}
+
+// commitConfig - saves the current configuration to history, persists
+// config and applies it on all servers. Returns true if all changes
+// were applied without the need of a server restart.
+func commitConfig(ctx context.Context, objAPI ObjectLayer, old, config *serverConfig) (bool, error) {
+	changed, err := changedConfigSubSystems(old, config)
+	if err != nil {
+		return false, err
+	}
+	if changed.IsEmpty() {
+		return true, nil
+	}
+
+	if err = saveConfigHistory(ctx, objAPI, old); err != nil {
+		return false, err
+	}
+
+	if err = saveServerConfig(objAPI, config); err != nil {
+		return false, err
+	}
+
+	// Apply the new configuration locally, values set
+	// through the environment keep precedence.
+	srvCfg := *config
+	srvCfg.loadFromEnvs()
+	srvCfg.loadToCachedConfigs()
+
+	globalServerConfigMu.Lock()
+	globalServerConfig = &srvCfg
+	globalServerConfigMu.Unlock()
+
+	if globalNotificationSys != nil {
+		globalNotificationSys.LoadConfig(ctx)
+		if changed.Contains("notify") {
+			globalNotificationSys.UpdateTargets(&srvCfg)
+			globalNotificationSys.ReloadNotificationTargets(ctx)
+		}
+	}
+
+	applied := changed.Difference(dynamicConfigSubSystems).IsEmpty()
+	if !applied {
+		logger.Info("Configuration of %s changed, restart the server to apply", strings.Join(changed.ToSlice(), ", "))
+	}
+	return applied, nil
+}
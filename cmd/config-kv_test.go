@@ -0,0 +1,230 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+// Tests getting, setting and deleting config keys.
+func TestConfigKV(t *testing.T) {
+	config := newServerConfig()
+
+	if _, err := getConfigKV(config, "credential"); err != errInvalidConfigKey {
+		t.Fatalf("Expected %v, got %v", errInvalidConfigKey, err)
+	}
+	if _, err := getConfigKV(config, "notify..webhook"); err != errInvalidConfigKey {
+		t.Fatalf("Expected %v, got %v", errInvalidConfigKey, err)
+	}
+	if _, err := getConfigKV(config, "notify.webhook.2"); err != errNoSuchConfigKey {
+		t.Fatalf("Expected %v, got %v", errNoSuchConfigKey, err)
+	}
+
+	value, err := getConfigKV(config, "region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != `"`+globalMinioDefaultRegion+`"` {
+		t.Fatalf("Unexpected region %s", value)
+	}
+
+	// Set a top level value.
+	newConfig, err := setConfigKV(config, "region", []byte(`"eu-west-1"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newConfig.Region != "eu-west-1" || config.Region != globalMinioDefaultRegion {
+		t.Fatalf("Unexpected regions %s and %s", newConfig.Region, config.Region)
+	}
+	if newConfig.Credential != config.Credential {
+		t.Fatal("Expected credentials to be preserved")
+	}
+
+	// Add a new notification target.
+	newConfig, err = setConfigKV(newConfig, "notify.webhook.2", []byte(`{"enable":true,"endpoint":"http://localhost:8080/events"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook, ok := newConfig.Notify.Webhook["2"]
+	if !ok || !webhook.Enable || webhook.Endpoint.String() != "http://localhost:8080/events" {
+		t.Fatalf("Unexpected webhook target %#v", webhook)
+	}
+
+	// Invalid values are rejected.
+	testCases := []struct {
+		key   string
+		value string
+	}{
+		{"region", `"eu-west-1`},
+		{"region", `1`},
+		{"notify.webhook.3", `{"enable":true,"endpoint":""}`},
+		{"notify.webhook.3", `{"enable":true,"enable":false}`},
+		{"region.name", `"eu-west-1"`},
+	}
+	for i, testCase := range testCases {
+		if _, err = setConfigKV(newConfig, testCase.key, []byte(testCase.value)); err == nil {
+			t.Errorf("Test %d: expected setting %s to %s to fail", i+1, testCase.key, testCase.value)
+		}
+	}
+
+	// Delete a notification target.
+	newConfig, err = delConfigKV(newConfig, "notify.webhook.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = newConfig.Notify.Webhook["2"]; ok {
+		t.Fatal("Expected webhook target to be removed")
+	}
+	if _, err = delConfigKV(newConfig, "notify.webhook.2"); err != errNoSuchConfigKey {
+		t.Fatalf("Expected %v, got %v", errNoSuchConfigKey, err)
+	}
+
+	// Deleting a subsystem resets it to defaults.
+	newConfig, err = delConfigKV(newConfig, "region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newConfig.Region != globalMinioDefaultRegion {
+		t.Fatalf("Expected region %s, got %s", globalMinioDefaultRegion, newConfig.Region)
+	}
+
+	changed, err := changedConfigSubSystems(config, newConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed.IsEmpty() {
+		t.Fatalf("Expected no changes, got %v", changed)
+	}
+}
+
+// Tests saving, listing and restoring config history.
+func TestConfigHistory(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+	defer resetTestGlobals()
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	old, err := readServerConfig(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := setConfigKV(old, "region", []byte(`"eu-west-1"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied, err := commitConfig(ctx, objLayer, old, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied {
+		t.Fatal("Expected region change to be applied")
+	}
+	if globalServerRegion != "eu-west-1" || globalServerConfig.GetRegion() != "eu-west-1" {
+		t.Fatalf("Expected region to be applied, got %s", globalServerRegion)
+	}
+
+	saved, err := readServerConfig(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Region != "eu-west-1" {
+		t.Fatalf("Expected saved region eu-west-1, got %s", saved.Region)
+	}
+
+	entries, err := listConfigHistory(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 history entry, got %d", len(entries))
+	}
+
+	restored, err := readConfigHistory(ctx, objLayer, entries[0].RestoreID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Region != old.Region {
+		t.Fatalf("Expected restored region %s, got %s", old.Region, restored.Region)
+	}
+	if _, err = readConfigHistory(ctx, objLayer, "../config"); err != errNoSuchConfigHistory {
+		t.Fatalf("Expected %v, got %v", errNoSuchConfigHistory, err)
+	}
+
+	// Changes to static subsystems are not applied.
+	config, err = setConfigKV(saved, "logger.console.enabled", []byte(`false`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied, err = commitConfig(ctx, objLayer, saved, config); err != nil {
+		t.Fatal(err)
+	}
+	if applied {
+		t.Fatal("Expected logger change to require a restart")
+	}
+
+	// Notification targets are reconfigured at runtime.
+	saved = config
+	config, err = setConfigKV(saved, "notify.webhook.1", []byte(`{"enable":true,"endpoint":"http://localhost:8080/events"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalNotificationSys = NewNotificationSys(saved, EndpointList{})
+	if applied, err = commitConfig(ctx, objLayer, saved, config); err != nil {
+		t.Fatal(err)
+	}
+	if !applied {
+		t.Fatal("Expected notify change to be applied")
+	}
+	if !globalNotificationSys.targetList.Exists(event.TargetID{ID: "1", Name: "webhook"}) {
+		t.Fatal("Expected webhook target to be started")
+	}
+
+	// Committing an unchanged configuration is a no-op.
+	if applied, err = commitConfig(ctx, objLayer, config, config); err != nil || !applied {
+		t.Fatalf("Expected unchanged config to be applied, got %v, %v", applied, err)
+	}
+
+	// Only the most recent entries are kept.
+	for i := 0; i < configHistoryMaxEntries; i++ {
+		if err = saveConfigHistory(ctx, objLayer, config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entries, err = listConfigHistory(ctx, objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != configHistoryMaxEntries {
+		t.Fatalf("Expected %d history entries, got %d", configHistoryMaxEntries, len(entries))
+	}
+	for i := 1; i < len(entries); i++ {
+		if !entries[i-1].CreateTime.Before(entries[i].CreateTime) {
+			t.Fatalf("Expected entries to be sorted, got %v", entries)
+		}
+	}
+}
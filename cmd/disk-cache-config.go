@@ -17,20 +17,63 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"path/filepath"
 	"strings"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio/pkg/ellipses"
 )
 
+// Cache commit modes.
+const (
+	// Objects are uploaded to the backend and the cache simultaneously.
+	cacheCommitWriteThrough = "writethrough"
+	// Objects are committed to the cache and uploaded to the backend
+	// asynchronously.
+	cacheCommitWriteBack = "writeback"
+)
+
+// Cache eviction policies.
+const (
+	// Least recently used entries are evicted first.
+	cachePolicyLRU = "lru"
+	// Least frequently used entries are evicted first.
+	cachePolicyLFU = "lfu"
+)
+
+// Default cache watermarks, in percent of the max usable disk space.
+const (
+	cacheDefaultWatermarkLow  = 80
+	cacheDefaultWatermarkHigh = 100
+)
+
 // CacheConfig represents cache config settings
 type CacheConfig struct {
 	Drives  []string `json:"drives"`
 	Expiry  int      `json:"expiry"`
 	MaxUse  int      `json:"maxuse"`
 	Exclude []string `json:"exclude"`
+	Commit  string   `json:"commit,omitempty"`
+	Range   BoolFlag `json:"range,omitempty"`
+	Policy  string   `json:"policy,omitempty"`
+	// Watermarks are percentages of MaxUse, eviction starts when the
+	// cache usage exceeds WatermarkHigh and stops below WatermarkLow.
+	WatermarkLow  int `json:"watermark_low,omitempty"`
+	WatermarkHigh int `json:"watermark_high,omitempty"`
+	// Only objects matching one of the include patterns are cached,
+	// all objects are cached if empty. Exclude patterns take precedence.
+	Include []string `json:"include,omitempty"`
+	// Maximum size of the cache each bucket may use, e.g. "10GiB".
+	Quota map[string]string `json:"quota,omitempty"`
+	// Objects smaller than MinSize or larger than MaxSize are not cached.
+	MinSize string `json:"min_size,omitempty"`
+	MaxSize string `json:"max_size,omitempty"`
+	// Objects are cached only after they were read from the backend
+	// AfterHits times.
+	AfterHits int `json:"after_hits,omitempty"`
 }
 
 // UnmarshalJSON - implements JSON unmarshal interface for unmarshalling
@@ -60,6 +103,68 @@ func (cfg *CacheConfig) UnmarshalJSON(data []byte) (err error) {
 	if _, err = parseCacheExcludes(_cfg.Exclude); err != nil {
 		return err
 	}
+	if _, err = parseCacheCommit(_cfg.Commit); err != nil {
+		return err
+	}
+	if _, err = parseCachePolicy(_cfg.Policy); err != nil {
+		return err
+	}
+	if err = validateCacheWatermarks(_cfg.WatermarkLow, _cfg.WatermarkHigh); err != nil {
+		return err
+	}
+	if _, err = parseCacheIncludes(_cfg.Include); err != nil {
+		return err
+	}
+	if _, err = parseCacheQuotas(_cfg.Quota); err != nil {
+		return err
+	}
+	if _, _, err = parseCacheSizes(_cfg.MinSize, _cfg.MaxSize); err != nil {
+		return err
+	}
+	if _cfg.AfterHits < 0 {
+		return errors.New("config after hits value should not be negative")
+	}
+	return nil
+}
+
+// Parses given cacheCommitEnv and returns the cache commit mode.
+func parseCacheCommit(commit string) (string, error) {
+	switch commit {
+	case "", cacheCommitWriteThrough, cacheCommitWriteBack:
+		return commit, nil
+	}
+	return "", uiErrInvalidCacheCommitValue(nil).Msg("unknown cache commit mode (%s)", commit)
+}
+
+// Parses given cachePolicyEnv and returns the cache eviction policy.
+func parseCachePolicy(policy string) (string, error) {
+	switch policy {
+	case "", cachePolicyLRU, cachePolicyLFU:
+		return policy, nil
+	}
+	return "", uiErrInvalidCachePolicyValue(nil).Msg("unknown cache eviction policy (%s)", policy)
+}
+
+// Returns the cache watermarks with defaults applied for unset values.
+func getCacheWatermarks(low, high int) (int, int) {
+	if low == 0 {
+		low = cacheDefaultWatermarkLow
+	}
+	if high == 0 {
+		high = cacheDefaultWatermarkHigh
+	}
+	return low, high
+}
+
+// Validates the low and high cache watermarks, unset values take
+// their defaults.
+func validateCacheWatermarks(low, high int) error {
+	if low < 0 || low > 100 || high < 0 || high > 100 {
+		return uiErrInvalidCacheWatermarkValue(nil).Msg("cache watermarks should be between 0-100")
+	}
+	if low, high = getCacheWatermarks(low, high); low >= high {
+		return uiErrInvalidCacheWatermarkValue(nil).Msg("cache low watermark (%d) should be less than high watermark (%d)", low, high)
+	}
 	return nil
 }
 
@@ -115,3 +220,89 @@ func parseCacheExcludes(excludes []string) ([]string, error) {
 	}
 	return excludes, nil
 }
+
+// Parses given cacheIncludesEnv and returns a list of cache include patterns.
+func parseCacheIncludes(includes []string) ([]string, error) {
+	for _, e := range includes {
+		if len(e) == 0 {
+			return nil, uiErrInvalidCacheIncludesValue(nil).Msg("cache include path (%s) cannot be empty", e)
+		}
+		if hasPrefix(e, slashSeparator) {
+			return nil, uiErrInvalidCacheIncludesValue(nil).Msg("cache include pattern (%s) cannot start with / as prefix", e)
+		}
+	}
+	return includes, nil
+}
+
+// Parses given cacheQuotaEnv entries of the form 'bucket=size' and
+// returns the cache quota of each bucket.
+func parseCacheQuotaEntries(entries []string) (map[string]string, error) {
+	quotas := make(map[string]string, len(entries))
+	for _, e := range entries {
+		tokens := strings.SplitN(e, "=", 2)
+		if len(tokens) != 2 {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("cache quota (%s) should be of the form bucket=size", e)
+		}
+		quotas[tokens[0]] = tokens[1]
+	}
+	if _, err := parseCacheQuotas(quotas); err != nil {
+		return nil, err
+	}
+	return quotas, nil
+}
+
+// Parses the cache quota of each bucket into bytes.
+func parseCacheQuotas(quotas map[string]string) (map[string]int64, error) {
+	quotaBytes := make(map[string]int64, len(quotas))
+	for bucket, quota := range quotas {
+		if !IsValidBucketName(bucket) {
+			return nil, uiErrInvalidCacheQuotaValue(nil).Msg("invalid bucket name (%s) in cache quota", bucket)
+		}
+		n, err := humanize.ParseBytes(quota)
+		if err != nil {
+			return nil, uiErrInvalidCacheQuotaValue(err).Msg("invalid cache quota (%s) of bucket %s", quota, bucket)
+		}
+		quotaBytes[bucket] = int64(n)
+	}
+	return quotaBytes, nil
+}
+
+// Parses the min and max size of cached objects into bytes, unset
+// values are returned as 0.
+func parseCacheSizes(minSize, maxSize string) (minBytes, maxBytes int64, err error) {
+	if minSize != "" {
+		n, perr := humanize.ParseBytes(minSize)
+		if perr != nil {
+			return 0, 0, uiErrInvalidCacheSizeValue(perr).Msg("invalid cache min size (%s)", minSize)
+		}
+		minBytes = int64(n)
+	}
+	if maxSize != "" {
+		n, perr := humanize.ParseBytes(maxSize)
+		if perr != nil {
+			return 0, 0, uiErrInvalidCacheSizeValue(perr).Msg("invalid cache max size (%s)", maxSize)
+		}
+		maxBytes = int64(n)
+	}
+	if maxBytes > 0 && minBytes > maxBytes {
+		return 0, 0, uiErrInvalidCacheSizeValue(nil).Msg("cache min size (%s) should not be larger than max size (%s)", minSize, maxSize)
+	}
+	return minBytes, maxBytes, nil
+}
+
+// Parses the cache master key of the form '<key-id>:<hex-encoded 256 bit key>'.
+func parseCacheEncryptionKey(masterKey string) (keyID string, key [32]byte, err error) {
+	tokens := strings.SplitN(masterKey, ":", 2)
+	if len(tokens) != 2 || tokens[0] == "" {
+		return "", key, uiErrInvalidCacheEncryptionKey(nil).Msg("cache master key should be of the form key-id:hex-key")
+	}
+	b, err := hex.DecodeString(tokens[1])
+	if err != nil {
+		return "", key, uiErrInvalidCacheEncryptionKey(err).Msg("cache master key (%s) is not hex encoded", tokens[0])
+	}
+	if len(b) != len(key) {
+		return "", key, uiErrInvalidCacheEncryptionKey(nil).Msg("cache master key (%s) should be 256 bits long", tokens[0])
+	}
+	copy(key[:], b)
+	return tokens[0], key, nil
+}
@@ -112,3 +112,199 @@ func TestParseCacheExclude(t *testing.T) {
 		}
 	}
 }
+
+// Tests cache commit mode parsing.
+func TestParseCacheCommit(t *testing.T) {
+	testCases := []struct {
+		commitStr      string
+		expectedCommit string
+		success        bool
+	}{
+		{"", "", true},
+		{"writethrough", cacheCommitWriteThrough, true},
+		{"writeback", cacheCommitWriteBack, true},
+		{"WriteBack", "", false},
+		{"writearound", "", false},
+	}
+
+	for i, testCase := range testCases {
+		commit, err := parseCacheCommit(testCase.commitStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && commit != testCase.expectedCommit {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expectedCommit, commit)
+		}
+	}
+}
+
+// Tests cache eviction policy parsing.
+func TestParseCachePolicy(t *testing.T) {
+	testCases := []struct {
+		policyStr      string
+		expectedPolicy string
+		success        bool
+	}{
+		{"", "", true},
+		{"lru", cachePolicyLRU, true},
+		{"lfu", cachePolicyLFU, true},
+		{"fifo", "", false},
+	}
+
+	for i, testCase := range testCases {
+		policy, err := parseCachePolicy(testCase.policyStr)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && policy != testCase.expectedPolicy {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.expectedPolicy, policy)
+		}
+	}
+}
+
+// Tests cache watermarks validation.
+func TestValidateCacheWatermarks(t *testing.T) {
+	testCases := []struct {
+		low, high int
+		success   bool
+	}{
+		{0, 0, true},
+		{70, 90, true},
+		{50, 0, true},
+		{0, 90, true},
+		{90, 70, false},
+		{80, 80, false},
+		{0, 70, false},
+		{-1, 90, false},
+		{70, 101, false},
+	}
+
+	for i, testCase := range testCases {
+		err := validateCacheWatermarks(testCase.low, testCase.high)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+	}
+}
+
+// Tests cache quota parsing.
+func TestParseCacheQuotaEntries(t *testing.T) {
+	testCases := []struct {
+		quotaStr       string
+		expectedQuotas map[string]int64
+		success        bool
+	}{
+		{"images=10KiB", map[string]int64{"images": 10 << 10}, true},
+		{"images=10KiB;videos=1MB", map[string]int64{"images": 10 << 10, "videos": 1000000}, true},
+		{"images", nil, false},
+		{"images=ten", nil, false},
+		{"Images_=10KiB", nil, false},
+	}
+
+	for i, testCase := range testCases {
+		quotas, err := parseCacheQuotaEntries(strings.Split(testCase.quotaStr, cacheEnvDelimiter))
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil {
+			quotaBytes, err := parseCacheQuotas(quotas)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(quotaBytes, testCase.expectedQuotas) {
+				t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expectedQuotas, quotaBytes)
+			}
+		}
+	}
+}
+
+// Tests cached object size range parsing.
+func TestParseCacheSizes(t *testing.T) {
+	testCases := []struct {
+		minSize, maxSize string
+		expectedMin      int64
+		expectedMax      int64
+		success          bool
+	}{
+		{"", "", 0, 0, true},
+		{"1KiB", "", 1 << 10, 0, true},
+		{"", "1MiB", 0, 1 << 20, true},
+		{"1KiB", "1MiB", 1 << 10, 1 << 20, true},
+		{"1MiB", "1KiB", 0, 0, false},
+		{"one", "", 0, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		minSize, maxSize, err := parseCacheSizes(testCase.minSize, testCase.maxSize)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err == nil && (minSize != testCase.expectedMin || maxSize != testCase.expectedMax) {
+			t.Errorf("Test %d: Expected %d-%d, got %d-%d", i+1, testCase.expectedMin, testCase.expectedMax, minSize, maxSize)
+		}
+	}
+}
+
+// Tests cache include parsing.
+func TestParseCacheInclude(t *testing.T) {
+	testCases := []struct {
+		includeStr string
+		success    bool
+	}{
+		{"bucket1/*;*.png", true},
+		{"/bucket1/*", false},
+		{"bucket1;;*.png", false},
+	}
+
+	for i, testCase := range testCases {
+		_, err := parseCacheIncludes(strings.Split(testCase.includeStr, cacheEnvDelimiter))
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+	}
+}
+
+// Tests cache master key parsing.
+func TestParseCacheEncryptionKey(t *testing.T) {
+	testCases := []struct {
+		masterKey     string
+		expectedKeyID string
+		success       bool
+	}{
+		{"cache-key:6368616e676520746869732070617373776f726420746f206120736563726574", "cache-key", true},
+		{"6368616e676520746869732070617373776f726420746f206120736563726574", "", false},
+		{":6368616e676520746869732070617373776f726420746f206120736563726574", "", false},
+		{"cache-key:6368616e6765", "", false},
+		{"cache-key:not-hex", "", false},
+	}
+	for i, testCase := range testCases {
+		keyID, _, err := parseCacheEncryptionKey(testCase.masterKey)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if keyID != testCase.expectedKeyID {
+			t.Errorf("Test %d: Expected key-id %s, got %s", i+1, testCase.expectedKeyID, keyID)
+		}
+	}
+}
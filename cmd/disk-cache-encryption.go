@@ -0,0 +1,193 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/ioutil"
+	"github.com/minio/sio"
+)
+
+// Encryption of cache drives covers the cached object data and ranges
+// only. The metadata of a cache entry (fs.json: user metadata,
+// content-type, ETag and the sealed key) and the access statistics in
+// cache-index.json are stored in plaintext, as are bucket and object
+// names which are paths on the cache drive.
+const (
+	// metadata key of the key-id of the cache master key which
+	// sealed the key of an encrypted cache entry.
+	cacheEncryptionKeyID = ReservedMetadataPrefix + "Cache-Encryption-Key-Id"
+
+	// metadata key of the sealed key of an encrypted cache entry.
+	cacheEncryptionSealedKey = ReservedMetadataPrefix + "Cache-Encryption-Sealed-Key"
+)
+
+// Generates the key encrypting the cached data of object and stores
+// it, sealed with the cache master key, in metadata.
+func (cfs *cacheFSObjects) generateObjectKey(bucket, object string, metadata map[string]string) (key crypto.ObjectKey, err error) {
+	extKey, sealedKey, err := cfs.kms.GenerateKey(cfs.kmsKeyID, crypto.Context{bucket: path.Join(bucket, object)})
+	if err != nil {
+		return key, err
+	}
+	metadata[cacheEncryptionKeyID] = cfs.kmsKeyID
+	metadata[cacheEncryptionSealedKey] = base64.StdEncoding.EncodeToString(sealedKey)
+	return crypto.ObjectKey(extKey), nil
+}
+
+// Unseals the key encrypting the cached data of object from metadata.
+func (cfs *cacheFSObjects) unsealObjectKey(bucket, object string, metadata map[string]string) (key crypto.ObjectKey, err error) {
+	sealedKey, err := base64.StdEncoding.DecodeString(metadata[cacheEncryptionSealedKey])
+	if err != nil {
+		return key, errObjectTampered
+	}
+	extKey, err := cfs.kms.UnsealKey(metadata[cacheEncryptionKeyID], sealedKey, crypto.Context{bucket: path.Join(bucket, object)})
+	if err != nil {
+		return key, errObjectTampered
+	}
+	return crypto.ObjectKey(extKey), nil
+}
+
+// Returns true if metadata describes cached data which is stored in
+// the encryption mode of the cache drive. Entries cached before the
+// encryption mode was changed are treated as not cached.
+func (cfs *cacheFSObjects) isEncryptionMode(metadata map[string]string) bool {
+	_, encrypted := metadata[cacheEncryptionSealedKey]
+	return encrypted == (cfs.kms != nil)
+}
+
+// Converts the info of an object as stored on the cache drive into
+// the info of the cached object.
+func (cfs *cacheFSObjects) toCacheObjectInfo(objInfo ObjectInfo) (ObjectInfo, error) {
+	if objInfo.IsDir {
+		return objInfo, nil
+	}
+	if !cfs.isEncryptionMode(objInfo.UserDefined) {
+		return ObjectInfo{}, ObjectNotFound{Bucket: objInfo.Bucket, Object: objInfo.Name}
+	}
+	if cfs.kms == nil {
+		return objInfo, nil
+	}
+	size, err := sio.DecryptedSize(uint64(objInfo.Size))
+	if err != nil {
+		return ObjectInfo{}, errObjectTampered
+	}
+	objInfo.Size = int64(size)
+	objInfo.UserDefined = cleanMetadataKeys(objInfo.UserDefined, cacheEncryptionKeyID, cacheEncryptionSealedKey)
+	return objInfo, nil
+}
+
+// GetObjectInfo - returns the info of the cached object.
+func (cfs *cacheFSObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	objInfo, err := cfs.FSObjects.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return objInfo, err
+	}
+	return cfs.toCacheObjectInfo(objInfo)
+}
+
+// Returns the info of the cached object and the key its data is
+// encrypted with, the key is zero if the cache drive is not encrypted.
+func (cfs *cacheFSObjects) getObjectKey(ctx context.Context, bucket, object string) (objInfo ObjectInfo, key crypto.ObjectKey, err error) {
+	if objInfo, err = cfs.FSObjects.GetObjectInfo(ctx, bucket, object); err != nil {
+		return objInfo, key, err
+	}
+	if cfs.kms != nil && !objInfo.IsDir && cfs.isEncryptionMode(objInfo.UserDefined) {
+		if key, err = cfs.unsealObjectKey(bucket, object, objInfo.UserDefined); err != nil {
+			return ObjectInfo{}, key, toObjectErr(err, bucket, object)
+		}
+	}
+	objInfo, err = cfs.toCacheObjectInfo(objInfo)
+	return objInfo, key, err
+}
+
+// GetObjectNInfo - returns the info and a reader of the cached object.
+func (cfs *cacheFSObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (ObjectInfo, io.ReadCloser, error) {
+	if cfs.kms == nil {
+		objInfo, reader, err := cfs.FSObjects.GetObjectNInfo(ctx, bucket, object, rs)
+		if err != nil {
+			return objInfo, reader, err
+		}
+		if objInfo, err = cfs.toCacheObjectInfo(objInfo); err != nil {
+			reader.Close()
+			return objInfo, nil, err
+		}
+		return objInfo, reader, nil
+	}
+
+	objInfo, key, err := cfs.getObjectKey(ctx, bucket, object)
+	if err != nil {
+		return objInfo, nil, err
+	}
+	offset, length := int64(0), objInfo.Size
+	if rs != nil {
+		offset, length = rs.GetOffsetLength(objInfo.Size)
+	}
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(cfs.getDecrypted(ctx, bucket, object, offset, length, pipeWriter, objInfo.ETag, objInfo, key))
+	}()
+	return objInfo, NewGetObjectReader(pipeReader, nil, func() { pipeReader.Close() }), nil
+}
+
+// Writes length bytes at offset of the encrypted cached object to writer.
+func (cfs *cacheFSObjects) getDecrypted(ctx context.Context, bucket, object string, offset, length int64, writer io.Writer, etag string, objInfo ObjectInfo, key crypto.ObjectKey) error {
+	if offset < 0 || length < 0 || offset+length > objInfo.Size {
+		return InvalidRange{offset, length, objInfo.Size}
+	}
+	if length == 0 {
+		return nil
+	}
+	seqNumber, encOffset, encLength := getEncryptedSinglePartOffsetLength(offset, length, objInfo)
+	// The writer must not be closed by the decrypting writer.
+	w := ioutil.LimitedWriter(ioutil.NopCloser(writer), offset%sseDAREPackageBlockSize, length)
+	decWriter, err := sio.DecryptWriter(w, sio.Config{Key: key[:], SequenceNumber: seqNumber})
+	if err != nil {
+		return err
+	}
+	if err = cfs.GetObject(ctx, bucket, object, encOffset, encLength, decWriter, etag); err != nil {
+		return err
+	}
+	if err = decWriter.Close(); err != nil {
+		return toObjectErr(errObjectTampered, bucket, object)
+	}
+	return nil
+}
+
+// Returns a reader of length bytes at offset of the encrypted range
+// of rangeSize bytes read from file.
+func newCacheRangeReader(file io.ReadCloser, key crypto.ObjectKey, offset, length, rangeSize int64) (io.ReadCloser, error) {
+	seqNumber, _, encLength := getEncryptedSinglePartOffsetLength(offset, length, ObjectInfo{Size: rangeSize})
+	decReader, err := sio.DecryptReader(io.LimitReader(file, encLength), sio.Config{Key: key[:], SequenceNumber: seqNumber})
+	if err != nil {
+		return nil, err
+	}
+	reader := io.LimitReader(ioutil.NewSkipReader(decReader, offset%sseDAREPackageBlockSize), length)
+	return NewGetObjectReader(reader, nil, func() { file.Close() }), nil
+}
+
+// Returns true if the SSE object described by metadata may be served
+// by the cache. SSE-C objects are never cached, single-part SSE-S3
+// objects are cached on encrypted cache drives.
+func isCacheableSSE(header http.Header, metadata map[string]string) bool {
+	return globalCacheKMS != nil && !crypto.SSEC.IsRequested(header) && !crypto.IsMultiPart(metadata)
+}
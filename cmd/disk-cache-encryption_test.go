@@ -0,0 +1,253 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/pkg/hash"
+)
+
+// Test caching objects and ranges on encrypted cache drives.
+func TestCacheEncryption(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{Range: true}, &backendDown)
+	defer removeRoots(dirs)
+
+	dcache := c.cache.cfs[0]
+	kms := crypto.NewKMS([32]byte{})
+	dcache.kms, dcache.kmsKeyID = kms, "cache-key"
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("cache encryption ")
+	content := bytes.Repeat(plaintext, 10000)
+	size := int64(len(content))
+	for _, objectName := range []string{"object", "ranged"} {
+		hashReader, err := hash.NewReader(bytes.NewReader(content), size, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objectName == "ranged" {
+			_, err = backend.PutObject(ctx, bucketName, objectName, hashReader, nil)
+		} else {
+			_, err = c.PutObject(ctx, bucketName, objectName, hashReader, map[string]string{"content-type": "text/plain"})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Cached data is not stored in plaintext.
+	objectPath := pathJoin(dcache.fsPath, bucketName, "object")
+	data, err := ioutil.ReadFile(objectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, plaintext) {
+		t.Fatal("Expected cached object to be encrypted")
+	}
+	objInfo, err := dcache.GetObjectInfo(ctx, bucketName, "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != size || objInfo.ContentType != "text/plain" {
+		t.Fatalf("Unexpected cached object size %d, content-type %s", objInfo.Size, objInfo.ContentType)
+	}
+	if _, ok := objInfo.UserDefined[cacheEncryptionSealedKey]; ok {
+		t.Fatal("Expected cache encryption metadata not to be returned")
+	}
+
+	// Cached ranges are encrypted and share the key of the object version.
+	writer := bytes.NewBuffer(nil)
+	for _, r := range [][2]int64{{70000, 80000}, {0, 10000}} {
+		writer.Reset()
+		if err = c.GetObject(ctx, bucketName, "ranged", r[0], r[1], writer, ""); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(writer.Bytes(), content[r[0]:r[0]+r[1]]) {
+			t.Fatalf("Unexpected range %d-%d", r[0], r[0]+r[1]-1)
+		}
+	}
+	entries, err := readDir(dcache.rangeDir(bucketName, "ranged"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 2 cached ranges, got %v", entries)
+	}
+	for _, entry := range entries {
+		if data, err = ioutil.ReadFile(pathJoin(dcache.rangeDir(bucketName, "ranged"), entry)); err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, plaintext) {
+			t.Fatalf("Expected cached range %s to be encrypted", entry)
+		}
+	}
+
+	// Reads are served from the encrypted cache when the backend is down.
+	atomic.StoreInt32(&backendDown, 1)
+	testCases := []struct {
+		objectName     string
+		offset, length int64
+	}{
+		{"object", 0, size},
+		{"object", 70000, 100000},
+		{"object", 65536, 1},
+		{"object", size - 1, 1},
+		{"ranged", 75000, 65536},
+		{"ranged", 100, 200},
+	}
+	for i, testCase := range testCases {
+		writer.Reset()
+		if err = c.GetObject(ctx, bucketName, testCase.objectName, testCase.offset, testCase.length, writer, ""); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(writer.Bytes(), content[testCase.offset:testCase.offset+testCase.length]) {
+			t.Fatalf("Test %d: Unexpected data read from cache", i+1)
+		}
+
+		rs := &HTTPRangeSpec{Start: testCase.offset, End: testCase.offset + testCase.length - 1}
+		_, reader, rerr := c.GetObjectNInfo(ctx, bucketName, testCase.objectName, rs)
+		if rerr != nil {
+			t.Fatalf("Test %d: %v", i+1, rerr)
+		}
+		data, rerr = ioutil.ReadAll(reader)
+		reader.Close()
+		if rerr != nil {
+			t.Fatalf("Test %d: %v", i+1, rerr)
+		}
+		if !bytes.Equal(data, content[testCase.offset:testCase.offset+testCase.length]) {
+			t.Fatalf("Test %d: Unexpected data read from cache", i+1)
+		}
+	}
+
+	// Entries cached in another encryption mode are not served.
+	dcache.kms = nil
+	if _, err = dcache.GetObjectInfo(ctx, bucketName, "object"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected encrypted object not to be found without the master key, got %v", err)
+	}
+	if _, _, err = dcache.GetRange(ctx, bucketName, "ranged", &HTTPRangeSpec{Start: 0, End: 99}, ""); err == nil {
+		t.Fatal("Expected encrypted range not to be served without the master key")
+	}
+	dcache.kms = kms
+
+	// Tampered cached data is detected.
+	file, err := os.OpenFile(objectPath, os.O_WRONLY, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = file.WriteAt([]byte{0}, 100); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	if err = dcache.Get(ctx, bucketName, "object", 0, 1000, ioutil.Discard, ""); err == nil {
+		t.Fatal("Expected tampered cached object to fail decryption")
+	}
+}
+
+// Test caching SSE-S3 encrypted objects on encrypted cache drives.
+func TestCacheEncryptionSSES3(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{}, &backendDown)
+	defer removeRoots(dirs)
+
+	defer func(kms, cacheKMS crypto.KMS, keyID string) {
+		globalKMS, globalCacheKMS, globalKMSKeyID = kms, cacheKMS, keyID
+	}(globalKMS, globalCacheKMS, globalKMSKeyID)
+	globalKMS, globalKMSKeyID = crypto.NewKMS([32]byte{1}), "sse-s3-key"
+	globalCacheKMS = crypto.NewKMS([32]byte{})
+
+	dcache := c.cache.cfs[0]
+	dcache.kms, dcache.kmsKeyID = globalCacheKMS, "cache-key"
+
+	ctx := context.Background()
+	bucketName, objectName := "testbucket", "object"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &http.Request{Header: http.Header{crypto.SSEHeader: []string{crypto.SSEAlgorithmAES256}}}
+	if isCacheableSSE(http.Header{crypto.SSECAlgorithm: []string{crypto.SSEAlgorithmAES256}}, map[string]string{}) {
+		t.Fatal("Expected SSE-C objects not to be cacheable")
+	}
+	plaintext := []byte("sse-s3 cache encryption ")
+	content := bytes.Repeat(plaintext, 10000)
+	metadata := map[string]string{"content-type": "text/plain"}
+	encReader, err := EncryptRequest(bytes.NewReader(content), r, bucketName, objectName, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCacheableSSE(r.Header, metadata) {
+		t.Fatal("Expected SSE-S3 objects to be cacheable on encrypted cache drives")
+	}
+	info := ObjectInfo{Size: int64(len(content))}
+	hashReader, err := hash.NewReader(encReader, info.EncryptedSize(), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.PutObject(ctx, bucketName, objectName, hashReader, metadata); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(pathJoin(dcache.fsPath, bucketName, objectName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, plaintext) {
+		t.Fatal("Expected cached object to be encrypted")
+	}
+
+	// The SSE-S3 object is served from the cache and decrypts to the
+	// original content.
+	atomic.StoreInt32(&backendDown, 1)
+	objInfo, reader, err := c.GetObjectNInfo(ctx, bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if !crypto.S3.IsEncrypted(objInfo.UserDefined) {
+		t.Fatal("Expected cached object to keep its SSE-S3 metadata")
+	}
+	if !isCacheableSSE(http.Header{}, objInfo.UserDefined) {
+		t.Fatal("Expected cached SSE-S3 object to be served from the cache")
+	}
+	writer := bytes.NewBuffer(nil)
+	decWriter, err := DecryptRequest(writer, &http.Request{Header: http.Header{}}, bucketName, objectName, objInfo.UserDefined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = io.Copy(decWriter, reader); err != nil {
+		t.Fatal(err)
+	}
+	if err = decWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Bytes(), content) {
+		t.Fatal("Unexpected data read from the encrypted cache")
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/disk"
 	"github.com/minio/minio/pkg/hash"
@@ -50,6 +51,20 @@ type cacheFSObjects struct {
 	expiry int
 	// max disk usage pct
 	maxDiskUsagePct int
+	// eviction starts above the high and stops below the low watermark,
+	// in percentage of maxDiskUsagePct
+	watermarkLow  int
+	watermarkHigh int
+	// eviction policy specified in config.json
+	policy string
+	// access statistics of cached entries
+	index *cacheIndex
+	// hit, miss and eviction counters
+	stats *cacheStats
+	// encrypts cached data with keys derived from the cache
+	// master key if set, cached data is stored in plaintext otherwise
+	kms      crypto.KMS
+	kmsKeyID string
 	// purge() listens on this channel to start the cache-purge process
 	purgeChan chan struct{}
 	// mark false if drive is offline
@@ -60,7 +75,7 @@ type cacheFSObjects struct {
 
 // Inits the cache directory if it is not init'ed already.
 // Initializing implies creation of new FS Object layer.
-func newCacheFSObjects(dir string, expiry int, maxDiskUsagePct int) (*cacheFSObjects, error) {
+func newCacheFSObjects(dir string, config CacheConfig) (*cacheFSObjects, error) {
 	// Assign a new UUID for FS minio mode. Each server instance
 	// gets its own UUID for temporary file transaction.
 	fsUUID := mustGetUUID()
@@ -75,9 +90,15 @@ func newCacheFSObjects(dir string, expiry int, maxDiskUsagePct int) (*cacheFSObj
 		return nil, err
 	}
 
+	expiry := config.Expiry
 	if expiry == 0 {
 		expiry = globalCacheExpiry
 	}
+	policy := config.Policy
+	if policy == "" {
+		policy = cachePolicyLRU
+	}
+	watermarkLow, watermarkHigh := getCacheWatermarks(config.WatermarkLow, config.WatermarkHigh)
 
 	// Initialize fs objects.
 	fsObjects := &FSObjects{
@@ -98,7 +119,14 @@ func newCacheFSObjects(dir string, expiry int, maxDiskUsagePct int) (*cacheFSObj
 		FSObjects:       fsObjects,
 		dir:             dir,
 		expiry:          expiry,
-		maxDiskUsagePct: maxDiskUsagePct,
+		maxDiskUsagePct: config.MaxUse,
+		watermarkLow:    watermarkLow,
+		watermarkHigh:   watermarkHigh,
+		policy:          policy,
+		index:           loadCacheIndex(pathJoin(dir, minioMetaBucket, cacheIndexFile)),
+		stats:           &cacheStats{},
+		kms:             globalCacheKMS,
+		kmsKeyID:        globalCacheKMSKeyID,
 		purgeChan:       make(chan struct{}),
 		online:          true,
 		onlineMutex:     &sync.RWMutex{},
@@ -106,36 +134,47 @@ func newCacheFSObjects(dir string, expiry int, maxDiskUsagePct int) (*cacheFSObj
 	return &cacheFS, nil
 }
 
-// Returns if the disk usage is low.
-// Disk usage is low if usage is < 80% of cacheMaxDiskUsagePct
-// Ex. for a 100GB disk, if maxUsage is configured as 70% then cacheMaxDiskUsagePct is 70G
-// hence disk usage is low if the disk usage is less than 56G (because 80% of 70G is 56G)
-func (cfs *cacheFSObjects) diskUsageLow() bool {
-
-	minUsage := cfs.maxDiskUsagePct * 80 / 100
+// Returns the percentage of the cache drive in use.
+func (cfs *cacheFSObjects) diskUsedPercent() (int, error) {
 	di, err := disk.GetInfo(cfs.dir)
 	if err != nil {
 		reqInfo := (&logger.ReqInfo{}).AppendTags("cachePath", cfs.dir)
 		ctx := logger.SetReqInfo(context.Background(), reqInfo)
 		logger.LogIf(ctx, err)
+		return 0, err
+	}
+	return int((di.Total - di.Free) * 100 / di.Total), nil
+}
+
+// Returns if the disk usage is low.
+// Disk usage is low if usage is < low watermark % of cacheMaxDiskUsagePct
+// Ex. for a 100GB disk, if maxUsage is configured as 70% and the low watermark
+// as 80% then cacheMaxDiskUsagePct is 70G hence disk usage is low if the disk
+// usage is less than 56G (because 80% of 70G is 56G)
+func (cfs *cacheFSObjects) diskUsageLow() bool {
+	usedPercent, err := cfs.diskUsedPercent()
+	if err != nil {
 		return false
 	}
-	usedPercent := (di.Total - di.Free) * 100 / di.Total
-	return int(usedPercent) < minUsage
+	return usedPercent < cfs.maxDiskUsagePct*cfs.watermarkLow/100
 }
 
 // Return if the disk usage is high.
-// Disk usage is high if disk used is > cacheMaxDiskUsagePct
+// Disk usage is high if disk used is > high watermark % of cacheMaxDiskUsagePct
 func (cfs *cacheFSObjects) diskUsageHigh() bool {
-	di, err := disk.GetInfo(cfs.dir)
+	usedPercent, err := cfs.diskUsedPercent()
 	if err != nil {
-		reqInfo := (&logger.ReqInfo{}).AppendTags("cachePath", cfs.dir)
-		ctx := logger.SetReqInfo(context.Background(), reqInfo)
-		logger.LogIf(ctx, err)
 		return true
 	}
-	usedPercent := (di.Total - di.Free) * 100 / di.Total
-	return int(usedPercent) > cfs.maxDiskUsagePct
+	return usedPercent > cfs.maxDiskUsagePct*cfs.watermarkHigh/100
+}
+
+// Starts the cache-purge process unless it is already running.
+func (cfs *cacheFSObjects) triggerPurge() {
+	select {
+	case cfs.purgeChan <- struct{}{}:
+	default:
+	}
 }
 
 // Returns if size space can be allocated without exceeding
@@ -182,59 +221,17 @@ func (cfs *cacheFSObjects) purgeTrash() {
 	}
 }
 
-// Purge cache entries that were not accessed.
+// Purge cache entries in the order of the eviction policy once the disk
+// usage crosses the high watermark, until it is below the low watermark.
 func (cfs *cacheFSObjects) purge() {
-	delimiter := slashSeparator
-	maxKeys := 1000
-	ctx := context.Background()
+	ctx := logger.SetReqInfo(context.Background(), (&logger.ReqInfo{}).AppendTags("cachePath", cfs.dir))
 	for {
-		olderThan := cfs.expiry
-		for !cfs.diskUsageLow() {
-			// delete unaccessed objects older than expiry duration
-			expiry := UTCNow().AddDate(0, 0, -1*olderThan)
-			olderThan /= 2
-			if olderThan < 1 {
-				break
-			}
-			deletedCount := 0
-			buckets, err := cfs.ListBuckets(ctx)
-			if err != nil {
-				logger.LogIf(ctx, err)
-			}
+		if cfs.diskUsageHigh() {
 			// Reset cache online status if drive was offline earlier.
 			if !cfs.IsOnline() {
 				cfs.setOnline(true)
 			}
-			for _, bucket := range buckets {
-				var continuationToken string
-				var marker string
-				for {
-					objects, err := cfs.ListObjects(ctx, bucket.Name, marker, continuationToken, delimiter, maxKeys)
-					if err != nil {
-						break
-					}
-
-					if !objects.IsTruncated {
-						break
-					}
-					marker = objects.NextMarker
-					for _, object := range objects.Objects {
-						// purge objects that qualify because of cache-control directives or
-						// past cache expiry duration.
-						if !filterFromCache(object.UserDefined) ||
-							!isStaleCache(object) ||
-							object.AccTime.After(expiry) {
-							continue
-						}
-						if err = cfs.DeleteObject(ctx, bucket.Name, object.Name); err != nil {
-							logger.LogIf(ctx, err)
-							continue
-						}
-						deletedCount++
-					}
-				}
-			}
-			if deletedCount == 0 {
+			if cfs.evict(ctx) == 0 {
 				// to avoid a busy loop
 				time.Sleep(time.Minute * 30)
 			}
@@ -260,11 +257,7 @@ func (cfs *cacheFSObjects) IsOnline() bool {
 // Caches the object to disk
 func (cfs *cacheFSObjects) Put(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) error {
 	if cfs.diskUsageHigh() {
-		select {
-		case cfs.purgeChan <- struct{}{}:
-		default:
-		}
-		return errDiskFull
+		cfs.triggerPurge()
 	}
 	if !cfs.diskAvailable(data.Size()) {
 		return errDiskFull
@@ -285,14 +278,56 @@ func (cfs *cacheFSObjects) Put(ctx context.Context, bucket, object string, data
 
 // Returns the handle for the cached object
 func (cfs *cacheFSObjects) Get(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) (err error) {
-	return cfs.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+	objInfo, key, err := cfs.getObjectKey(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	if cfs.kms == nil {
+		return cfs.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+	}
+	return cfs.getDecrypted(ctx, bucket, object, startOffset, length, writer, etag, objInfo, key)
 }
 
-// Deletes the cached object
+// Deletes the cached object and its cached ranges
 func (cfs *cacheFSObjects) Delete(ctx context.Context, bucket, object string) (err error) {
+	cfs.index.remove(bucket, object)
+	logger.LogIf(ctx, cfs.DeleteRanges(ctx, bucket, object))
 	return cfs.DeleteObject(ctx, bucket, object)
 }
 
+// Marks the cached object as uploaded to the backend and replaces its
+// ETag with the backend ETag, unless the cached object was replaced
+// after cacheETag was read.
+func (cfs *cacheFSObjects) commitWriteBack(ctx context.Context, bucket, object, cacheETag, etag string) error {
+	fs := cfs.FSObjects
+	objectLock := fs.nsMutex.NewNSLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	fsMetaPath := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix, bucket, object, fs.metaJSONFile)
+	wlk, err := fs.rwPool.Write(fsMetaPath)
+	if err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	// This close will allow for locks to be synchronized on `cache.json`.
+	defer wlk.Close()
+
+	fsMeta := newFSMetaV1()
+	if _, err = fsMeta.ReadFrom(ctx, wlk); err != nil {
+		return toObjectErr(err, bucket, object)
+	}
+	if extractETag(fsMeta.Meta) != cacheETag {
+		return nil
+	}
+
+	delete(fsMeta.Meta, cacheWriteBackPendingKey)
+	fsMeta.Meta["etag"] = etag
+	_, err = fsMeta.WriteTo(wlk)
+	return toObjectErr(err, bucket, object)
+}
+
 // convenience function to check if object is cached on this cacheFSObjects
 func (cfs *cacheFSObjects) Exists(ctx context.Context, bucket, object string) bool {
 	_, err := cfs.GetObjectInfo(ctx, bucket, object)
@@ -385,15 +420,26 @@ func (cfs *cacheFSObjects) PutObject(ctx context.Context, bucket string, object
 	// so that cleaning it up will be easy if the server goes down.
 	tempObj := mustGetUUID()
 
+	// Encrypt the object data if the cache drive is encrypted.
+	reader, size := io.Reader(data), data.Size()
+	if cfs.kms != nil {
+		key, kerr := cfs.generateObjectKey(bucket, object, fsMeta.Meta)
+		if kerr != nil {
+			return ObjectInfo{}, toObjectErr(kerr, bucket, object)
+		}
+		info := ObjectInfo{Size: size}
+		reader, size = crypto.EncryptSinglePart(data, key), info.EncryptedSize()
+	}
+
 	// Allocate a buffer to Read() from request body
 	bufSize := int64(readSizeV1)
-	if size := data.Size(); size > 0 && bufSize > size {
+	if size > 0 && bufSize > size {
 		bufSize = size
 	}
 
 	buf := make([]byte, int(bufSize))
 	fsTmpObjPath := pathJoin(fs.fsPath, minioMetaTmpBucket, fs.fsUUID, tempObj)
-	bytesWritten, err := fsCreateFile(ctx, fsTmpObjPath, data, buf, data.Size())
+	bytesWritten, err := fsCreateFile(ctx, fsTmpObjPath, reader, buf, size)
 	if err != nil {
 		fsRemoveFile(ctx, fsTmpObjPath)
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
@@ -403,7 +449,7 @@ func (cfs *cacheFSObjects) PutObject(ctx context.Context, bucket string, object
 	}
 	// Should return IncompleteBody{} error when reader has fewer
 	// bytes than specified in request header.
-	if bytesWritten < data.Size() {
+	if bytesWritten < size {
 		fsRemoveFile(ctx, fsTmpObjPath)
 		return ObjectInfo{}, IncompleteBody{}
 	}
@@ -431,8 +477,11 @@ func (cfs *cacheFSObjects) PutObject(ctx context.Context, bucket string, object
 	if err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
+	if bucket != minioMetaBucket {
+		cfs.index.add(bucket, object, fi.Size())
+	}
 	// Success.
-	return fsMeta.ToObjectInfo(bucket, object, fi), nil
+	return cfs.toCacheObjectInfo(fsMeta.ToObjectInfo(bucket, object, fi))
 }
 
 // Implements S3 compatible initiate multipart API. Operation here is identical
@@ -440,10 +489,9 @@ func (cfs *cacheFSObjects) PutObject(ctx context.Context, bucket string, object
 // generated on the backend
 func (cfs *cacheFSObjects) NewMultipartUpload(ctx context.Context, bucket, object string, meta map[string]string, uploadID string) (string, error) {
 	if cfs.diskUsageHigh() {
-		select {
-		case cfs.purgeChan <- struct{}{}:
-		default:
-		}
+		cfs.triggerPurge()
+	}
+	if !cfs.diskAvailable(0) {
 		return "", errDiskFull
 	}
 
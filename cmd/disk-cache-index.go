@@ -0,0 +1,479 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Index of the entries cached on a drive, saved in the cache
+	// drive meta bucket.
+	cacheIndexFile = "cache-index.json"
+
+	cacheIndexVersionV1 = "1"
+	cacheIndexVersion   = cacheIndexVersionV1
+
+	// interval at which the index is saved to the cache drive.
+	cacheIndexSaveInterval = time.Minute
+
+	// maximum number of uncached objects whose reads are counted for
+	// admission, counts are reset once exceeded.
+	cacheAdmissionMaxEntries = 100000
+)
+
+// cacheStats - counters of a cache drive, updated atomically.
+type cacheStats struct {
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// cacheIndexEntry - access statistics and size of a cached object,
+// including its cached ranges.
+type cacheIndexEntry struct {
+	Size       int64     `json:"size"`
+	Hits       uint64    `json:"hits"`
+	LastAccess time.Time `json:"lastAccess"`
+}
+
+// cacheIndexV1 - format of the cache index file.
+type cacheIndexV1 struct {
+	Version string                     `json:"version"`
+	Entries map[string]cacheIndexEntry `json:"entries"`
+}
+
+// cacheIndex - tracks accesses of the entries cached on a drive, which
+// decides the order of eviction independent of filesystem atime.
+type cacheIndex struct {
+	sync.Mutex
+	entries map[string]cacheIndexEntry
+	// cached bytes of each bucket
+	usage map[string]int64
+	dirty bool
+}
+
+// cacheAdmission - counts reads of uncached objects, which are admitted
+// into the cache only after they were read afterHits times.
+type cacheAdmission struct {
+	sync.Mutex
+	afterHits int
+	reads     map[string]int
+}
+
+func newCacheAdmission(afterHits int) *cacheAdmission {
+	return &cacheAdmission{
+		afterHits: afterHits,
+		reads:     make(map[string]int),
+	}
+}
+
+// admit - records a read of an uncached object and returns true if the
+// object should be cached.
+func (a *cacheAdmission) admit(bucket, object string) bool {
+	if a == nil || a.afterHits <= 0 {
+		return true
+	}
+	a.Lock()
+	defer a.Unlock()
+	key := cacheIndexKey(bucket, object)
+	reads := a.reads[key] + 1
+	if reads > a.afterHits {
+		delete(a.reads, key)
+		return true
+	}
+	if len(a.reads) >= cacheAdmissionMaxEntries {
+		// Bound the memory used by objects which are read only once.
+		a.reads = make(map[string]int)
+	}
+	a.reads[key] = reads
+	return false
+}
+
+// Returns the index key of object.
+func cacheIndexKey(bucket, object string) string {
+	return bucket + slashSeparator + object
+}
+
+// Returns the bucket and object of an index key.
+func splitCacheIndexKey(key string) (bucket, object string) {
+	tokens := strings.SplitN(key, slashSeparator, 2)
+	if len(tokens) != 2 {
+		return tokens[0], ""
+	}
+	return tokens[0], tokens[1]
+}
+
+func newCacheIndex() *cacheIndex {
+	return &cacheIndex{
+		entries: make(map[string]cacheIndexEntry),
+		usage:   make(map[string]int64),
+	}
+}
+
+// Loads the cache index saved at indexPath, an empty index is returned
+// if it can't be read.
+func loadCacheIndex(indexPath string) *cacheIndex {
+	index := newCacheIndex()
+	data, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		return index
+	}
+	var indexV1 cacheIndexV1
+	if err = json.Unmarshal(data, &indexV1); err != nil || indexV1.Version != cacheIndexVersionV1 {
+		return index
+	}
+	for key, entry := range indexV1.Entries {
+		index.set(key, entry)
+	}
+	return index
+}
+
+// Saves the index to indexPath if it was modified since the last save,
+// tmpDir is used to replace the index file atomically.
+func (ci *cacheIndex) save(indexPath, tmpDir string) error {
+	ci.Lock()
+	if !ci.dirty {
+		ci.Unlock()
+		return nil
+	}
+	indexV1 := cacheIndexV1{
+		Version: cacheIndexVersion,
+		Entries: make(map[string]cacheIndexEntry, len(ci.entries)),
+	}
+	for key, entry := range ci.entries {
+		indexV1.Entries[key] = entry
+	}
+	ci.dirty = false
+	ci.Unlock()
+
+	data, err := json.Marshal(indexV1)
+	if err == nil {
+		tmpPath := pathJoin(tmpDir, mustGetUUID())
+		if err = ioutil.WriteFile(tmpPath, data, 0644); err == nil {
+			if err = os.Rename(tmpPath, indexPath); err != nil {
+				os.Remove(tmpPath)
+			}
+		}
+	}
+	if err != nil {
+		// Retry on the next save.
+		ci.Lock()
+		ci.dirty = true
+		ci.Unlock()
+	}
+	return err
+}
+
+// Sets the entry of key and accounts its size to the bucket usage,
+// replacing a previous entry. The caller must hold the lock.
+func (ci *cacheIndex) set(key string, entry cacheIndexEntry) {
+	bucket, _ := splitCacheIndexKey(key)
+	ci.usage[bucket] += entry.Size - ci.entries[key].Size
+	ci.entries[key] = entry
+	ci.dirty = true
+}
+
+// Deletes the entry of key. The caller must hold the lock.
+func (ci *cacheIndex) unset(key string) {
+	entry, ok := ci.entries[key]
+	if !ok {
+		return
+	}
+	bucket, _ := splitCacheIndexKey(key)
+	if ci.usage[bucket] -= entry.Size; ci.usage[bucket] <= 0 {
+		delete(ci.usage, bucket)
+	}
+	delete(ci.entries, key)
+	ci.dirty = true
+}
+
+// Adds a newly cached object of size bytes, replacing the statistics
+// of a previously cached version.
+func (ci *cacheIndex) add(bucket, object string, size int64) {
+	ci.Lock()
+	defer ci.Unlock()
+	ci.set(cacheIndexKey(bucket, object), cacheIndexEntry{Size: size, LastAccess: UTCNow()})
+}
+
+// Adds a newly cached range of length bytes of an object.
+func (ci *cacheIndex) addRange(bucket, object string, length int64) {
+	ci.Lock()
+	defer ci.Unlock()
+	key := cacheIndexKey(bucket, object)
+	entry, ok := ci.entries[key]
+	if !ok {
+		entry.LastAccess = UTCNow()
+	}
+	entry.Size += length
+	ci.set(key, entry)
+}
+
+// Records an access of a cached object.
+func (ci *cacheIndex) hit(bucket, object string) {
+	ci.Lock()
+	defer ci.Unlock()
+	key := cacheIndexKey(bucket, object)
+	entry := ci.entries[key]
+	entry.Hits++
+	entry.LastAccess = UTCNow()
+	ci.set(key, entry)
+}
+
+// Removes an object which is not cached anymore.
+func (ci *cacheIndex) remove(bucket, object string) {
+	ci.Lock()
+	defer ci.Unlock()
+	ci.unset(cacheIndexKey(bucket, object))
+}
+
+// Returns the size of a cached object.
+func (ci *cacheIndex) size(bucket, object string) int64 {
+	ci.Lock()
+	defer ci.Unlock()
+	return ci.entries[cacheIndexKey(bucket, object)].Size
+}
+
+// Returns the cached bytes of bucket.
+func (ci *cacheIndex) bucketUsage(bucket string) int64 {
+	ci.Lock()
+	defer ci.Unlock()
+	return ci.usage[bucket]
+}
+
+// Synchronizes the index with the entries cached on the drive, the
+// LastAccess of cached entries holds their modification time. Missing
+// entries are added as last accessed at their modification time, sizes
+// of existing entries are updated.
+func (ci *cacheIndex) reconcile(cached map[string]cacheIndexEntry) {
+	ci.Lock()
+	defer ci.Unlock()
+	for key := range ci.entries {
+		if _, ok := cached[key]; !ok {
+			ci.unset(key)
+		}
+	}
+	for key, c := range cached {
+		entry, ok := ci.entries[key]
+		if !ok {
+			ci.set(key, c)
+			continue
+		}
+		if entry.Size != c.Size {
+			entry.Size = c.Size
+			ci.set(key, entry)
+		}
+	}
+}
+
+// Returns the index keys of bucket, or of all buckets if bucket is
+// empty, in the order of eviction. Entries not accessed since expiry are
+// evicted first, followed by the remaining entries in the order of policy.
+func (ci *cacheIndex) evictionOrder(policy string, expiry time.Time, bucket string) []string {
+	type indexEntry struct {
+		key string
+		cacheIndexEntry
+	}
+	ci.Lock()
+	entries := make([]indexEntry, 0, len(ci.entries))
+	for key, entry := range ci.entries {
+		if bucket != "" && !strings.HasPrefix(key, bucket+slashSeparator) {
+			continue
+		}
+		entries = append(entries, indexEntry{key, entry})
+	}
+	ci.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if aExpired, bExpired := a.LastAccess.Before(expiry), b.LastAccess.Before(expiry); aExpired != bExpired {
+			return aExpired
+		}
+		if policy == cachePolicyLFU && a.Hits != b.Hits {
+			return a.Hits < b.Hits
+		}
+		if !a.LastAccess.Equal(b.LastAccess) {
+			return a.LastAccess.Before(b.LastAccess)
+		}
+		return a.key < b.key
+	})
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.key
+	}
+	return keys
+}
+
+// Returns the path of the index file of the cache drive.
+func (cfs *cacheFSObjects) indexPath() string {
+	return pathJoin(cfs.fsPath, minioMetaBucket, cacheIndexFile)
+}
+
+// Saves the index of the cache drive.
+func (cfs *cacheFSObjects) saveIndex() error {
+	return cfs.index.save(cfs.indexPath(), pathJoin(cfs.fsPath, minioMetaTmpBucket, cfs.fsUUID))
+}
+
+// Synchronizes the index with the cache drive and saves it periodically.
+func (cfs *cacheFSObjects) persistIndex() {
+	ticker := time.NewTicker(cacheIndexSaveInterval)
+	defer ticker.Stop()
+
+	ctx := logger.SetReqInfo(context.Background(), (&logger.ReqInfo{}).AppendTags("cachePath", cfs.dir))
+	// Account entries cached while the index was not saved to the bucket
+	// usage.
+	cfs.reconcileIndex(ctx)
+	for {
+		select {
+		case <-globalServiceDoneCh:
+			logger.LogIf(ctx, cfs.saveIndex())
+			return
+		case <-ticker.C:
+			logger.LogIf(ctx, cfs.saveIndex())
+		}
+	}
+}
+
+// Records a read served from the cache drive.
+func (cfs *cacheFSObjects) recordHit(bucket, object string) {
+	atomic.AddUint64(&cfs.stats.hits, 1)
+	cfs.index.hit(bucket, object)
+}
+
+// Records a cacheable read which was served from the backend.
+func (cfs *cacheFSObjects) recordMiss() {
+	atomic.AddUint64(&cfs.stats.misses, 1)
+}
+
+// Synchronizes the index with the objects and ranges cached on the
+// drive, which adds entries cached before the index existed or cached
+// by multipart uploads.
+func (cfs *cacheFSObjects) reconcileIndex(ctx context.Context) {
+	cached := make(map[string]cacheIndexEntry)
+	buckets, err := cfs.ListBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	for _, bucket := range buckets {
+		var marker string
+		for {
+			objects, err := cfs.ListObjects(ctx, bucket.Name, "", marker, "", maxObjectList)
+			if err != nil {
+				logger.LogIf(ctx, err)
+				return
+			}
+			for _, object := range objects.Objects {
+				cached[cacheIndexKey(bucket.Name, object.Name)] = cacheIndexEntry{Size: object.Size, LastAccess: object.ModTime}
+			}
+			if !objects.IsTruncated {
+				break
+			}
+			marker = objects.NextMarker
+		}
+	}
+
+	rangesPath := pathJoin(cfs.fsPath, minioMetaBucket, cacheRangesDir)
+	dirs, err := readDir(rangesPath)
+	if err != nil && err != errFileNotFound {
+		logger.LogIf(ctx, err)
+		return
+	}
+	for _, dir := range dirs {
+		m, err := readCacheRangeMeta(pathJoin(rangesPath, dir))
+		if err != nil {
+			continue
+		}
+		entries, err := readDir(pathJoin(rangesPath, dir))
+		if err != nil {
+			continue
+		}
+		key := cacheIndexKey(m.Bucket, m.Object)
+		entry, ok := cached[key]
+		if !ok {
+			entry.LastAccess = m.ModTime
+		}
+		for _, name := range entries {
+			if start, end, perr := parseCacheRangeName(name); perr == nil {
+				entry.Size += end - start + 1
+			}
+		}
+		cached[key] = entry
+	}
+	cfs.index.reconcile(cached)
+}
+
+// Evicts cached entries in the order of the eviction policy until the
+// cache usage is below the low watermark. Returns the number of evicted
+// entries.
+func (cfs *cacheFSObjects) evict(ctx context.Context) (evicted int) {
+	cfs.reconcileIndex(ctx)
+
+	evicted, _ = cfs.evictEntries(ctx, "", func(int64) bool {
+		return cfs.diskUsageLow()
+	})
+	logger.LogIf(ctx, cfs.saveIndex())
+	return evicted
+}
+
+// Evicts cached entries of bucket in the order of the eviction policy
+// until at least size bytes are freed. Returns the freed bytes.
+func (cfs *cacheFSObjects) evictBucket(ctx context.Context, bucket string, size int64) (freed int64) {
+	_, freed = cfs.evictEntries(ctx, bucket, func(freed int64) bool {
+		return freed >= size
+	})
+	return freed
+}
+
+// Evicts cached entries of bucket, or of all buckets if bucket is empty,
+// in the order of the eviction policy until done returns true for the
+// freed bytes. Objects pending upload to the backend are never evicted.
+func (cfs *cacheFSObjects) evictEntries(ctx context.Context, bucket string, done func(freed int64) bool) (evicted int, freed int64) {
+	expiry := UTCNow().AddDate(0, 0, -1*cfs.expiry)
+	for _, key := range cfs.index.evictionOrder(cfs.policy, expiry, bucket) {
+		if done(freed) {
+			break
+		}
+		entryBucket, object := splitCacheIndexKey(key)
+		if objInfo, err := cfs.GetObjectInfo(ctx, entryBucket, object); err == nil && isWriteBackPending(objInfo) {
+			continue
+		}
+		size := cfs.index.size(entryBucket, object)
+		switch err := cfs.Delete(ctx, entryBucket, object); err.(type) {
+		case nil, ObjectNotFound, BucketNotFound:
+			// Entries holding only cached ranges have no object.
+		default:
+			logger.LogIf(ctx, err)
+			continue
+		}
+		atomic.AddUint64(&cfs.stats.evictions, 1)
+		evicted++
+		freed += size
+	}
+	return evicted, freed
+}
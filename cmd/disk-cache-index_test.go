@@ -0,0 +1,245 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/hash"
+)
+
+// Tests the eviction order of cache index entries.
+func TestCacheIndexEvictionOrder(t *testing.T) {
+	now := UTCNow()
+	index := newCacheIndex()
+	index.entries = map[string]cacheIndexEntry{
+		"bucket/expired":   {Hits: 10, LastAccess: now.AddDate(0, 0, -40)},
+		"bucket/popular":   {Hits: 10, LastAccess: now.Add(-3 * time.Hour)},
+		"bucket/recent":    {Hits: 1, LastAccess: now.Add(-time.Hour)},
+		"bucket/untouched": {Hits: 0, LastAccess: now.Add(-2 * time.Hour)},
+	}
+	expiry := now.AddDate(0, 0, -30)
+
+	testCases := []struct {
+		policy   string
+		expected []string
+	}{
+		{cachePolicyLRU, []string{"bucket/expired", "bucket/popular", "bucket/untouched", "bucket/recent"}},
+		{cachePolicyLFU, []string{"bucket/expired", "bucket/untouched", "bucket/recent", "bucket/popular"}},
+	}
+	for i, testCase := range testCases {
+		if keys := index.evictionOrder(testCase.policy, expiry, ""); !reflect.DeepEqual(keys, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, keys)
+		}
+	}
+
+	index.entries["other/object"] = cacheIndexEntry{LastAccess: now.AddDate(0, 0, -50)}
+	if keys := index.evictionOrder(cachePolicyLRU, expiry, "other"); !reflect.DeepEqual(keys, []string{"other/object"}) {
+		t.Errorf("Expected entries of bucket other only, got %v", keys)
+	}
+}
+
+// Tests admission of objects into the cache after a number of reads.
+func TestCacheAdmission(t *testing.T) {
+	var admission *cacheAdmission
+	if !admission.admit("bucket", "object") {
+		t.Fatal("Expected objects to be admitted without admission counts")
+	}
+
+	admission = newCacheAdmission(2)
+	for i, expected := range []bool{false, false, true, false} {
+		if admitted := admission.admit("bucket", "object"); admitted != expected {
+			t.Errorf("Read %d: Expected %v, got %v", i+1, expected, admitted)
+		}
+	}
+	if admission.admit("bucket", "other") {
+		t.Error("Expected reads to be counted per object")
+	}
+}
+
+// Tests saving and loading the cache index.
+func TestCacheIndexSaveLoad(t *testing.T) {
+	dirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(dirs)
+
+	indexPath := pathJoin(dirs[0], cacheIndexFile)
+	if index := loadCacheIndex(indexPath); len(index.entries) != 0 {
+		t.Fatalf("Expected an empty index, got %v", index.entries)
+	}
+
+	index := newCacheIndex()
+	index.add("bucket", "object", 10)
+	index.add("bucket", "dir/object", 20)
+	index.addRange("bucket", "object", 5)
+	index.hit("bucket", "object")
+	index.remove("bucket", "dir/object")
+	if err = index.save(indexPath, dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if index.dirty {
+		t.Fatal("Expected saved index not to be modified")
+	}
+
+	loaded := loadCacheIndex(indexPath)
+	if len(loaded.entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %v", loaded.entries)
+	}
+	entry := loaded.entries[cacheIndexKey("bucket", "object")]
+	expected := index.entries[cacheIndexKey("bucket", "object")]
+	if entry.Hits != 1 || entry.Size != 15 || !entry.LastAccess.Equal(expected.LastAccess) {
+		t.Fatalf("Expected %v, got %v", expected, entry)
+	}
+	if usage := loaded.bucketUsage("bucket"); usage != 15 {
+		t.Fatalf("Expected bucket usage 15, got %d", usage)
+	}
+
+	// Unmodified index is not written again.
+	if err = os.Remove(indexPath); err != nil {
+		t.Fatal(err)
+	}
+	if err = index.save(indexPath, dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected unmodified index not to be saved, got %v", err)
+	}
+}
+
+// Tests eviction of cached entries.
+func TestCacheEvict(t *testing.T) {
+	dirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(dirs)
+
+	// The low watermark can't be reached with a max use of 1%, which
+	// evicts every entry.
+	cfs, err := newCacheFSObjects(dirs[0], CacheConfig{Expiry: 30, MaxUse: 1, Policy: cachePolicyLFU})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	if err = cfs.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("cached object content")
+	objects := map[string]map[string]string{
+		"object1": nil,
+		"object2": nil,
+		"pending": {cacheWriteBackPendingKey: "true"},
+	}
+	for objectName, metadata := range objects {
+		hashReader, herr := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+		if herr != nil {
+			t.Fatal(herr)
+		}
+		if _, err = cfs.PutObject(ctx, bucketName, objectName, hashReader, metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rangeWriter, err := cfs.newRangeWriter(ctx, ObjectInfo{Bucket: bucketName, Name: "ranged", Size: 100}, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rangeWriter.Write(content[:10])
+	if err = rangeWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Entries missing in the index are added before eviction.
+	cfs.index.remove(bucketName, "object2")
+
+	if evicted := cfs.evict(ctx); evicted != 3 {
+		t.Fatalf("Expected 3 evicted entries, got %d", evicted)
+	}
+	if evictions := atomic.LoadUint64(&cfs.stats.evictions); evictions != 3 {
+		t.Fatalf("Expected 3 evictions, got %d", evictions)
+	}
+	for _, objectName := range []string{"object1", "object2"} {
+		if cfs.Exists(ctx, bucketName, objectName) {
+			t.Fatalf("Expected %s to be evicted", objectName)
+		}
+	}
+	if _, _, err = cfs.GetRange(ctx, bucketName, "ranged", &HTTPRangeSpec{Start: 0, End: 9}, ""); err == nil {
+		t.Fatal("Expected cached range to be evicted")
+	}
+	if !cfs.Exists(ctx, bucketName, "pending") {
+		t.Fatal("Expected object pending upload not to be evicted")
+	}
+	if keys := cfs.index.evictionOrder(cfs.policy, time.Time{}, ""); !reflect.DeepEqual(keys, []string{cacheIndexKey(bucketName, "pending")}) {
+		t.Fatalf("Unexpected index entries %v", keys)
+	}
+	if _, err = os.Stat(cfs.indexPath()); err != nil {
+		t.Fatalf("Expected index to be saved, got %v", err)
+	}
+}
+
+// Tests cache hit and miss statistics.
+func TestCacheStats(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{}, &backendDown)
+	defer removeRoots(dirs)
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	objectName := "object"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("cached object content")
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = backend.PutObject(ctx, bucketName, objectName, hashReader, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first read is served from the backend and caches the object.
+	for i := 0; i < 3; i++ {
+		writer := bytes.NewBuffer(nil)
+		if err = c.GetObject(ctx, bucketName, objectName, 0, int64(len(content)), writer, ""); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(writer.Bytes(), content) {
+			t.Fatalf("Unexpected object content %s", writer.Bytes())
+		}
+	}
+
+	info := c.StorageInfo(ctx)
+	if info.Hits != 2 || info.Misses != 1 || info.Evictions != 0 {
+		t.Fatalf("Expected 2 hits, 1 miss and no evictions, got %d, %d and %d", info.Hits, info.Misses, info.Evictions)
+	}
+	dcache := c.cache.cfs[0]
+	dcache.index.Lock()
+	entry := dcache.index.entries[cacheIndexKey(bucketName, objectName)]
+	dcache.index.Unlock()
+	if entry.Hits != 2 {
+		t.Fatalf("Expected 2 hits in the index, got %d", entry.Hits)
+	}
+}
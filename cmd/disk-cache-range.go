@@ -0,0 +1,314 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/sio"
+)
+
+const (
+	// Ranges of objects are cached below this directory of the
+	// cache drive meta bucket, one directory per object.
+	cacheRangesDir = "ranges"
+
+	// Describes the object whose ranges are cached in a directory.
+	cacheRangeMetaFile = "range.json"
+)
+
+// cacheRangeMeta - describes the object version whose ranges are
+// cached, which allows serving cached ranges when the backend is down.
+type cacheRangeMeta struct {
+	Bucket  string            `json:"bucket"`
+	Object  string            `json:"object"`
+	Size    int64             `json:"size"`
+	ModTime time.Time         `json:"modTime"`
+	ETag    string            `json:"etag"`
+	Meta    map[string]string `json:"meta"`
+}
+
+// ToObjectInfo - converts cacheRangeMeta into ObjectInfo.
+func (m cacheRangeMeta) ToObjectInfo() ObjectInfo {
+	meta := make(map[string]string, len(m.Meta))
+	for k, v := range m.Meta {
+		meta[k] = v
+	}
+	return ObjectInfo{
+		Bucket:          m.Bucket,
+		Name:            m.Object,
+		Size:            m.Size,
+		ModTime:         m.ModTime,
+		ETag:            m.ETag,
+		ContentType:     meta["content-type"],
+		ContentEncoding: meta["content-encoding"],
+		UserDefined:     cleanMetadataKeys(cleanMetadata(meta), cacheEncryptionKeyID, cacheEncryptionSealedKey),
+	}
+}
+
+// Reads the description of the object whose ranges are cached in dir.
+func readCacheRangeMeta(dir string) (m cacheRangeMeta, err error) {
+	data, err := ioutil.ReadFile(pathJoin(dir, cacheRangeMetaFile))
+	if err != nil {
+		return m, osErrToFSFileErr(err)
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Parses the name of a cached range file of the form '<start>-<end>'.
+func parseCacheRangeName(name string) (start, end int64, err error) {
+	i := strings.Index(name, "-")
+	if i <= 0 {
+		return 0, 0, errInvalidArgument
+	}
+	if start, err = strconv.ParseInt(name[:i], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if end, err = strconv.ParseInt(name[i+1:], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// Returns the directory holding the cached ranges of an object.
+func (cfs *cacheFSObjects) rangeDir(bucket, object string) string {
+	return pathJoin(cfs.fsPath, minioMetaBucket, cacheRangesDir, getSHA256Hash([]byte(pathJoin(bucket, object))))
+}
+
+// Returns a lock on the directory holding cached ranges.
+func (cfs *cacheFSObjects) newRangeLock(dir string) RWLocker {
+	return cfs.nsMutex.NewNSLock(minioMetaBucket, pathJoin(cacheRangesDir, path.Base(dir)))
+}
+
+// GetRange - returns a reader of the range rs of object if it is
+// covered by a single cached range. If etag is not empty, the cached
+// ranges must belong to the object version with that ETag.
+func (cfs *cacheFSObjects) GetRange(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, etag string) (ObjectInfo, io.ReadCloser, error) {
+	dir := cfs.rangeDir(bucket, object)
+	m, err := readCacheRangeMeta(dir)
+	if err != nil {
+		return ObjectInfo{}, nil, err
+	}
+	if m.Bucket != bucket || m.Object != object || (etag != "" && m.ETag != etag) || !cfs.isEncryptionMode(m.Meta) {
+		return ObjectInfo{}, nil, errFileNotFound
+	}
+	var key crypto.ObjectKey
+	if cfs.kms != nil {
+		if key, err = cfs.unsealObjectKey(bucket, object, m.Meta); err != nil {
+			return ObjectInfo{}, nil, err
+		}
+	}
+
+	start, length := rs.GetOffsetLength(m.Size)
+	if start < 0 || length <= 0 || start+length > m.Size {
+		return ObjectInfo{}, nil, errFileNotFound
+	}
+
+	entries, err := readDir(dir)
+	if err != nil {
+		return ObjectInfo{}, nil, err
+	}
+	for _, entry := range entries {
+		rangeStart, rangeEnd, perr := parseCacheRangeName(entry)
+		if perr != nil || rangeStart > start || rangeEnd < start+length-1 {
+			continue
+		}
+		if cfs.kms == nil {
+			reader, _, oerr := fsOpenFile(ctx, pathJoin(dir, entry), start-rangeStart)
+			if oerr != nil {
+				continue
+			}
+			return m.ToObjectInfo(), NewGetObjectReader(io.LimitReader(reader, length), nil, func() { reader.Close() }), nil
+		}
+		offset, rangeSize := start-rangeStart, rangeEnd-rangeStart+1
+		_, encOffset, _ := getEncryptedSinglePartOffsetLength(offset, length, ObjectInfo{Size: rangeSize})
+		file, _, oerr := fsOpenFile(ctx, pathJoin(dir, entry), encOffset)
+		if oerr != nil {
+			continue
+		}
+		reader, rerr := newCacheRangeReader(file, key, offset, length, rangeSize)
+		if rerr != nil {
+			file.Close()
+			continue
+		}
+		return m.ToObjectInfo(), reader, nil
+	}
+	return ObjectInfo{}, nil, errFileNotFound
+}
+
+// DeleteRanges - deletes all cached ranges of object.
+func (cfs *cacheFSObjects) DeleteRanges(ctx context.Context, bucket, object string) error {
+	dir := cfs.rangeDir(bucket, object)
+	rangeLock := cfs.newRangeLock(dir)
+	if err := rangeLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer rangeLock.Unlock()
+
+	return os.RemoveAll(dir)
+}
+
+// Saves the range written to tmpPath into the range directory of the
+// object version described by m, cached ranges of other versions of the
+// object or encrypted with another key are removed.
+func (cfs *cacheFSObjects) commitRange(ctx context.Context, m cacheRangeMeta, start, length int64, tmpPath string) error {
+	dir := cfs.rangeDir(m.Bucket, m.Object)
+	rangeLock := cfs.newRangeLock(dir)
+	if err := rangeLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer rangeLock.Unlock()
+
+	if cm, err := readCacheRangeMeta(dir); err != nil || cm.Bucket != m.Bucket || cm.Object != m.Object || cm.ETag != m.ETag ||
+		cm.Meta[cacheEncryptionSealedKey] != m.Meta[cacheEncryptionSealedKey] {
+		if err = os.RemoveAll(dir); err != nil {
+			return err
+		}
+		if err = mkdirAll(dir, 0777); err != nil {
+			return err
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(pathJoin(dir, cacheRangeMetaFile), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := fsRenameFile(ctx, tmpPath, pathJoin(dir, fmt.Sprintf("%d-%d", start, start+length-1))); err != nil {
+		return err
+	}
+	cfs.index.addRange(m.Bucket, m.Object, length)
+	return nil
+}
+
+// cacheRangeWriter - saves a range of an object read from the backend
+// into the range cache. Write errors are not returned so that serving
+// the request is not affected, the range is then discarded.
+type cacheRangeWriter struct {
+	ctx     context.Context
+	cfs     *cacheFSObjects
+	meta    cacheRangeMeta
+	start   int64
+	length  int64
+	written int64
+	tmpPath string
+	file    *os.File
+	writer  io.WriteCloser
+	err     error
+}
+
+// newRangeWriter - returns a writer saving length bytes at offset start
+// of the object described by objInfo and metadata. On encrypted cache
+// drives the ranges of an object version share the key of the range
+// directory.
+func (cfs *cacheFSObjects) newRangeWriter(ctx context.Context, objInfo ObjectInfo, metadata map[string]string, start, length int64) (*cacheRangeWriter, error) {
+	var key crypto.ObjectKey
+	if cfs.kms != nil {
+		meta := make(map[string]string, len(metadata)+2)
+		for k, v := range metadata {
+			meta[k] = v
+		}
+		metadata = meta
+
+		var err error
+		m, rerr := readCacheRangeMeta(cfs.rangeDir(objInfo.Bucket, objInfo.Name))
+		if rerr == nil && m.Bucket == objInfo.Bucket && m.Object == objInfo.Name && m.ETag == objInfo.ETag && cfs.isEncryptionMode(m.Meta) {
+			if key, err = cfs.unsealObjectKey(objInfo.Bucket, objInfo.Name, m.Meta); err != nil {
+				return nil, err
+			}
+			metadata[cacheEncryptionKeyID] = m.Meta[cacheEncryptionKeyID]
+			metadata[cacheEncryptionSealedKey] = m.Meta[cacheEncryptionSealedKey]
+		} else if key, err = cfs.generateObjectKey(objInfo.Bucket, objInfo.Name, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	tmpPath := pathJoin(cfs.fsPath, minioMetaTmpBucket, cfs.fsUUID, mustGetUUID())
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, osErrToFSFileErr(err)
+	}
+	writer := io.WriteCloser(file)
+	if cfs.kms != nil {
+		if writer, err = sio.EncryptWriter(file, sio.Config{MinVersion: sio.Version20, Key: key[:]}); err != nil {
+			file.Close()
+			os.Remove(tmpPath)
+			return nil, err
+		}
+	}
+	return &cacheRangeWriter{
+		ctx: ctx,
+		cfs: cfs,
+		meta: cacheRangeMeta{
+			Bucket:  objInfo.Bucket,
+			Object:  objInfo.Name,
+			Size:    objInfo.Size,
+			ModTime: objInfo.ModTime,
+			ETag:    objInfo.ETag,
+			Meta:    metadata,
+		},
+		start:   start,
+		length:  length,
+		tmpPath: tmpPath,
+		file:    file,
+		writer:  writer,
+	}, nil
+}
+
+// Write - writes p into the temporary range file.
+func (w *cacheRangeWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return len(p), nil
+	}
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	w.err = err
+	return len(p), nil
+}
+
+// Close - commits the range if it was written completely, otherwise
+// the range is discarded.
+func (w *cacheRangeWriter) Close() error {
+	defer os.Remove(w.tmpPath)
+	// Closing the writer flushes the encrypted data and closes the file.
+	if err := w.writer.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err != nil || w.written != w.length {
+		return w.err
+	}
+	return w.cfs.commitRange(w.ctx, w.meta, w.start, w.length, w.tmpPath)
+}
+
+// discard - discards the range.
+func (w *cacheRangeWriter) discard() {
+	w.file.Close()
+	os.Remove(w.tmpPath)
+}
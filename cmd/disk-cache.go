@@ -18,19 +18,16 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/djherbis/atime"
-
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/hash"
 	"github.com/minio/minio/pkg/wildcard"
@@ -41,6 +38,18 @@ const (
 	cacheSizeMultiplier  = 100
 	cacheTrashDir        = "trash"
 	cacheCleanupInterval = 10 // in minutes
+
+	// metadata key of objects committed to the cache in write-back
+	// mode which are not uploaded to the backend yet.
+	cacheWriteBackPendingKey = ReservedMetadataPrefix + "Cache-Writeback-Pending"
+
+	// backoff between attempts to upload write-back objects.
+	cacheWriteBackRetryUnit = time.Second
+	cacheWriteBackRetryCap  = 5 * time.Minute
+
+	// uploads failing with errors other than the backend being down are
+	// given up after this many attempts, until the server is restarted.
+	cacheWriteBackMaxAttempts = 10
 )
 
 // abstract slice of cache drives backed by FS.
@@ -56,6 +65,21 @@ type cacheObjects struct {
 	listPool *treeWalkPool
 	// file path patterns to exclude from cache
 	exclude []string
+	// file path patterns to include in cache, everything if empty
+	include []string
+	// max bytes of the cache each bucket may use
+	quota map[string]int64
+	// size range of cached objects, unbounded if 0
+	minSize int64
+	maxSize int64
+	// counts reads of uncached objects before they are cached
+	admission *cacheAdmission
+	// commit uploads to the cache and upload them to the backend asynchronously
+	writeBack bool
+	// objects committed in write-back mode which are not uploaded yet
+	writeBacks *cacheWriteBacks
+	// cache ranged reads as partial segments
+	cacheRange bool
 	// Object functions pointing to the corresponding functions of backend implementation.
 	GetObjectNInfoFn          func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (objInfo ObjectInfo, reader io.ReadCloser, err error)
 	GetObjectFn               func(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) (err error)
@@ -74,10 +98,13 @@ type cacheObjects struct {
 }
 
 // CacheStorageInfo - represents total, free capacity of
-// underlying cache storage.
+// underlying cache storage and its usage statistics.
 type CacheStorageInfo struct {
-	Total uint64 // Total cache disk space.
-	Free  uint64 // Free cache available space.
+	Total     uint64 // Total cache disk space.
+	Free      uint64 // Free cache available space.
+	Hits      uint64 // Reads served from the cache.
+	Misses    uint64 // Cacheable reads served from the backend.
+	Evictions uint64 // Entries evicted from the cache.
 }
 
 // CacheObjectLayer implements primitives for cache object API layer.
@@ -159,6 +186,16 @@ func (c diskCache) getCacheFS(ctx context.Context, bucket, object string) (*cach
 	return nil, errDiskNotFound
 }
 
+// Returns the cached bytes of bucket on all cache drives.
+func (c diskCache) bucketUsage(bucket string) (used int64) {
+	for _, cfs := range c.cfs {
+		if cfs != nil {
+			used += cfs.index.bucketUsage(bucket)
+		}
+	}
+	return used
+}
+
 // Compute a unique hash sum for bucket and object
 func (c diskCache) hashIndex(bucket, object string) int {
 	return crcHashMod(pathJoin(bucket, object), len(c.cfs))
@@ -178,6 +215,15 @@ func (c cacheObjects) getMetadata(objInfo ObjectInfo) map[string]string {
 }
 
 func (c cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (oi ObjectInfo, r io.ReadCloser, err error) {
+	// Objects pending upload are only available in the cache.
+	if dcache, ok := c.getWriteBackCacheFS(ctx, bucket, object); ok {
+		oi, r, err = dcache.GetObjectNInfo(ctx, bucket, object, rs)
+		if err == nil {
+			dcache.recordHit(bucket, object)
+		}
+		oi.UserDefined = cleanMetadataKeys(oi.UserDefined, cacheWriteBackPendingKey)
+		return oi, r, err
+	}
 
 	bkObjInfo, bkReader, bkErr := c.GetObjectNInfoFn(ctx, bucket, object, rs)
 
@@ -208,20 +254,28 @@ func (c cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string,
 	if cacheErr == nil {
 		if backendDown {
 			// If the backend is down, serve the request from cache.
+			dcache.recordHit(bucket, object)
 			return cacheObjInfo, cacheReader, nil
 		}
 		if cacheObjInfo.ETag == bkObjInfo.ETag && !isStaleCache(bkObjInfo) {
+			bkReader.Close()
+			dcache.recordHit(bucket, object)
 			return cacheObjInfo, cacheReader, nil
 		}
+		cacheReader.Close()
 		dcache.Delete(ctx, bucket, object)
 	}
 
 	if rs != nil {
-		// We don't cache partial objects.
-		return bkObjInfo, bkReader, bkErr
+		if !c.cacheRange {
+			// Partial objects are only cached when range caching is enabled.
+			dcache.recordMiss()
+			return bkObjInfo, bkReader, bkErr
+		}
+		return c.getObjectRangeNInfo(ctx, dcache, bucket, object, rs, bkObjInfo, bkReader, bkErr)
 	}
-	if !dcache.diskAvailable(bkObjInfo.Size * cacheSizeMultiplier) {
-		// cache only objects < 1/100th of disk capacity
+	dcache.recordMiss()
+	if !c.admitRead(ctx, dcache, bkObjInfo, bkObjInfo.Size) {
 		return bkObjInfo, bkReader, bkErr
 	}
 
@@ -246,6 +300,78 @@ func (c cacheObjects) GetObjectNInfo(ctx context.Context, bucket, object string,
 	return bkObjInfo, getObjReader, nil
 }
 
+// Serves a ranged read from the cached ranges of object, or from the backend reader while
+// saving the range in the cache for serving subsequent requests.
+func (c cacheObjects) getObjectRangeNInfo(ctx context.Context, dcache *cacheFSObjects, bucket, object string, rs *HTTPRangeSpec,
+	bkObjInfo ObjectInfo, bkReader io.ReadCloser, bkErr error) (ObjectInfo, io.ReadCloser, error) {
+	if bkErr != nil {
+		// If the backend is down, serve the request from cached ranges.
+		if objInfo, reader, err := dcache.GetRange(ctx, bucket, object, rs, ""); err == nil {
+			dcache.recordHit(bucket, object)
+			return objInfo, reader, nil
+		}
+		return bkObjInfo, bkReader, bkErr
+	}
+	if isStaleCache(bkObjInfo) {
+		return bkObjInfo, bkReader, bkErr
+	}
+
+	if _, reader, err := dcache.GetRange(ctx, bucket, object, rs, bkObjInfo.ETag); err == nil {
+		bkReader.Close()
+		dcache.recordHit(bucket, object)
+		return bkObjInfo, reader, nil
+	}
+	dcache.recordMiss()
+
+	start, length := rs.GetOffsetLength(bkObjInfo.Size)
+	if !c.admitRead(ctx, dcache, bkObjInfo, length) {
+		return bkObjInfo, bkReader, bkErr
+	}
+	rangeWriter, err := dcache.newRangeWriter(ctx, bkObjInfo, c.getMetadata(bkObjInfo), start, length)
+	if err != nil {
+		return bkObjInfo, bkReader, bkErr
+	}
+
+	cleanupBackend := func() {
+		bkReader.Close()
+		// The range is saved only if it was read completely.
+		logger.LogIf(ctx, rangeWriter.Close())
+	}
+	return bkObjInfo, NewGetObjectReader(io.TeeReader(bkReader, rangeWriter), nil, cleanupBackend), nil
+}
+
+// Writes a range of object from its cached ranges, or from the backend while saving the range
+// in the cache for serving subsequent requests.
+func (c cacheObjects) getObjectRange(ctx context.Context, dcache *cacheFSObjects, bucket, object string, startOffset int64, length int64,
+	writer io.Writer, etag string, objInfo ObjectInfo, backendDown bool) error {
+	rs := &HTTPRangeSpec{Start: startOffset, End: startOffset + length - 1}
+	cacheETag := objInfo.ETag
+	if backendDown {
+		cacheETag = ""
+	}
+	if _, reader, err := dcache.GetRange(ctx, bucket, object, rs, cacheETag); err == nil {
+		defer reader.Close()
+		dcache.recordHit(bucket, object)
+		_, err = io.Copy(writer, reader)
+		return err
+	}
+	dcache.recordMiss()
+
+	if backendDown || isStaleCache(objInfo) || length <= 0 || !c.admitRead(ctx, dcache, objInfo, length) {
+		return c.GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
+	}
+	rangeWriter, err := dcache.newRangeWriter(ctx, objInfo, c.getMetadata(objInfo), startOffset, length)
+	if err != nil {
+		return c.GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
+	}
+	if err = c.GetObjectFn(ctx, bucket, object, startOffset, length, io.MultiWriter(writer, rangeWriter), etag); err != nil {
+		rangeWriter.discard()
+		return err
+	}
+	logger.LogIf(ctx, rangeWriter.Close())
+	return nil
+}
+
 // Uses cached-object to serve the request. If object is not cached it serves the request from the backend and also
 // stores it in the cache for serving subsequent requests.
 func (c cacheObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) (err error) {
@@ -255,6 +381,11 @@ func (c cacheObjects) GetObject(ctx context.Context, bucket, object string, star
 	if c.isCacheExclude(bucket, object) {
 		return GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
 	}
+	// Objects pending upload are only available in the cache.
+	if dcache, ok := c.getWriteBackCacheFS(ctx, bucket, object); ok {
+		dcache.recordHit(bucket, object)
+		return dcache.Get(ctx, bucket, object, startOffset, length, writer, etag)
+	}
 	// fetch cacheFSObjects if object is currently cached or nearest available cache drive
 	dcache, err := c.cache.getCachedFSLoc(ctx, bucket, object)
 	if err != nil {
@@ -279,19 +410,25 @@ func (c cacheObjects) GetObject(ctx context.Context, bucket, object string, star
 	if err == nil {
 		if backendDown {
 			// If the backend is down, serve the request from cache.
+			dcache.recordHit(bucket, object)
 			return dcache.Get(ctx, bucket, object, startOffset, length, writer, etag)
 		}
 		if cachedObjInfo.ETag == objInfo.ETag && !isStaleCache(objInfo) {
+			dcache.recordHit(bucket, object)
 			return dcache.Get(ctx, bucket, object, startOffset, length, writer, etag)
 		}
 		dcache.Delete(ctx, bucket, object)
 	}
 	if startOffset != 0 || length != objInfo.Size {
-		// We don't cache partial objects.
-		return GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
+		if !c.cacheRange {
+			// Partial objects are only cached when range caching is enabled.
+			dcache.recordMiss()
+			return GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
+		}
+		return c.getObjectRange(ctx, dcache, bucket, object, startOffset, length, writer, etag, objInfo, backendDown)
 	}
-	if !dcache.diskAvailable(objInfo.Size * cacheSizeMultiplier) {
-		// cache only objects < 1/100th of disk capacity
+	dcache.recordMiss()
+	if !c.admitRead(ctx, dcache, objInfo, objInfo.Size) {
 		return GetObjectFn(ctx, bucket, object, startOffset, length, writer, etag)
 	}
 	// Initialize pipe.
@@ -301,8 +438,8 @@ func (c cacheObjects) GetObject(ctx context.Context, bucket, object string, star
 		return err
 	}
 	go func() {
-		if err = GetObjectFn(ctx, bucket, object, 0, objInfo.Size, io.MultiWriter(writer, pipeWriter), etag); err != nil {
-			pipeWriter.CloseWithError(err)
+		if gerr := GetObjectFn(ctx, bucket, object, 0, objInfo.Size, io.MultiWriter(writer, pipeWriter), etag); gerr != nil {
+			pipeWriter.CloseWithError(gerr)
 			return
 		}
 		pipeWriter.Close() // Close writer explicitly signaling we wrote all data.
@@ -321,6 +458,12 @@ func (c cacheObjects) GetObjectInfo(ctx context.Context, bucket, object string)
 	if c.isCacheExclude(bucket, object) {
 		return getObjectInfoFn(ctx, bucket, object)
 	}
+	// Objects pending upload are only available in the cache.
+	if dcache, ok := c.getWriteBackCacheFS(ctx, bucket, object); ok {
+		objInfo, err := dcache.GetObjectInfo(ctx, bucket, object)
+		objInfo.UserDefined = cleanMetadataKeys(objInfo.UserDefined, cacheWriteBackPendingKey)
+		return objInfo, err
+	}
 	// fetch cacheFSObjects if object is currently cached or nearest available cache drive
 	dcache, err := c.cache.getCachedFSLoc(ctx, bucket, object)
 	if err != nil {
@@ -465,6 +608,9 @@ func (c cacheObjects) listCacheObjects(ctx context.Context, bucket, prefix, mark
 				return result, toObjectErr(err, bucket, prefix)
 			}
 			objInfo, err = fs.getObjectInfo(ctx, bucket, entry)
+			if err == nil {
+				objInfo, err = fs.toCacheObjectInfo(objInfo)
+			}
 			if err != nil {
 				// Ignore ObjectNotFound error
 				if _, ok := err.(ObjectNotFound); ok {
@@ -529,7 +675,7 @@ func (c cacheObjects) ListObjects(ctx context.Context, bucket, prefix, marker, d
 		}
 		return
 	}
-	return
+	return c.mergeWriteBacks(ctx, bucket, prefix, marker, delimiter, maxKeys, result), nil
 }
 
 // ListObjectsV2 lists all blobs in bucket filtered by prefix
@@ -543,7 +689,104 @@ func (c cacheObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continu
 		}
 		return
 	}
-	return
+
+	// Continuation tokens which are not markers are decoded, to list
+	// pending objects after the previous page.
+	token, _ := decodeListContinuation(continuationToken)
+	marker := token.Marker
+	if startAfter > marker {
+		marker = startAfter
+	}
+	loi := c.mergeWriteBacks(ctx, bucket, prefix, marker, delimiter, maxKeys, ListObjectsInfo{
+		IsTruncated: result.IsTruncated,
+		Objects:     result.Objects,
+		Prefixes:    result.Prefixes,
+	})
+	if loi.NextMarker != "" {
+		// Page was cut short by pending objects, continue after its end.
+		result.NextContinuationToken = loi.NextMarker
+	}
+	result.IsTruncated, result.Objects, result.Prefixes = loi.IsTruncated, loi.Objects, loi.Prefixes
+	return result, nil
+}
+
+// mergeWriteBacks - adds the objects pending upload in write-back mode to
+// a page of the backend listing after marker. Pending objects after the
+// end of a truncated page are listed with the following pages.
+func (c cacheObjects) mergeWriteBacks(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int, result ListObjectsInfo) ListObjectsInfo {
+	if !c.writeBack {
+		return result
+	}
+	names := c.writeBacks.list(bucket, prefix)
+	if len(names) == 0 {
+		return result
+	}
+
+	objects := make(map[string]ObjectInfo)
+	prefixes := make(map[string]struct{})
+	var end string
+	for _, objInfo := range result.Objects {
+		objects[objInfo.Name] = objInfo
+		if objInfo.Name > end {
+			end = objInfo.Name
+		}
+	}
+	for _, p := range result.Prefixes {
+		prefixes[p] = struct{}{}
+		if p > end {
+			end = p
+		}
+	}
+
+	for _, name := range names {
+		entry := name
+		if delimiter == slashSeparator {
+			if i := strings.Index(name[len(prefix):], slashSeparator); i >= 0 {
+				entry = name[:len(prefix)+i+1]
+			}
+		}
+		if entry <= marker || (result.IsTruncated && entry > end) {
+			continue
+		}
+		dcache, ok := c.getWriteBackCacheFS(ctx, bucket, name)
+		if !ok {
+			continue
+		}
+		if entry != name {
+			prefixes[entry] = struct{}{}
+			continue
+		}
+		objInfo, err := dcache.GetObjectInfo(ctx, bucket, name)
+		if err != nil {
+			continue
+		}
+		objInfo.UserDefined = cleanMetadataKeys(objInfo.UserDefined, cacheWriteBackPendingKey)
+		objects[name] = objInfo
+	}
+
+	var entries []string
+	for name := range objects {
+		entries = append(entries, name)
+	}
+	for p := range prefixes {
+		entries = append(entries, p)
+	}
+	sort.Strings(entries)
+
+	merged := ListObjectsInfo{IsTruncated: result.IsTruncated, NextMarker: result.NextMarker}
+	if len(entries) > maxKeys {
+		entries = entries[:maxKeys]
+		merged.IsTruncated = true
+		merged.NextMarker = entries[len(entries)-1]
+	}
+	for _, entry := range entries {
+		if objInfo, ok := objects[entry]; ok {
+			merged.Objects = append(merged.Objects, objInfo)
+		} else {
+			merged.Prefixes = append(merged.Prefixes, entry)
+		}
+	}
+	return merged
 }
 
 // Lists all the buckets in the cache
@@ -606,6 +849,18 @@ func (c cacheObjects) GetBucketInfo(ctx context.Context, bucket string) (bucketI
 
 // Delete Object deletes from cache as well if backend operation succeeds
 func (c cacheObjects) DeleteObject(ctx context.Context, bucket, object string) (err error) {
+	// Objects pending upload are deleted from the cache first, which
+	// stops their upload, they may not exist on the backend yet.
+	if dcache, ok := c.getWriteBackCacheFS(ctx, bucket, object); ok {
+		if err = dcache.Delete(ctx, bucket, object); err != nil {
+			return err
+		}
+		if err = c.DeleteObjectFn(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
 	if err = c.DeleteObjectFn(ctx, bucket, object); err != nil {
 		return
 	}
@@ -614,20 +869,71 @@ func (c cacheObjects) DeleteObject(ctx context.Context, bucket, object string) (
 	}
 	dcache, cerr := c.cache.getCachedFSLoc(ctx, bucket, object)
 	if cerr == nil {
-		_ = dcache.DeleteObject(ctx, bucket, object)
+		_ = dcache.Delete(ctx, bucket, object)
 	}
 	return
 }
 
 // Returns true if object should be excluded from cache
 func (c cacheObjects) isCacheExclude(bucket, object string) bool {
+	matchStr := fmt.Sprintf("%s/%s", bucket, object)
 	for _, pattern := range c.exclude {
-		matchStr := fmt.Sprintf("%s/%s", bucket, object)
 		if ok := wildcard.MatchSimple(pattern, matchStr); ok {
 			return true
 		}
 	}
-	return false
+	if len(c.include) == 0 {
+		return false
+	}
+	for _, pattern := range c.include {
+		if ok := wildcard.MatchSimple(pattern, matchStr); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if an object of size is within the size range of cached
+// objects, objects of unknown size are only cached without a size range.
+func (c cacheObjects) isCacheableSize(size int64) bool {
+	if size < 0 {
+		return c.minSize == 0 && c.maxSize == 0
+	}
+	return size >= c.minSize && (c.maxSize == 0 || size <= c.maxSize)
+}
+
+// Makes room for size bytes in the cache quota of bucket by evicting
+// cached entries of the bucket, returns false if the quota can't be
+// satisfied.
+func (c cacheObjects) reserveQuota(ctx context.Context, bucket string, size int64) bool {
+	quota, ok := c.quota[bucket]
+	if !ok {
+		return true
+	}
+	if size < 0 || size > quota {
+		return false
+	}
+	used := c.cache.bucketUsage(bucket)
+	for _, cfs := range c.cache.cfs {
+		if used+size <= quota {
+			break
+		}
+		if cfs == nil {
+			continue
+		}
+		used -= cfs.evictBucket(ctx, bucket, used+size-quota)
+	}
+	return used+size <= quota
+}
+
+// Returns true if an object read from the backend should be cached in
+// dcache, cacheSize is the size of the object or the range to cache.
+func (c cacheObjects) admitRead(ctx context.Context, dcache *cacheFSObjects, objInfo ObjectInfo, cacheSize int64) bool {
+	// cache only objects < 1/100th of disk capacity
+	return c.isCacheableSize(objInfo.Size) &&
+		dcache.diskAvailable(cacheSize*cacheSizeMultiplier) &&
+		c.admission.admit(objInfo.Bucket, objInfo.Name) &&
+		c.reserveQuota(ctx, objInfo.Bucket, cacheSize)
 }
 
 // PutObject - caches the uploaded object for single Put operations
@@ -640,16 +946,27 @@ func (c cacheObjects) PutObject(ctx context.Context, bucket, object string, r *h
 	}
 	size := r.Size()
 
+	// A version pending upload in write-back mode is replaced in the
+	// cache regardless of the cache rules, so that uploads reach the
+	// backend in order.
+	if pendingCache, ok := c.getWriteBackCacheFS(ctx, bucket, object); ok {
+		return c.putObjectWriteBack(ctx, pendingCache, bucket, object, r, metadata)
+	}
+
 	// fetch from backend if there is no space on cache drive
 	if !dcache.diskAvailable(size * cacheSizeMultiplier) {
 		return putObjectFn(ctx, bucket, object, r, metadata)
 	}
 	// fetch from backend if cache exclude pattern or cache-control
 	// directive set to exclude
-	if c.isCacheExclude(bucket, object) || filterFromCache(metadata) {
+	if c.isCacheExclude(bucket, object) || filterFromCache(metadata) ||
+		!c.isCacheableSize(size) || !c.reserveQuota(ctx, bucket, size) {
 		dcache.Delete(ctx, bucket, object)
 		return putObjectFn(ctx, bucket, object, r, metadata)
 	}
+	if c.writeBack {
+		return c.putObjectWriteBack(ctx, dcache, bucket, object, r, metadata)
+	}
 	objInfo = ObjectInfo{}
 	// Initialize pipe to stream data to backend
 	pipeReader, pipeWriter := io.Pipe()
@@ -678,9 +995,11 @@ func (c cacheObjects) PutObject(ctx context.Context, bucket, object string, r *h
 		oinfoCh <- oinfo
 	}()
 
+	cacheDoneCh := make(chan struct{})
 	go func() {
-		if err = dcache.Put(ctx, bucket, object, cHashReader, metadata); err != nil {
-			wPipe.CloseWithError(err)
+		defer close(cacheDoneCh)
+		if perr := dcache.Put(ctx, bucket, object, cHashReader, metadata); perr != nil {
+			wPipe.CloseWithError(perr)
 			return
 		}
 	}()
@@ -694,9 +1013,252 @@ func (c cacheObjects) PutObject(ctx context.Context, bucket, object string, r *h
 	pipeWriter.Close()
 	wPipe.Close()
 	objInfo = <-oinfoCh
+	// Wait for the cached object to be committed, which keeps the cache
+	// usage of buckets accurate for subsequent uploads.
+	<-cacheDoneCh
 	return objInfo, err
 }
 
+// putObjectWriteBack - commits the uploaded object to the cache and
+// uploads it to the backend asynchronously.
+func (c cacheObjects) putObjectWriteBack(ctx context.Context, dcache *cacheFSObjects, bucket, object string, r *hash.Reader, metadata map[string]string) (objInfo ObjectInfo, err error) {
+	// Make sure the bucket exists on the backend, unless the backend
+	// is down in which case the upload is retried until it is back.
+	if _, err = c.GetBucketInfoFn(ctx, bucket); err != nil && !backendDownError(err) {
+		return objInfo, err
+	}
+
+	meta := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		meta[k] = v
+	}
+	meta[cacheWriteBackPendingKey] = "true"
+	if err = dcache.Put(ctx, bucket, object, r, meta); err != nil {
+		if err == errDiskFull {
+			// Nothing was read from r, upload to the backend instead.
+			return c.PutObjectFn(ctx, bucket, object, r, metadata)
+		}
+		return objInfo, err
+	}
+	if objInfo, err = dcache.GetObjectInfo(ctx, bucket, object); err != nil {
+		return objInfo, err
+	}
+
+	go c.uploadObject(dcache, bucket, object)
+
+	objInfo.UserDefined = cleanMetadataKeys(objInfo.UserDefined, cacheWriteBackPendingKey)
+	return objInfo, nil
+}
+
+// getWriteBackCacheFS - returns the cache drive of an object committed
+// in write-back mode which is not uploaded to the backend yet.
+func (c cacheObjects) getWriteBackCacheFS(ctx context.Context, bucket, object string) (*cacheFSObjects, bool) {
+	if !c.writeBack || c.isCacheExclude(bucket, object) {
+		return nil, false
+	}
+	dcache, err := c.cache.getCachedFSLoc(ctx, bucket, object)
+	if err != nil {
+		return nil, false
+	}
+	objInfo, err := dcache.GetObjectInfo(ctx, bucket, object)
+	if err != nil || !isWriteBackPending(objInfo) {
+		return nil, false
+	}
+	return dcache, true
+}
+
+// cacheWriteBack - upload state of an object committed in write-back mode.
+type cacheWriteBack struct {
+	// set while the object is being uploaded
+	uploading bool
+	// set if the object was committed again during its upload
+	committed bool
+}
+
+// cacheWriteBacks - objects committed to the cache in write-back mode which
+// are not uploaded yet. Uploads of an object are serialized, such that the
+// last version committed to the cache is the last version uploaded.
+type cacheWriteBacks struct {
+	sync.Mutex
+	objects map[string]map[string]*cacheWriteBack
+}
+
+func newCacheWriteBacks() *cacheWriteBacks {
+	return &cacheWriteBacks{objects: make(map[string]map[string]*cacheWriteBack)}
+}
+
+// start - records a commit of object, returns true if the caller should
+// upload it, false if it is uploaded again after the current upload.
+func (w *cacheWriteBacks) start(bucket, object string) bool {
+	w.Lock()
+	defer w.Unlock()
+	objects, ok := w.objects[bucket]
+	if !ok {
+		objects = make(map[string]*cacheWriteBack)
+		w.objects[bucket] = objects
+	}
+	wb, ok := objects[object]
+	if !ok {
+		wb = &cacheWriteBack{}
+		objects[object] = wb
+	}
+	if wb.uploading {
+		wb.committed = true
+		return false
+	}
+	wb.uploading = true
+	return true
+}
+
+// finish - ends an upload of object, returns true if the object was
+// committed again meanwhile and must be uploaded again. Objects which
+// were not uploaded are still listed as pending.
+func (w *cacheWriteBacks) finish(bucket, object string, uploaded bool) bool {
+	w.Lock()
+	defer w.Unlock()
+	wb := w.objects[bucket][object]
+	if wb.committed {
+		wb.committed = false
+		return true
+	}
+	if !uploaded {
+		wb.uploading = false
+		return false
+	}
+	delete(w.objects[bucket], object)
+	if len(w.objects[bucket]) == 0 {
+		delete(w.objects, bucket)
+	}
+	return false
+}
+
+// list - returns the sorted names of the pending objects of bucket
+// starting with prefix.
+func (w *cacheWriteBacks) list(bucket, prefix string) (objects []string) {
+	w.Lock()
+	defer w.Unlock()
+	for object := range w.objects[bucket] {
+		if hasPrefix(object, prefix) {
+			objects = append(objects, object)
+		}
+	}
+	sort.Strings(objects)
+	return objects
+}
+
+// uploadObject - uploads an object committed to the cache in write-back
+// mode to the backend, unless it is being uploaded already in which case
+// it is uploaded again once the current upload is finished.
+func (c cacheObjects) uploadObject(dcache *cacheFSObjects, bucket, object string) {
+	if !c.writeBacks.start(bucket, object) {
+		return
+	}
+
+	reqInfo := &logger.ReqInfo{BucketName: bucket, ObjectName: object}
+	ctx := logger.SetReqInfo(context.Background(), reqInfo)
+	for {
+		uploaded := c.retryWriteBackObject(ctx, dcache, bucket, object)
+		if !c.writeBacks.finish(bucket, object, uploaded) {
+			return
+		}
+	}
+}
+
+// retryWriteBackObject - uploads a pending write-back object, retrying until
+// it succeeds or the cached object is uploaded, replaced or deleted otherwise.
+// Uploads are retried as long as the backend is down, other errors are only
+// retried cacheWriteBackMaxAttempts times.
+func (c cacheObjects) retryWriteBackObject(ctx context.Context, dcache *cacheFSObjects, bucket, object string) bool {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	var attempts int
+	for range newRetryTimerWithJitter(cacheWriteBackRetryUnit, cacheWriteBackRetryCap, MaxJitter, doneCh) {
+		done, err := c.writeBackObject(ctx, dcache, bucket, object)
+		if done {
+			logger.LogIf(ctx, err)
+			return true
+		}
+		if backendDownError(err) {
+			continue
+		}
+		logger.LogIf(ctx, err)
+		if attempts++; attempts == cacheWriteBackMaxAttempts {
+			logger.LogIf(ctx, fmt.Errorf("Unable to upload the cached object to the backend after %d attempts, the upload is retried on restart", attempts))
+			return false
+		}
+	}
+	return false
+}
+
+// writeBackObject - uploads a pending write-back object from the cache
+// to the backend once, done is false if the upload should be retried.
+func (c cacheObjects) writeBackObject(ctx context.Context, dcache *cacheFSObjects, bucket, object string) (done bool, err error) {
+	objInfo, reader, err := dcache.GetObjectNInfo(ctx, bucket, object, nil)
+	if err != nil {
+		// Nothing left to upload if the cached object was deleted.
+		return isErrObjectNotFound(err), err
+	}
+	if !isWriteBackPending(objInfo) {
+		reader.Close()
+		return true, nil
+	}
+
+	hashReader, err := hash.NewReader(reader, objInfo.Size, "", "")
+	if err != nil {
+		reader.Close()
+		return false, err
+	}
+	metadata := cleanMetadataKeys(objInfo.UserDefined, cacheWriteBackPendingKey)
+	bkObjInfo, err := c.PutObjectFn(ctx, bucket, object, hashReader, metadata)
+	reader.Close()
+	if err != nil {
+		return false, err
+	}
+
+	err = dcache.commitWriteBack(ctx, bucket, object, objInfo.ETag, bkObjInfo.ETag)
+	if isErrObjectNotFound(err) {
+		// The cached object was deleted during the upload.
+		return true, c.DeleteObjectFn(ctx, bucket, object)
+	}
+	return true, err
+}
+
+// resumeWriteBacks - uploads objects committed to the cache drives in
+// write-back mode which were not uploaded before the server stopped.
+func (c cacheObjects) resumeWriteBacks() {
+	ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{})
+	for _, dcache := range c.cache.cfs {
+		if dcache == nil {
+			continue
+		}
+		buckets, err := dcache.ListBuckets(ctx)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		for _, bucket := range buckets {
+			var marker string
+			for {
+				objects, err := dcache.ListObjects(ctx, bucket.Name, "", marker, "", 1000)
+				if err != nil {
+					logger.LogIf(ctx, err)
+					break
+				}
+				for _, object := range objects.Objects {
+					if isWriteBackPending(object) {
+						go c.uploadObject(dcache, bucket.Name, object.Name)
+					}
+				}
+				if !objects.IsTruncated {
+					break
+				}
+				marker = objects.NextMarker
+			}
+		}
+	}
+}
+
 // NewMultipartUpload - Starts a new multipart upload operation to backend and cache.
 func (c cacheObjects) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
 	newMultipartUploadFn := c.NewMultipartUploadFn
@@ -710,6 +1272,11 @@ func (c cacheObjects) NewMultipartUpload(ctx context.Context, bucket, object str
 		// disk cache could not be located,execute backend call.
 		return newMultipartUploadFn(ctx, bucket, object, metadata)
 	}
+	// Parts are not encrypted, multipart uploads are cached on
+	// encrypted cache drives only when they are read.
+	if dcache.kms != nil {
+		return newMultipartUploadFn(ctx, bucket, object, metadata)
+	}
 
 	uploadID, err = newMultipartUploadFn(ctx, bucket, object, metadata)
 	if err != nil {
@@ -729,7 +1296,7 @@ func (c cacheObjects) PutObjectPart(ctx context.Context, bucket, object, uploadI
 		return putObjectPartFn(ctx, bucket, object, uploadID, partID, data)
 	}
 
-	if c.isCacheExclude(bucket, object) {
+	if c.isCacheExclude(bucket, object) || dcache.kms != nil {
 		return putObjectPartFn(ctx, bucket, object, uploadID, partID, data)
 	}
 
@@ -831,13 +1398,20 @@ func (c cacheObjects) CompleteMultipartUpload(ctx context.Context, bucket, objec
 		return
 	}
 	// create new multipart upload in cache with same uploadID
-	dcache.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	cacheObjInfo, cerr := dcache.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	if cerr != nil {
+		return
+	}
+	if !c.isCacheableSize(cacheObjInfo.Size) || !c.reserveQuota(ctx, bucket, cacheObjInfo.Size) {
+		dcache.Delete(ctx, bucket, object)
+		return
+	}
+	dcache.index.add(bucket, object, cacheObjInfo.Size)
 	return
 }
 
 // StorageInfo - returns underlying storage statistics.
 func (c cacheObjects) StorageInfo(ctx context.Context) (cInfo CacheStorageInfo) {
-	var total, free uint64
 	for _, cfs := range c.cache.cfs {
 		if cfs == nil {
 			continue
@@ -845,13 +1419,13 @@ func (c cacheObjects) StorageInfo(ctx context.Context) (cInfo CacheStorageInfo)
 		info, err := getDiskInfo(cfs.fsPath)
 		logger.GetReqInfo(ctx).AppendTags("cachePath", cfs.fsPath)
 		logger.LogIf(ctx, err)
-		total += info.Total
-		free += info.Free
-	}
-	return CacheStorageInfo{
-		Total: total,
-		Free:  free,
+		cInfo.Total += info.Total
+		cInfo.Free += info.Free
+		cInfo.Hits += atomic.LoadUint64(&cfs.stats.hits)
+		cInfo.Misses += atomic.LoadUint64(&cfs.stats.misses)
+		cInfo.Evictions += atomic.LoadUint64(&cfs.stats.evictions)
 	}
+	return cInfo
 }
 
 // DeleteBucket - marks bucket to be deleted from cache if bucket is deleted from backend.
@@ -894,16 +1468,16 @@ func newCache(config CacheConfig) (*diskCache, error) {
 			cfsObjects = append(cfsObjects, nil)
 			continue
 		}
-		if err := checkAtimeSupport(dir); err != nil {
-			return nil, errors.New("Atime support required for disk caching")
-		}
-		cache, err := newCacheFSObjects(dir, config.Expiry, config.MaxUse)
+		cache, err := newCacheFSObjects(dir, config)
 		if err != nil {
 			return nil, err
 		}
-		// Start the purging go-routine for entries that have expired
+		// Start the purging go-routine for evicting entries
 		go cache.purge()
 
+		// Start the go-routine saving the index of cached entries.
+		go cache.persistIndex()
+
 		// Start trash purge routine for deleted buckets.
 		go cache.purgeTrash()
 
@@ -912,29 +1486,6 @@ func newCache(config CacheConfig) (*diskCache, error) {
 	return &diskCache{cfs: cfsObjects}, nil
 }
 
-// Return error if Atime is disabled on the O/S
-func checkAtimeSupport(dir string) (err error) {
-	file, err := ioutil.TempFile(dir, "prefix")
-	if err != nil {
-		return
-	}
-	defer os.Remove(file.Name())
-	finfo1, err := os.Stat(file.Name())
-	if err != nil {
-		return
-	}
-	if _, err = io.Copy(ioutil.Discard, file); err != io.EOF {
-		return
-	}
-
-	finfo2, err := os.Stat(file.Name())
-
-	if atime.Get(finfo2).Equal(atime.Get(finfo1)) {
-		return errors.New("Atime not supported")
-	}
-	return
-}
-
 // Returns cacheObjects for use by Server.
 func newServerCacheObjects(config CacheConfig) (CacheObjectLayer, error) {
 	// list of disk caches for cache "drives" specified in config.json or MINIO_CACHE_DRIVES env var.
@@ -943,10 +1494,27 @@ func newServerCacheObjects(config CacheConfig) (CacheObjectLayer, error) {
 		return nil, err
 	}
 
-	return &cacheObjects{
-		cache:    dcache,
-		exclude:  config.Exclude,
-		listPool: newTreeWalkPool(globalLookupTimeout),
+	quota, err := parseCacheQuotas(config.Quota)
+	if err != nil {
+		return nil, err
+	}
+	minSize, maxSize, err := parseCacheSizes(config.MinSize, config.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cacheObjects{
+		cache:      dcache,
+		exclude:    config.Exclude,
+		include:    config.Include,
+		quota:      quota,
+		minSize:    minSize,
+		maxSize:    maxSize,
+		admission:  newCacheAdmission(config.AfterHits),
+		writeBack:  config.Commit == cacheCommitWriteBack,
+		writeBacks: newCacheWriteBacks(),
+		cacheRange: bool(config.Range),
+		listPool:   newTreeWalkPool(globalLookupTimeout),
 		GetObjectFn: func(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
 			return newObjectLayerFn().GetObject(ctx, bucket, object, startOffset, length, writer, etag)
 		},
@@ -986,7 +1554,12 @@ func newServerCacheObjects(config CacheConfig) (CacheObjectLayer, error) {
 		DeleteBucketFn: func(ctx context.Context, bucket string) error {
 			return newObjectLayerFn().DeleteBucket(ctx, bucket)
 		},
-	}, nil
+	}
+
+	if c.writeBack {
+		go c.resumeWriteBacks()
+	}
+	return c, nil
 }
 
 type cacheControl struct {
@@ -1076,6 +1649,13 @@ func filterFromCache(m map[string]string) bool {
 	return c.exclude
 }
 
+// Returns true if object was committed to the cache in write-back mode
+// and is not uploaded to the backend yet.
+func isWriteBackPending(objInfo ObjectInfo) bool {
+	_, ok := objInfo.UserDefined[cacheWriteBackPendingKey]
+	return ok
+}
+
 // returns true if cache expiry conditions met in cache-control/expiry metadata.
 func isStaleCache(objInfo ObjectInfo) bool {
 	c, err := getCacheControlOpts(objInfo.UserDefined)
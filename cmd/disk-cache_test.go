@@ -19,7 +19,11 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"io"
+	"io/ioutil"
 	"reflect"
+	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,7 +32,7 @@ import (
 
 // Initialize cache FS objects.
 func initCacheFSObjects(disk string, cacheMaxUse int) (*cacheFSObjects, error) {
-	return newCacheFSObjects(disk, globalCacheExpiry, cacheMaxUse)
+	return newCacheFSObjects(disk, CacheConfig{Expiry: globalCacheExpiry, MaxUse: cacheMaxUse})
 }
 
 // inits diskCache struct for nDisks
@@ -162,6 +166,31 @@ func TestCacheExclusion(t *testing.T) {
 	}
 }
 
+// test wildcard patterns for including entries in cache
+func TestCacheInclusion(t *testing.T) {
+	testCases := []struct {
+		bucketName     string
+		objectName     string
+		include        []string
+		exclude        []string
+		expectedResult bool
+	}{
+		{"testbucket", "testobject", nil, nil, false},
+		{"testbucket", "testobject", []string{"testbucket/*"}, nil, false},
+		{"otherbucket", "testobject", []string{"testbucket/*"}, nil, true},
+		{"photos", "europe/paris/seine.jpg", []string{"*.png", "photos/europe/*"}, nil, false},
+		{"photos", "asia/tokyo/tower.jpg", []string{"*.png", "photos/europe/*"}, nil, true},
+		{"photos", "europe/paris/seine.jpg", []string{"photos/*"}, []string{"*.jpg"}, true},
+	}
+
+	for i, testCase := range testCases {
+		cobj := cacheObjects{include: testCase.include, exclude: testCase.exclude}
+		if cobj.isCacheExclude(testCase.bucketName, testCase.objectName) != testCase.expectedResult {
+			t.Errorf("Test %d: Expected %v", i+1, testCase.expectedResult)
+		}
+	}
+}
+
 // Test diskCache.
 func TestDiskCache(t *testing.T) {
 	fsDirs, err := getRandomDisks(1)
@@ -388,3 +417,470 @@ func TestFilterFromCache(t *testing.T) {
 		}
 	}
 }
+
+// returns cacheObjects backed by an FS backend, backend calls fail with
+// BackendDown while backendDown is set.
+func prepareCacheObjects(t *testing.T, config CacheConfig, backendDown *int32) (*cacheObjects, ObjectLayer, []string) {
+	backend, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Drives = cacheDirs
+	config.Expiry = 30
+	config.MaxUse = 100
+	dcache, err := newCache(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quota, err := parseCacheQuotas(config.Quota)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minSize, maxSize, err := parseCacheSizes(config.MinSize, config.MaxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &cacheObjects{
+		cache:      dcache,
+		include:    config.Include,
+		quota:      quota,
+		minSize:    minSize,
+		maxSize:    maxSize,
+		admission:  newCacheAdmission(config.AfterHits),
+		writeBack:  config.Commit == cacheCommitWriteBack,
+		writeBacks: newCacheWriteBacks(),
+		cacheRange: bool(config.Range),
+		listPool:   newTreeWalkPool(globalLookupTimeout),
+	}
+	c.GetBucketInfoFn = func(ctx context.Context, bucket string) (BucketInfo, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return BucketInfo{}, BackendDown{}
+		}
+		return backend.GetBucketInfo(ctx, bucket)
+	}
+	c.GetObjectInfoFn = func(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return ObjectInfo{}, BackendDown{}
+		}
+		return backend.GetObjectInfo(ctx, bucket, object)
+	}
+	c.GetObjectNInfoFn = func(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (ObjectInfo, io.ReadCloser, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return ObjectInfo{}, nil, BackendDown{}
+		}
+		return backend.GetObjectNInfo(ctx, bucket, object, rs)
+	}
+	c.GetObjectFn = func(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return BackendDown{}
+		}
+		return backend.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+	}
+	c.PutObjectFn = func(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (ObjectInfo, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return ObjectInfo{}, BackendDown{}
+		}
+		return backend.PutObject(ctx, bucket, object, data, metadata)
+	}
+	c.DeleteObjectFn = func(ctx context.Context, bucket, object string) error {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return BackendDown{}
+		}
+		return backend.DeleteObject(ctx, bucket, object)
+	}
+	c.ListObjectsFn = func(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return ListObjectsInfo{}, BackendDown{}
+		}
+		return backend.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	}
+	c.ListObjectsV2Fn = func(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (ListObjectsV2Info, error) {
+		if atomic.LoadInt32(backendDown) == 1 {
+			return ListObjectsV2Info{}, BackendDown{}
+		}
+		return backend.ListObjectsV2(ctx, bucket, prefix, continuationToken, delimiter, maxKeys, fetchOwner, startAfter)
+	}
+	return c, backend, append(cacheDirs, fsDir)
+}
+
+// Test uploading objects in write-back mode.
+func TestCacheWriteBack(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{Commit: cacheCommitWriteBack}, &backendDown)
+	defer removeRoots(dirs)
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uploads are committed to the cache while the backend is down.
+	atomic.StoreInt32(&backendDown, 1)
+	content := []byte("write-back object content")
+	for _, objectName := range []string{"uploaded", "deleted"} {
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		objInfo, err := c.PutObject(ctx, bucketName, objectName, hashReader, map[string]string{"content-type": "text/plain"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isWriteBackPending(objInfo) {
+			t.Fatal("Expected write-back status not to be returned")
+		}
+	}
+
+	objInfo, reader, err := c.GetObjectNInfo(ctx, bucketName, "uploaded", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) || objInfo.ContentType != "text/plain" {
+		t.Fatalf("Unexpected object served from cache: %s, %s", data, objInfo.ContentType)
+	}
+
+	// Deleting a pending object stops its upload.
+	atomic.StoreInt32(&backendDown, 0)
+	if err = c.DeleteObject(ctx, bucketName, "deleted"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.GetObjectInfo(ctx, bucketName, "deleted"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected deleted object not to be found, got %v", err)
+	}
+
+	// Pending objects are uploaded once the backend is back.
+	dcache := c.cache.cfs[0]
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		cachedObjInfo, cerr := dcache.GetObjectInfo(ctx, bucketName, "uploaded")
+		if cerr != nil {
+			t.Fatal(cerr)
+		}
+		if !isWriteBackPending(cachedObjInfo) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the object to be uploaded")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	bkObjInfo, err := backend.GetObjectInfo(ctx, bucketName, "uploaded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo, err = c.GetObjectInfo(ctx, bucketName, "uploaded"); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ETag != bkObjInfo.ETag || bkObjInfo.ContentType != "text/plain" {
+		t.Fatalf("Expected uploaded object %s, got %s", objInfo.ETag, bkObjInfo.ETag)
+	}
+	if _, err = backend.GetObjectInfo(ctx, bucketName, "deleted"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected deleted object not to be uploaded, got %v", err)
+	}
+}
+
+// Test caching of ranged reads.
+func TestCacheRange(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{Range: true}, &backendDown)
+	defer removeRoots(dirs)
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	objectName := "video"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bkObjInfo, err := backend.PutObject(ctx, bucketName, objectName, hashReader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readRange := func(rs *HTTPRangeSpec) []byte {
+		_, reader, rerr := c.GetObjectNInfo(ctx, bucketName, objectName, rs)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		defer reader.Close()
+		data, rerr := ioutil.ReadAll(reader)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		return data
+	}
+
+	// Ranges read from the backend are cached.
+	if data := readRange(&HTTPRangeSpec{Start: 10, End: 39}); !bytes.Equal(data, content[10:40]) {
+		t.Fatalf("Unexpected range %s", data)
+	}
+	writer := bytes.NewBuffer(nil)
+	if err = c.GetObject(ctx, bucketName, objectName, 60, 20, writer, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Bytes(), content[60:80]) {
+		t.Fatalf("Unexpected range %s", writer.Bytes())
+	}
+
+	dcache := c.cache.cfs[0]
+	if dcache.Exists(ctx, bucketName, objectName) {
+		t.Fatal("Expected partial reads not to cache the whole object")
+	}
+	if _, _, err = dcache.GetRange(ctx, bucketName, objectName, &HTTPRangeSpec{Start: 10, End: 39}, bkObjInfo.ETag); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cached ranges are served when the backend is down.
+	atomic.StoreInt32(&backendDown, 1)
+	if data := readRange(&HTTPRangeSpec{Start: 20, End: 29}); !bytes.Equal(data, content[20:30]) {
+		t.Fatalf("Unexpected cached range %s", data)
+	}
+	writer.Reset()
+	if err = c.GetObject(ctx, bucketName, objectName, 65, 10, writer, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Bytes(), content[65:75]) {
+		t.Fatalf("Unexpected cached range %s", writer.Bytes())
+	}
+	if _, _, err = c.GetObjectNInfo(ctx, bucketName, objectName, &HTTPRangeSpec{Start: 30, End: 49}); err == nil {
+		t.Fatal("Expected range not covered by a cached range to fail")
+	}
+	atomic.StoreInt32(&backendDown, 0)
+
+	// Cached ranges of a replaced object are not served.
+	if _, _, err = dcache.GetRange(ctx, bucketName, objectName, &HTTPRangeSpec{Start: 10, End: 19}, "replaced-etag"); err == nil {
+		t.Fatal("Expected ranges of another object version not to be served")
+	}
+	if err = c.DeleteObject(ctx, bucketName, objectName); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = dcache.GetRange(ctx, bucketName, objectName, &HTTPRangeSpec{Start: 10, End: 19}, ""); err == nil {
+		t.Fatal("Expected ranges of deleted object to be removed")
+	}
+}
+
+// Test cache admission rules for object sizes, reads and bucket quotas.
+func TestCacheRules(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{
+		Quota:     map[string]string{"quotabucket": "50B"},
+		MinSize:   "10B",
+		MaxSize:   "40B",
+		AfterHits: 1,
+	}, &backendDown)
+	defer removeRoots(dirs)
+
+	ctx := context.Background()
+	putObject := func(bucket, object string, size int) {
+		content := bytes.Repeat([]byte("a"), size)
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(size), "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = c.PutObject(ctx, bucket, object, hashReader, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readObject := func(bucket, object string) {
+		objInfo, err := backend.GetObjectInfo(ctx, bucket, object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = c.GetObject(ctx, bucket, object, 0, objInfo.Size, ioutil.Discard, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	isCached := func(bucket, object string) bool {
+		return c.cache.cfs[0].Exists(ctx, bucket, object)
+	}
+
+	for _, bucket := range []string{"testbucket", "quotabucket"} {
+		if err := backend.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Uploads outside of the size range are not cached.
+	putObject("testbucket", "small", 5)
+	putObject("testbucket", "large", 45)
+	putObject("testbucket", "medium", 20)
+	if isCached("testbucket", "small") || isCached("testbucket", "large") || !isCached("testbucket", "medium") {
+		t.Fatal("Expected only uploads within the size range to be cached")
+	}
+
+	// Reads are cached after the configured number of reads.
+	if err := c.DeleteObjectFn(ctx, "testbucket", "medium"); err != nil {
+		t.Fatal(err)
+	}
+	putObject("testbucket", "medium", 20)
+	if err := c.cache.cfs[0].Delete(ctx, "testbucket", "medium"); err != nil {
+		t.Fatal(err)
+	}
+	readObject("testbucket", "medium")
+	if isCached("testbucket", "medium") {
+		t.Fatal("Expected object not to be cached on the first read")
+	}
+	readObject("testbucket", "medium")
+	if !isCached("testbucket", "medium") {
+		t.Fatal("Expected object to be cached on the second read")
+	}
+
+	// Cached entries of a bucket are evicted to stay within its quota.
+	putObject("quotabucket", "object1", 20)
+	putObject("quotabucket", "object2", 20)
+	putObject("quotabucket", "object3", 20)
+	if isCached("quotabucket", "object1") || !isCached("quotabucket", "object2") || !isCached("quotabucket", "object3") {
+		t.Fatal("Expected least recently used object of the bucket to be evicted")
+	}
+	if usage := c.cache.bucketUsage("quotabucket"); usage != 40 {
+		t.Fatalf("Expected bucket usage of 40 bytes, got %d", usage)
+	}
+	if !isCached("testbucket", "medium") {
+		t.Fatal("Expected objects of other buckets not to be evicted")
+	}
+}
+
+// Test serializing uploads of objects committed in write-back mode.
+func TestCacheWriteBacks(t *testing.T) {
+	w := newCacheWriteBacks()
+	if !w.start("bucket", "object") {
+		t.Fatal("Expected first commit to be uploaded")
+	}
+	// Commits during an upload are uploaded after it.
+	if w.start("bucket", "object") {
+		t.Fatal("Expected commit during an upload not to be uploaded concurrently")
+	}
+	if !w.finish("bucket", "object", true) {
+		t.Fatal("Expected object committed again to be uploaded again")
+	}
+	if w.finish("bucket", "object", true) {
+		t.Fatal("Expected upload to be finished")
+	}
+	if objects := w.list("bucket", ""); len(objects) != 0 {
+		t.Fatalf("Expected no pending objects, got %v", objects)
+	}
+
+	// Objects which failed to upload are still pending.
+	w.start("bucket", "b")
+	w.start("bucket", "a")
+	if w.finish("bucket", "b", false) {
+		t.Fatal("Expected failed upload to be finished")
+	}
+	if objects := w.list("bucket", ""); !reflect.DeepEqual(objects, []string{"a", "b"}) {
+		t.Fatalf("Expected pending objects a and b, got %v", objects)
+	}
+	if !w.start("bucket", "b") {
+		t.Fatal("Expected failed object to be uploaded again once committed")
+	}
+}
+
+// Test listing objects pending upload in write-back mode.
+func TestCacheWriteBackList(t *testing.T) {
+	var backendDown int32
+	c, backend, dirs := prepareCacheObjects(t, CacheConfig{Commit: cacheCommitWriteBack}, &backendDown)
+	defer removeRoots(dirs)
+
+	ctx := context.Background()
+	bucketName := "testbucket"
+	if err := backend.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("content")
+	for _, objectName := range []string{"b", "d", "e/g"} {
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = backend.PutObject(ctx, bucketName, objectName, hashReader, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Commit objects to the cache without uploading them.
+	dcache := c.cache.cfs[0]
+	if err := dcache.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, objectName := range []string{"a", "c", "e/f"} {
+		hashReader, err := hash.NewReader(bytes.NewReader(content), int64(len(content)), "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = dcache.Put(ctx, bucketName, objectName, hashReader, map[string]string{cacheWriteBackPendingKey: "true"}); err != nil {
+			t.Fatal(err)
+		}
+		c.writeBacks.start(bucketName, objectName)
+	}
+
+	testCases := []struct {
+		delimiter string
+		expected  []string
+	}{
+		{"", []string{"a", "b", "c", "d", "e/f", "e/g"}},
+		{"/", []string{"a", "b", "c", "d", "e/"}},
+	}
+	for i, testCase := range testCases {
+		for _, maxKeys := range []int{1, 2, 1000} {
+			var names []string
+			marker := ""
+			for {
+				result, err := c.ListObjects(ctx, bucketName, "", marker, testCase.delimiter, maxKeys)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, objInfo := range result.Objects {
+					if isWriteBackPending(objInfo) {
+						t.Fatalf("Test %d: expected write-back status not to be listed", i+1)
+					}
+					names = append(names, objInfo.Name)
+				}
+				names = append(names, result.Prefixes...)
+				if !result.IsTruncated {
+					break
+				}
+				marker = result.NextMarker
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, testCase.expected) {
+				t.Fatalf("Test %d, max keys %d: expected %v, got %v", i+1, maxKeys, testCase.expected, names)
+			}
+
+			names = nil
+			token := ""
+			for {
+				result, err := c.ListObjectsV2(ctx, bucketName, "", token, testCase.delimiter, maxKeys, false, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, objInfo := range result.Objects {
+					names = append(names, objInfo.Name)
+				}
+				names = append(names, result.Prefixes...)
+				if !result.IsTruncated {
+					break
+				}
+				token = result.NextContinuationToken
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, testCase.expected) {
+				t.Fatalf("Test %d, max keys %d: expected %v, got %v with V2", i+1, maxKeys, testCase.expected, names)
+			}
+		}
+	}
+}
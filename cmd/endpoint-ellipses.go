@@ -263,20 +263,147 @@ func getAllSets(args ...string) ([][]string, error) {
 	return setArgs, nil
 }
 
+// ZoneEndpoints - endpoints of a server pool (zone), which is formatted
+// and placed into erasure coded sets independently of other zones.
+type ZoneEndpoints struct {
+	// CmdLine is the command line argument defining the zone, it
+	// identifies the zone across restarts.
+	CmdLine      string
+	SetCount     int
+	DrivesPerSet int
+	Endpoints    EndpointList
+}
+
+// EndpointZones - list of all zones of a deployment.
+type EndpointZones []ZoneEndpoints
+
+// Endpoints - returns endpoints of all zones as a single list.
+func (z EndpointZones) Endpoints() (endpoints EndpointList) {
+	for _, zone := range z {
+		endpoints = append(endpoints, zone.Endpoints...)
+	}
+	return endpoints
+}
+
+// zoneArgPrefix - prefix of command line arguments defining a server
+// pool (zone), e.g. "zone:http://host{1...4}/export{1...16}".
+const zoneArgPrefix = "zone:"
+
+// getZoneArgs - returns the zone arguments without their prefix, ok is
+// false if args do not define zones. Either all or no args define zones.
+func getZoneArgs(args []string) (zoneArgs []string, ok bool, err error) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, zoneArgPrefix) {
+			zoneArgs = append(zoneArgs, strings.TrimPrefix(arg, zoneArgPrefix))
+		}
+	}
+	if len(zoneArgs) == 0 {
+		return nil, false, nil
+	}
+	if len(zoneArgs) != len(args) {
+		return nil, false, uiErrInvalidErasureEndpoints(nil).Msg("All arguments should define zones when one does, using the prefix %s", zoneArgPrefix)
+	}
+	for _, arg := range zoneArgs {
+		if !ellipses.HasEllipses(arg) {
+			return nil, false, uiErrInvalidErasureEndpoints(nil).Msg("Zone argument %s should use ellipses", arg)
+		}
+	}
+	return zoneArgs, true, nil
+}
+
 // CreateServerEndpoints - validates and creates new endpoints from input args, supports
-// both ellipses and without ellipses transparently.
-func createServerEndpoints(serverAddr string, args ...string) (string, EndpointList, SetupType, int, int, error) {
-	setArgs, err := getAllSets(args...)
+// both ellipses and without ellipses transparently. Args with the "zone:" prefix form
+// independent zones, all zones must have the same number of drives per set. Otherwise
+// all args form a single zone.
+func createServerEndpoints(serverAddr string, args ...string) (string, EndpointZones, SetupType, error) {
+	if len(args) == 0 {
+		return serverAddr, nil, -1, errInvalidArgument
+	}
+
+	zoneArgs, ok, err := getZoneArgs(args)
 	if err != nil {
-		return serverAddr, nil, -1, 0, 0, err
+		return serverAddr, nil, -1, err
 	}
 
+	if !ok {
+		setArgs, err := getAllSets(args...)
+		if err != nil {
+			return serverAddr, nil, -1, err
+		}
+
+		var endpoints EndpointList
+		var setupType SetupType
+		serverAddr, endpoints, setupType, err = CreateEndpoints(serverAddr, setArgs...)
+		if err != nil {
+			return serverAddr, nil, -1, err
+		}
+
+		return serverAddr, EndpointZones{{
+			CmdLine:      strings.Join(args, " "),
+			SetCount:     len(setArgs),
+			DrivesPerSet: len(setArgs[0]),
+			Endpoints:    endpoints,
+		}}, setupType, nil
+	}
+	args = zoneArgs
+
+	// Look for duplicate disks across zones.
+	if _, err := getAllSets(args...); err != nil {
+		return serverAddr, nil, -1, err
+	}
+
+	var zoneSetArgs [][][]string
+	var allSetArgs [][]string
+	for _, arg := range args {
+		setArgs, err := getAllSets(arg)
+		if err != nil {
+			return serverAddr, nil, -1, err
+		}
+
+		if len(zoneSetArgs) > 0 && len(zoneSetArgs[0][0]) != len(setArgs[0]) {
+			return serverAddr, nil, -1, uiErrInvalidErasureEndpoints(nil).Msg(
+				"All zones should have same drives per set, expected %d, got %d", len(zoneSetArgs[0][0]), len(setArgs[0]))
+		}
+
+		zoneSetArgs = append(zoneSetArgs, setArgs)
+		allSetArgs = append(allSetArgs, setArgs...)
+	}
+
+	// Endpoints of all zones are validated together, as a server may
+	// not have any local endpoint in some of the zones.
 	var endpoints EndpointList
 	var setupType SetupType
-	serverAddr, endpoints, setupType, err = CreateEndpoints(serverAddr, setArgs...)
+	serverAddr, endpoints, setupType, err = CreateEndpoints(serverAddr, allSetArgs...)
 	if err != nil {
-		return serverAddr, nil, -1, 0, 0, err
+		return serverAddr, nil, -1, err
+	}
+
+	var zones EndpointZones
+	var offset, setOffset int
+	for i, setArgs := range zoneSetArgs {
+		zone := ZoneEndpoints{
+			CmdLine:      args[i],
+			SetCount:     len(setArgs),
+			DrivesPerSet: len(setArgs[0]),
+		}
+
+		if setupType == DistXLSetupType {
+			for _, endpoint := range endpoints[offset : offset+zone.SetCount*zone.DrivesPerSet] {
+				endpoint.SetIndex -= setOffset
+				zone.Endpoints = append(zone.Endpoints, endpoint)
+			}
+		} else {
+			// All endpoints are local, they are converted to path style
+			// endpoints losing their order, hence created per zone.
+			if _, zone.Endpoints, _, err = CreateEndpoints(serverAddr, setArgs...); err != nil {
+				return serverAddr, nil, -1, err
+			}
+		}
+
+		offset += zone.SetCount * zone.DrivesPerSet
+		setOffset += zone.SetCount
+		zones = append(zones, zone)
 	}
 
-	return serverAddr, endpoints, setupType, len(setArgs), len(setArgs[0]), nil
+	return serverAddr, zones, setupType, nil
 }
@@ -42,6 +42,14 @@ func TestCreateServerEndpoints(t *testing.T) {
 		{":9000", []string{"/export1{a...z}"}, false},
 		// Duplicate disks not allowed.
 		{":9000", []string{"/export1{1...32}", "/export1{1...32}"}, false},
+		// Zones with different drives per set not allowed.
+		{":9000", []string{"zone:/export1{1...16}", "zone:/export2{1...12}"}, false},
+		// Either all or no arguments define zones.
+		{":9000", []string{"zone:/export1{1...16}", "/export2{1...16}"}, false},
+		// Zone arguments need ellipses.
+		{":9000", []string{"zone:/export1", "zone:/export2"}, false},
+		// Duplicate disks across zones not allowed.
+		{":9000", []string{"zone:/export1{1...16}", "zone:/export1{1...16}"}, false},
 		// Same host cannot export same disk on two ports - special case localhost.
 		{":9001", []string{"http://localhost:900{1...2}/export{1...64}"}, false},
 
@@ -51,12 +59,14 @@ func TestCreateServerEndpoints(t *testing.T) {
 		{":9000", []string{"/export1{1...64}"}, true},
 		{":9000", []string{"/export1{01...64}"}, true},
 		{":9000", []string{"/export1{1...32}", "/export1{33...64}"}, true},
+		{":9000", []string{"/export1{1...16}", "/export2{1...32}"}, true},
+		{":9000", []string{"zone:/export1{1...16}", "zone:/export2{1...32}"}, true},
 		{":9001", []string{"http://localhost:9001/export{1...64}"}, true},
 		{":9001", []string{"http://localhost:9001/export{01...64}"}, true},
 	}
 
 	for i, testCase := range testCases {
-		_, _, _, _, _, err := createServerEndpoints(testCase.serverAddr, testCase.args...)
+		_, _, _, err := createServerEndpoints(testCase.serverAddr, testCase.args...)
 		if err != nil && testCase.success {
 			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
 		}
@@ -66,6 +76,43 @@ func TestCreateServerEndpoints(t *testing.T) {
 	}
 }
 
+// Tests each zone argument is created as a separate zone.
+func TestCreateServerEndpointZones(t *testing.T) {
+	// Multiple ellipses arguments without zone prefix form a single zone.
+	_, endpointZones, _, err := createServerEndpoints(":9000", "/export1{1...16}", "/export2{1...32}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpointZones) != 1 || endpointZones[0].CmdLine != "/export1{1...16} /export2{1...32}" {
+		t.Fatalf("Expected a single zone, got %v", endpointZones)
+	}
+
+	_, endpointZones, setupType, err := createServerEndpoints(":9000", "zone:/export1{1...16}", "zone:/export2{1...32}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if setupType != XLSetupType {
+		t.Fatalf("Expected setup type %s, got %s", XLSetupType, setupType)
+	}
+	if len(endpointZones) != 2 {
+		t.Fatalf("Expected 2 zones, got %d", len(endpointZones))
+	}
+	if endpointZones[0].CmdLine != "/export1{1...16}" || endpointZones[1].CmdLine != "/export2{1...32}" {
+		t.Errorf("Unexpected zone arguments %s, %s", endpointZones[0].CmdLine, endpointZones[1].CmdLine)
+	}
+	if endpointZones[0].SetCount != 1 || endpointZones[1].SetCount != 2 {
+		t.Errorf("Unexpected set count per zone %d, %d", endpointZones[0].SetCount, endpointZones[1].SetCount)
+	}
+	for i, zone := range endpointZones {
+		if zone.DrivesPerSet != 16 {
+			t.Errorf("Zone %d: expected 16 drives per set, got %d", i+1, zone.DrivesPerSet)
+		}
+	}
+	if len(endpointZones.Endpoints()) != 48 {
+		t.Errorf("Expected 48 endpoints, got %d", len(endpointZones.Endpoints()))
+	}
+}
+
 func TestGetDivisibleSize(t *testing.T) {
 	testCases := []struct {
 		totalSizes []uint64
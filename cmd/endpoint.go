@@ -502,8 +502,9 @@ func GetRemotePeers(endpoints EndpointList) []string {
 func updateDomainIPs(endPoints set.StringSet) {
 	_, dok := os.LookupEnv("MINIO_DOMAIN")
 	_, eok := os.LookupEnv("MINIO_ETCD_ENDPOINTS")
+	_, wok := os.LookupEnv("MINIO_DNS_WEBHOOK_ENDPOINT")
 	_, iok := os.LookupEnv("MINIO_PUBLIC_IPS")
-	if dok && eok && !iok {
+	if dok && (eok || wok) && !iok {
 		globalDomainIPs = set.NewStringSet()
 		for e := range endPoints {
 			host, _, _ := net.SplitHostPort(e)
@@ -0,0 +1,1028 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Listing indexes are saved in `.minio.sys/list-index/<bucket>/index.json`,
+	// `clean` is present next to the index if it was saved on shutdown.
+	fsListIndexPrefix    = "list-index"
+	fsListIndexFile      = "index.json"
+	fsListIndexCleanFile = "clean"
+
+	// Current version of the listing index format.
+	fsListIndexVersion = "2"
+
+	// Maximum size of a single listing index record.
+	fsListIndexMaxRecordSize = 1 << 20
+
+	// Every fsListIndexBlockSize-th name of an index is kept in
+	// memory with its offset to seek into the index.
+	fsListIndexBlockSize = 256
+
+	// Updates are kept in memory until they outgrow an eighth of
+	// the index, but at least fsListIndexMinUpdates of them. They
+	// are merged into the index on disk in the background.
+	fsListIndexMinUpdates = 10000
+
+	// Updated names are kept sorted in chunks of this size.
+	fsListIndexChunkSize = 512
+)
+
+var (
+	errFSListIndexInconsistent = errors.New("listing index is inconsistent")
+	errFSListIndexShared       = errors.New("listing index cannot be enabled on a shared backend")
+)
+
+// fsListIndexHeader - first record of a listing index.
+type fsListIndexHeader struct {
+	Version string `json:"version"`
+}
+
+// fsListIndexEntry - a listed object, the index holds entries sorted
+// by name after the header.
+type fsListIndexEntry struct {
+	Name            string            `json:"name"`
+	Size            int64             `json:"size,omitempty"`
+	ModTime         time.Time         `json:"modTime"`
+	ETag            string            `json:"etag,omitempty"`
+	ContentType     string            `json:"contentType,omitempty"`
+	ContentEncoding string            `json:"contentEncoding,omitempty"`
+	StorageClass    string            `json:"storageClass,omitempty"`
+	UserDefined     map[string]string `json:"userDefined,omitempty"`
+	IsDir           bool              `json:"isDir,omitempty"`
+	Deleted         bool              `json:"deleted,omitempty"`
+}
+
+func newFSListIndexEntry(objInfo ObjectInfo) fsListIndexEntry {
+	return fsListIndexEntry{
+		Name:            objInfo.Name,
+		Size:            objInfo.Size,
+		ModTime:         objInfo.ModTime,
+		ETag:            objInfo.ETag,
+		ContentType:     objInfo.ContentType,
+		ContentEncoding: objInfo.ContentEncoding,
+		StorageClass:    objInfo.StorageClass,
+		UserDefined:     objInfo.UserDefined,
+		IsDir:           objInfo.IsDir,
+	}
+}
+
+// ToObjectInfo - converts the entry into ObjectInfo.
+func (e fsListIndexEntry) ToObjectInfo(bucket string) ObjectInfo {
+	return ObjectInfo{
+		Bucket:          bucket,
+		Name:            e.Name,
+		Size:            e.Size,
+		ModTime:         e.ModTime,
+		ETag:            e.ETag,
+		ContentType:     e.ContentType,
+		ContentEncoding: e.ContentEncoding,
+		StorageClass:    e.StorageClass,
+		UserDefined:     e.UserDefined,
+		IsDir:           e.IsDir,
+	}
+}
+
+// fsListIndexUpdates - updates not merged into the index on disk yet,
+// deleted entries are kept as well. Names are kept sorted in chunks so
+// that an update does not shift all names.
+type fsListIndexUpdates struct {
+	chunks  [][]string
+	entries map[string]fsListIndexEntry
+}
+
+func newFSListIndexUpdates() *fsListIndexUpdates {
+	return &fsListIndexUpdates{entries: make(map[string]fsListIndexEntry)}
+}
+
+// search - returns the position of the first name not less than name.
+func (u *fsListIndexUpdates) search(name string) (c, i int) {
+	c = sort.Search(len(u.chunks), func(k int) bool {
+		chunk := u.chunks[k]
+		return chunk[len(chunk)-1] >= name
+	})
+	if c == len(u.chunks) {
+		return c, 0
+	}
+	return c, sort.SearchStrings(u.chunks[c], name)
+}
+
+func (u *fsListIndexUpdates) set(e fsListIndexEntry) {
+	if _, ok := u.entries[e.Name]; !ok {
+		c, i := u.search(e.Name)
+		if c == len(u.chunks) {
+			// Name is appended to the last chunk.
+			if c == 0 {
+				u.chunks = append(u.chunks, nil)
+			} else {
+				c--
+			}
+			i = len(u.chunks[c])
+		}
+		chunk := append(u.chunks[c], "")
+		copy(chunk[i+1:], chunk[i:])
+		chunk[i] = e.Name
+		if len(chunk) > 2*fsListIndexChunkSize {
+			tail := append([]string(nil), chunk[fsListIndexChunkSize:]...)
+			chunk = chunk[:fsListIndexChunkSize:fsListIndexChunkSize]
+			u.chunks = append(u.chunks, nil)
+			copy(u.chunks[c+2:], u.chunks[c+1:])
+			u.chunks[c+1] = tail
+		}
+		u.chunks[c] = chunk
+	}
+	u.entries[e.Name] = e
+}
+
+// fsListIndexBlock - position of a name in an index on disk.
+type fsListIndexBlock struct {
+	name   string
+	offset int64
+}
+
+// fsListIndexReader - reads the entries of an index on disk in order.
+type fsListIndexReader struct {
+	f       *os.File
+	scanner *bufio.Scanner
+	entry   fsListIndexEntry
+	valid   bool
+	err     error
+}
+
+// seek - positions the reader at the entry starting at offset.
+func (r *fsListIndexReader) seek(offset int64) {
+	if _, r.err = r.f.Seek(offset, io.SeekStart); r.err != nil {
+		r.valid = false
+		return
+	}
+	r.scanner = bufio.NewScanner(r.f)
+	r.scanner.Buffer(make([]byte, 4096), fsListIndexMaxRecordSize)
+	r.next()
+}
+
+// seekTo - positions the reader at the first entry not less than
+// name, the reader only moves forward.
+func (r *fsListIndexReader) seekTo(blocks []fsListIndexBlock, name string) {
+	k := sort.Search(len(blocks), func(k int) bool { return blocks[k].name > name }) - 1
+	if k < 0 {
+		k = 0
+	}
+	if r.scanner == nil || (r.valid && blocks[k].name > r.entry.Name) {
+		r.seek(blocks[k].offset)
+	}
+	for r.valid && r.entry.Name < name {
+		r.next()
+	}
+}
+
+func (r *fsListIndexReader) next() {
+	r.valid = false
+	if r.err != nil || !r.scanner.Scan() {
+		if r.err == nil {
+			r.err = r.scanner.Err()
+		}
+		return
+	}
+	r.entry = fsListIndexEntry{}
+	if r.err = json.Unmarshal(r.scanner.Bytes(), &r.entry); r.err == nil {
+		r.valid = true
+	}
+}
+
+func (r *fsListIndexReader) Close() {
+	if r.f != nil {
+		r.f.Close()
+	}
+}
+
+// fsListIndexWriter - writes a sorted index to a temporary file.
+type fsListIndexWriter struct {
+	tmpPath string
+	f       *os.File
+	w       *bufio.Writer
+	offset  int64
+
+	blocks []fsListIndexBlock
+	count  int
+	dirs   map[string]struct{}
+}
+
+func newFSListIndexWriter(tmpDir string) (*fsListIndexWriter, error) {
+	tmpPath := pathJoin(tmpDir, mustGetUUID())
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := &fsListIndexWriter{
+		tmpPath: tmpPath,
+		f:       f,
+		w:       bufio.NewWriter(f),
+		dirs:    make(map[string]struct{}),
+	}
+	if err = w.writeRecord(fsListIndexHeader{Version: fsListIndexVersion}); err != nil {
+		w.abort()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *fsListIndexWriter) writeRecord(v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	n, err := w.w.Write(append(buf, '\n'))
+	w.offset += int64(n)
+	return err
+}
+
+func (w *fsListIndexWriter) write(e fsListIndexEntry) error {
+	if w.count%fsListIndexBlockSize == 0 {
+		w.blocks = append(w.blocks, fsListIndexBlock{e.Name, w.offset})
+	}
+	if e.IsDir {
+		w.dirs[e.Name] = struct{}{}
+	}
+	w.count++
+	return w.writeRecord(e)
+}
+
+// commit - moves the written index to indexPath.
+func (w *fsListIndexWriter) commit(indexPath string) error {
+	err := w.w.Flush()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = renameAll(w.tmpPath, indexPath)
+	}
+	if err != nil {
+		os.Remove(w.tmpPath)
+	}
+	return err
+}
+
+func (w *fsListIndexWriter) abort() {
+	w.f.Close()
+	os.Remove(w.tmpPath)
+}
+
+// fsBucketListIndex - listing index of a single bucket, holds objects
+// and empty directories just like a tree walk would list them. Entries
+// are read from the sorted index on disk merged with recent updates.
+type fsBucketListIndex struct {
+	sync.RWMutex
+
+	bucket    string
+	indexPath string
+
+	// Layout of the index on disk.
+	blocks []fsListIndexBlock
+	count  int
+	dirs   map[string]struct{}
+
+	// Updates received while merging is in progress are recorded in
+	// updates, merging holds the updates being merged into the index
+	// on disk. mergeDoneCh is closed once the merge is finished.
+	updates     *fsListIndexUpdates
+	merging     *fsListIndexUpdates
+	mergeDoneCh chan struct{}
+
+	// Set once the index on disk is known to match the bucket,
+	// listings walk the bucket until then.
+	ready bool
+
+	// Set once the bucket is deleted or the server shuts down.
+	closed bool
+}
+
+// setIndex - replaces the layout of the index on disk.
+func (b *fsBucketListIndex) setIndex(blocks []fsListIndexBlock, count int, dirs map[string]struct{}) {
+	b.blocks, b.count, b.dirs = blocks, count, dirs
+}
+
+// openIndex - returns a reader of the index on disk positioned at the
+// first entry not less than name.
+func (b *fsBucketListIndex) openIndex(name string) (*fsListIndexReader, error) {
+	r := &fsListIndexReader{}
+	if b.count == 0 {
+		return r, nil
+	}
+	f, err := os.Open(b.indexPath)
+	if err != nil {
+		return nil, err
+	}
+	r.f = f
+	r.seekTo(b.blocks, name)
+	if r.err != nil {
+		r.Close()
+		return nil, r.err
+	}
+	return r, nil
+}
+
+// fsListIndexCursor - position of an iterator in the updates.
+type fsListIndexCursor struct {
+	updates *fsListIndexUpdates
+	c, i    int
+}
+
+func (cur *fsListIndexCursor) name() (string, bool) {
+	if cur.c == len(cur.updates.chunks) {
+		return "", false
+	}
+	return cur.updates.chunks[cur.c][cur.i], true
+}
+
+func (cur *fsListIndexCursor) next() {
+	if cur.i++; cur.i == len(cur.updates.chunks[cur.c]) {
+		cur.c, cur.i = cur.c+1, 0
+	}
+}
+
+// fsListIndexIterator - iterates over the index on disk merged with
+// the updates being merged and the recent updates, deleted entries
+// are skipped.
+type fsListIndexIterator struct {
+	blocks  []fsListIndexBlock
+	reader  *fsListIndexReader
+	cursors []*fsListIndexCursor
+}
+
+func (b *fsBucketListIndex) newIterator(name string) (*fsListIndexIterator, error) {
+	reader, err := b.openIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	it := &fsListIndexIterator{blocks: b.blocks, reader: reader}
+	// Cursors are ordered from older to more recent updates.
+	for _, updates := range []*fsListIndexUpdates{b.merging, b.updates} {
+		if updates != nil {
+			cur := &fsListIndexCursor{updates: updates}
+			cur.c, cur.i = updates.search(name)
+			it.cursors = append(it.cursors, cur)
+		}
+	}
+	return it, nil
+}
+
+// seek - moves forward to the first entry not less than name.
+func (it *fsListIndexIterator) seek(name string) {
+	if it.reader.f != nil {
+		it.reader.seekTo(it.blocks, name)
+	}
+	for _, cur := range it.cursors {
+		if c, i := cur.updates.search(name); c > cur.c || (c == cur.c && i > cur.i) {
+			cur.c, cur.i = c, i
+		}
+	}
+}
+
+// next - returns the next entry, false at the end.
+func (it *fsListIndexIterator) next() (fsListIndexEntry, bool, error) {
+	for {
+		name, found := "", false
+		if it.reader.valid {
+			name, found = it.reader.entry.Name, true
+		}
+		for _, cur := range it.cursors {
+			if updated, ok := cur.name(); ok && (!found || updated < name) {
+				name, found = updated, true
+			}
+		}
+		if !found {
+			return fsListIndexEntry{}, false, it.reader.err
+		}
+
+		// More recent updates replace older updates and the
+		// entries on disk.
+		var e fsListIndexEntry
+		var updated bool
+		if it.reader.valid && it.reader.entry.Name == name {
+			e = it.reader.entry
+			it.reader.next()
+		}
+		for _, cur := range it.cursors {
+			if n, ok := cur.name(); ok && n == name {
+				e, updated = cur.updates.entries[name], true
+				cur.next()
+			}
+		}
+		if !updated || !e.Deleted {
+			return e, true, nil
+		}
+	}
+}
+
+func (it *fsListIndexIterator) Close() {
+	it.reader.Close()
+}
+
+// Returns true if there are entries below the directory.
+func (b *fsBucketListIndex) hasChildren(dir string) (bool, error) {
+	it, err := b.newIterator(dir)
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	for {
+		e, ok, err := it.next()
+		if err != nil || !ok {
+			return false, err
+		}
+		if e.Name != dir {
+			return hasPrefix(e.Name, dir), nil
+		}
+	}
+}
+
+// isDir - returns true if the name is listed as an empty directory.
+func (b *fsBucketListIndex) isDir(name string) bool {
+	if e, ok := b.updates.entries[name]; ok {
+		return e.IsDir && !e.Deleted
+	}
+	if b.merging != nil {
+		if e, ok := b.merging.entries[name]; ok {
+			return e.IsDir && !e.Deleted
+		}
+	}
+	_, ok := b.dirs[name]
+	return ok
+}
+
+// apply - applies an update to the index, directories are only listed
+// while they are empty.
+func (b *fsBucketListIndex) apply(e fsListIndexEntry) error {
+	if e.Deleted {
+		b.updates.set(e)
+		return nil
+	}
+	if e.IsDir {
+		hasChildren, err := b.hasChildren(e.Name)
+		if err != nil || hasChildren {
+			return err
+		}
+		b.updates.set(e)
+		return nil
+	}
+	for i := strings.Index(e.Name, slashSeparator); i >= 0; {
+		dir := e.Name[:i+1]
+		if b.isDir(dir) {
+			b.updates.set(fsListIndexEntry{Name: dir, IsDir: true, Deleted: true})
+		}
+		j := strings.Index(e.Name[i+1:], slashSeparator)
+		if j < 0 {
+			break
+		}
+		i += j + 1
+	}
+	b.updates.set(e)
+	return nil
+}
+
+// startMerge - freezes the updates to be merged into the index on disk,
+// returns an iterator over the index merged with the frozen updates.
+// Updates received afterwards are recorded on top of the frozen ones.
+func (b *fsBucketListIndex) startMerge() (*fsListIndexIterator, error) {
+	it, err := b.newIterator("")
+	if err != nil {
+		return nil, err
+	}
+	b.merging = b.updates
+	b.updates = newFSListIndexUpdates()
+	return it, nil
+}
+
+// finishMerge - swaps in the merged index written by w, the frozen
+// updates are restored underneath the recent ones if merging failed.
+func (b *fsBucketListIndex) finishMerge(w *fsListIndexWriter, err error) error {
+	if err == nil {
+		err = w.commit(b.indexPath)
+	}
+	if err != nil {
+		updates := b.merging
+		for _, chunk := range b.updates.chunks {
+			for _, name := range chunk {
+				updates.set(b.updates.entries[name])
+			}
+		}
+		b.updates = updates
+		b.merging = nil
+		return err
+	}
+	b.setIndex(w.blocks, w.count, w.dirs)
+	b.merging = nil
+	return nil
+}
+
+// writeFSListIndex - writes all entries of the iterator to a new index,
+// the iterator is closed.
+func writeFSListIndex(tmpDir string, it *fsListIndexIterator) (*fsListIndexWriter, error) {
+	defer it.Close()
+
+	w, err := newFSListIndexWriter(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		e, ok, err := it.next()
+		if err == nil && ok {
+			err = w.write(e)
+		}
+		if err != nil {
+			w.abort()
+			return nil, err
+		}
+		if !ok {
+			return w, nil
+		}
+	}
+}
+
+// merge - writes the index merged with the updates to disk while
+// holding the lock of the index.
+func (b *fsBucketListIndex) merge(tmpDir string) error {
+	it, err := b.startMerge()
+	if err != nil {
+		return err
+	}
+	w, err := writeFSListIndex(tmpDir, it)
+	return b.finishMerge(w, err)
+}
+
+// reapply - applies the updates again on top of a rebuilt index, the
+// directories listed as empty may have changed meanwhile.
+func (b *fsBucketListIndex) reapply() error {
+	updates := b.updates
+	b.updates = newFSListIndexUpdates()
+	for _, chunk := range updates.chunks {
+		for _, name := range chunk {
+			if err := b.apply(updates.entries[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// scanFSListIndex - reads the layout of an index on disk.
+func scanFSListIndex(indexPath string) (blocks []fsListIndexBlock, count int, dirs map[string]struct{}, err error) {
+	f, err := os.Open(indexPath)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 4096), fsListIndexMaxRecordSize)
+	if !scanner.Scan() {
+		return nil, 0, nil, errFSListIndexInconsistent
+	}
+	var header fsListIndexHeader
+	if err = json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, 0, nil, err
+	}
+	if header.Version != fsListIndexVersion {
+		return nil, 0, nil, errFSListIndexInconsistent
+	}
+
+	dirs = make(map[string]struct{})
+	offset := int64(len(scanner.Bytes()) + 1)
+	var prevName string
+	for scanner.Scan() {
+		var e fsListIndexEntry
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, 0, nil, err
+		}
+		if count > 0 && e.Name <= prevName {
+			return nil, 0, nil, errFSListIndexInconsistent
+		}
+		if count%fsListIndexBlockSize == 0 {
+			blocks = append(blocks, fsListIndexBlock{e.Name, offset})
+		}
+		if e.IsDir {
+			dirs[e.Name] = struct{}{}
+		}
+		offset += int64(len(scanner.Bytes()) + 1)
+		prevName = e.Name
+		count++
+	}
+	return blocks, count, dirs, scanner.Err()
+}
+
+// list - lists the index similar to a tree walk.
+func (b *fsBucketListIndex) list(prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	start := prefix
+	if marker > start {
+		start = marker
+	}
+	it, err := b.newIterator(start)
+	if err != nil {
+		return result, err
+	}
+	defer it.Close()
+
+	count := 0
+	for {
+		e, ok, err := it.next()
+		if err != nil {
+			return result, err
+		}
+		if !ok || !hasPrefix(e.Name, prefix) {
+			break
+		}
+		if e.Name <= marker || (e.Name == prefix && e.IsDir) {
+			// Directory of the prefix itself is not listed.
+			continue
+		}
+
+		commonPrefix := ""
+		if delimiter == slashSeparator {
+			if j := strings.Index(e.Name[len(prefix):], slashSeparator); j >= 0 {
+				commonPrefix = e.Name[:len(prefix)+j+1]
+				// Skip all the entries below the common prefix.
+				it.seek(commonPrefix[:len(commonPrefix)-1] + string(commonPrefix[len(commonPrefix)-1]+1))
+				if commonPrefix <= marker {
+					continue
+				}
+			}
+		}
+
+		if count == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		if commonPrefix != "" {
+			result.Prefixes = append(result.Prefixes, commonPrefix)
+			result.NextMarker = commonPrefix
+		} else {
+			result.Objects = append(result.Objects, e.ToObjectInfo(b.bucket))
+			result.NextMarker = e.Name
+		}
+		count++
+	}
+	return result, nil
+}
+
+// fsListIndex - persistent listing indexes of all buckets, kept up
+// to date by all object operations of the FS backend.
+type fsListIndex struct {
+	sync.Mutex
+	fs      *FSObjects
+	buckets map[string]*fsBucketListIndex
+}
+
+// newFSListIndex - loads the listing index of every bucket. Indexes are
+// verified against the buckets in the background, indexes which were
+// not saved on shutdown or do not match their bucket are rebuilt.
+func newFSListIndex(ctx context.Context, fs *FSObjects) (*fsListIndex, error) {
+	buckets, err := fs.ListBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &fsListIndex{
+		fs:      fs,
+		buckets: make(map[string]*fsBucketListIndex),
+	}
+	for _, bucket := range buckets {
+		l.buckets[bucket.Name] = l.newBucketIndex(bucket.Name)
+	}
+
+	go func() {
+		for _, bucket := range buckets {
+			l.load(ctx, l.buckets[bucket.Name])
+		}
+	}()
+
+	return l, nil
+}
+
+func (l *fsListIndex) tmpDir() string {
+	return pathJoin(l.fs.fsPath, minioMetaTmpBucket, l.fs.fsUUID)
+}
+
+func (l *fsListIndex) bucketDir(bucket string) string {
+	return pathJoin(l.fs.fsPath, minioMetaBucket, fsListIndexPrefix, bucket)
+}
+
+func (l *fsListIndex) newBucketIndex(bucket string) *fsBucketListIndex {
+	return &fsBucketListIndex{
+		bucket:    bucket,
+		indexPath: pathJoin(l.bucketDir(bucket), fsListIndexFile),
+		dirs:      make(map[string]struct{}),
+		updates:   newFSListIndexUpdates(),
+	}
+}
+
+// load - loads an index saved on shutdown once it is verified against
+// the bucket, the index is rebuilt otherwise.
+func (l *fsListIndex) load(ctx context.Context, b *fsBucketListIndex) {
+	// The index is not reliable anymore once it is updated.
+	cleanPath := pathJoin(l.bucketDir(b.bucket), fsListIndexCleanFile)
+	err := os.Remove(cleanPath)
+	if err == nil {
+		var blocks []fsListIndexBlock
+		var count int
+		var dirs map[string]struct{}
+		if blocks, count, dirs, err = scanFSListIndex(b.indexPath); err == nil {
+			b.Lock()
+			b.setIndex(blocks, count, dirs)
+			b.Unlock()
+			err = l.verify(ctx, b)
+		}
+	}
+	if err != nil && !os.IsNotExist(err) {
+		logger.GetReqInfo(ctx).AppendTags("bucket", b.bucket)
+		logger.LogIf(ctx, err)
+	}
+	if err != nil {
+		l.rebuild(ctx, b)
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	b.ready = !b.closed
+}
+
+// verify - walks the bucket and checks that the index on disk lists the
+// same objects with the same size and modification time.
+func (l *fsListIndex) verify(ctx context.Context, b *fsBucketListIndex) error {
+	b.RLock()
+	reader, err := b.openIndex("")
+	b.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+
+	walkResultCh := l.fs.startTreeWalk(ctx, b.bucket, "", "", true, endWalkCh)
+	for walkResult := range walkResultCh {
+		if walkResult.err != nil {
+			// File not found is returned for empty buckets.
+			if walkResult.err == errFileNotFound {
+				break
+			}
+			return walkResult.err
+		}
+		if !reader.valid || reader.entry.Name != walkResult.entry {
+			return errFSListIndexInconsistent
+		}
+		fi, err := os.Stat(pathJoin(l.fs.fsPath, b.bucket, walkResult.entry))
+		if err != nil {
+			return err
+		}
+		e := reader.entry
+		if e.IsDir != fi.IsDir() || (!e.IsDir && (e.Size != fi.Size() || !e.ModTime.Equal(fi.ModTime()))) {
+			return errFSListIndexInconsistent
+		}
+		reader.next()
+		if walkResult.end {
+			break
+		}
+	}
+	if reader.valid {
+		return errFSListIndexInconsistent
+	}
+	return reader.err
+}
+
+// rebuild - walks the bucket to rebuild its index, updates received
+// meanwhile are applied on top of the rebuilt index.
+func (l *fsListIndex) rebuild(ctx context.Context, b *fsBucketListIndex) {
+	logError := func(err error) {
+		logger.GetReqInfo(ctx).AppendTags("bucket", b.bucket)
+		logger.LogIf(ctx, err)
+	}
+
+	w, err := newFSListIndexWriter(l.tmpDir())
+	if err != nil {
+		logError(err)
+		return
+	}
+
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+
+	walkResultCh := l.fs.startTreeWalk(ctx, b.bucket, "", "", true, endWalkCh)
+	for walkResult := range walkResultCh {
+		if walkResult.err != nil {
+			// File not found is returned for empty buckets.
+			if walkResult.err == errFileNotFound {
+				break
+			}
+			w.abort()
+			logError(walkResult.err)
+			return
+		}
+		objInfo, err := l.fs.getObjectInfo(ctx, b.bucket, walkResult.entry)
+		if err == nil {
+			if err = w.write(newFSListIndexEntry(objInfo)); err != nil {
+				w.abort()
+				logError(err)
+				return
+			}
+		}
+		if walkResult.end {
+			break
+		}
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if b.closed {
+		w.abort()
+		return
+	}
+	if err = w.commit(b.indexPath); err != nil {
+		logError(err)
+		return
+	}
+	b.setIndex(w.blocks, w.count, w.dirs)
+	if err = b.reapply(); err != nil {
+		logError(err)
+		return
+	}
+	b.ready = true
+}
+
+func (l *fsListIndex) getBucket(bucket string) *fsBucketListIndex {
+	l.Lock()
+	defer l.Unlock()
+	return l.buckets[bucket]
+}
+
+// update - records an update of the bucket, updates are merged into the
+// index on disk in the background once they outgrow an eighth of it.
+func (l *fsListIndex) update(ctx context.Context, bucket string, e fsListIndexEntry) {
+	b := l.getBucket(bucket)
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	if b.closed {
+		return
+	}
+
+	err := b.apply(e)
+	if err == nil && b.ready && b.merging == nil &&
+		len(b.updates.entries) > fsListIndexMinUpdates && len(b.updates.entries) > b.count/8 {
+		var it *fsListIndexIterator
+		if it, err = b.startMerge(); err == nil {
+			b.mergeDoneCh = make(chan struct{})
+			go l.mergeInBackground(b, it)
+		}
+	}
+	if err != nil {
+		logger.GetReqInfo(ctx).AppendTags("bucket", bucket)
+		logger.LogIf(ctx, err)
+	}
+}
+
+// mergeInBackground - writes the index merged with the frozen updates
+// without holding the lock of the index, the lock is only taken to swap
+// in the merged index.
+func (l *fsListIndex) mergeInBackground(b *fsBucketListIndex, it *fsListIndexIterator) {
+	w, err := writeFSListIndex(l.tmpDir(), it)
+
+	b.Lock()
+	defer b.Unlock()
+	defer func() {
+		close(b.mergeDoneCh)
+		b.mergeDoneCh = nil
+	}()
+
+	if b.closed {
+		if w != nil {
+			w.abort()
+		}
+		return
+	}
+	if err = b.finishMerge(w, err); err != nil {
+		ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{BucketName: b.bucket})
+		logger.LogIf(ctx, err)
+	}
+}
+
+// Put - records a new or overwritten object.
+func (l *fsListIndex) Put(ctx context.Context, bucket string, objInfo ObjectInfo) {
+	l.update(ctx, bucket, newFSListIndexEntry(objInfo))
+}
+
+// Delete - records a deleted object.
+func (l *fsListIndex) Delete(ctx context.Context, bucket, object string) {
+	l.update(ctx, bucket, fsListIndexEntry{Name: object, Deleted: true})
+}
+
+// List - lists the bucket from its index, returns false if the index
+// is not available.
+func (l *fsListIndex) List(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, bool) {
+	b := l.getBucket(bucket)
+	if b == nil {
+		return ListObjectsInfo{}, false
+	}
+	b.RLock()
+	defer b.RUnlock()
+	if !b.ready {
+		return ListObjectsInfo{}, false
+	}
+	result, err := b.list(prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		logger.GetReqInfo(ctx).AppendTags("bucket", bucket)
+		logger.LogIf(ctx, err)
+		return ListObjectsInfo{}, false
+	}
+	return result, true
+}
+
+// MakeBucket - starts an empty index for a new bucket.
+func (l *fsListIndex) MakeBucket(ctx context.Context, bucket string) error {
+	b := l.newBucketIndex(bucket)
+	b.ready = true
+
+	l.Lock()
+	defer l.Unlock()
+	l.buckets[bucket] = b
+	return nil
+}
+
+// DeleteBucket - removes the index of a deleted bucket.
+func (l *fsListIndex) DeleteBucket(ctx context.Context, bucket string) error {
+	l.Lock()
+	b := l.buckets[bucket]
+	delete(l.buckets, bucket)
+	l.Unlock()
+
+	if b != nil {
+		b.Lock()
+		b.closed = true
+		b.Unlock()
+	}
+	return fsRemoveAll(ctx, l.bucketDir(bucket))
+}
+
+// Shutdown - saves all indexes which are ready and marks them clean.
+func (l *fsListIndex) Shutdown(ctx context.Context) {
+	l.Lock()
+	defer l.Unlock()
+	for _, b := range l.buckets {
+		b.Lock()
+		// Wait for the merge in progress, if any.
+		for b.mergeDoneCh != nil {
+			doneCh := b.mergeDoneCh
+			b.Unlock()
+			<-doneCh
+			b.Lock()
+		}
+		if b.ready && !b.closed {
+			err := b.merge(l.tmpDir())
+			if err == nil {
+				var f *os.File
+				if f, err = os.Create(pathJoin(l.bucketDir(b.bucket), fsListIndexCleanFile)); err == nil {
+					err = f.Close()
+				}
+			}
+			if err != nil {
+				logger.GetReqInfo(ctx).AppendTags("bucket", b.bucket)
+				logger.LogIf(ctx, err)
+			}
+		}
+		b.closed = true
+		b.Unlock()
+	}
+}
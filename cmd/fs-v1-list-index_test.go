@@ -0,0 +1,389 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Returns names of the listed prefixes and objects.
+func listResultNames(result ListObjectsInfo) []string {
+	var names []string
+	names = append(names, result.Prefixes...)
+	for _, objInfo := range result.Objects {
+		names = append(names, objInfo.Name)
+	}
+	return names
+}
+
+// Waits for the bucket index to be ready.
+func waitFSListIndex(t *testing.T, fs *FSObjects, bucket string) {
+	for i := 0; i < 100; i++ {
+		if _, ok := fs.listIndex.List(context.Background(), bucket, "", "", "", 1); ok {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Listing index of %s is not ready", bucket)
+}
+
+// Tests that listing from the index matches a tree walk.
+func TestFSListIndex(t *testing.T) {
+	globalFSListIndexEnabled = true
+	defer func() { globalFSListIndexEnabled = false }()
+
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer os.RemoveAll(disk)
+
+	obj := initFSObjects(disk, t)
+	fs := obj.(*FSObjects)
+	ctx := context.Background()
+	bucketName := "bucket"
+	if err := obj.MakeBucketWithLocation(ctx, bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, object := range []string{"a/b/c", "a/d", "a-b", "e", "empty/", "f/", "f/g", "h/i/j/k"} {
+		var data []byte
+		if !hasSuffix(object, slashSeparator) {
+			data = []byte(object)
+		}
+		metadata := map[string]string{"x-amz-meta-name": object}
+		if _, err := obj.PutObject(ctx, bucketName, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), metadata); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := obj.DeleteObject(ctx, bucketName, "h/i/j/k"); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := obj.NewMultipartUpload(ctx, bucketName, "m/n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md5Hex := getMD5Hash([]byte("m/n"))
+	if _, err = obj.PutObjectPart(ctx, bucketName, "m/n", uploadID, 1, mustGetHashReader(t, bytes.NewReader([]byte("m/n")), 3, md5Hex, "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.CompleteMultipartUpload(ctx, bucketName, "m/n", uploadID, []CompletePart{{PartNumber: 1, ETag: md5Hex}}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		prefix, marker, delimiter string
+		maxKeys                   int
+	}{
+		{"", "", "", 1000},
+		{"", "", "/", 1000},
+		{"", "", "", 3},
+		{"", "a/d", "", 3},
+		{"", "", "/", 2},
+		{"", "a/", "/", 2},
+		{"a/", "", "/", 1000},
+		{"a", "", "/", 1000},
+		{"f/", "", "", 1000},
+		{"empty/", "", "/", 1000},
+		{"missing/", "", "", 1000},
+	}
+
+	check := func() {
+		for i, testCase := range testCases {
+			result, ok := fs.listIndex.List(ctx, bucketName, testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+			if !ok {
+				t.Fatalf("Test %d: listing index is not ready", i+1)
+			}
+
+			// List by walking the tree.
+			listIndex := fs.listIndex
+			fs.listIndex = nil
+			expected, err := obj.ListObjects(ctx, bucketName, testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+			fs.listIndex = listIndex
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(listResultNames(result), listResultNames(expected)) ||
+				result.IsTruncated != expected.IsTruncated ||
+				(result.IsTruncated && result.NextMarker != expected.NextMarker) {
+				t.Errorf("Test %d: expected %v (truncated %t, %s), got %v (truncated %t, %s)", i+1,
+					listResultNames(expected), expected.IsTruncated, expected.NextMarker,
+					listResultNames(result), result.IsTruncated, result.NextMarker)
+			}
+			for j, objInfo := range result.Objects {
+				if j >= len(expected.Objects) {
+					break
+				}
+				if objInfo.ETag != expected.Objects[j].ETag || objInfo.Size != expected.Objects[j].Size ||
+					objInfo.IsDir != expected.Objects[j].IsDir ||
+					objInfo.UserDefined["x-amz-meta-name"] != expected.Objects[j].UserDefined["x-amz-meta-name"] {
+					t.Errorf("Test %d: expected %v, got %v", i+1, expected.Objects[j], objInfo)
+				}
+			}
+		}
+	}
+	check()
+
+	// Cleanly closed index is loaded once verified.
+	if err = obj.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	obj = initFSObjects(disk, t)
+	fs = obj.(*FSObjects)
+	waitFSListIndex(t, fs, bucketName)
+	check()
+
+	// Cleanly closed index which does not match the bucket is rebuilt.
+	if err = obj.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Remove(filepath.Join(disk, bucketName, "e")); err != nil {
+		t.Fatal(err)
+	}
+	obj = initFSObjects(disk, t)
+	fs = obj.(*FSObjects)
+	waitFSListIndex(t, fs, bucketName)
+	check()
+
+	// Index which was not closed cleanly is rebuilt.
+	obj = initFSObjects(disk, t)
+	fs = obj.(*FSObjects)
+	waitFSListIndex(t, fs, bucketName)
+	check()
+
+	if err = obj.DeleteBucket(ctx, bucketName); err == nil {
+		t.Fatal("Expected non-empty bucket not to be deleted")
+	}
+}
+
+// Returns names listed by the bucket index.
+func fsBucketListIndexNames(t *testing.T, b *fsBucketListIndex) []string {
+	it, err := b.newIterator("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var names []string
+	for {
+		e, ok, err := it.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			return names
+		}
+		names = append(names, e.Name)
+	}
+}
+
+// Tests that the index keeps track of empty directories.
+func TestFSBucketListIndexApply(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := &fsBucketListIndex{
+		bucket:    "bucket",
+		indexPath: filepath.Join(dir, "index", fsListIndexFile),
+		dirs:      make(map[string]struct{}),
+		updates:   newFSListIndexUpdates(),
+	}
+	apply := func(e fsListIndexEntry) {
+		if err := b.apply(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apply(fsListIndexEntry{Name: "a/", IsDir: true})
+	apply(fsListIndexEntry{Name: "a/b/c"})
+	apply(fsListIndexEntry{Name: "a/b/", IsDir: true})
+	apply(fsListIndexEntry{Name: "d"})
+	if expected, names := []string{"a/b/c", "d"}, fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	apply(fsListIndexEntry{Name: "a/b/c", Deleted: true})
+	apply(fsListIndexEntry{Name: "e/", IsDir: true})
+	if expected, names := []string{"d", "e/"}, fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+
+	// Merged index on disk is read back along with new updates.
+	if err = b.merge(dir); err != nil {
+		t.Fatal(err)
+	}
+	apply(fsListIndexEntry{Name: "e/f"})
+	apply(fsListIndexEntry{Name: "d", Deleted: true})
+	if expected, names := []string{"e/f"}, fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	if err = b.merge(dir); err != nil {
+		t.Fatal(err)
+	}
+	blocks, count, dirs, err := scanFSListIndex(b.indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(blocks, b.blocks) || count != 1 || len(dirs) != 0 {
+		t.Fatalf("Unexpected index layout %v, %d, %v", blocks, count, dirs)
+	}
+}
+
+// Tests that updates received while merging are kept on top of the
+// merged index.
+func TestFSBucketListIndexMerge(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := &fsBucketListIndex{
+		bucket:    "bucket",
+		indexPath: filepath.Join(dir, "index", fsListIndexFile),
+		dirs:      make(map[string]struct{}),
+		updates:   newFSListIndexUpdates(),
+	}
+	apply := func(e fsListIndexEntry) {
+		if err := b.apply(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a", "b", "c/", "d"} {
+		apply(fsListIndexEntry{Name: name, IsDir: hasSuffix(name, slashSeparator)})
+	}
+
+	for i, mergeErr := range []error{errFSListIndexInconsistent, nil} {
+		it, err := b.startMerge()
+		if err != nil {
+			t.Fatal(err)
+		}
+		apply(fsListIndexEntry{Name: "b", Deleted: true})
+		apply(fsListIndexEntry{Name: "c/e"})
+		apply(fsListIndexEntry{Name: "f"})
+		expected := []string{"a", "c/e", "d", "f"}
+		if names := fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Test %d: Expected %v while merging, got %v", i+1, expected, names)
+		}
+
+		w, err := writeFSListIndex(dir, it)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mergeErr != nil {
+			w.abort()
+			w = nil
+		}
+		if err = b.finishMerge(w, mergeErr); err != mergeErr {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, mergeErr, err)
+		}
+		if b.merging != nil {
+			t.Fatalf("Test %d: Expected merging to be finished", i+1)
+		}
+		if names := fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Test %d: Expected %v after merging, got %v", i+1, expected, names)
+		}
+	}
+
+	// Only the frozen updates are on disk, recent updates are kept.
+	if b.count != 4 || len(b.dirs) != 0 {
+		t.Fatalf("Unexpected index layout %d, %v", b.count, b.dirs)
+	}
+	if len(b.updates.entries) != 3 {
+		t.Fatalf("Expected 3 updates to be kept, got %d", len(b.updates.entries))
+	}
+}
+
+// Tests listing an index spanning many blocks and update chunks.
+func TestFSBucketListIndexList(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := &fsBucketListIndex{
+		bucket:    "bucket",
+		indexPath: filepath.Join(dir, "index", fsListIndexFile),
+		dirs:      make(map[string]struct{}),
+		updates:   newFSListIndexUpdates(),
+	}
+	var expected []string
+	for i := 0; i < 3000; i++ {
+		name := fmt.Sprintf("%c/%04d", 'a'+i%3, i)
+		expected = append(expected, name)
+		if i%2 == 1 {
+			continue
+		}
+		if err = b.apply(fsListIndexEntry{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = b.merge(dir); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 3000; i += 2 {
+		if err = b.apply(fsListIndexEntry{Name: expected[i]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(b.updates.chunks) < 2 {
+		t.Fatalf("Expected updates to be split in chunks, got %d", len(b.updates.chunks))
+	}
+	sort.Strings(expected)
+	if names := fsBucketListIndexNames(t, b); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %d names, got %d", len(expected), len(names))
+	}
+
+	result, err := b.list("", "", "/", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := listResultNames(result); !reflect.DeepEqual(names, []string{"a/", "b/", "c/"}) {
+		t.Fatalf("Unexpected prefixes %v", names)
+	}
+
+	var names []string
+	marker := ""
+	for {
+		result, err = b.list("b/", marker, "", 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, listResultNames(result)...)
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+	var expectedB []string
+	for _, name := range expected {
+		if hasPrefix(name, "b/") {
+			expectedB = append(expectedB, name)
+		}
+	}
+	if !reflect.DeepEqual(names, expectedB) {
+		t.Fatalf("Expected %d names, got %d", len(expectedB), len(names))
+	}
+}
@@ -45,6 +45,13 @@ func (fs *FSObjects) getMultipartSHADir(bucket, object string) string {
 	return pathJoin(fs.fsPath, minioMetaMultipartBucket, getSHA256Hash([]byte(pathJoin(bucket, object))))
 }
 
+// Returns a namespace lock on the uploadID, serializing completion and
+// abortion of an upload with uploads of its parts.
+func (fs *FSObjects) newUploadIDLock(bucket, object, uploadID string) RWLocker {
+	uploadIDPath := pathJoin(getSHA256Hash([]byte(pathJoin(bucket, object))), uploadID)
+	return fs.nsMutex.NewNSLock(minioMetaMultipartBucket, uploadIDPath)
+}
+
 // Returns partNumber.etag
 func (fs *FSObjects) encodePartFile(partNumber int, etag string) string {
 	return fmt.Sprintf("%.5d.%s", partNumber, etag)
@@ -298,6 +305,14 @@ func (fs *FSObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID
 		return pi, toObjectErr(errInvalidArgument)
 	}
 
+	// Hold read lock on the uploadID so that it cannot be completed
+	// or aborted while this part is being written.
+	uploadIDLock := fs.newUploadIDLock(bucket, object, uploadID)
+	if err := uploadIDLock.GetRLock(globalOperationTimeout); err != nil {
+		return pi, err
+	}
+	defer uploadIDLock.RUnlock()
+
 	uploadIDDir := fs.getUploadIDDir(bucket, object, uploadID)
 
 	// Just check if the uploadID exists to avoid copy if it doesn't.
@@ -344,7 +359,11 @@ func (fs *FSObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID
 		return pi, toObjectErr(err, minioMetaMultipartBucket, partPath)
 	}
 
-	go fs.backgroundAppend(ctx, bucket, object, uploadID)
+	// Parts of an upload on a shared backend may be uploaded through
+	// any instance, they are only appended on completion.
+	if !fs.shared {
+		go fs.backgroundAppend(ctx, bucket, object, uploadID)
+	}
 
 	fi, err := fsStatFile(ctx, partPath)
 	if err != nil {
@@ -493,6 +512,14 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		return oi, toObjectErr(err, bucket)
 	}
 
+	// Hold write lock on the uploadID, no parts may be uploaded
+	// while the upload is being completed.
+	uploadIDLock := fs.newUploadIDLock(bucket, object, uploadID)
+	if err := uploadIDLock.GetLock(globalOperationTimeout); err != nil {
+		return oi, err
+	}
+	defer uploadIDLock.Unlock()
+
 	uploadIDDir := fs.getUploadIDDir(bucket, object, uploadID)
 	// Just check if the uploadID exists to avoid copy if it doesn't.
 	_, err := fsStatFile(ctx, pathJoin(uploadIDDir, fs.metaJSONFile))
@@ -559,7 +586,10 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	// 1. The last PutObjectPart triggers go-routine fs.backgroundAppend, this go-routine has not started yet.
 	// 2. Now CompleteMultipartUpload gets called which sees that lastPart is not appended and starts appending
 	//    from the beginning
-	fs.backgroundAppend(ctx, bucket, object, uploadID)
+	// On a shared backend the parts are always appended here.
+	if !fs.shared {
+		fs.backgroundAppend(ctx, bucket, object, uploadID)
+	}
 
 	fs.appendFileMapMu.Lock()
 	file := fs.appendFileMap[uploadID]
@@ -587,7 +617,9 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	}
 
 	if appendFallback {
-		fsRemoveFile(ctx, file.filePath)
+		if file != nil {
+			fsRemoveFile(ctx, file.filePath)
+		}
 		for _, part := range parts {
 			partPath := pathJoin(uploadIDDir, fs.encodePartFile(part.PartNumber, part.ETag))
 			err = mioutil.AppendFile(appendFilePath, partPath)
@@ -651,7 +683,11 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		return oi, toObjectErr(err, bucket, object)
 	}
 
-	return fsMeta.ToObjectInfo(bucket, object, fi), nil
+	oi = fsMeta.ToObjectInfo(bucket, object, fi)
+	if fs.listIndex != nil {
+		fs.listIndex.Put(ctx, bucket, oi)
+	}
+	return oi, nil
 }
 
 // AbortMultipartUpload - aborts an ongoing multipart operation
@@ -675,6 +711,14 @@ func (fs *FSObjects) AbortMultipartUpload(ctx context.Context, bucket, object, u
 		return toObjectErr(err, bucket)
 	}
 
+	// Hold write lock on the uploadID, no parts may be uploaded
+	// while the upload is being aborted.
+	uploadIDLock := fs.newUploadIDLock(bucket, object, uploadID)
+	if err := uploadIDLock.GetLock(globalOperationTimeout); err != nil {
+		return err
+	}
+	defer uploadIDLock.Unlock()
+
 	fs.appendFileMapMu.Lock()
 	delete(fs.appendFileMap, uploadID)
 	fs.appendFileMapMu.Unlock()
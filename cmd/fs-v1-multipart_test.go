@@ -19,10 +19,14 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/dsync"
 )
 
 // Tests cleanup multipart uploads for filesystem backend.
@@ -246,3 +250,80 @@ func TestListMultipartUploadsFaultyDisk(t *testing.T) {
 		}
 	}
 }
+
+// TestSharedFSMultipartUpload - test multipart upload continued on
+// another instance sharing the same backend.
+func TestSharedFSMultipartUpload(t *testing.T) {
+	disk := filepath.Join(globalTestTmpDir, "minio-"+nextSuffix())
+	defer os.RemoveAll(disk)
+
+	// Distributed locks served by two in-process lockers.
+	var clnts []dsync.NetLocker
+	for i := 0; i < 2; i++ {
+		clnts = append(clnts, &localLocker{
+			serverAddr:      fmt.Sprintf("node%d:9000", i),
+			serviceEndpoint: lockServicePath,
+			lockMap:         make(map[string][]lockRequesterInfo),
+		})
+	}
+	prevDsync := globalDsync
+	defer func() { globalDsync = prevDsync }()
+	var err error
+	if globalDsync, err = dsync.New(clnts, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var instances []ObjectLayer
+	for i := 0; i < 2; i++ {
+		obj, err := NewSharedFSObjectLayer(disk)
+		if err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+		defer obj.Shutdown(context.Background())
+		instances = append(instances, obj)
+	}
+
+	bucketName := "bucket"
+	objectName := "object"
+	if err = instances[0].MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal("Cannot create bucket, err: ", err)
+	}
+	uploadID, err := instances[0].NewMultipartUpload(context.Background(), bucketName, objectName, nil)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// Upload every part through a different instance.
+	partsData := [][]byte{bytes.Repeat([]byte("a"), 5*humanize.MiByte), []byte("12345")}
+	var parts []CompletePart
+	for i, data := range partsData {
+		md5Hex := getMD5Hash(data)
+		if _, err = instances[i].PutObjectPart(context.Background(), bucketName, objectName, uploadID, i+1, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), md5Hex, "")); err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+		parts = append(parts, CompletePart{PartNumber: i + 1, ETag: md5Hex})
+	}
+
+	result, err := instances[1].ListObjectParts(context.Background(), bucketName, objectName, uploadID, 0, 1000)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	if len(result.Parts) != len(parts) {
+		t.Fatalf("Expected %d parts, got %d", len(parts), len(result.Parts))
+	}
+
+	if _, err = instances[1].CompleteMultipartUpload(context.Background(), bucketName, objectName, uploadID, parts); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	var buffer bytes.Buffer
+	if err = instances[0].GetObject(context.Background(), bucketName, objectName, 0, -1, &buffer, ""); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), bytes.Join(partsData, nil)) {
+		t.Fatal("Object content does not match the uploaded parts")
+	}
+	if err = instances[0].AbortMultipartUpload(context.Background(), bucketName, objectName, uploadID); err == nil {
+		t.Fatal("Expected completed upload to be removed")
+	}
+}
@@ -71,6 +71,14 @@ type FSObjects struct {
 
 	// To manage the appendRoutine go-routines
 	nsMutex *nsLockMap
+
+	// Indicates that the backend is shared with other gateway
+	// instances, namespace locks are distributed and multipart
+	// uploads are only staged on the shared backend.
+	shared bool
+
+	// Persistent listing index, nil unless enabled.
+	listIndex *fsListIndex
 }
 
 // Represents the background append file.
@@ -152,6 +160,12 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 	// or cause changes on backend format.
 	fs.fsFormatRlk = rlk
 
+	if globalFSListIndexEnabled {
+		if fs.listIndex, err = newFSListIndex(ctx, fs); err != nil {
+			return nil, err
+		}
+	}
+
 	if !fs.diskMount {
 		go fs.diskUsage(globalServiceDoneCh)
 	}
@@ -162,8 +176,37 @@ func NewFSObjectLayer(fsPath string) (ObjectLayer, error) {
 	return fs, nil
 }
 
+// NewSharedFSObjectLayer - initialize new fs object layer on a volume
+// shared by several gateway instances. Namespace locks are taken with
+// dsync across all instances, hence dsync must be initialized first.
+func NewSharedFSObjectLayer(fsPath string) (ObjectLayer, error) {
+	if globalDsync == nil {
+		return nil, errInvalidArgument
+	}
+
+	// Listing index is only updated by this instance, it cannot
+	// be used on a backend shared with other instances.
+	if globalFSListIndexEnabled {
+		return nil, errFSListIndexShared
+	}
+
+	obj, err := NewFSObjectLayer(fsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := obj.(*FSObjects)
+	fs.nsMutex = newNSLock(true)
+	fs.shared = true
+	return fs, nil
+}
+
 // Shutdown - should be called when process shuts down.
 func (fs *FSObjects) Shutdown(ctx context.Context) error {
+	if fs.listIndex != nil {
+		fs.listIndex.Shutdown(ctx)
+	}
+
 	fs.fsFormatRlk.Close()
 
 	// Cleanup and delete tmp uuid.
@@ -314,6 +357,10 @@ func (fs *FSObjects) MakeBucketWithLocation(ctx context.Context, bucket, locatio
 		return toObjectErr(err, bucket)
 	}
 
+	if fs.listIndex != nil {
+		return fs.listIndex.MakeBucket(ctx, bucket)
+	}
+
 	return nil
 }
 
@@ -407,6 +454,12 @@ func (fs *FSObjects) DeleteBucket(ctx context.Context, bucket string) error {
 	// Delete all bucket metadata.
 	deleteBucketMetadata(ctx, bucket, fs)
 
+	if fs.listIndex != nil {
+		if err = fs.listIndex.DeleteBucket(ctx, bucket); err != nil {
+			return toObjectErr(err, bucket)
+		}
+	}
+
 	return nil
 }
 
@@ -474,7 +527,11 @@ func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBu
 		}
 
 		// Return the new object info.
-		return fsMeta.ToObjectInfo(srcBucket, srcObject, fi), nil
+		objInfo := fsMeta.ToObjectInfo(srcBucket, srcObject, fi)
+		if fs.listIndex != nil {
+			fs.listIndex.Put(ctx, srcBucket, objInfo)
+		}
+		return objInfo, nil
 	}
 
 	go func() {
@@ -893,6 +950,10 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 		if fi, err = fsStatDir(ctx, pathJoin(fs.fsPath, bucket, object)); err != nil {
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
+		if fs.listIndex != nil {
+			// Metadata of directories is not saved.
+			fs.listIndex.Put(ctx, bucket, fsMetaV1{}.ToObjectInfo(bucket, object, fi))
+		}
 		return fsMeta.ToObjectInfo(bucket, object, fi), nil
 	}
 
@@ -992,7 +1053,11 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 	}
 
 	// Success.
-	return fsMeta.ToObjectInfo(bucket, object, fi), nil
+	objInfo = fsMeta.ToObjectInfo(bucket, object, fi)
+	if fs.listIndex != nil {
+		fs.listIndex.Put(ctx, bucket, objInfo)
+	}
+	return objInfo, nil
 }
 
 // DeleteObject - deletes an object from a bucket, this operation is destructive
@@ -1032,6 +1097,10 @@ func (fs *FSObjects) DeleteObject(ctx context.Context, bucket, object string) er
 		return toObjectErr(err, bucket, object)
 	}
 
+	if fs.listIndex != nil {
+		fs.listIndex.Delete(ctx, bucket, object)
+	}
+
 	if bucket != minioMetaBucket {
 		// Delete the metadata object.
 		err := fsDeleteFile(ctx, minioMetaBucketDir, fsMetaPath)
@@ -1073,6 +1142,26 @@ func (fs *FSObjects) isObjectDir(bucket, prefix string) bool {
 	return len(entries) == 0
 }
 
+// startTreeWalk - starts a tree walk listing objects and empty
+// directories of the bucket.
+func (fs *FSObjects) startTreeWalk(ctx context.Context, bucket, prefix, marker string, recursive bool, endWalkCh chan struct{}) chan treeWalkResult {
+	isLeaf := func(bucket, object string) bool {
+		// bucket argument is unused as we don't need to StatFile
+		// to figure if it's a file, just need to check that the
+		// object string does not end with "/".
+		return !hasSuffix(object, slashSeparator)
+	}
+	// Return true if the specified object is an empty directory
+	isLeafDir := func(bucket, object string) bool {
+		if !hasSuffix(object, slashSeparator) {
+			return false
+		}
+		return fs.isObjectDir(bucket, object)
+	}
+	listDir := fs.listDirFactory(isLeaf)
+	return startTreeWalk(ctx, bucket, prefix, marker, recursive, listDir, isLeaf, isLeafDir, endWalkCh)
+}
+
 // getObjectETag is a helper function, which returns only the md5sum
 // of the file on the disk.
 func (fs *FSObjects) getObjectETag(ctx context.Context, bucket, entry string, lock bool) (string, error) {
@@ -1180,6 +1269,13 @@ func (fs *FSObjects) ListObjects(ctx context.Context, bucket, prefix, marker, de
 		recursive = false
 	}
 
+	// Page from the listing index when it is available.
+	if fs.listIndex != nil {
+		if result, ok := fs.listIndex.List(ctx, bucket, prefix, marker, delimiter, maxKeys); ok {
+			return result, nil
+		}
+	}
+
 	// Convert entry to ObjectInfo
 	entryToObjectInfo := func(entry string) (objInfo ObjectInfo, err error) {
 		// Protect the entry from concurrent deletes, or renames.
@@ -1196,21 +1292,7 @@ func (fs *FSObjects) ListObjects(ctx context.Context, bucket, prefix, marker, de
 	walkResultCh, endWalkCh := fs.listPool.Release(listParams{bucket, recursive, marker, prefix, heal})
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
-		isLeaf := func(bucket, object string) bool {
-			// bucket argument is unused as we don't need to StatFile
-			// to figure if it's a file, just need to check that the
-			// object string does not end with "/".
-			return !hasSuffix(object, slashSeparator)
-		}
-		// Return true if the specified object is an empty directory
-		isLeafDir := func(bucket, object string) bool {
-			if !hasSuffix(object, slashSeparator) {
-				return false
-			}
-			return fs.isObjectDir(bucket, object)
-		}
-		listDir := fs.listDirFactory(isLeaf)
-		walkResultCh = startTreeWalk(ctx, bucket, prefix, marker, recursive, listDir, isLeaf, isLeafDir, endWalkCh)
+		walkResultCh = fs.startTreeWalk(ctx, bucket, prefix, marker, recursive, endWalkCh)
 	}
 
 	var objInfos []ObjectInfo
@@ -30,6 +30,9 @@ var (
 
 	// MustGetUUID function alias.
 	MustGetUUID = mustGetUUID
+
+	// ComputeCompleteMultipartMD5 function alias.
+	ComputeCompleteMultipartMD5 = getCompleteMultipartMD5
 )
 
 // AnonErrToObjectErr - converts standard http codes into meaningful object layer errors.
@@ -0,0 +1,530 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	// Prefix under which the encryption layer keeps its sidecar
+	// objects inside every bucket of the backend.
+	gatewayMetaPrefix = minioMetaBucket + "/"
+
+	// Backend user metadata prefix used to carry reserved metadata.
+	gatewayMetaUserPrefix = "X-Amz-Meta-"
+
+	gatewayMetaFile     = "gw.json"
+	gatewayMetaVersion1 = "1"
+)
+
+// gatewayMetaV1 - sidecar object stored next to encrypted objects
+// on the backend, it keeps the information which cannot be stored
+// as user metadata on the backend.
+type gatewayMetaV1 struct {
+	Version string `json:"version"`
+	// ETag of the backend object this sidecar belongs to.
+	ETag string `json:"etag,omitempty"`
+	// Metadata of an ongoing multipart upload.
+	Meta map[string]string `json:"meta,omitempty"`
+	// Layout of the encrypted parts of a completed multipart object.
+	Parts []objectPartInfo `json:"parts,omitempty"`
+}
+
+// gatewayEncryptionObjects - implements server side encryption on top
+// of a gateway backend. Objects are encrypted by the object handlers
+// before they reach this layer, the layer only persists the sealed
+// keys as backend user metadata and the part layout of multipart
+// objects in sidecar objects so that they can be decrypted again.
+type gatewayEncryptionObjects struct {
+	ObjectLayer
+}
+
+// NewGatewayEncryptionLayer returns an object layer which enables
+// server side encryption on top of the given gateway backend, the
+// backend never sees plain text data or keys.
+func NewGatewayEncryptionLayer(backend ObjectLayer) ObjectLayer {
+	return &gatewayEncryptionObjects{ObjectLayer: backend}
+}
+
+func isGatewayMetaObject(object string) bool {
+	return strings.HasPrefix(object, gatewayMetaPrefix)
+}
+
+func gatewayUploadMetaPath(object, uploadID string) string {
+	return pathJoin(gatewayMetaPrefix, mpartMetaPrefix, object, uploadID, gatewayMetaFile)
+}
+
+func gatewayPartsMetaPath(object string) string {
+	return pathJoin(gatewayMetaPrefix, "parts", object, gatewayMetaFile)
+}
+
+// toBackendMetadata - reserved metadata is not accepted by the backend,
+// save it as regular user metadata instead.
+func toBackendMetadata(metadata map[string]string) map[string]string {
+	m := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if strings.HasPrefix(http.CanonicalHeaderKey(k), ReservedMetadataPrefix) {
+			k = gatewayMetaUserPrefix + k
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// fromBackendMetadata - reverses toBackendMetadata.
+func fromBackendMetadata(metadata map[string]string) map[string]string {
+	m := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		ck := http.CanonicalHeaderKey(k)
+		if strings.HasPrefix(ck, gatewayMetaUserPrefix+ReservedMetadataPrefix) {
+			k = strings.TrimPrefix(ck, gatewayMetaUserPrefix)
+		}
+		m[k] = v
+	}
+	return m
+}
+
+func (l *gatewayEncryptionObjects) readGatewayMeta(ctx context.Context, bucket, metaPath string) (meta gatewayMetaV1, err error) {
+	var buffer bytes.Buffer
+	if err = l.ObjectLayer.GetObject(ctx, bucket, metaPath, 0, -1, &buffer, ""); err != nil {
+		return meta, err
+	}
+	if err = json.Unmarshal(buffer.Bytes(), &meta); err != nil {
+		logger.LogIf(ctx, err)
+		return meta, err
+	}
+	return meta, nil
+}
+
+func (l *gatewayEncryptionObjects) writeGatewayMeta(ctx context.Context, bucket, metaPath string, meta gatewayMetaV1) error {
+	meta.Version = gatewayMetaVersion1
+	data, err := json.Marshal(meta)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", getSHA256Hash(data))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	_, err = l.ObjectLayer.PutObject(ctx, bucket, metaPath, hashReader, nil)
+	return err
+}
+
+// deleteGatewayMeta - removes a sidecar object, a missing sidecar is not an error.
+func (l *gatewayEncryptionObjects) deleteGatewayMeta(ctx context.Context, bucket, metaPath string) error {
+	if err := l.ObjectLayer.DeleteObject(ctx, bucket, metaPath); err != nil && !isErrObjectNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// IsEncryptionSupported returns whether server side encryption is applicable for this layer.
+func (l *gatewayEncryptionObjects) IsEncryptionSupported() bool {
+	return true
+}
+
+// DeleteBucket - removes left over sidecar objects before deleting the bucket.
+func (l *gatewayEncryptionObjects) DeleteBucket(ctx context.Context, bucket string) error {
+	var sidecars []string
+	marker := ""
+	for {
+		loi, err := l.ObjectLayer.ListObjects(ctx, bucket, "", marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, obj := range loi.Objects {
+			if !isGatewayMetaObject(obj.Name) {
+				return BucketNotEmpty{Bucket: bucket}
+			}
+			sidecars = append(sidecars, obj.Name)
+		}
+		if !loi.IsTruncated || len(loi.Objects) == 0 {
+			break
+		}
+		marker = loi.Objects[len(loi.Objects)-1].Name
+	}
+	for _, sidecar := range sidecars {
+		if err := l.deleteGatewayMeta(ctx, bucket, sidecar); err != nil {
+			return err
+		}
+	}
+	return l.ObjectLayer.DeleteBucket(ctx, bucket)
+}
+
+// ListObjects - lists objects hiding the sidecar objects, the listed
+// objects carry their metadata so that the decrypted size of encrypted
+// objects can be computed. Only encrypted multipart objects are looked
+// up, to restore their part layout.
+func (l *gatewayEncryptionObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (result ListObjectsInfo, err error) {
+	for {
+		var loi ListObjectsInfo
+		loi, err = l.ObjectLayer.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+		if err != nil {
+			return result, err
+		}
+
+		result = ListObjectsInfo{
+			IsTruncated: loi.IsTruncated,
+			NextMarker:  loi.NextMarker,
+		}
+		for _, p := range loi.Prefixes {
+			if !isGatewayMetaObject(p) {
+				result.Prefixes = append(result.Prefixes, p)
+			}
+		}
+		for _, obj := range loi.Objects {
+			if isGatewayMetaObject(obj.Name) {
+				continue
+			}
+			obj.UserDefined = fromBackendMetadata(obj.UserDefined)
+			if crypto.IsMultiPart(obj.UserDefined) {
+				objInfo, err := l.GetObjectInfo(ctx, bucket, obj.Name)
+				if err != nil {
+					if isErrObjectNotFound(err) {
+						continue
+					}
+					return result, err
+				}
+				obj = objInfo
+			}
+			result.Objects = append(result.Objects, obj)
+		}
+
+		// A page made up of sidecar objects only is never returned
+		// as long as there are more entries to be listed.
+		if len(result.Objects) > 0 || len(result.Prefixes) > 0 || !loi.IsTruncated {
+			return result, nil
+		}
+		switch {
+		case loi.NextMarker != "":
+			marker = loi.NextMarker
+		case len(loi.Objects) > 0:
+			marker = loi.Objects[len(loi.Objects)-1].Name
+		case len(loi.Prefixes) > 0:
+			marker = loi.Prefixes[len(loi.Prefixes)-1]
+		default:
+			return result, nil
+		}
+	}
+}
+
+// ListObjectsV2 - lists objects, see ListObjects.
+func (l *gatewayEncryptionObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
+	marker := continuationToken
+	if marker == "" {
+		marker = startAfter
+	}
+	loi, err := l.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return result, err
+	}
+	return ListObjectsV2Info{
+		IsTruncated:           loi.IsTruncated,
+		ContinuationToken:     continuationToken,
+		NextContinuationToken: loi.NextMarker,
+		Objects:               loi.Objects,
+		Prefixes:              loi.Prefixes,
+	}, nil
+}
+
+// GetObjectInfo - returns object info with the encryption metadata
+// and the part layout of encrypted multipart objects restored.
+func (l *gatewayEncryptionObjects) GetObjectInfo(ctx context.Context, bucket, object string) (objInfo ObjectInfo, err error) {
+	if isGatewayMetaObject(object) {
+		return objInfo, ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	objInfo, err = l.ObjectLayer.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return objInfo, err
+	}
+	objInfo.UserDefined = fromBackendMetadata(objInfo.UserDefined)
+	if !crypto.IsMultiPart(objInfo.UserDefined) {
+		return objInfo, nil
+	}
+
+	meta, err := l.readGatewayMeta(ctx, bucket, gatewayPartsMetaPath(object))
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			err = errObjectTampered
+		}
+		return objInfo, err
+	}
+	// The object was replaced on the backend behind our back,
+	// it cannot be decrypted with the saved part layout.
+	if meta.ETag != objInfo.ETag {
+		return objInfo, errObjectTampered
+	}
+	objInfo.Parts = meta.Parts
+	return objInfo, nil
+}
+
+// GetObjectNInfo - returns object info and a reader for the object content.
+func (l *gatewayEncryptionObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (objInfo ObjectInfo, reader io.ReadCloser, err error) {
+	objInfo, err = l.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return objInfo, reader, err
+	}
+
+	startOffset, length := int64(0), objInfo.Size
+	if rs != nil {
+		startOffset, length = rs.GetOffsetLength(objInfo.Size)
+	}
+
+	pr, pw := io.Pipe()
+	objReader := NewGetObjectReader(pr, nil, nil)
+	go func() {
+		err := l.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, pw, objInfo.ETag)
+		pw.CloseWithError(err)
+	}()
+
+	return objInfo, objReader, nil
+}
+
+// GetObject - reads object content from the backend.
+func (l *gatewayEncryptionObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	if isGatewayMetaObject(object) {
+		return ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return l.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+}
+
+// PutObject - saves the object on the backend along with its encryption metadata.
+func (l *gatewayEncryptionObjects) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (objInfo ObjectInfo, err error) {
+	if isGatewayMetaObject(object) {
+		return objInfo, ObjectNameInvalid{Bucket: bucket, Object: object}
+	}
+	objInfo, err = l.ObjectLayer.PutObject(ctx, bucket, object, data, toBackendMetadata(metadata))
+	if err != nil {
+		return objInfo, err
+	}
+	objInfo.UserDefined = fromBackendMetadata(objInfo.UserDefined)
+	return objInfo, nil
+}
+
+// CopyObject - copies an object, the data is streamed through the
+// gateway whenever it has to be decrypted or encrypted on the way.
+func (l *gatewayEncryptionObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo ObjectInfo) (objInfo ObjectInfo, err error) {
+	if isGatewayMetaObject(srcObject) {
+		return objInfo, ObjectNotFound{Bucket: srcBucket, Object: srcObject}
+	}
+	if isGatewayMetaObject(dstObject) {
+		return objInfo, ObjectNameInvalid{Bucket: dstBucket, Object: dstObject}
+	}
+
+	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(dstBucket, dstObject))
+	if cpSrcDstSame && srcInfo.metadataOnly {
+		return l.copyObjectOnBackend(ctx, srcBucket, srcObject, dstBucket, dstObject, srcInfo, srcInfo.Parts)
+	}
+
+	if !crypto.IsEncrypted(srcInfo.UserDefined) {
+		oi, err := l.GetObjectInfo(ctx, srcBucket, srcObject)
+		if err != nil {
+			return objInfo, err
+		}
+		if !crypto.IsEncrypted(oi.UserDefined) {
+			return l.copyObjectOnBackend(ctx, srcBucket, srcObject, dstBucket, dstObject, srcInfo, nil)
+		}
+	}
+
+	go func() {
+		if gerr := l.ObjectLayer.GetObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
+			return
+		}
+		// Close writer explicitly signaling we wrote all data.
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+			return
+		}
+	}()
+
+	return l.PutObject(ctx, dstBucket, dstObject, srcInfo.Reader, srcInfo.UserDefined)
+}
+
+// copyObjectOnBackend - copies an object on the backend without reading
+// its content, the part layout is saved for the copy when given.
+func (l *gatewayEncryptionObjects) copyObjectOnBackend(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo ObjectInfo, parts []objectPartInfo) (objInfo ObjectInfo, err error) {
+	metadata := srcInfo.UserDefined
+	srcInfo.UserDefined = toBackendMetadata(metadata)
+	objInfo, err = l.ObjectLayer.CopyObject(ctx, srcBucket, srcObject, dstBucket, dstObject, srcInfo)
+	if err != nil {
+		return objInfo, err
+	}
+	objInfo.UserDefined = fromBackendMetadata(objInfo.UserDefined)
+	if crypto.IsMultiPart(metadata) {
+		// The backend may have changed the ETag of the object.
+		if err = l.writeGatewayMeta(ctx, dstBucket, gatewayPartsMetaPath(dstObject), gatewayMetaV1{
+			ETag:  objInfo.ETag,
+			Parts: parts,
+		}); err != nil {
+			return objInfo, err
+		}
+		objInfo.Parts = parts
+	}
+	return objInfo, nil
+}
+
+// DeleteObject - deletes the object along with its sidecar object.
+func (l *gatewayEncryptionObjects) DeleteObject(ctx context.Context, bucket, object string) error {
+	if isGatewayMetaObject(object) {
+		return ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	if err := l.ObjectLayer.DeleteObject(ctx, bucket, object); err != nil {
+		return err
+	}
+	return l.deleteGatewayMeta(ctx, bucket, gatewayPartsMetaPath(object))
+}
+
+// NewMultipartUpload - initiates a multipart upload, the metadata of
+// encrypted uploads is saved in a sidecar object as backends do not
+// return it while the upload is ongoing.
+func (l *gatewayEncryptionObjects) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
+	if isGatewayMetaObject(object) {
+		return "", ObjectNameInvalid{Bucket: bucket, Object: object}
+	}
+	uploadID, err = l.ObjectLayer.NewMultipartUpload(ctx, bucket, object, toBackendMetadata(metadata))
+	if err != nil || !crypto.IsEncrypted(metadata) {
+		return uploadID, err
+	}
+	if err = l.writeGatewayMeta(ctx, bucket, gatewayUploadMetaPath(object, uploadID), gatewayMetaV1{Meta: metadata}); err != nil {
+		logger.LogIf(ctx, l.ObjectLayer.AbortMultipartUpload(ctx, bucket, object, uploadID))
+		return "", err
+	}
+	return uploadID, nil
+}
+
+// ListObjectParts - lists the parts of an upload along with the upload metadata.
+func (l *gatewayEncryptionObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (lpi ListPartsInfo, err error) {
+	lpi, err = l.ObjectLayer.ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts)
+	if err != nil {
+		return lpi, err
+	}
+	meta, err := l.readGatewayMeta(ctx, bucket, gatewayUploadMetaPath(object, uploadID))
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			return lpi, nil
+		}
+		return lpi, err
+	}
+	lpi.UserDefined = meta.Meta
+	return lpi, nil
+}
+
+// CopyObjectPart - copies a part of an object into an upload, the data
+// is streamed through the gateway so it can be decrypted or encrypted.
+func (l *gatewayEncryptionObjects) CopyObjectPart(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject, uploadID string, partID int, startOffset int64, length int64, srcInfo ObjectInfo) (pi PartInfo, err error) {
+	if isGatewayMetaObject(srcObject) {
+		return pi, ObjectNotFound{Bucket: srcBucket, Object: srcObject}
+	}
+
+	go func() {
+		if gerr := l.ObjectLayer.GetObject(ctx, srcBucket, srcObject, startOffset, length, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
+			return
+		}
+		// Close writer explicitly signaling we wrote all data.
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+			return
+		}
+	}()
+
+	return l.ObjectLayer.PutObjectPart(ctx, dstBucket, dstObject, uploadID, partID, srcInfo.Reader)
+}
+
+// AbortMultipartUpload - aborts an upload and removes its sidecar object.
+func (l *gatewayEncryptionObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	if err := l.ObjectLayer.AbortMultipartUpload(ctx, bucket, object, uploadID); err != nil {
+		return err
+	}
+	return l.deleteGatewayMeta(ctx, bucket, gatewayUploadMetaPath(object, uploadID))
+}
+
+// CompleteMultipartUpload - completes an upload, the encrypted size of
+// every part is saved as it is required to decrypt the object later.
+func (l *gatewayEncryptionObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []CompletePart) (objInfo ObjectInfo, err error) {
+	uploadMetaPath := gatewayUploadMetaPath(object, uploadID)
+	meta, err := l.readGatewayMeta(ctx, bucket, uploadMetaPath)
+	if err != nil {
+		if !isErrObjectNotFound(err) {
+			return objInfo, err
+		}
+		// Upload is not encrypted.
+		return l.ObjectLayer.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	}
+
+	partSizes := make(map[int]int64)
+	partNumberMarker := 0
+	for {
+		lpi, err := l.ObjectLayer.ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxPartsList)
+		if err != nil {
+			return objInfo, err
+		}
+		for _, part := range lpi.Parts {
+			partSizes[part.PartNumber] = part.Size
+		}
+		if !lpi.IsTruncated {
+			break
+		}
+		partNumberMarker = lpi.NextPartNumberMarker
+	}
+
+	parts := make([]objectPartInfo, len(uploadedParts))
+	for i, part := range uploadedParts {
+		size, ok := partSizes[part.PartNumber]
+		if !ok {
+			return objInfo, InvalidPart{PartNumber: part.PartNumber}
+		}
+		parts[i] = objectPartInfo{
+			Number: part.PartNumber,
+			ETag:   part.ETag,
+			Size:   size,
+		}
+	}
+
+	objInfo, err = l.ObjectLayer.CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	if err != nil {
+		return objInfo, err
+	}
+	if err = l.writeGatewayMeta(ctx, bucket, gatewayPartsMetaPath(object), gatewayMetaV1{
+		ETag:  objInfo.ETag,
+		Parts: parts,
+	}); err != nil {
+		return objInfo, err
+	}
+	logger.LogIf(ctx, l.deleteGatewayMeta(ctx, bucket, uploadMetaPath))
+
+	objInfo.UserDefined = meta.Meta
+	objInfo.Parts = parts
+	return objInfo, nil
+}
@@ -0,0 +1,265 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/crypto"
+)
+
+func prepareGatewayEncryption(t *testing.T) (backend, objLayer ObjectLayer, fsDir string) {
+	backend, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.MakeBucketWithLocation(context.Background(), "bucket", ""); err != nil {
+		os.RemoveAll(fsDir)
+		t.Fatal(err)
+	}
+	return backend, NewGatewayEncryptionLayer(backend), fsDir
+}
+
+func TestGatewayBackendMetadata(t *testing.T) {
+	metadata := map[string]string{
+		"content-type":          "application/octet-stream",
+		"X-Amz-Meta-Foo":        "bar",
+		crypto.SSEIV:            "iv",
+		crypto.SSECSealedKey:    "sealed-key",
+		crypto.SSESealAlgorithm: "algorithm",
+	}
+	backendMetadata := toBackendMetadata(metadata)
+	for k := range backendMetadata {
+		if strings.HasPrefix(k, ReservedMetadataPrefix) {
+			t.Errorf("Reserved metadata %s must not be sent to the backend", k)
+		}
+	}
+	if backendMetadata["X-Amz-Meta-"+crypto.SSEIV] != "iv" {
+		t.Errorf("Expected %s to be saved as user metadata, got %v", crypto.SSEIV, backendMetadata)
+	}
+	if got := fromBackendMetadata(backendMetadata); !reflect.DeepEqual(got, metadata) {
+		t.Errorf("Expected %v, got %v", metadata, got)
+	}
+}
+
+// statCountingObjects - counts object lookups on the backend.
+type statCountingObjects struct {
+	ObjectLayer
+	count int
+}
+
+func (l *statCountingObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	l.count++
+	return l.ObjectLayer.GetObjectInfo(ctx, bucket, object)
+}
+
+func TestGatewayEncryptionSinglePart(t *testing.T) {
+	defer func(flag bool) { globalIsSSL = flag }(globalIsSSL)
+	globalIsSSL = true
+
+	backend, objLayer, fsDir := prepareGatewayEncryption(t)
+	defer os.RemoveAll(fsDir)
+	ctx := context.Background()
+
+	if !objLayer.IsEncryptionSupported() {
+		t.Fatal("Expected encryption to be supported")
+	}
+
+	// The customer key is removed from the request once it is parsed.
+	newSSECRequest := func() *http.Request {
+		req := &http.Request{Header: http.Header{}}
+		req.Header.Set(crypto.SSECAlgorithm, "AES256")
+		req.Header.Set(crypto.SSECKey, "XAm0dRrJsEsyPb1UuFNezv1bl9hxuYsgUVC/MUctE2k=")
+		req.Header.Set(crypto.SSECKeyMD5, "bY4wkxQejw9mUJfo72k53A==")
+		return req
+	}
+
+	data := bytes.Repeat([]byte("a"), 1024)
+	metadata := map[string]string{}
+	reader, err := EncryptRequest(bytes.NewReader(data), newSSECRequest(), "bucket", "object", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = objLayer.PutObject(ctx, "bucket", "object", mustGetHashReader(t, bytes.NewReader(encrypted), int64(len(encrypted)), "", ""), metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	// The backend must only see encrypted data and no reserved metadata.
+	var buffer bytes.Buffer
+	if err = backend.GetObject(ctx, "bucket", "object", 0, -1, &buffer, ""); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(buffer.Bytes(), data) {
+		t.Fatal("Backend object is not encrypted")
+	}
+	backendInfo, err := backend.GetObjectInfo(ctx, "bucket", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backendInfo.UserDefined[crypto.SSECSealedKey]; ok {
+		t.Fatal("Sealed key must be saved as user metadata on the backend")
+	}
+
+	objInfo, err := objLayer.GetObjectInfo(ctx, "bucket", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crypto.SSEC.IsEncrypted(objInfo.UserDefined) {
+		t.Fatalf("Expected object to be SSE-C encrypted, got %v", objInfo.UserDefined)
+	}
+	if size, err := objInfo.DecryptedSize(); err != nil || size != int64(len(data)) {
+		t.Fatalf("Expected decrypted size %d, got %d (%v)", len(data), size, err)
+	}
+
+	// Listings use the metadata listed by the backend.
+	counting := &statCountingObjects{ObjectLayer: backend}
+	loi, err := NewGatewayEncryptionLayer(counting).ListObjects(ctx, "bucket", "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loi.Objects) != 1 || !crypto.SSEC.IsEncrypted(loi.Objects[0].UserDefined) {
+		t.Fatalf("Expected encrypted object to be listed, got %v", loi.Objects)
+	}
+	if size, err := loi.Objects[0].DecryptedSize(); err != nil || size != int64(len(data)) {
+		t.Fatalf("Expected listed decrypted size %d, got %d (%v)", len(data), size, err)
+	}
+	if counting.count != 0 {
+		t.Fatalf("Expected no object lookups while listing, got %d", counting.count)
+	}
+
+	var decrypted bytes.Buffer
+	writer, err := DecryptRequest(&decrypted, newSSECRequest(), "bucket", "object", objInfo.UserDefined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = objLayer.GetObject(ctx, "bucket", "object", 0, objInfo.Size, writer, objInfo.ETag); err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted.Bytes(), data) {
+		t.Fatal("Decrypted data does not match the original data")
+	}
+}
+
+func TestGatewayEncryptionMultipart(t *testing.T) {
+	backend, objLayer, fsDir := prepareGatewayEncryption(t)
+	defer os.RemoveAll(fsDir)
+	ctx := context.Background()
+
+	metadata := map[string]string{
+		crypto.SSEMultipart:     "",
+		crypto.SSEIV:            "iv",
+		crypto.SSECSealedKey:    "sealed-key",
+		crypto.SSESealAlgorithm: "algorithm",
+	}
+	uploadID, err := objLayer.NewMultipartUpload(ctx, "bucket", "dir/object", metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lpi, err := objLayer.ListObjectParts(ctx, "bucket", "dir/object", uploadID, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lpi.UserDefined, metadata) {
+		t.Fatalf("Expected upload metadata %v, got %v", metadata, lpi.UserDefined)
+	}
+
+	sizes := []int64{5 * humanize.MiByte, 1024}
+	var parts []CompletePart
+	for i, size := range sizes {
+		data := bytes.Repeat([]byte("a"), int(size))
+		pi, err := objLayer.PutObjectPart(ctx, "bucket", "dir/object", uploadID, i+1, mustGetHashReader(t, bytes.NewReader(data), size, "", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, CompletePart{PartNumber: pi.PartNumber, ETag: pi.ETag})
+	}
+	if _, err = objLayer.CompleteMultipartUpload(ctx, "bucket", "dir/object", uploadID, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	objInfo, err := objLayer.GetObjectInfo(ctx, "bucket", "dir/object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(objInfo.UserDefined, metadata) {
+		t.Fatalf("Expected metadata %v, got %v", metadata, objInfo.UserDefined)
+	}
+	if len(objInfo.Parts) != len(sizes) {
+		t.Fatalf("Expected %d parts, got %d", len(sizes), len(objInfo.Parts))
+	}
+	for i, part := range objInfo.Parts {
+		if part.Number != i+1 || part.Size != sizes[i] {
+			t.Errorf("Part %d: expected number %d and size %d, got %d and %d", i, i+1, sizes[i], part.Number, part.Size)
+		}
+	}
+
+	// Sidecar objects are hidden from listings.
+	for _, delimiter := range []string{"", slashSeparator} {
+		loi, err := objLayer.ListObjects(ctx, "bucket", "", "", delimiter, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, prefix := range loi.Prefixes {
+			if isGatewayMetaObject(prefix) {
+				t.Errorf("Sidecar prefix %s must not be listed", prefix)
+			}
+		}
+		for _, obj := range loi.Objects {
+			if isGatewayMetaObject(obj.Name) {
+				t.Errorf("Sidecar object %s must not be listed", obj.Name)
+			}
+			if obj.Name == "dir/object" && len(obj.Parts) != len(sizes) {
+				t.Errorf("Expected listed object to carry %d parts, got %d", len(sizes), len(obj.Parts))
+			}
+		}
+	}
+	if _, err = objLayer.GetObjectInfo(ctx, "bucket", gatewayPartsMetaPath("dir/object")); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected sidecar object to be hidden, got %v", err)
+	}
+
+	// Replacing the object on the backend must be detected.
+	data := []byte("tampered")
+	if _, err = backend.PutObject(ctx, "bucket", "dir/object", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), toBackendMetadata(metadata)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = objLayer.GetObjectInfo(ctx, "bucket", "dir/object"); err != errObjectTampered {
+		t.Fatalf("Expected %v, got %v", errObjectTampered, err)
+	}
+
+	if err = objLayer.DeleteObject(ctx, "bucket", "dir/object"); err != nil {
+		t.Fatal(err)
+	}
+	if err = objLayer.DeleteBucket(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+}
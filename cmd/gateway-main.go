@@ -27,9 +27,12 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/minio/cli"
+	"github.com/minio/dsync"
+	"github.com/minio/minio-go/pkg/set"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/certs"
+	xnet "github.com/minio/minio/pkg/net"
 )
 
 func init() {
@@ -95,6 +98,59 @@ func ValidateGatewayArguments(serverAddr, endpointAddr string) error {
 	return nil
 }
 
+// parseGatewayPeers - parses the addresses of all gateway instances
+// sharing a backend into an endpoint list, the address of the local
+// instance is identified by the gateway address. Locks need n/2+1
+// instances online, at least 3 instances are required so that writes
+// survive the loss of one instance.
+func parseGatewayPeers(peers []string, gatewayAddr string) (EndpointList, error) {
+	if len(peers) < 3 {
+		return nil, fmt.Errorf("at least 3 peer addresses are required, %d given", len(peers))
+	}
+
+	scheme := "http"
+	if globalIsSSL {
+		scheme = "https"
+	}
+
+	var endpoints EndpointList
+	seenHosts := set.NewStringSet()
+	localFound := false
+	for _, peer := range peers {
+		host, err := xnet.ParseHost(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer address %s: %s", peer, err)
+		}
+		if !host.IsPortSet {
+			return nil, fmt.Errorf("invalid peer address %s: missing port", peer)
+		}
+		if seenHosts.Contains(host.String()) {
+			return nil, fmt.Errorf("duplicate peer address %s", peer)
+		}
+		seenHosts.Add(host.String())
+
+		isLocal, err := sameLocalAddrs(host.String(), gatewayAddr)
+		if err != nil {
+			return nil, err
+		}
+		if isLocal {
+			if localFound {
+				return nil, fmt.Errorf("more than one peer address points to the local gateway")
+			}
+			localFound = true
+		}
+
+		endpoints = append(endpoints, Endpoint{
+			URL:     &url.URL{Scheme: scheme, Host: host.String()},
+			IsLocal: isLocal,
+		})
+	}
+	if !localFound {
+		return nil, fmt.Errorf("none of the peer addresses points to the local gateway %s", gatewayAddr)
+	}
+	return endpoints, nil
+}
+
 // StartGateway - handler for 'minio gateway <name>'.
 func StartGateway(ctx *cli.Context, gw Gateway) {
 	if gw == nil {
@@ -153,6 +209,15 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Initialize server config.
 	srvCfg := newServerConfig()
 
+	// Load notification targets and other settings from the local
+	// configuration file, if present.
+	if isFile(getConfigFile()) {
+		logger.FatalIf(migrateConfig(), "Unable to migrate configuration file")
+		_, err := Load(getConfigFile(), srvCfg)
+		logger.FatalIf(err, "Unable to load configuration file")
+		logger.FatalIf(srvCfg.Validate(), "Invalid configuration file")
+	}
+
 	// Override any values from ENVs.
 	srvCfg.loadFromEnvs()
 
@@ -180,7 +245,16 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Set system resources to maximum.
 	logger.LogIf(context.Background(), setMaxResources())
 
-	initNSLock(false) // Enable local namespace lock.
+	// Gateway instances sharing a backend take namespace locks with
+	// dsync, all of them serve the lock RPC service.
+	var peers EndpointList
+	if dgw, ok := gw.(DistributedGateway); ok && len(dgw.Peers()) > 0 {
+		peers, err = parseGatewayPeers(dgw.Peers(), gatewayAddr)
+		logger.FatalIf(err, "Invalid gateway peers")
+		globalDsync, err = dsync.New(newDsyncNodes(peers))
+		logger.FatalIf(err, "Unable to initialize distributed locking with %s", peers)
+	}
+	initNSLock(len(peers) > 0)
 
 	// Create new notification system.
 	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
@@ -190,6 +264,11 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 
 	router := mux.NewRouter().SkipClean(true)
 
+	// Add distributed namespace lock router.
+	if len(peers) > 0 {
+		registerDistNSLockRouter(router)
+	}
+
 	// Add healthcheck router
 	registerHealthCheckRouter(router)
 
@@ -227,6 +306,14 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 		logger.FatalIf(err, "Unable to initialize gateway backend")
 	}
 
+	// Bucket notification configurations are kept in the local
+	// configuration directory for backends which cannot store them.
+	if !newObject.IsNotificationSupported() {
+		newObject, err = newGatewayNotificationLayer(newObject, getGatewayMetaDir(gatewayName))
+		logger.FatalIf(err, "Unable to initialize gateway notification store")
+	}
+	go initGatewayNotification(newObject)
+
 	go globalPolicySys.Init(newObject)
 
 	// Once endpoints are finalized, initialize the new object api.
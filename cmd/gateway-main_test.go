@@ -96,3 +96,43 @@ func TestValidateGatewayArguments(t *testing.T) {
 		}
 	}
 }
+
+// Test parseGatewayPeers
+func TestParseGatewayPeers(t *testing.T) {
+	testCases := []struct {
+		peers       []string
+		gatewayAddr string
+		localIndex  int
+		errReturned bool
+	}{
+		{[]string{"127.0.0.1:9000", "192.0.2.10:9000", "192.0.2.11:9000"}, ":9000", 0, false},
+		{[]string{"192.0.2.10:9000", "127.0.0.1:9001", "127.0.0.1:9000"}, "127.0.0.1:9001", 1, false},
+		// Less than 3 instances cannot keep a lock quorum with one instance down.
+		{[]string{"127.0.0.1:9000", "192.0.2.10:9000"}, ":9000", -1, true},
+		// Local gateway is not a peer.
+		{[]string{"192.0.2.10:9000", "192.0.2.11:9000", "192.0.2.12:9000"}, ":9000", -1, true},
+		// Local gateway is listed twice.
+		{[]string{"127.0.0.1:9000", "localhost:9000", "192.0.2.10:9000"}, ":9000", -1, true},
+		{[]string{"127.0.0.1:9000", "127.0.0.1:9000", "192.0.2.10:9000"}, ":9000", -1, true},
+		// Port is mandatory.
+		{[]string{"127.0.0.1:9000", "192.0.2.10", "192.0.2.11:9000"}, ":9000", -1, true},
+	}
+
+	for i, test := range testCases {
+		endpoints, err := parseGatewayPeers(test.peers, test.gatewayAddr)
+		if errReturned := err != nil; errReturned != test.errReturned {
+			t.Fatalf("Test %d: expected error %t, got %v", i+1, test.errReturned, err)
+		}
+		if err != nil {
+			continue
+		}
+		if len(endpoints) != len(test.peers) {
+			t.Fatalf("Test %d: expected %d endpoints, got %d", i+1, len(test.peers), len(endpoints))
+		}
+		for j, endpoint := range endpoints {
+			if endpoint.IsLocal != (j == test.localIndex) {
+				t.Errorf("Test %d: endpoint %s expected to be local %t", i+1, endpoint, j == test.localIndex)
+			}
+		}
+	}
+}
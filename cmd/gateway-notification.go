@@ -0,0 +1,137 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+)
+
+// gatewayNotificationObjects - enables bucket notifications for gateways
+// whose backend cannot store minio metadata. Bucket notification
+// configurations are kept in a local FS object layer instead.
+type gatewayNotificationObjects struct {
+	ObjectLayer
+
+	// Local object layer holding the minio meta bucket.
+	meta ObjectLayer
+}
+
+// getGatewayMetaDir - returns the local directory holding the minio meta
+// bucket of the gateway, MINIO_GATEWAY_META_DIR overrides the default
+// directory under the configuration directory.
+func getGatewayMetaDir(gatewayName string) string {
+	if metaDir := os.Getenv("MINIO_GATEWAY_META_DIR"); metaDir != "" {
+		return metaDir
+	}
+	return filepath.Join(getConfigDir(), "gateway", gatewayName)
+}
+
+// initGatewayNotification - loads bucket notification configurations,
+// the backend may not be reachable yet so loading is retried in the
+// background until it succeeds.
+func initGatewayNotification(objAPI ObjectLayer) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	for range newRetryTimerSimple(doneCh) {
+		err := globalNotificationSys.Init(objAPI)
+		if err == nil {
+			return
+		}
+		logger.LogIf(context.Background(), err)
+
+		select {
+		case <-globalServiceDoneCh:
+			return
+		default:
+		}
+	}
+}
+
+// newGatewayNotificationLayer - returns an object layer which stores
+// the minio meta bucket of the backend in the local directory metaDir.
+func newGatewayNotificationLayer(backend ObjectLayer, metaDir string) (ObjectLayer, error) {
+	meta, err := NewFSObjectLayer(metaDir)
+	if err != nil {
+		return nil, err
+	}
+	return &gatewayNotificationObjects{
+		ObjectLayer: backend,
+		meta:        meta,
+	}, nil
+}
+
+// Shutdown - shuts down the backend and the local meta object layer.
+func (l *gatewayNotificationObjects) Shutdown(ctx context.Context) error {
+	if err := l.ObjectLayer.Shutdown(ctx); err != nil {
+		return err
+	}
+	return l.meta.Shutdown(ctx)
+}
+
+// IsNotificationSupported returns whether bucket notification is applicable for this layer.
+func (l *gatewayNotificationObjects) IsNotificationSupported() bool {
+	return true
+}
+
+// DeleteBucket - deletes the bucket along with its notification configuration.
+func (l *gatewayNotificationObjects) DeleteBucket(ctx context.Context, bucket string) error {
+	if err := l.ObjectLayer.DeleteBucket(ctx, bucket); err != nil {
+		return err
+	}
+	// Delete notification config, if present - ignore any errors.
+	removeNotificationConfig(ctx, l.meta, bucket)
+	return nil
+}
+
+// GetObjectInfo - returns object info, from the local meta object layer for the minio meta bucket.
+func (l *gatewayNotificationObjects) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	if bucket == minioMetaBucket {
+		return l.meta.GetObjectInfo(ctx, bucket, object)
+	}
+	return l.ObjectLayer.GetObjectInfo(ctx, bucket, object)
+}
+
+// GetObject - reads an object, from the local meta object layer for the minio meta bucket.
+func (l *gatewayNotificationObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	if bucket == minioMetaBucket {
+		return l.meta.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+	}
+	return l.ObjectLayer.GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+}
+
+// PutObject - writes an object, to the local meta object layer for the minio meta bucket.
+func (l *gatewayNotificationObjects) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (ObjectInfo, error) {
+	if bucket == minioMetaBucket {
+		return l.meta.PutObject(ctx, bucket, object, data, metadata)
+	}
+	return l.ObjectLayer.PutObject(ctx, bucket, object, data, metadata)
+}
+
+// DeleteObject - deletes an object, from the local meta object layer for the minio meta bucket.
+func (l *gatewayNotificationObjects) DeleteObject(ctx context.Context, bucket, object string) error {
+	if bucket == minioMetaBucket {
+		return l.meta.DeleteObject(ctx, bucket, object)
+	}
+	return l.ObjectLayer.DeleteObject(ctx, bucket, object)
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+func TestGatewayNotificationLayer(t *testing.T) {
+	backend, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	metaDirs, err := getRandomDisks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(metaDirs)
+
+	objLayer, err := newGatewayNotificationLayer(backend, metaDirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !objLayer.IsNotificationSupported() {
+		t.Fatal("Expected bucket notifications to be supported")
+	}
+
+	ctx := context.Background()
+	if err = objLayer.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = saveNotificationConfig(objLayer, "bucket", &event.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	configFile := path.Join(bucketConfigPrefix, "bucket", bucketNotificationConfig)
+	if _, err = readConfig(ctx, objLayer, configFile); err != nil {
+		t.Fatalf("Unable to read notification config: %v", err)
+	}
+	// The configuration must never reach the backend.
+	if _, err = readConfig(ctx, backend, configFile); err != errConfigNotFound {
+		t.Fatalf("Expected %v, got %v", errConfigNotFound, err)
+	}
+
+	if err = objLayer.DeleteBucket(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readConfig(ctx, objLayer, configFile); err != errConfigNotFound {
+		t.Fatalf("Expected notification config to be removed, got %v", err)
+	}
+}
+
+func TestGetGatewayMetaDir(t *testing.T) {
+	defer os.Unsetenv("MINIO_GATEWAY_META_DIR")
+
+	os.Unsetenv("MINIO_GATEWAY_META_DIR")
+	if metaDir := getGatewayMetaDir("s3"); metaDir != filepath.Join(getConfigDir(), "gateway", "s3") {
+		t.Fatalf("Unexpected default meta directory %s", metaDir)
+	}
+
+	os.Setenv("MINIO_GATEWAY_META_DIR", "/mnt/gateway")
+	if metaDir := getGatewayMetaDir("s3"); metaDir != "/mnt/gateway" {
+		t.Fatalf("Expected /mnt/gateway, got %s", metaDir)
+	}
+}
@@ -34,3 +34,14 @@ type Gateway interface {
 	// Returns true if gateway is ready for production.
 	Production() bool
 }
+
+// DistributedGateway is implemented by gateways whose instances share
+// a backend and synchronize namespace access with distributed locks.
+type DistributedGateway interface {
+	Gateway
+
+	// Peers returns the addresses of all gateway instances sharing
+	// the backend, including this one. No peers disables distributed
+	// locking.
+	Peers() []string
+}
@@ -83,6 +83,17 @@ ENVIRONMENT VARIABLES:
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
+     MINIO_CACHE_COMMIT: Commit mode of uploads, "writethrough" or "writeback".
+     MINIO_CACHE_RANGE: To enable caching of ranged reads, set this value to "on".
+     MINIO_CACHE_POLICY: Eviction policy of the cache, "lru" or "lfu".
+     MINIO_CACHE_WATERMARK_LOW: Cache usage in percentage of max use at which eviction stops.
+     MINIO_CACHE_WATERMARK_HIGH: Cache usage in percentage of max use at which eviction starts.
+     MINIO_CACHE_INCLUDE: List of cache inclusion patterns delimited by ";".
+     MINIO_CACHE_QUOTA: List of cache quotas of buckets delimited by ";", for example "images=10GiB".
+     MINIO_CACHE_MIN_SIZE: Minimum size of cached objects, for example "1KiB".
+     MINIO_CACHE_MAX_SIZE: Maximum size of cached objects, for example "1GiB".
+     MINIO_CACHE_AFTER_HITS: Number of reads of an object from the backend before it is cached.
+     MINIO_CACHE_ENCRYPTION_MASTER_KEY: Master key encrypting the cache drives, of the form "key-id:hex-key".
 
 EXAMPLES:
   1. Start minio gateway server for Azure Blob Storage backend.
@@ -0,0 +1,908 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdfs
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	hdfsBackend = "hdfs"
+
+	// Directory under the root directory holding temporary files
+	// and staged multipart uploads, it is never listed as a bucket.
+	hdfsMinioSysDir = ".minio.sys"
+
+	// Extended attribute keeping the ETag of files written by the
+	// gateway, HDFS does not keep checksums of the content.
+	hdfsETagXAttr = "user.minio.etag"
+)
+
+func init() {
+	const hdfsGatewayTemplate = `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} {{if .VisibleFlags}}[FLAGS]{{end}} WEBHDFS-ENDPOINT
+{{if .VisibleFlags}}
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}{{end}}
+WEBHDFS-ENDPOINT:
+  WebHDFS endpoint of the name node, the optional path is the directory holding the buckets.
+
+ENVIRONMENT VARIABLES:
+  ACCESS:
+     MINIO_ACCESS_KEY: Username or access key of minimum 3 characters in length.
+     MINIO_SECRET_KEY: Password or secret key of minimum 8 characters in length.
+
+  BROWSER:
+     MINIO_BROWSER: To disable web browser access, set this value to "off".
+
+  DOMAIN:
+     MINIO_DOMAIN: To enable virtual-host-style requests, set this value to Minio host domain name.
+
+  CACHE:
+     MINIO_CACHE_DRIVES: List of mounted drives or directories delimited by ";".
+     MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
+     MINIO_CACHE_EXPIRY: Cache expiry duration in days.
+     MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
+     MINIO_CACHE_COMMIT: Commit mode of uploads, "writethrough" or "writeback".
+     MINIO_CACHE_RANGE: To enable caching of ranged reads, set this value to "on".
+     MINIO_CACHE_POLICY: Eviction policy of the cache, "lru" or "lfu".
+     MINIO_CACHE_WATERMARK_LOW: Cache usage in percentage of max use at which eviction stops.
+     MINIO_CACHE_WATERMARK_HIGH: Cache usage in percentage of max use at which eviction starts.
+     MINIO_CACHE_INCLUDE: List of cache inclusion patterns delimited by ";".
+     MINIO_CACHE_QUOTA: List of cache quotas of buckets delimited by ";", for example "images=10GiB".
+     MINIO_CACHE_MIN_SIZE: Minimum size of cached objects, for example "1KiB".
+     MINIO_CACHE_MAX_SIZE: Maximum size of cached objects, for example "1GiB".
+     MINIO_CACHE_AFTER_HITS: Number of reads of an object from the backend before it is cached.
+     MINIO_CACHE_ENCRYPTION_MASTER_KEY: Master key encrypting the cache drives, of the form "key-id:hex-key".
+
+  HDFS:
+     HADOOP_USER_NAME: User name sent to WebHDFS with every request. (default is empty)
+
+EXAMPLES:
+  1. Start minio gateway server for HDFS backend.
+     $ export MINIO_ACCESS_KEY=accesskey
+     $ export MINIO_SECRET_KEY=secretkey
+     $ {{.HelpName}} http://namenode:9870
+
+  2. Start minio gateway server for HDFS backend storing buckets under /user/minio.
+     $ export MINIO_ACCESS_KEY=accesskey
+     $ export MINIO_SECRET_KEY=secretkey
+     $ export HADOOP_USER_NAME=minio
+     $ {{.HelpName}} http://namenode:9870/user/minio
+`
+
+	minio.RegisterGatewayCommand(cli.Command{
+		Name:               hdfsBackend,
+		Usage:              "Hadoop Distributed File System (HDFS) over WebHDFS.",
+		Action:             hdfsGatewayMain,
+		CustomHelpTemplate: hdfsGatewayTemplate,
+		HideHelpCommand:    true,
+	})
+}
+
+// Handler for 'minio gateway hdfs' command line.
+func hdfsGatewayMain(ctx *cli.Context) {
+	// Validate gateway arguments.
+	if !ctx.Args().Present() || ctx.Args().First() == "help" {
+		cli.ShowCommandHelpAndExit(ctx, hdfsBackend, 1)
+	}
+
+	endpoint, err := parseHDFSEndpoint(ctx.Args().First())
+	logger.FatalIf(err, "Invalid argument")
+	logger.FatalIf(minio.ValidateGatewayArguments(ctx.GlobalString("address"), endpoint.Host), "Invalid argument")
+
+	minio.StartGateway(ctx, &HDFS{endpoint})
+}
+
+// parseHDFSEndpoint - parses the WebHDFS endpoint given on the command line.
+func parseHDFSEndpoint(arg string) (*url.URL, error) {
+	u, err := url.Parse(arg)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("Unrecognized scheme %s", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("Missing host in %s", arg)
+	}
+	return u, nil
+}
+
+// HDFS implements Gateway.
+type HDFS struct {
+	endpoint *url.URL
+}
+
+// Name implements Gateway interface.
+func (g *HDFS) Name() string {
+	return hdfsBackend
+}
+
+// NewGatewayLayer returns hdfs gateway layer, implements ObjectLayer interface to
+// talk to WebHDFS.
+func (g *HDFS) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error) {
+	endpoint := &url.URL{Scheme: g.endpoint.Scheme, Host: g.endpoint.Host}
+	clnt := newWebHDFSClient(endpoint, os.Getenv("HADOOP_USER_NAME"), minio.NewCustomHTTPTransport())
+	return newHDFSObjects(clnt, g.endpoint.Path)
+}
+
+// newHDFSObjects - returns an object layer storing buckets under root.
+func newHDFSObjects(clnt *webHDFSClient, root string) (*hdfsObjects, error) {
+	n := &hdfsObjects{
+		clnt: clnt,
+		root: path.Clean("/" + root),
+	}
+	// Verifies the name node is reachable as well.
+	if err := n.clnt.Mkdirs(context.Background(), path.Join(n.root, hdfsMinioSysDir, "tmp")); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Production - hdfs gateway is not ready for production use.
+func (g *HDFS) Production() bool {
+	return false
+}
+
+// hdfsObjects - Implements Object layer for HDFS over WebHDFS, buckets
+// are the directories of the root directory.
+type hdfsObjects struct {
+	minio.GatewayUnsupported
+	clnt *webHDFSClient
+	root string
+}
+
+func (n *hdfsObjects) bucketPath(bucket string) string {
+	return path.Join(n.root, bucket)
+}
+
+func (n *hdfsObjects) objectPath(bucket, object string) string {
+	return path.Join(n.root, bucket, object)
+}
+
+func (n *hdfsObjects) tmpPath() string {
+	return path.Join(n.root, hdfsMinioSysDir, "tmp", minio.MustGetUUID())
+}
+
+// uploadDir - directory staging the parts of a multipart upload.
+func (n *hdfsObjects) uploadDir(bucket, object, uploadID string) string {
+	sum := sha256.Sum256([]byte(path.Join(bucket, object)))
+	return path.Join(n.root, hdfsMinioSysDir, "multipart", hex.EncodeToString(sum[:]), uploadID)
+}
+
+// hdfsPartName - name of a staged part, the md5sum of its content is
+// kept in the hdfsETagXAttr extended attribute.
+func hdfsPartName(partID int) string {
+	return fmt.Sprintf("%05d", partID)
+}
+
+// parseHDFSPartName - reverses hdfsPartName.
+func parseHDFSPartName(name string) (partID int, ok bool) {
+	partID, err := strconv.Atoi(name)
+	if err != nil || hdfsPartName(partID) != name {
+		return 0, false
+	}
+	return partID, true
+}
+
+// hdfsETag - ETag of files not written by the gateway, which have no
+// ETag saved, it is derived from the modification time and the size
+// of the file.
+func hdfsETag(fi hdfsFileStatus) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%d.%d", fi.ModificationTime, fi.Length)))
+	return minio.ToS3ETag(hex.EncodeToString(sum[:]))
+}
+
+func hdfsModTime(fi hdfsFileStatus) time.Time {
+	return time.Unix(0, fi.ModificationTime*int64(time.Millisecond)).UTC()
+}
+
+func hdfsObjectInfo(bucket, object, etag string, fi hdfsFileStatus) minio.ObjectInfo {
+	if fi.isDir() {
+		return minio.ObjectInfo{
+			Bucket:  bucket,
+			Name:    object,
+			ModTime: hdfsModTime(fi),
+			IsDir:   true,
+		}
+	}
+	return minio.ObjectInfo{
+		Bucket:  bucket,
+		Name:    object,
+		ModTime: hdfsModTime(fi),
+		Size:    fi.Length,
+		ETag:    etag,
+	}
+}
+
+// getETag - returns the ETag saved with a file.
+func (n *hdfsObjects) getETag(ctx context.Context, p string, fi hdfsFileStatus) (string, error) {
+	if fi.isDir() {
+		return "", nil
+	}
+	xattrs, err := n.clnt.GetXAttrs(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if etag, ok := xattrs[hdfsETagXAttr]; ok {
+		return etag, nil
+	}
+	return hdfsETag(fi), nil
+}
+
+// hdfsToObjectErr - converts WebHDFS errors to object layer errors.
+func hdfsToObjectErr(ctx context.Context, err error, params ...string) error {
+	if err == nil {
+		return nil
+	}
+	bucket := ""
+	object := ""
+	uploadID := ""
+	switch len(params) {
+	case 3:
+		uploadID = params[2]
+		fallthrough
+	case 2:
+		object = params[1]
+		fallthrough
+	case 1:
+		bucket = params[0]
+	}
+
+	remoteErr, ok := err.(hdfsRemoteException)
+	if !ok {
+		logger.LogIf(ctx, err)
+		return err
+	}
+	switch remoteErr.Exception {
+	case "FileNotFoundException":
+		if uploadID != "" {
+			return minio.InvalidUploadID{UploadID: uploadID}
+		}
+		if object != "" {
+			return minio.ObjectNotFound{Bucket: bucket, Object: object}
+		}
+		return minio.BucketNotFound{Bucket: bucket}
+	case "FileAlreadyExistsException":
+		if object != "" {
+			return minio.ObjectAlreadyExists{Bucket: bucket, Object: object}
+		}
+		return minio.BucketExists{Bucket: bucket}
+	case "PathIsNotEmptyDirectoryException":
+		return minio.BucketNotEmpty{Bucket: bucket}
+	case "ParentNotDirectoryException":
+		return minio.PrefixAccessDenied{Bucket: bucket, Object: object}
+	case "AccessControlException", "SecurityException":
+		return minio.PrefixAccessDenied{Bucket: bucket, Object: object}
+	}
+	logger.LogIf(ctx, err)
+	return err
+}
+
+// Shutdown - nothing to be done for HDFS.
+func (n *hdfsObjects) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// StorageInfo - Not relevant to HDFS backend.
+func (n *hdfsObjects) StorageInfo(ctx context.Context) (si minio.StorageInfo) {
+	return si
+}
+
+// MakeBucketWithLocation - creates a new directory under the root directory.
+func (n *hdfsObjects) MakeBucketWithLocation(ctx context.Context, bucket, location string) error {
+	if !minio.IsValidBucketName(bucket) {
+		return minio.BucketNameInvalid{Bucket: bucket}
+	}
+	if _, err := n.clnt.GetFileStatus(ctx, n.bucketPath(bucket)); err == nil {
+		return minio.BucketExists{Bucket: bucket}
+	}
+	return hdfsToObjectErr(ctx, n.clnt.Mkdirs(ctx, n.bucketPath(bucket)), bucket)
+}
+
+// GetBucketInfo - returns the bucket directory information.
+func (n *hdfsObjects) GetBucketInfo(ctx context.Context, bucket string) (bi minio.BucketInfo, err error) {
+	fi, err := n.clnt.GetFileStatus(ctx, n.bucketPath(bucket))
+	if err != nil {
+		return bi, hdfsToObjectErr(ctx, err, bucket)
+	}
+	if !fi.isDir() {
+		return bi, minio.BucketNotFound{Bucket: bucket}
+	}
+	return minio.BucketInfo{
+		Name:    bucket,
+		Created: hdfsModTime(fi),
+	}, nil
+}
+
+// ListBuckets - lists the directories of the root directory.
+func (n *hdfsObjects) ListBuckets(ctx context.Context) (buckets []minio.BucketInfo, err error) {
+	entries, err := n.clnt.ListStatus(ctx, n.root)
+	if err != nil {
+		return nil, hdfsToObjectErr(ctx, err)
+	}
+	for _, fi := range entries {
+		// Skip the gateway's own metadata directory, files and
+		// directories which are not valid bucket names.
+		if !fi.isDir() || fi.PathSuffix == hdfsMinioSysDir || !minio.IsValidBucketName(fi.PathSuffix) {
+			continue
+		}
+		buckets = append(buckets, minio.BucketInfo{
+			Name:    fi.PathSuffix,
+			Created: hdfsModTime(fi),
+		})
+	}
+	return buckets, nil
+}
+
+// DeleteBucket - deletes the bucket directory, only if empty.
+func (n *hdfsObjects) DeleteBucket(ctx context.Context, bucket string) error {
+	entries, err := n.clnt.ListStatus(ctx, n.bucketPath(bucket))
+	if err != nil {
+		return hdfsToObjectErr(ctx, err, bucket)
+	}
+	if len(entries) > 0 {
+		return minio.BucketNotEmpty{Bucket: bucket}
+	}
+	return hdfsToObjectErr(ctx, n.clnt.Delete(ctx, n.bucketPath(bucket), false), bucket)
+}
+
+// hdfsListEntry - entry of a directory, directory names end with a slash.
+type hdfsListEntry struct {
+	name string
+	fi   hdfsFileStatus
+}
+
+// listDir - returns the entries of dir sorted the way S3 sorts keys.
+func (n *hdfsObjects) listDir(ctx context.Context, bucket, dir string) ([]hdfsListEntry, error) {
+	statuses, err := n.clnt.ListStatus(ctx, n.objectPath(bucket, dir))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]hdfsListEntry, 0, len(statuses))
+	for _, fi := range statuses {
+		name := dir + fi.PathSuffix
+		if fi.isDir() {
+			name += "/"
+		}
+		entries = append(entries, hdfsListEntry{name, fi})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries, nil
+}
+
+// walk - calls fn for every file and every empty directory under dir
+// in lexical order, entries up to marker and not matching prefix are
+// skipped. Walking stops when fn returns false.
+func (n *hdfsObjects) walk(ctx context.Context, bucket, dir, prefix, marker string, fn func(hdfsListEntry) bool) (bool, error) {
+	entries, err := n.listDir(ctx, bucket, dir)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 && dir != "" && strings.HasPrefix(dir, prefix) && dir > marker {
+		fi, err := n.clnt.GetFileStatus(ctx, n.objectPath(bucket, dir))
+		if err != nil {
+			return false, err
+		}
+		return fn(hdfsListEntry{dir, fi}), nil
+	}
+	for _, entry := range entries {
+		if !entry.fi.isDir() {
+			if strings.HasPrefix(entry.name, prefix) && entry.name > marker {
+				if !fn(entry) {
+					return false, nil
+				}
+			}
+			continue
+		}
+		// Skip directories which cannot hold matching entries.
+		if !strings.HasPrefix(entry.name, prefix) && !strings.HasPrefix(prefix, entry.name) {
+			continue
+		}
+		if entry.name < marker && !strings.HasPrefix(marker, entry.name) {
+			continue
+		}
+		ok, err := n.walk(ctx, bucket, entry.name, prefix, marker, fn)
+		if err != nil || !ok {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+// ListObjects - lists objects by walking the bucket directory.
+func (n *hdfsObjects) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (loi minio.ListObjectsInfo, err error) {
+	if delimiter != "" && delimiter != "/" {
+		return loi, minio.UnsupportedDelimiter{Delimiter: delimiter}
+	}
+	if _, err = n.GetBucketInfo(ctx, bucket); err != nil {
+		return loi, err
+	}
+	if maxKeys <= 0 {
+		return loi, nil
+	}
+
+	var etagErr error
+	add := func(entry hdfsListEntry) bool {
+		if len(loi.Objects)+len(loi.Prefixes) == maxKeys {
+			loi.IsTruncated = true
+			return false
+		}
+		if delimiter != "" && entry.fi.isDir() {
+			loi.Prefixes = append(loi.Prefixes, entry.name)
+		} else {
+			etag, err := n.getETag(ctx, n.objectPath(bucket, entry.name), entry.fi)
+			if err != nil {
+				etagErr = err
+				return false
+			}
+			loi.Objects = append(loi.Objects, hdfsObjectInfo(bucket, entry.name, etag, entry.fi))
+		}
+		loi.NextMarker = entry.name
+		return true
+	}
+
+	if delimiter == "" {
+		_, err = n.walk(ctx, bucket, "", prefix, marker, add)
+	} else {
+		var entries []hdfsListEntry
+		dir := prefix[:strings.LastIndex(prefix, "/")+1]
+		entries, err = n.listDir(ctx, bucket, dir)
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.name, prefix) && entry.name > marker && !add(entry) {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = etagErr
+	}
+	if err != nil {
+		if remoteErr, ok := err.(hdfsRemoteException); ok && remoteErr.Exception == "FileNotFoundException" {
+			// Prefix does not exist.
+			return minio.ListObjectsInfo{}, nil
+		}
+		return loi, hdfsToObjectErr(ctx, err, bucket)
+	}
+	if !loi.IsTruncated {
+		loi.NextMarker = ""
+	}
+	return loi, nil
+}
+
+// ListObjectsV2 - lists objects, see ListObjects.
+func (n *hdfsObjects) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (loi minio.ListObjectsV2Info, err error) {
+	marker := continuationToken
+	if marker == "" {
+		marker = startAfter
+	}
+	resultV1, err := n.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return loi, err
+	}
+	return minio.ListObjectsV2Info{
+		Objects:               resultV1.Objects,
+		Prefixes:              resultV1.Prefixes,
+		ContinuationToken:     continuationToken,
+		NextContinuationToken: resultV1.NextMarker,
+		IsTruncated:           resultV1.IsTruncated,
+	}, nil
+}
+
+// GetObjectInfo - returns the information of a file, directories are
+// only reported for object names ending with a slash.
+func (n *hdfsObjects) GetObjectInfo(ctx context.Context, bucket, object string) (objInfo minio.ObjectInfo, err error) {
+	if _, err = n.GetBucketInfo(ctx, bucket); err != nil {
+		return objInfo, err
+	}
+	fi, err := n.clnt.GetFileStatus(ctx, n.objectPath(bucket, object))
+	if err != nil {
+		return objInfo, hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	if fi.isDir() != strings.HasSuffix(object, "/") {
+		return objInfo, minio.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	etag, err := n.getETag(ctx, n.objectPath(bucket, object), fi)
+	if err != nil {
+		return objInfo, hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	return hdfsObjectInfo(bucket, object, etag, fi), nil
+}
+
+// GetObjectNInfo - returns object info and a reader for the object content.
+func (n *hdfsObjects) GetObjectNInfo(ctx context.Context, bucket, object string, rs *minio.HTTPRangeSpec) (objInfo minio.ObjectInfo, reader io.ReadCloser, err error) {
+	objInfo, err = n.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return objInfo, reader, err
+	}
+
+	startOffset, length := int64(0), objInfo.Size
+	if rs != nil {
+		startOffset, length = rs.GetOffsetLength(objInfo.Size)
+	}
+
+	pr, pw := io.Pipe()
+	objReader := minio.NewGetObjectReader(pr, nil, nil)
+	go func() {
+		err := n.GetObject(ctx, bucket, object, startOffset, length, pw, objInfo.ETag)
+		pw.CloseWithError(err)
+	}()
+
+	return objInfo, objReader, nil
+}
+
+// GetObject - reads length bytes of the file starting at startOffset.
+func (n *hdfsObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	objInfo, err := n.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	if objInfo.IsDir {
+		return nil
+	}
+	if length < 0 {
+		length = objInfo.Size - startOffset
+	}
+	if startOffset < 0 || startOffset > objInfo.Size || startOffset+length > objInfo.Size {
+		return minio.InvalidRange{
+			OffsetBegin:  startOffset,
+			OffsetEnd:    length,
+			ResourceSize: objInfo.Size,
+		}
+	}
+	if length == 0 {
+		return nil
+	}
+
+	reader, err := n.clnt.Open(ctx, n.objectPath(bucket, object), startOffset, length)
+	if err != nil {
+		return hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	defer reader.Close()
+	_, err = io.Copy(writer, io.LimitReader(reader, length))
+	return err
+}
+
+// commit - saves the ETag of a temporary file and moves it to its
+// final location, an existing object is replaced atomically.
+func (n *hdfsObjects) commit(ctx context.Context, tmpPath, etag, bucket, object string) error {
+	if err := n.clnt.SetXAttr(ctx, tmpPath, hdfsETagXAttr, etag); err != nil {
+		return hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	objectPath := n.objectPath(bucket, object)
+	fi, err := n.clnt.GetFileStatus(ctx, objectPath)
+	switch {
+	case err == nil && fi.isDir():
+		return minio.ObjectExistsAsDirectory{Bucket: bucket, Object: object}
+	case err != nil:
+		if err = n.clnt.Mkdirs(ctx, path.Dir(objectPath)); err != nil {
+			return hdfsToObjectErr(ctx, err, bucket, object)
+		}
+	}
+	return hdfsToObjectErr(ctx, n.clnt.Replace(ctx, tmpPath, objectPath), bucket, object)
+}
+
+// PutObject - creates the file in a temporary location first and then
+// renames it to its final location.
+func (n *hdfsObjects) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (objInfo minio.ObjectInfo, err error) {
+	if _, err = n.GetBucketInfo(ctx, bucket); err != nil {
+		return objInfo, err
+	}
+
+	// Directory objects are saved as directories.
+	if strings.HasSuffix(object, "/") {
+		if err = n.clnt.Mkdirs(ctx, n.objectPath(bucket, object)); err != nil {
+			return objInfo, hdfsToObjectErr(ctx, err, bucket, object)
+		}
+		return n.GetObjectInfo(ctx, bucket, object)
+	}
+
+	tmpPath := n.tmpPath()
+	if err = n.clnt.Create(ctx, tmpPath, data, data.Size(), false); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return objInfo, hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	if err = n.commit(ctx, tmpPath, hex.EncodeToString(data.MD5Current()), bucket, object); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return objInfo, err
+	}
+	return n.GetObjectInfo(ctx, bucket, object)
+}
+
+// CopyObject - copies an object by streaming it through the gateway,
+// HDFS keeps no metadata so metadata only copies are no-ops.
+func (n *hdfsObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, srcInfo minio.ObjectInfo) (objInfo minio.ObjectInfo, err error) {
+	cpSrcDstSame := path.Join(srcBucket, srcObject) == path.Join(dstBucket, dstObject)
+	if cpSrcDstSame {
+		return n.GetObjectInfo(ctx, srcBucket, srcObject)
+	}
+
+	go func() {
+		if gerr := n.GetObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
+			return
+		}
+		// Close writer explicitly signaling we wrote all data.
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+			return
+		}
+	}()
+
+	return n.PutObject(ctx, dstBucket, dstObject, srcInfo.Reader, srcInfo.UserDefined)
+}
+
+// deleteEmptyParents - removes the empty parent directories of object
+// up to the bucket directory, as prefixes do not exist without objects.
+func (n *hdfsObjects) deleteEmptyParents(ctx context.Context, bucket, object string) {
+	for dir := path.Dir(strings.TrimSuffix(object, "/")); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		entries, err := n.clnt.ListStatus(ctx, n.objectPath(bucket, dir))
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err = n.clnt.Delete(ctx, n.objectPath(bucket, dir), false); err != nil {
+			return
+		}
+	}
+}
+
+// DeleteObject - deletes a file or an empty directory object.
+func (n *hdfsObjects) DeleteObject(ctx context.Context, bucket, object string) error {
+	objInfo, err := n.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	if objInfo.IsDir {
+		entries, err := n.clnt.ListStatus(ctx, n.objectPath(bucket, object))
+		if err != nil {
+			return hdfsToObjectErr(ctx, err, bucket, object)
+		}
+		// Directory still holds objects, only the directory object is gone.
+		if len(entries) > 0 {
+			return nil
+		}
+	}
+	if err = n.clnt.Delete(ctx, n.objectPath(bucket, object), false); err != nil {
+		return hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	n.deleteEmptyParents(ctx, bucket, object)
+	return nil
+}
+
+// NewMultipartUpload - creates the directory staging the parts of the upload.
+func (n *hdfsObjects) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
+	if _, err = n.GetBucketInfo(ctx, bucket); err != nil {
+		return "", err
+	}
+	uploadID = minio.MustGetUUID()
+	if err = n.clnt.Mkdirs(ctx, n.uploadDir(bucket, object, uploadID)); err != nil {
+		return "", hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	return uploadID, nil
+}
+
+// checkUploadIDExists - validates the upload is ongoing.
+func (n *hdfsObjects) checkUploadIDExists(ctx context.Context, bucket, object, uploadID string) error {
+	if _, err := n.clnt.GetFileStatus(ctx, n.uploadDir(bucket, object, uploadID)); err != nil {
+		return hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+	return nil
+}
+
+// listParts - returns the staged parts of an upload sorted by part number.
+func (n *hdfsObjects) listParts(ctx context.Context, bucket, object, uploadID string) ([]minio.PartInfo, error) {
+	entries, err := n.clnt.ListStatus(ctx, n.uploadDir(bucket, object, uploadID))
+	if err != nil {
+		return nil, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+	var parts []minio.PartInfo
+	for _, fi := range entries {
+		partID, ok := parseHDFSPartName(fi.PathSuffix)
+		if !ok || fi.isDir() {
+			continue
+		}
+		etag, err := n.getETag(ctx, path.Join(n.uploadDir(bucket, object, uploadID), fi.PathSuffix), fi)
+		if err != nil {
+			return nil, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+		}
+		parts = append(parts, minio.PartInfo{
+			PartNumber:   partID,
+			LastModified: hdfsModTime(fi),
+			ETag:         etag,
+			Size:         fi.Length,
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts, nil
+}
+
+// PutObjectPart - stages a part in the upload directory, the part is
+// written to a temporary file which atomically replaces a previously
+// uploaded part with the same number.
+func (n *hdfsObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *hash.Reader) (info minio.PartInfo, err error) {
+	if err = n.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+		return info, err
+	}
+
+	tmpPath := n.tmpPath()
+	if err = n.clnt.Create(ctx, tmpPath, data, data.Size(), false); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return info, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+	etag := hex.EncodeToString(data.MD5Current())
+	if err = n.clnt.SetXAttr(ctx, tmpPath, hdfsETagXAttr, etag); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return info, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+
+	partPath := path.Join(n.uploadDir(bucket, object, uploadID), hdfsPartName(partID))
+	if err = n.clnt.Replace(ctx, tmpPath, partPath); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return info, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+	fi, err := n.clnt.GetFileStatus(ctx, partPath)
+	if err != nil {
+		return info, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+	}
+	return minio.PartInfo{
+		PartNumber:   partID,
+		LastModified: hdfsModTime(fi),
+		ETag:         etag,
+		Size:         fi.Length,
+	}, nil
+}
+
+// ListObjectParts - lists the staged parts of an upload.
+func (n *hdfsObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (result minio.ListPartsInfo, err error) {
+	parts, err := n.listParts(ctx, bucket, object, uploadID)
+	if err != nil {
+		return result, err
+	}
+	result = minio.ListPartsInfo{
+		Bucket:           bucket,
+		Object:           object,
+		UploadID:         uploadID,
+		PartNumberMarker: partNumberMarker,
+		MaxParts:         maxParts,
+	}
+	for _, part := range parts {
+		if part.PartNumber <= partNumberMarker {
+			continue
+		}
+		if len(result.Parts) == maxParts {
+			result.IsTruncated = true
+			break
+		}
+		result.Parts = append(result.Parts, part)
+		result.NextPartNumberMarker = part.PartNumber
+	}
+	return result, nil
+}
+
+// AbortMultipartUpload - removes the upload directory with all its parts.
+func (n *hdfsObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	if err := n.checkUploadIDExists(ctx, bucket, object, uploadID); err != nil {
+		return err
+	}
+	return hdfsToObjectErr(ctx, n.clnt.Delete(ctx, n.uploadDir(bucket, object, uploadID), true), bucket, object, uploadID)
+}
+
+// CompleteMultipartUpload - concatenates the staged parts into a
+// temporary file, which is then renamed to the object.
+func (n *hdfsObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []minio.CompletePart) (objInfo minio.ObjectInfo, err error) {
+	parts, err := n.listParts(ctx, bucket, object, uploadID)
+	if err != nil {
+		return objInfo, err
+	}
+	stagedParts := make(map[int]minio.PartInfo, len(parts))
+	for _, part := range parts {
+		stagedParts[part.PartNumber] = part
+	}
+
+	uploadDir := n.uploadDir(bucket, object, uploadID)
+	for i := range uploadedParts {
+		part := &uploadedParts[i]
+		part.ETag = minio.CanonicalizeETag(part.ETag)
+		stagedPart, ok := stagedParts[part.PartNumber]
+		if !ok || stagedPart.ETag != part.ETag {
+			return objInfo, minio.InvalidPart{
+				PartNumber: part.PartNumber,
+				GotETag:    part.ETag,
+			}
+		}
+		size := stagedPart.Size
+		// Error out if parts except last part sizing < 5MiB.
+		if i < len(uploadedParts)-1 && size < 5*humanize.MiByte {
+			return objInfo, minio.PartTooSmall{
+				PartNumber: part.PartNumber,
+				PartSize:   size,
+				PartETag:   part.ETag,
+			}
+		}
+	}
+
+	s3MD5, err := minio.ComputeCompleteMultipartMD5(ctx, uploadedParts)
+	if err != nil {
+		return objInfo, err
+	}
+
+	tmpPath := n.tmpPath()
+	if err = n.clnt.Create(ctx, tmpPath, http.NoBody, 0, false); err != nil {
+		return objInfo, hdfsToObjectErr(ctx, err, bucket, object)
+	}
+	for _, part := range uploadedParts {
+		partPath := path.Join(uploadDir, hdfsPartName(part.PartNumber))
+		if err = n.appendPart(ctx, tmpPath, partPath, stagedParts[part.PartNumber].Size); err != nil {
+			n.clnt.Delete(ctx, tmpPath, false)
+			return objInfo, hdfsToObjectErr(ctx, err, bucket, object, uploadID)
+		}
+	}
+	if err = n.commit(ctx, tmpPath, s3MD5, bucket, object); err != nil {
+		n.clnt.Delete(ctx, tmpPath, false)
+		return objInfo, err
+	}
+	if err = n.clnt.Delete(ctx, uploadDir, true); err != nil {
+		logger.LogIf(ctx, err)
+	}
+	return n.GetObjectInfo(ctx, bucket, object)
+}
+
+// appendPart - appends the content of a staged part to the file at p.
+func (n *hdfsObjects) appendPart(ctx context.Context, p, partPath string, size int64) error {
+	if size == 0 {
+		return nil
+	}
+	reader, err := n.clnt.Open(ctx, partPath, 0, size)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return n.clnt.Append(ctx, p, reader, size)
+}
@@ -0,0 +1,528 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	humanize "github.com/dustin/go-humanize"
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/pkg/hash"
+)
+
+// webHDFSStub - WebHDFS server storing files in a local directory,
+// data of CREATE, APPEND and OPEN calls goes through a redirect to
+// a "data node" like on a real cluster. Extended attributes are kept
+// in memory.
+type webHDFSStub struct {
+	root string
+
+	mu     sync.Mutex
+	xattrs map[string]map[string]string
+}
+
+// moveXAttrs - moves the extended attributes of src and of all the
+// files under it to dst, they are dropped if dst is empty.
+func (s *webHDFSStub) moveXAttrs(src, dst string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p, xattrs := range s.xattrs {
+		if p != src && !strings.HasPrefix(p, src+string(filepath.Separator)) {
+			continue
+		}
+		delete(s.xattrs, p)
+		if dst != "" {
+			s.xattrs[dst+strings.TrimPrefix(p, src)] = xattrs
+		}
+	}
+}
+
+const webHDFSStubDataNode = "/datanode"
+
+func (s *webHDFSStub) writeException(w http.ResponseWriter, status int, exception string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]hdfsRemoteException{
+		"RemoteException": {Exception: exception, Message: exception},
+	})
+}
+
+func (s *webHDFSStub) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *webHDFSStub) fileStatus(fi os.FileInfo, pathSuffix string) hdfsFileStatus {
+	status := hdfsFileStatus{
+		PathSuffix:       pathSuffix,
+		Type:             hdfsTypeFile,
+		Length:           fi.Size(),
+		ModificationTime: fi.ModTime().UnixNano() / 1e6,
+	}
+	if fi.IsDir() {
+		status.Type = hdfsTypeDirectory
+		status.Length = 0
+	}
+	return status
+}
+
+func (s *webHDFSStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	dataNode := strings.HasPrefix(r.URL.Path, webHDFSStubDataNode)
+	p := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, webHDFSStubDataNode), webHDFSPrefix)
+	fsPath := filepath.Join(s.root, filepath.FromSlash(p))
+	query := r.URL.Query()
+
+	redirect := func() {
+		u := *r.URL
+		u.Path = webHDFSStubDataNode + r.URL.Path
+		w.Header().Set("Location", "http://"+r.Host+u.String())
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	}
+
+	switch query.Get("op") {
+	case "GETFILESTATUS":
+		fi, err := os.Stat(fsPath)
+		if err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		s.writeJSON(w, map[string]hdfsFileStatus{"FileStatus": s.fileStatus(fi, "")})
+	case "LISTSTATUS":
+		fi, err := os.Stat(fsPath)
+		if err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		statuses := []hdfsFileStatus{}
+		if fi.IsDir() {
+			fis, err := ioutil.ReadDir(fsPath)
+			if err != nil {
+				s.writeException(w, http.StatusInternalServerError, "IOException")
+				return
+			}
+			for _, fi := range fis {
+				statuses = append(statuses, s.fileStatus(fi, fi.Name()))
+			}
+		} else {
+			statuses = append(statuses, s.fileStatus(fi, ""))
+		}
+		s.writeJSON(w, map[string]map[string][]hdfsFileStatus{
+			"FileStatuses": {"FileStatus": statuses},
+		})
+	case "MKDIRS":
+		if err := os.MkdirAll(fsPath, 0755); err != nil {
+			s.writeException(w, http.StatusForbidden, "ParentNotDirectoryException")
+			return
+		}
+		s.writeJSON(w, map[string]bool{"boolean": true})
+	case "CREATE":
+		if !dataNode {
+			redirect()
+			return
+		}
+		if _, err := os.Stat(fsPath); err == nil && query.Get("overwrite") != "true" {
+			s.writeException(w, http.StatusForbidden, "FileAlreadyExistsException")
+			return
+		}
+		os.MkdirAll(filepath.Dir(fsPath), 0755)
+		s.moveXAttrs(fsPath, "")
+		f, err := os.Create(fsPath)
+		if err != nil {
+			s.writeException(w, http.StatusForbidden, "ParentNotDirectoryException")
+			return
+		}
+		defer f.Close()
+		if _, err = io.Copy(f, r.Body); err != nil {
+			s.writeException(w, http.StatusInternalServerError, "IOException")
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case "APPEND":
+		if !dataNode {
+			redirect()
+			return
+		}
+		f, err := os.OpenFile(fsPath, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		defer f.Close()
+		if _, err = io.Copy(f, r.Body); err != nil {
+			s.writeException(w, http.StatusInternalServerError, "IOException")
+		}
+	case "OPEN":
+		if !dataNode {
+			redirect()
+			return
+		}
+		f, err := os.Open(fsPath)
+		if err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		defer f.Close()
+		offset, _ := strconv.ParseInt(query.Get("offset"), 10, 64)
+		f.Seek(offset, io.SeekStart)
+		var reader io.Reader = f
+		if query.Get("length") != "" {
+			length, _ := strconv.ParseInt(query.Get("length"), 10, 64)
+			reader = io.LimitReader(f, length)
+		}
+		io.Copy(w, reader)
+	case "RENAME":
+		dst := filepath.Join(s.root, filepath.FromSlash(query.Get("destination")))
+		_, srcErr := os.Stat(fsPath)
+		dstFi, dstErr := os.Stat(dst)
+		if query.Get("renameoptions") == "OVERWRITE" {
+			if srcErr != nil {
+				s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+				return
+			}
+			if (dstErr == nil && dstFi.IsDir()) || os.Rename(fsPath, dst) != nil {
+				s.writeException(w, http.StatusForbidden, "FileAlreadyExistsException")
+				return
+			}
+			s.moveXAttrs(dst, "")
+			s.moveXAttrs(fsPath, dst)
+			return
+		}
+		if srcErr != nil || dstErr == nil || os.Rename(fsPath, dst) != nil {
+			s.writeJSON(w, map[string]bool{"boolean": false})
+			return
+		}
+		s.moveXAttrs(fsPath, dst)
+		s.writeJSON(w, map[string]bool{"boolean": true})
+	case "SETXATTR":
+		if _, err := os.Stat(fsPath); err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		s.mu.Lock()
+		if s.xattrs[fsPath] == nil {
+			s.xattrs[fsPath] = make(map[string]string)
+		}
+		s.xattrs[fsPath][query.Get("xattr.name")] = query.Get("xattr.value")
+		s.mu.Unlock()
+	case "GETXATTRS":
+		if _, err := os.Stat(fsPath); err != nil {
+			s.writeException(w, http.StatusNotFound, "FileNotFoundException")
+			return
+		}
+		xattrs := []hdfsXAttr{}
+		s.mu.Lock()
+		for name, value := range s.xattrs[fsPath] {
+			xattrs = append(xattrs, hdfsXAttr{Name: name, Value: value})
+		}
+		s.mu.Unlock()
+		s.writeJSON(w, map[string][]hdfsXAttr{"XAttrs": xattrs})
+	case "DELETE":
+		if _, err := os.Stat(fsPath); err != nil {
+			s.writeJSON(w, map[string]bool{"boolean": false})
+			return
+		}
+		var err error
+		if query.Get("recursive") == "true" {
+			err = os.RemoveAll(fsPath)
+		} else {
+			err = os.Remove(fsPath)
+		}
+		if err != nil {
+			s.writeException(w, http.StatusForbidden, "PathIsNotEmptyDirectoryException")
+			return
+		}
+		s.moveXAttrs(fsPath, "")
+		s.writeJSON(w, map[string]bool{"boolean": true})
+	default:
+		s.writeException(w, http.StatusBadRequest, "IllegalArgumentException")
+	}
+}
+
+func prepareHDFS(t *testing.T) (*hdfsObjects, func()) {
+	root, err := ioutil.TempDir("", "minio-webhdfs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(&webHDFSStub{root: root, xattrs: make(map[string]map[string]string)})
+	endpoint, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := newHDFSObjects(newWebHDFSClient(endpoint, "minio", http.DefaultTransport), "/user/minio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n, func() {
+		server.Close()
+		os.RemoveAll(root)
+	}
+}
+
+func mustGetHashReader(t *testing.T, data []byte) *hash.Reader {
+	reader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func getMD5Sum(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseHDFSEndpoint(t *testing.T) {
+	testCases := []struct {
+		arg     string
+		host    string
+		path    string
+		success bool
+	}{
+		{"http://namenode:9870", "namenode:9870", "", true},
+		{"https://namenode:9871/user/minio", "namenode:9871", "/user/minio", true},
+		{"hdfs://namenode:8020", "", "", false},
+		{"namenode:9870", "", "", false},
+	}
+	for i, testCase := range testCases {
+		u, err := parseHDFSEndpoint(testCase.arg)
+		if testCase.success != (err == nil) {
+			t.Fatalf("Test %d: expected success %t, got %v", i+1, testCase.success, err)
+		}
+		if err == nil && (u.Host != testCase.host || u.Path != testCase.path) {
+			t.Errorf("Test %d: expected %s%s, got %s%s", i+1, testCase.host, testCase.path, u.Host, u.Path)
+		}
+	}
+}
+
+func TestHDFSObjects(t *testing.T) {
+	n, cleanup := prepareHDFS(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := n.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.MakeBucketWithLocation(ctx, "bucket", ""); !reflect.DeepEqual(err, minio.BucketExists{Bucket: "bucket"}) {
+		t.Fatalf("Expected BucketExists, got %v", err)
+	}
+	buckets, err := n.ListBuckets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "bucket" {
+		t.Fatalf("Expected only bucket to be listed, got %v", buckets)
+	}
+
+	objects := []string{"e.txt", "a/d.txt", "a/b/c.txt", "a-b.txt"}
+	for _, object := range objects {
+		data := []byte("content of " + object)
+		objInfo, err := n.PutObject(ctx, "bucket", object, mustGetHashReader(t, data), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.Size != int64(len(data)) {
+			t.Fatalf("%s: expected size %d, got %d", object, len(data), objInfo.Size)
+		}
+		if etag := getMD5Sum(data); objInfo.ETag != etag {
+			t.Fatalf("%s: expected ETag %s, got %s", object, etag, objInfo.ETag)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err = n.GetObject(ctx, "bucket", "a/b/c.txt", 11, 5, &buffer, ""); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != "a/b/c" {
+		t.Fatalf("Expected a/b/c, got %s", buffer.String())
+	}
+	if _, err = n.GetObjectInfo(ctx, "bucket", "a"); !reflect.DeepEqual(err, minio.ObjectNotFound{Bucket: "bucket", Object: "a"}) {
+		t.Fatalf("Expected directory not to be an object, got %v", err)
+	}
+
+	listNames := func(prefix, marker, delimiter string, maxKeys int) (names []string, loi minio.ListObjectsInfo) {
+		loi, err := n.ListObjects(ctx, "bucket", prefix, marker, delimiter, maxKeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, prefix := range loi.Prefixes {
+			names = append(names, prefix)
+		}
+		for _, obj := range loi.Objects {
+			names = append(names, obj.Name)
+			if etag := getMD5Sum([]byte("content of " + obj.Name)); obj.ETag != etag {
+				t.Fatalf("%s: expected listed ETag %s, got %s", obj.Name, etag, obj.ETag)
+			}
+		}
+		return names, loi
+	}
+
+	testCases := []struct {
+		prefix, marker, delimiter string
+		maxKeys                   int
+		names                     []string
+		isTruncated               bool
+	}{
+		{"", "", "", 1000, []string{"a-b.txt", "a/b/c.txt", "a/d.txt", "e.txt"}, false},
+		{"", "", "", 2, []string{"a-b.txt", "a/b/c.txt"}, true},
+		{"", "a/b/c.txt", "", 2, []string{"a/d.txt", "e.txt"}, false},
+		{"a/", "", "", 1000, []string{"a/b/c.txt", "a/d.txt"}, false},
+		{"", "", "/", 1000, []string{"a/", "a-b.txt", "e.txt"}, false},
+		{"a/", "", "/", 1000, []string{"a/b/", "a/d.txt"}, false},
+		{"missing/", "", "/", 1000, nil, false},
+	}
+	for i, testCase := range testCases {
+		names, loi := listNames(testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if !reflect.DeepEqual(names, testCase.names) || loi.IsTruncated != testCase.isTruncated {
+			t.Errorf("Test %d: expected %v (truncated %t), got %v (truncated %t)", i+1,
+				testCase.names, testCase.isTruncated, names, loi.IsTruncated)
+		}
+	}
+
+	if err = n.DeleteBucket(ctx, "bucket"); !reflect.DeepEqual(err, minio.BucketNotEmpty{Bucket: "bucket"}) {
+		t.Fatalf("Expected BucketNotEmpty, got %v", err)
+	}
+
+	// Deleting the last object of a directory removes the prefix.
+	if err = n.DeleteObject(ctx, "bucket", "a/b/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if names, _ := listNames("a/", "", "/", 1000); !reflect.DeepEqual(names, []string{"a/d.txt"}) {
+		t.Fatalf("Expected only a/d.txt, got %v", names)
+	}
+	if err = n.DeleteObject(ctx, "bucket", "a/b/c.txt"); !reflect.DeepEqual(err, minio.ObjectNotFound{Bucket: "bucket", Object: "a/b/c.txt"}) {
+		t.Fatalf("Expected ObjectNotFound, got %v", err)
+	}
+	for _, object := range []string{"a/d.txt", "a-b.txt", "e.txt"} {
+		if err = n.DeleteObject(ctx, "bucket", object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = n.DeleteBucket(ctx, "bucket"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHDFSMultipartUpload(t *testing.T) {
+	n, cleanup := prepareHDFS(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	if err := n.MakeBucketWithLocation(ctx, "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := n.NewMultipartUpload(ctx, "bucket", "dir/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part1 := bytes.Repeat([]byte("a"), 5*humanize.MiByte)
+	part2 := []byte("second part")
+	var parts []minio.CompletePart
+	for i, data := range [][]byte{part1, []byte("replaced"), part2} {
+		partID := 1
+		if i > 0 {
+			partID = 2
+		}
+		pi, err := n.PutObjectPart(ctx, "bucket", "dir/object", uploadID, partID, mustGetHashReader(t, data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pi.Size != int64(len(data)) {
+			t.Fatalf("Part %d: expected size %d, got %d", partID, len(data), pi.Size)
+		}
+		if i != 1 {
+			parts = append(parts, minio.CompletePart{PartNumber: pi.PartNumber, ETag: pi.ETag})
+		}
+	}
+
+	lpi, err := n.ListObjectParts(ctx, "bucket", "dir/object", uploadID, 0, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lpi.Parts) != 2 || lpi.Parts[1].ETag != parts[1].ETag {
+		t.Fatalf("Expected 2 parts with the replaced second part, got %v", lpi.Parts)
+	}
+	if _, err = n.ListObjectParts(ctx, "bucket", "other", uploadID, 0, 1000); !reflect.DeepEqual(err, minio.InvalidUploadID{UploadID: uploadID}) {
+		t.Fatalf("Expected InvalidUploadID, got %v", err)
+	}
+
+	// Parts other than the last one must be at least 5MiB.
+	_, err = n.CompleteMultipartUpload(ctx, "bucket", "dir/object", uploadID, []minio.CompletePart{parts[1], parts[0]})
+	if _, ok := err.(minio.PartTooSmall); !ok {
+		t.Fatalf("Expected PartTooSmall, got %v", err)
+	}
+
+	objInfo, err := n.CompleteMultipartUpload(ctx, "bucket", "dir/object", uploadID, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(part1)+len(part2)) {
+		t.Fatalf("Expected size %d, got %d", len(part1)+len(part2), objInfo.Size)
+	}
+	if etag, _ := minio.ComputeCompleteMultipartMD5(ctx, parts); objInfo.ETag != etag || !strings.HasSuffix(etag, "-2") {
+		t.Fatalf("Expected multipart ETag %s, got %s", etag, objInfo.ETag)
+	}
+	var buffer bytes.Buffer
+	if err = n.GetObject(ctx, "bucket", "dir/object", 0, -1, &buffer, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), append(part1, part2...)) {
+		t.Fatal("Object content does not match the uploaded parts")
+	}
+	if _, err = n.ListObjectParts(ctx, "bucket", "dir/object", uploadID, 0, 1000); !reflect.DeepEqual(err, minio.InvalidUploadID{UploadID: uploadID}) {
+		t.Fatalf("Expected upload to be removed, got %v", err)
+	}
+
+	uploadID, err = n.NewMultipartUpload(ctx, "bucket", "dir/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = n.AbortMultipartUpload(ctx, "bucket", "dir/object", uploadID); err != nil {
+		t.Fatal(err)
+	}
+	if err = n.AbortMultipartUpload(ctx, "bucket", "dir/object", uploadID); !reflect.DeepEqual(err, minio.InvalidUploadID{UploadID: uploadID}) {
+		t.Fatalf("Expected InvalidUploadID, got %v", err)
+	}
+}
+
+func TestParseHDFSPartName(t *testing.T) {
+	for _, partID := range []int{1, 99, 10000} {
+		gotPartID, ok := parseHDFSPartName(hdfsPartName(partID))
+		if !ok || gotPartID != partID {
+			t.Errorf("Expected %d, got %d", partID, gotPartID)
+		}
+	}
+	if _, ok := parseHDFSPartName(minio.MustGetUUID()); ok {
+		t.Error("Temporary part files must not be parsed as parts")
+	}
+}
@@ -0,0 +1,332 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+)
+
+const (
+	// Prefix of all WebHDFS REST API calls.
+	webHDFSPrefix = "/webhdfs/v1"
+
+	hdfsTypeFile      = "FILE"
+	hdfsTypeDirectory = "DIRECTORY"
+)
+
+// hdfsFileStatus - status of a file or a directory as returned by
+// GETFILESTATUS and LISTSTATUS.
+type hdfsFileStatus struct {
+	PathSuffix       string `json:"pathSuffix"`
+	Type             string `json:"type"`
+	Length           int64  `json:"length"`
+	ModificationTime int64  `json:"modificationTime"`
+}
+
+// isDir - returns true if the status describes a directory.
+func (fi hdfsFileStatus) isDir() bool {
+	return fi.Type == hdfsTypeDirectory
+}
+
+// hdfsXAttr - extended attribute of a file as returned by GETXATTRS.
+type hdfsXAttr struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// hdfsRemoteException - error returned by the WebHDFS REST API.
+type hdfsRemoteException struct {
+	Exception     string `json:"exception"`
+	JavaClassName string `json:"javaClassName"`
+	Message       string `json:"message"`
+}
+
+func (e hdfsRemoteException) Error() string {
+	return fmt.Sprintf("%s: %s", e.Exception, e.Message)
+}
+
+// errHDFSOperationFailed - returned when a call answers `{"boolean": false}`.
+var errHDFSOperationFailed = errors.New("WebHDFS operation failed")
+
+// webHDFSClient - minimal client of the WebHDFS REST API.
+type webHDFSClient struct {
+	// Scheme and host of the name node.
+	endpoint *url.URL
+	// User name sent with every call, empty when not set.
+	user string
+	// HTTP client, never follows redirects on its own.
+	client *http.Client
+}
+
+func newWebHDFSClient(endpoint *url.URL, user string, transport http.RoundTripper) *webHDFSClient {
+	return &webHDFSClient{
+		endpoint: endpoint,
+		user:     user,
+		client: &http.Client{
+			Transport: transport,
+			// Data of CREATE and APPEND calls is sent to the data node
+			// the name node redirects to, redirects are handled by the
+			// caller for all calls.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// opURL - returns the URL of the operation op on the path p.
+func (c *webHDFSClient) opURL(p, op string, params url.Values) string {
+	if params == nil {
+		params = make(url.Values)
+	}
+	params.Set("op", op)
+	if c.user != "" {
+		params.Set("user.name", c.user)
+	}
+	u := *c.endpoint
+	u.Path = webHDFSPrefix + path.Clean("/"+p)
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+// decodeRemoteException - returns the error of a failed call.
+func decodeRemoteException(resp *http.Response) error {
+	var v struct {
+		RemoteException hdfsRemoteException `json:"RemoteException"`
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, &v); err != nil || v.RemoteException.Exception == "" {
+		return fmt.Errorf("WebHDFS call failed with %s", resp.Status)
+	}
+	return v.RemoteException
+}
+
+// do - issues a call, the response body must be closed by the caller
+// unless an error is returned. Redirects are followed for calls
+// without a body.
+func (c *webHDFSClient) do(ctx context.Context, method, urlStr string, body io.Reader, size int64) (*http.Response, error) {
+	if body != nil && size == 0 {
+		// Avoids a chunked request for empty content.
+		body = http.NoBody
+	}
+	for redirects := 0; ; redirects++ {
+		req, err := http.NewRequest(method, urlStr, body)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		if body != nil {
+			req.ContentLength = size
+			req.Header.Set("Content-Type", "application/octet-stream")
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusTemporaryRedirect, http.StatusFound, http.StatusSeeOther:
+			resp.Body.Close()
+			if body != nil || redirects >= 5 {
+				return nil, fmt.Errorf("WebHDFS call redirected unexpectedly")
+			}
+			location, err := resp.Location()
+			if err != nil {
+				return nil, err
+			}
+			urlStr = location.String()
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			err = decodeRemoteException(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// call - issues a call and decodes its JSON response into v, if not nil.
+func (c *webHDFSClient) call(ctx context.Context, method, p, op string, params url.Values, v interface{}) error {
+	resp, err := c.do(ctx, method, c.opURL(p, op, params), nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// callBool - issues a call answering a boolean.
+func (c *webHDFSClient) callBool(ctx context.Context, method, p, op string, params url.Values) error {
+	var v struct {
+		Boolean bool `json:"boolean"`
+	}
+	if err := c.call(ctx, method, p, op, params, &v); err != nil {
+		return err
+	}
+	if !v.Boolean {
+		return errHDFSOperationFailed
+	}
+	return nil
+}
+
+// sendData - issues a CREATE or APPEND call, the name node answers
+// with a redirect to the data node which receives the data.
+func (c *webHDFSClient) sendData(ctx context.Context, method, p, op string, params url.Values, data io.Reader, size int64) error {
+	req, err := http.NewRequest(method, c.opURL(p, op, params), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTemporaryRedirect {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return decodeRemoteException(resp)
+		}
+		return fmt.Errorf("WebHDFS %s call was not redirected to a data node", op)
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return err
+	}
+
+	resp, err = c.do(ctx, method, location.String(), data, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetFileStatus - returns the status of a file or a directory.
+func (c *webHDFSClient) GetFileStatus(ctx context.Context, p string) (fi hdfsFileStatus, err error) {
+	var v struct {
+		FileStatus hdfsFileStatus `json:"FileStatus"`
+	}
+	if err = c.call(ctx, http.MethodGet, p, "GETFILESTATUS", nil, &v); err != nil {
+		return fi, err
+	}
+	return v.FileStatus, nil
+}
+
+// ListStatus - returns the status of all entries of a directory.
+func (c *webHDFSClient) ListStatus(ctx context.Context, p string) ([]hdfsFileStatus, error) {
+	var v struct {
+		FileStatuses struct {
+			FileStatus []hdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	if err := c.call(ctx, http.MethodGet, p, "LISTSTATUS", nil, &v); err != nil {
+		return nil, err
+	}
+	return v.FileStatuses.FileStatus, nil
+}
+
+// Mkdirs - creates a directory and all its missing parents.
+func (c *webHDFSClient) Mkdirs(ctx context.Context, p string) error {
+	return c.callBool(ctx, http.MethodPut, p, "MKDIRS", nil)
+}
+
+// Create - creates a file with the given content.
+func (c *webHDFSClient) Create(ctx context.Context, p string, data io.Reader, size int64, overwrite bool) error {
+	params := url.Values{}
+	params.Set("overwrite", strconv.FormatBool(overwrite))
+	return c.sendData(ctx, http.MethodPut, p, "CREATE", params, data, size)
+}
+
+// Append - appends the given content to a file.
+func (c *webHDFSClient) Append(ctx context.Context, p string, data io.Reader, size int64) error {
+	return c.sendData(ctx, http.MethodPost, p, "APPEND", nil, data, size)
+}
+
+// Open - returns the content of a file starting at offset, the
+// whole remaining content is returned for a negative length.
+func (c *webHDFSClient) Open(ctx context.Context, p string, offset, length int64) (io.ReadCloser, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	if length >= 0 {
+		params.Set("length", strconv.FormatInt(length, 10))
+	}
+	resp, err := c.do(ctx, http.MethodGet, c.opURL(p, "OPEN", params), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Replace - renames a file, an existing destination file is replaced
+// atomically.
+func (c *webHDFSClient) Replace(ctx context.Context, src, dst string) error {
+	params := url.Values{}
+	params.Set("destination", path.Clean("/"+dst))
+	params.Set("renameoptions", "OVERWRITE")
+	return c.call(ctx, http.MethodPut, src, "RENAME", params, nil)
+}
+
+// SetXAttr - creates an extended attribute of a file with a text value.
+func (c *webHDFSClient) SetXAttr(ctx context.Context, p, name, value string) error {
+	params := url.Values{}
+	params.Set("xattr.name", name)
+	params.Set("xattr.value", strconv.Quote(value))
+	params.Set("flag", "CREATE")
+	return c.call(ctx, http.MethodPut, p, "SETXATTR", params, nil)
+}
+
+// GetXAttrs - returns the extended attributes of a file with text values.
+func (c *webHDFSClient) GetXAttrs(ctx context.Context, p string) (map[string]string, error) {
+	params := url.Values{}
+	params.Set("encoding", "text")
+	var v struct {
+		XAttrs []hdfsXAttr `json:"XAttrs"`
+	}
+	if err := c.call(ctx, http.MethodGet, p, "GETXATTRS", params, &v); err != nil {
+		return nil, err
+	}
+	xattrs := make(map[string]string, len(v.XAttrs))
+	for _, xattr := range v.XAttrs {
+		value, err := strconv.Unquote(xattr.Value)
+		if err != nil {
+			value = xattr.Value
+		}
+		xattrs[xattr.Name] = value
+	}
+	return xattrs, nil
+}
+
+// Delete - deletes a file or a directory.
+func (c *webHDFSClient) Delete(ctx context.Context, p string, recursive bool) error {
+	params := url.Values{}
+	params.Set("recursive", strconv.FormatBool(recursive))
+	return c.callBool(ctx, http.MethodDelete, p, "DELETE", params)
+}
@@ -17,6 +17,9 @@
 package nas
 
 import (
+	"os"
+	"strings"
+
 	"github.com/minio/cli"
 	minio "github.com/minio/minio/cmd"
 	"github.com/minio/minio/pkg/auth"
@@ -50,11 +53,26 @@ ENVIRONMENT VARIABLES:
   DOMAIN:
      MINIO_DOMAIN: To enable virtual-host-style requests, set this value to Minio host domain name.
 
+  DISTRIBUTED:
+     MINIO_NAS_PEERS: List of addresses of all gateway instances sharing the NAS volume delimited by ";",
+                      at least 3 instances are required as writes need n/2+1 instances online.
+
   CACHE:
      MINIO_CACHE_DRIVES: List of mounted drives or directories delimited by ";".
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
+     MINIO_CACHE_COMMIT: Commit mode of uploads, "writethrough" or "writeback".
+     MINIO_CACHE_RANGE: To enable caching of ranged reads, set this value to "on".
+     MINIO_CACHE_POLICY: Eviction policy of the cache, "lru" or "lfu".
+     MINIO_CACHE_WATERMARK_LOW: Cache usage in percentage of max use at which eviction stops.
+     MINIO_CACHE_WATERMARK_HIGH: Cache usage in percentage of max use at which eviction starts.
+     MINIO_CACHE_INCLUDE: List of cache inclusion patterns delimited by ";".
+     MINIO_CACHE_QUOTA: List of cache quotas of buckets delimited by ";", for example "images=10GiB".
+     MINIO_CACHE_MIN_SIZE: Minimum size of cached objects, for example "1KiB".
+     MINIO_CACHE_MAX_SIZE: Maximum size of cached objects, for example "1GiB".
+     MINIO_CACHE_AFTER_HITS: Number of reads of an object from the backend before it is cached.
+     MINIO_CACHE_ENCRYPTION_MASTER_KEY: Master key encrypting the cache drives, of the form "key-id:hex-key".
 
 EXAMPLES:
   1. Start minio gateway server for NAS backend.
@@ -70,6 +88,12 @@ EXAMPLES:
      $ export MINIO_CACHE_EXPIRY=40
      $ export MINIO_CACHE_MAXUSE=80
      $ {{.HelpName}} /shared/nasvol
+
+  3. Start minio gateway server for NAS shared by three gateway instances, run on every instance.
+     $ export MINIO_ACCESS_KEY=accesskey
+     $ export MINIO_SECRET_KEY=secretkey
+     $ export MINIO_NAS_PEERS="nas1.example.com:9000;nas2.example.com:9000;nas3.example.com:9000"
+     $ {{.HelpName}} /shared/nasvol
 `
 
 	minio.RegisterGatewayCommand(cli.Command{
@@ -88,12 +112,18 @@ func nasGatewayMain(ctx *cli.Context) {
 		cli.ShowCommandHelpAndExit(ctx, nasBackend, 1)
 	}
 
-	minio.StartGateway(ctx, &NAS{ctx.Args().First()})
+	var peers []string
+	if v := os.Getenv("MINIO_NAS_PEERS"); v != "" {
+		peers = strings.Split(v, ";")
+	}
+
+	minio.StartGateway(ctx, &NAS{ctx.Args().First(), peers})
 }
 
 // NAS implements Gateway.
 type NAS struct {
-	path string
+	path  string
+	peers []string
 }
 
 // Name implements Gateway interface.
@@ -101,10 +131,19 @@ func (g *NAS) Name() string {
 	return nasBackend
 }
 
+// Peers implements DistributedGateway interface, gateway instances
+// sharing the NAS volume synchronize with distributed locks.
+func (g *NAS) Peers() []string {
+	return g.peers
+}
+
 // NewGatewayLayer returns nas gatewaylayer.
 func (g *NAS) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error) {
-	var err error
-	newObject, err := minio.NewFSObjectLayer(g.path)
+	newFSObjectLayer := minio.NewFSObjectLayer
+	if len(g.peers) > 0 {
+		newFSObjectLayer = minio.NewSharedFSObjectLayer
+	}
+	newObject, err := newFSObjectLayer(g.path)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +161,7 @@ type nasObjects struct {
 }
 
 // IsNotificationSupported returns whether notifications are applicable for this layer.
+// Bucket notification configurations are stored in the shared `.minio.sys` of the NAS volume.
 func (l *nasObjects) IsNotificationSupported() bool {
-	return false
+	return true
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"io"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 
@@ -68,6 +69,20 @@ ENVIRONMENT VARIABLES:
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
+     MINIO_CACHE_COMMIT: Commit mode of uploads, "writethrough" or "writeback".
+     MINIO_CACHE_RANGE: To enable caching of ranged reads, set this value to "on".
+     MINIO_CACHE_POLICY: Eviction policy of the cache, "lru" or "lfu".
+     MINIO_CACHE_WATERMARK_LOW: Cache usage in percentage of max use at which eviction stops.
+     MINIO_CACHE_WATERMARK_HIGH: Cache usage in percentage of max use at which eviction starts.
+     MINIO_CACHE_INCLUDE: List of cache inclusion patterns delimited by ";".
+     MINIO_CACHE_QUOTA: List of cache quotas of buckets delimited by ";", for example "images=10GiB".
+     MINIO_CACHE_MIN_SIZE: Minimum size of cached objects, for example "1KiB".
+     MINIO_CACHE_MAX_SIZE: Maximum size of cached objects, for example "1GiB".
+     MINIO_CACHE_AFTER_HITS: Number of reads of an object from the backend before it is cached.
+     MINIO_CACHE_ENCRYPTION_MASTER_KEY: Master key encrypting the cache drives, of the form "key-id:hex-key".
+
+  ENCRYPTION:
+     MINIO_GATEWAY_SSE: To enable server side encryption of objects in the gateway, set this value to "on".
 
 EXAMPLES:
   1. Start minio gateway server for AWS S3 backend.
@@ -88,6 +103,13 @@ EXAMPLES:
      $ export MINIO_CACHE_EXPIRY=40
      $ export MINIO_CACHE_MAXUSE=80
      $ {{.HelpName}}
+
+  4. Start minio gateway server for S3 backend with server side encryption enabled.
+     $ export MINIO_ACCESS_KEY=accesskey
+     $ export MINIO_SECRET_KEY=secretkey
+     $ export MINIO_GATEWAY_SSE=on
+     $ export MINIO_SSE_VAULT_ENDPOINT=https://vault-endpoint-ip:8200
+     $ {{.HelpName}}
 `
 
 	minio.RegisterGatewayCommand(cli.Command{
@@ -186,9 +208,22 @@ func (g *S3) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error)
 		return nil, err
 	}
 
-	return &s3Objects{
+	s3 := &s3Objects{
 		Client: clnt,
-	}, nil
+	}
+
+	// Encrypt objects in the gateway when requested, the backend
+	// only ever sees encrypted data and sealed keys.
+	if sse := os.Getenv("MINIO_GATEWAY_SSE"); sse != "" {
+		enabled, err := minio.ParseBoolFlag(sse)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			return minio.NewGatewayEncryptionLayer(s3), nil
+		}
+	}
+	return s3, nil
 }
 
 // Production - s3 gateway is production ready.
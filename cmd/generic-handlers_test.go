@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
@@ -92,6 +93,23 @@ func TestGuessIsRPC(t *testing.T) {
 	}
 }
 
+// Tests health check request guess function.
+func TestGuessIsHealthCheck(t *testing.T) {
+	if guessIsHealthCheckReq(nil) {
+		t.Fatal("Unexpected return for nil request")
+	}
+	for _, path := range []string{healthCheckLivenessPath, healthCheckReadinessPath, healthCheckClusterPath, healthCheckClusterReadPath} {
+		r := httptest.NewRequest(http.MethodGet, healthCheckPathPrefix+path, nil)
+		if !guessIsHealthCheckReq(r) {
+			t.Fatalf("Test shouldn't fail for a health check request %s.", path)
+		}
+	}
+	r := httptest.NewRequest(http.MethodGet, healthCheckPathPrefix+"/unknown", nil)
+	if guessIsHealthCheckReq(r) {
+		t.Fatal("Test shouldn't report as health check for an unknown path.")
+	}
+}
+
 // Tests browser request guess function.
 func TestGuessIsBrowser(t *testing.T) {
 	if guessIsBrowserReq(nil) {
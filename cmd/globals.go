@@ -34,6 +34,8 @@ import (
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/certs"
 	"github.com/minio/minio/pkg/dns"
+	"github.com/minio/minio/pkg/iam/ldap"
+	"github.com/minio/minio/pkg/iam/validator"
 )
 
 // minio configuration related constants.
@@ -87,10 +89,7 @@ const (
 )
 
 var (
-	// Indicates the total number of erasure coded sets configured.
-	globalXLSetCount int
-
-	// Indicates set drive count.
+	// Indicates set drive count, same across all zones.
 	globalXLSetDriveCount int
 
 	// Indicates if the running minio server is distributed setup.
@@ -132,6 +131,9 @@ var (
 
 	globalNotificationSys *NotificationSys
 	globalPolicySys       *PolicySys
+	globalInventorySys    *InventorySys
+	globalACLSys          *ACLSys
+	globalIAMSys          *IAMSys
 
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
@@ -154,7 +156,9 @@ var (
 	// Minio server user agent string.
 	globalServerUserAgent = "Minio/" + ReleaseTag + " (" + runtime.GOOS + "; " + runtime.GOARCH + ")"
 
-	globalEndpoints EndpointList
+	// Endpoints of all zones, globalEndpoints is their combined list.
+	globalEndpointZones EndpointZones
+	globalEndpoints     EndpointList
 
 	// Global server's network statistics
 	globalConnStats = newConnStats()
@@ -184,11 +188,16 @@ var (
 	globalRRStorageClass storageClass
 	// Set to store standard storage class
 	globalStandardStorageClass storageClass
+	// Set to store custom storage classes by name
+	globalCustomStorageClasses map[string]storageClass
 
 	globalIsEnvWORM bool
 	// Is worm enabled
 	globalWORMEnabled bool
 
+	// Is the persistent listing index of the FS backend enabled
+	globalFSListIndexEnabled bool
+
 	// Is Disk Caching set up
 	globalIsDiskCacheEnabled bool
 
@@ -202,6 +211,27 @@ var (
 	globalCacheExpiry = 90
 	// Max allowed disk cache percentage
 	globalCacheMaxUse = 80
+	// Disk cache commit mode
+	globalCacheCommit string
+	// Is caching of ranged reads enabled
+	globalCacheRange bool
+	// Disk cache eviction policy
+	globalCachePolicy string
+	// Disk cache low and high watermarks, percentage of max use
+	globalCacheWatermarkLow  int
+	globalCacheWatermarkHigh int
+	// Disk cache includes
+	globalCacheIncludes []string
+	// Disk cache quota of buckets
+	globalCacheQuota map[string]string
+	// Min and max size of cached objects
+	globalCacheMinSize string
+	globalCacheMaxSize string
+	// Reads of an object before it is cached
+	globalCacheAfterHits int
+	// KMS and key-id deriving the keys of encrypted cache drives
+	globalCacheKMS      crypto.KMS
+	globalCacheKMSKeyID string
 
 	// RPC V1 - Initial version
 	// RPC V2 - format.json XL version changed to 2
@@ -226,6 +256,15 @@ var (
 	globalKMS crypto.KMS
 	// KMS config
 	globalKMSConfig crypto.KMSConfig
+
+	// Is set to true if OpenID config is set via environment.
+	globalIsEnvOpenID bool
+	// OpenID identity provider config
+	globalOpenIDConfig openIDConfig
+	// Authentication validators of STS requests.
+	globalIAMValidators *validator.Validators
+	// LDAP identity backend config, set via environment.
+	globalLDAPConfig ldap.Config
 	// Add new variable global values here.
 )
 
@@ -21,12 +21,18 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"strconv"
 
+	"github.com/minio/minio-go/pkg/set"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
 )
 
 const (
 	minioHealthGoroutineThreshold = 1000
+
+	// Number of drives which must come online for the cluster to have quorum.
+	minioHealthDrivesNeeded = "X-Minio-Drives-Needed"
 )
 
 // ReadinessCheckHandler -- checks if there are more than threshold number of goroutines running,
@@ -102,3 +108,103 @@ func goroutineCountCheck(threshold int) error {
 	}
 	return nil
 }
+
+// ClusterCheckHandler -- checks if all the erasure sets of the cluster have
+// write quorum, returns service unavailable otherwise. With the query
+// parameter `maintenance=true` it checks whether the cluster keeps write
+// quorum when the drives of this server are taken down.
+func ClusterCheckHandler(w http.ResponseWriter, r *http.Request) {
+	clusterCheck(w, r, "ClusterCheckHandler", true)
+}
+
+// ClusterReadCheckHandler -- checks if all the erasure sets of the cluster
+// have read quorum, returns service unavailable otherwise.
+func ClusterReadCheckHandler(w http.ResponseWriter, r *http.Request) {
+	clusterCheck(w, r, "ClusterReadCheckHandler", false)
+}
+
+func clusterCheck(w http.ResponseWriter, r *http.Request, api string, write bool) {
+	ctx := newContext(r, w, api)
+
+	objLayer := newObjectLayerFn()
+	// Service not initialized yet
+	if objLayer == nil {
+		writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+		return
+	}
+
+	s := objLayer.StorageInfo(ctx)
+	switch s.Backend.Type {
+	case BackendErasure:
+	case Unknown:
+		// ListBuckets to confirm gateway backend is up
+		if _, err := objLayer.ListBuckets(ctx); err != nil {
+			writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+			return
+		}
+		writeResponse(w, http.StatusOK, nil, mimeNone)
+		return
+	default:
+		// Single drive has no quorum.
+		writeResponse(w, http.StatusOK, nil, mimeNone)
+		return
+	}
+
+	if needed := clusterDrivesNeeded(s.Backend.Sets, write, nil); needed > 0 {
+		w.Header().Set(minioHealthDrivesNeeded, strconv.Itoa(needed))
+		writeResponse(w, http.StatusServiceUnavailable, nil, mimeNone)
+		return
+	}
+
+	if r.URL.Query().Get("maintenance") == "true" {
+		// Consider the drives of this server offline.
+		localDrives := set.NewStringSet()
+		for _, endpoint := range globalEndpoints {
+			if endpoint.IsLocal {
+				localDrives.Add(endpoint.String())
+			}
+		}
+		if needed := clusterDrivesNeeded(s.Backend.Sets, write, localDrives); needed > 0 {
+			w.Header().Set(minioHealthDrivesNeeded, strconv.Itoa(needed))
+			writeResponse(w, http.StatusPreconditionFailed, nil, mimeNone)
+			return
+		}
+	}
+	writeResponse(w, http.StatusOK, nil, mimeNone)
+}
+
+// clusterDrivesNeeded - returns the number of drives which must come online
+// for every erasure set to have read or write quorum for all the storage
+// classes, drives of the offline endpoints are not counted as online.
+func clusterDrivesNeeded(sets [][]madmin.DriveInfo, write bool, offline set.StringSet) (needed int) {
+	storageClasses := []string{standardStorageClass, reducedRedundancyStorageClass}
+	for name := range globalCustomStorageClasses {
+		storageClasses = append(storageClasses, name)
+	}
+
+	for _, drives := range sets {
+		var online int
+		for _, drive := range drives {
+			if drive.State == madmin.DriveStateOk && !offline.Contains(drive.Endpoint) {
+				online++
+			}
+		}
+
+		// Quorum of the storage class with most data drives is required.
+		var quorum int
+		for _, sc := range storageClasses {
+			dataDrives, _ := getRedundancyCount(sc, len(drives))
+			if write {
+				// writeQuorum is dataBlocks + 1
+				dataDrives++
+			}
+			if dataDrives > quorum {
+				quorum = dataDrives
+			}
+		}
+		if online < quorum {
+			needed += quorum - online
+		}
+	}
+	return needed
+}
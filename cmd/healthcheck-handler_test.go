@@ -17,8 +17,15 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/pkg/madmin"
 )
 
 func TestGoroutineCountCheck(t *testing.T) {
@@ -42,3 +49,106 @@ func TestGoroutineCountCheck(t *testing.T) {
 		}
 	}
 }
+
+// Returns drives of an erasure set with the given number of online drives.
+func newHealthTestSet(setIndex, drives, online int) []madmin.DriveInfo {
+	set := make([]madmin.DriveInfo, drives)
+	for i := range set {
+		set[i].Endpoint = fmt.Sprintf("http://server%d/disk%d", i%4, setIndex*drives+i)
+		set[i].State = madmin.DriveStateOffline
+		if i < online {
+			set[i].State = madmin.DriveStateOk
+		}
+	}
+	return set
+}
+
+func TestClusterDrivesNeeded(t *testing.T) {
+	resetGlobalStorageEnvs()
+	defer resetGlobalStorageEnvs()
+
+	tests := []struct {
+		sets           [][]madmin.DriveInfo
+		write          bool
+		offline        set.StringSet
+		customClasses  map[string]storageClass
+		expectedNeeded int
+	}{
+		// All drives online.
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 16), newHealthTestSet(1, 16, 16)}, true, nil, nil, 0},
+		// Reduced redundancy writes need 15 drives, reads need 14.
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 14)}, true, nil, nil, 1},
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 14)}, false, nil, nil, 0},
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 12)}, false, nil, nil, 2},
+		// Drives needed are summed across sets.
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 4, 2), newHealthTestSet(1, 4, 1)}, true, nil, nil, 3},
+		// Drives of a server taken down for maintenance.
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 16)}, true, set.CreateStringSet("http://server0/disk0", "http://server0/disk4"), nil, 1},
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 16, 16)}, false, set.CreateStringSet("http://server0/disk0", "http://server0/disk4"), nil, 0},
+		// Custom storage classes with more data drives are required as well.
+		{[][]madmin.DriveInfo{newHealthTestSet(0, 32, 28)}, true, nil, map[string]storageClass{"DENSE": {"EC", 2}}, 3},
+	}
+	for i, tt := range tests {
+		globalCustomStorageClasses = tt.customClasses
+		if needed := clusterDrivesNeeded(tt.sets, tt.write, tt.offline); needed != tt.expectedNeeded {
+			t.Errorf("Test %d: expected %d drives needed, got %d", i+1, tt.expectedNeeded, needed)
+		}
+	}
+}
+
+func TestClusterCheckHandler(t *testing.T) {
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	resetGlobalStorageEnvs()
+
+	saveObjectAPI, saveEndpoints := newObjectLayerFn(), globalEndpoints
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = saveObjectAPI
+		globalObjLayerMutex.Unlock()
+		globalEndpoints = saveEndpoints
+	}()
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+	globalEndpoints = mustGetNewEndpointList(fsDirs...)
+
+	router := http.NewServeMux()
+	router.HandleFunc(healthCheckPathPrefix+healthCheckClusterPath, ClusterCheckHandler)
+	router.HandleFunc(healthCheckPathPrefix+healthCheckClusterReadPath, ClusterReadCheckHandler)
+
+	check := func(path string, expectedStatus int, expectedNeeded string) {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != expectedStatus {
+			t.Fatalf("%s: expected status %d, got %d", path, expectedStatus, rec.Code)
+		}
+		if needed := rec.Header().Get(minioHealthDrivesNeeded); needed != expectedNeeded {
+			t.Fatalf("%s: expected %q drives needed, got %q", path, expectedNeeded, needed)
+		}
+	}
+
+	clusterPath := healthCheckPathPrefix + healthCheckClusterPath
+	readPath := healthCheckPathPrefix + healthCheckClusterReadPath
+	check(clusterPath, http.StatusOK, "")
+	check(readPath, http.StatusOK, "")
+
+	// All drives are local, taking this server down breaks quorum.
+	check(clusterPath+"?maintenance=true", http.StatusPreconditionFailed, "30")
+
+	// Take two drives of the first set offline.
+	for _, dir := range fsDirs[:2] {
+		if err = os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(clusterPath, http.StatusServiceUnavailable, "1")
+	check(readPath, http.StatusOK, "")
+}
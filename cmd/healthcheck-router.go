@@ -23,13 +23,15 @@ import (
 )
 
 const (
-	healthCheckPath          = "/health"
-	healthCheckLivenessPath  = "/live"
-	healthCheckReadinessPath = "/ready"
-	healthCheckPathPrefix    = minioReservedBucketPath + healthCheckPath
+	healthCheckPath            = "/health"
+	healthCheckLivenessPath    = "/live"
+	healthCheckReadinessPath   = "/ready"
+	healthCheckClusterPath     = "/cluster"
+	healthCheckClusterReadPath = "/cluster/read"
+	healthCheckPathPrefix      = minioReservedBucketPath + healthCheckPath
 )
 
-// registerHealthCheckRouter - add handler functions for liveness and readiness routes.
+// registerHealthCheckRouter - add handler functions for liveness, readiness and cluster routes.
 func registerHealthCheckRouter(router *mux.Router) {
 
 	// Healthcheck router
@@ -42,4 +44,10 @@ func registerHealthCheckRouter(router *mux.Router) {
 	// Readiness handler
 	healthRouter.Methods(http.MethodGet).Path(healthCheckReadinessPath).HandlerFunc(httpTraceAll(ReadinessCheckHandler))
 	healthRouter.Methods(http.MethodHead).Path(healthCheckReadinessPath).HandlerFunc(httpTraceAll(ReadinessCheckHandler))
+
+	// Cluster handlers
+	healthRouter.Methods(http.MethodGet).Path(healthCheckClusterPath).HandlerFunc(httpTraceAll(ClusterCheckHandler))
+	healthRouter.Methods(http.MethodHead).Path(healthCheckClusterPath).HandlerFunc(httpTraceAll(ClusterCheckHandler))
+	healthRouter.Methods(http.MethodGet).Path(healthCheckClusterReadPath).HandlerFunc(httpTraceAll(ClusterReadCheckHandler))
+	healthRouter.Methods(http.MethodHead).Path(healthCheckClusterReadPath).HandlerFunc(httpTraceAll(ClusterReadCheckHandler))
 }
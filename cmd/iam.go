@@ -0,0 +1,493 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// IAM configuration directory.
+	iamConfigPrefix = minioConfigPrefix + "/iam"
+
+	// IAM configuration directory of temporary credentials.
+	iamConfigSTSPrefix = iamConfigPrefix + "/sts/"
+
+	// IAM configuration directory of canned policies.
+	iamConfigPoliciesPrefix = iamConfigPrefix + "/policies/"
+
+	// IAM configuration directory of group to canned policy mappings.
+	iamConfigPolicyDBGroupsPrefix = iamConfigPrefix + "/policydb/groups/"
+
+	// IAM identity file which captures temporary credentials.
+	iamIdentityFile = "identity.json"
+
+	// IAM canned policy file.
+	iamPolicyFile = "policy.json"
+
+	// IAM group policy mapping file.
+	iamPolicyMappingFile = "mapping.json"
+
+	// Current version of IAM identity file.
+	iamIdentityVersion = "1"
+
+	// Current version of IAM group policy mapping file.
+	iamPolicyMappingVersion = "1"
+)
+
+// iamIdentity - temporary credentials along with its session policy and
+// the groups its user belongs to.
+type iamIdentity struct {
+	Version     string           `json:"version"`
+	Credentials auth.Credentials `json:"credentials"`
+	Policy      *policy.Policy   `json:"policy,omitempty"`
+	Groups      []string         `json:"groups,omitempty"`
+}
+
+// iamPolicyMapping - canned policy name mapped to a group.
+type iamPolicyMapping struct {
+	Version string `json:"version"`
+	Policy  string `json:"policy"`
+}
+
+// IAMSys - temporary credentials subsystem.
+type IAMSys struct {
+	sync.RWMutex
+	identityMap    map[string]iamIdentity
+	policyMap      map[string]policy.Policy
+	groupPolicyMap map[string]string
+}
+
+// Set - sets identity of temporary credentials.
+func (sys *IAMSys) Set(identity iamIdentity) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.identityMap[identity.Credentials.AccessKey] = identity
+}
+
+// Remove - removes identity of given access key.
+func (sys *IAMSys) Remove(accessKey string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.identityMap, accessKey)
+}
+
+// GetUser - returns temporary credentials of given access key.
+func (sys *IAMSys) GetUser(accessKey string) (auth.Credentials, bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	identity, ok := sys.identityMap[accessKey]
+	return identity.Credentials, ok
+}
+
+// SetPolicy - sets canned policy of given name.
+func (sys *IAMSys) SetPolicy(name string, p policy.Policy) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.policyMap[name] = p
+}
+
+// RemovePolicy - removes canned policy of given name.
+func (sys *IAMSys) RemovePolicy(name string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.policyMap, name)
+}
+
+// GetPolicy - returns canned policy of given name.
+func (sys *IAMSys) GetPolicy(name string) (policy.Policy, bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	p, ok := sys.policyMap[name]
+	return p, ok
+}
+
+// ListPolicies - returns all canned policies.
+func (sys *IAMSys) ListPolicies() map[string]policy.Policy {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	policies := make(map[string]policy.Policy, len(sys.policyMap))
+	for name, p := range sys.policyMap {
+		policies[name] = p
+	}
+	return policies
+}
+
+// SetGroupPolicy - maps canned policy to given group, empty policy name
+// removes the mapping.
+func (sys *IAMSys) SetGroupPolicy(group, policyName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if policyName == "" {
+		delete(sys.groupPolicyMap, group)
+		return
+	}
+	sys.groupPolicyMap[group] = policyName
+}
+
+// IsAllowed - checks given policy args is allowed by session policy of
+// temporary credentials in args.AccountName or by canned policy of any
+// group its user belongs to.
+func (sys *IAMSys) IsAllowed(args policy.Args) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	identity, ok := sys.identityMap[args.AccountName]
+	if !ok || identity.Credentials.IsExpired() {
+		return false
+	}
+
+	if identity.Policy != nil && identity.Policy.IsAllowed(args) {
+		return true
+	}
+
+	for _, group := range identity.Groups {
+		if p, ok := sys.policyMap[sys.groupPolicyMap[group]]; ok && p.IsAllowed(args) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeExpired - removes expired identities from the cache.
+func (sys *IAMSys) removeExpired() {
+	sys.Lock()
+	defer sys.Unlock()
+
+	for accessKey, identity := range sys.identityMap {
+		if identity.Credentials.IsExpired() {
+			delete(sys.identityMap, accessKey)
+		}
+	}
+}
+
+// listIAMConfigNames - returns names of all entries directly under given
+// IAM configuration directory.
+func listIAMConfigNames(ctx context.Context, objAPI ObjectLayer, prefix string) ([]string, error) {
+	var names []string
+	var marker string
+	for {
+		result, err := objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, slashSeparator, maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range result.Prefixes {
+			names = append(names, path.Base(strings.TrimSuffix(p, slashSeparator)))
+		}
+
+		if !result.IsTruncated {
+			return names, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+// Refresh IAMSys.
+func (sys *IAMSys) refresh(objAPI ObjectLayer) error {
+	ctx := context.Background()
+
+	accessKeys, err := listIAMConfigNames(ctx, objAPI, iamConfigSTSPrefix)
+	if err != nil {
+		if _, ok := err.(BucketNotFound); ok {
+			return nil
+		}
+		logger.LogIf(ctx, err)
+		return err
+	}
+
+	for _, accessKey := range accessKeys {
+		identity, err := readIAMIdentity(ctx, objAPI, accessKey)
+		if err != nil {
+			continue
+		}
+
+		if identity.Credentials.IsExpired() {
+			// Temporary credentials expired, remove them.
+			removeIAMIdentity(ctx, objAPI, accessKey)
+			continue
+		}
+
+		sys.Set(identity)
+	}
+
+	sys.removeExpired()
+
+	names, err := listIAMConfigNames(ctx, objAPI, iamConfigPoliciesPrefix)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+
+	policyMap := make(map[string]policy.Policy)
+	for _, name := range names {
+		p, err := readIAMPolicy(ctx, objAPI, name)
+		if err != nil {
+			continue
+		}
+		policyMap[name] = p
+	}
+
+	groups, err := listIAMConfigNames(ctx, objAPI, iamConfigPolicyDBGroupsPrefix)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return err
+	}
+
+	groupPolicyMap := make(map[string]string)
+	for _, group := range groups {
+		mapping, err := readIAMPolicyMapping(ctx, objAPI, group)
+		if err != nil {
+			continue
+		}
+		groupPolicyMap[group] = mapping.Policy
+	}
+
+	sys.Lock()
+	sys.policyMap = policyMap
+	sys.groupPolicyMap = groupPolicyMap
+	sys.Unlock()
+
+	return nil
+}
+
+// Init - initializes IAM system from identity.json of all temporary credentials.
+func (sys *IAMSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load IAMSys once during boot.
+	if err := sys.refresh(objAPI); err != nil {
+		return err
+	}
+
+	// Refresh IAMSys in background.
+	go func() {
+		ticker := time.NewTicker(globalRefreshBucketPolicyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-ticker.C:
+				sys.refresh(objAPI)
+			}
+		}
+	}()
+	return nil
+}
+
+// NewIAMSys - creates new IAM system.
+func NewIAMSys() *IAMSys {
+	return &IAMSys{
+		identityMap:    make(map[string]iamIdentity),
+		policyMap:      make(map[string]policy.Policy),
+		groupPolicyMap: make(map[string]string),
+	}
+}
+
+// getIAMIdentityFile - returns path of identity.json of given access key.
+func getIAMIdentityFile(accessKey string) string {
+	return path.Join(iamConfigSTSPrefix, accessKey, iamIdentityFile)
+}
+
+// readIAMIdentity - reads identity.json of given access key.
+func readIAMIdentity(ctx context.Context, objAPI ObjectLayer, accessKey string) (identity iamIdentity, err error) {
+	reader, err := readConfig(ctx, objAPI, getIAMIdentityFile(accessKey))
+	if err != nil {
+		return identity, err
+	}
+
+	if err = json.NewDecoder(reader).Decode(&identity); err != nil {
+		return identity, err
+	}
+
+	if identity.Version != iamIdentityVersion {
+		return identity, errInvalidArgument
+	}
+
+	return identity, nil
+}
+
+// saveIAMIdentity - saves identity.json of temporary credentials.
+func saveIAMIdentity(objAPI ObjectLayer, identity iamIdentity) error {
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, getIAMIdentityFile(identity.Credentials.AccessKey), data)
+}
+
+// removeIAMIdentity - removes identity.json of given access key.
+func removeIAMIdentity(ctx context.Context, objAPI ObjectLayer, accessKey string) error {
+	return objAPI.DeleteObject(ctx, minioMetaBucket, getIAMIdentityFile(accessKey))
+}
+
+// setIAMIdentity - saves identity of temporary credentials and propagates it to all peers.
+func setIAMIdentity(ctx context.Context, objAPI ObjectLayer, identity iamIdentity) error {
+	if err := saveIAMIdentity(objAPI, identity); err != nil {
+		return err
+	}
+
+	globalIAMSys.Set(identity)
+	globalNotificationSys.SetIAMIdentity(ctx, identity)
+	return nil
+}
+
+// isValidIAMName - checks whether given canned policy or group name is
+// usable as a single path element of IAM configuration.
+func isValidIAMName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.Contains(name, slashSeparator)
+}
+
+// getIAMPolicyFile - returns path of policy.json of given canned policy.
+func getIAMPolicyFile(name string) string {
+	return path.Join(iamConfigPoliciesPrefix, name, iamPolicyFile)
+}
+
+// getIAMPolicyMappingFile - returns path of mapping.json of given group.
+func getIAMPolicyMappingFile(group string) string {
+	return path.Join(iamConfigPolicyDBGroupsPrefix, group, iamPolicyMappingFile)
+}
+
+// readIAMPolicy - reads policy.json of given canned policy.
+func readIAMPolicy(ctx context.Context, objAPI ObjectLayer, name string) (p policy.Policy, err error) {
+	reader, err := readConfig(ctx, objAPI, getIAMPolicyFile(name))
+	if err != nil {
+		return p, err
+	}
+
+	err = json.NewDecoder(reader).Decode(&p)
+	return p, err
+}
+
+// readIAMPolicyMapping - reads mapping.json of given group.
+func readIAMPolicyMapping(ctx context.Context, objAPI ObjectLayer, group string) (mapping iamPolicyMapping, err error) {
+	reader, err := readConfig(ctx, objAPI, getIAMPolicyMappingFile(group))
+	if err != nil {
+		return mapping, err
+	}
+
+	if err = json.NewDecoder(reader).Decode(&mapping); err != nil {
+		return mapping, err
+	}
+
+	if mapping.Version != iamPolicyMappingVersion {
+		return mapping, errInvalidArgument
+	}
+
+	return mapping, nil
+}
+
+// setIAMPolicy - saves canned policy and makes all peers reload IAM configuration.
+func setIAMPolicy(ctx context.Context, objAPI ObjectLayer, name string, p policy.Policy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	if err = saveConfig(objAPI, getIAMPolicyFile(name), data); err != nil {
+		return err
+	}
+
+	globalIAMSys.SetPolicy(name, p)
+	globalNotificationSys.LoadIAM(ctx)
+	return nil
+}
+
+// removeIAMPolicy - removes canned policy and makes all peers reload IAM configuration.
+func removeIAMPolicy(ctx context.Context, objAPI ObjectLayer, name string) error {
+	if err := objAPI.DeleteObject(ctx, minioMetaBucket, getIAMPolicyFile(name)); err != nil {
+		return err
+	}
+
+	globalIAMSys.RemovePolicy(name)
+	globalNotificationSys.LoadIAM(ctx)
+	return nil
+}
+
+// setIAMGroupPolicy - saves canned policy mapping of given group and makes
+// all peers reload IAM configuration. Empty policy name removes the mapping.
+func setIAMGroupPolicy(ctx context.Context, objAPI ObjectLayer, group, policyName string) error {
+	if policyName == "" {
+		err := objAPI.DeleteObject(ctx, minioMetaBucket, getIAMPolicyMappingFile(group))
+		if _, ok := err.(ObjectNotFound); err != nil && !ok {
+			return err
+		}
+	} else {
+		data, err := json.Marshal(iamPolicyMapping{
+			Version: iamPolicyMappingVersion,
+			Policy:  policyName,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = saveConfig(objAPI, getIAMPolicyMappingFile(group), data); err != nil {
+			return err
+		}
+	}
+
+	globalIAMSys.SetGroupPolicy(group, policyName)
+	globalNotificationSys.LoadIAM(ctx)
+	return nil
+}
+
+// checkKeyValid - validates given access key and returns its credential.
+// Returned bool is true if the access key is the server owner's.
+func checkKeyValid(accessKey string) (auth.Credentials, bool, APIErrorCode) {
+	cred := globalServerConfig.GetCredential()
+	if cred.AccessKey == accessKey {
+		return cred, true, ErrNone
+	}
+
+	// globalIAMSys is not initialized in gateway mode.
+	if globalIAMSys == nil {
+		return cred, false, ErrInvalidAccessKeyID
+	}
+
+	cred, ok := globalIAMSys.GetUser(accessKey)
+	if !ok {
+		return cred, false, ErrInvalidAccessKeyID
+	}
+
+	if cred.IsExpired() {
+		return cred, false, ErrExpiredToken
+	}
+
+	return cred, false, ErrNone
+}
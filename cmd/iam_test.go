@@ -0,0 +1,276 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+// newTestIAMIdentity - returns identity of new temporary credentials
+// expiring at given time.
+func newTestIAMIdentity(t *testing.T, expiry time.Time, p *policy.Policy) iamIdentity {
+	cred, err := auth.GetNewCredentialsWithMetadata(map[string]interface{}{
+		"exp": expiry.Unix(),
+	}, globalServerConfig.GetCredential().SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return iamIdentity{
+		Version:     iamIdentityVersion,
+		Credentials: cred,
+		Policy:      p,
+	}
+}
+
+func TestIAMSysRefresh(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	validIdentity := newTestIAMIdentity(t, UTCNow().Add(time.Hour), nil)
+	expiredIdentity := newTestIAMIdentity(t, UTCNow().Add(-time.Hour), nil)
+	for _, identity := range []iamIdentity{validIdentity, expiredIdentity} {
+		if err = saveIAMIdentity(objLayer, identity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sys := NewIAMSys()
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := sys.GetUser(validIdentity.Credentials.AccessKey); !ok {
+		t.Fatalf("valid temporary credentials are not loaded")
+	}
+
+	if _, ok := sys.GetUser(expiredIdentity.Credentials.AccessKey); ok {
+		t.Fatalf("expired temporary credentials are loaded")
+	}
+
+	// Expired identity is removed from backend.
+	if _, err = readIAMIdentity(context.Background(), objLayer, expiredIdentity.Credentials.AccessKey); err != errConfigNotFound {
+		t.Fatalf("expected: %v, got: %v", errConfigNotFound, err)
+	}
+
+	// Identity removed from backend is dropped on next refresh.
+	sys.Set(expiredIdentity)
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.GetUser(expiredIdentity.Credentials.AccessKey); ok {
+		t.Fatalf("expired temporary credentials are not removed")
+	}
+}
+
+func TestIAMSysIsAllowed(t *testing.T) {
+	tmpGlobalServerConfig := globalServerConfig
+	defer func() {
+		globalServerConfig = tmpGlobalServerConfig
+	}()
+	globalServerConfig = newServerConfig()
+
+	p := &policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource("mybucket", "/myobject*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+
+	withPolicy := newTestIAMIdentity(t, UTCNow().Add(time.Hour), p)
+	withoutPolicy := newTestIAMIdentity(t, UTCNow().Add(time.Hour), nil)
+
+	sys := NewIAMSys()
+	sys.Set(withPolicy)
+	sys.Set(withoutPolicy)
+
+	testCases := []struct {
+		accessKey      string
+		action         policy.Action
+		objectName     string
+		expectedResult bool
+	}{
+		{withPolicy.Credentials.AccessKey, policy.GetObjectAction, "myobject", true},
+		{withPolicy.Credentials.AccessKey, policy.PutObjectAction, "myobject", false},
+		{withPolicy.Credentials.AccessKey, policy.GetObjectAction, "otherobject", false},
+		{withoutPolicy.Credentials.AccessKey, policy.GetObjectAction, "myobject", false},
+		{"unknown", policy.GetObjectAction, "myobject", false},
+	}
+
+	for i, testCase := range testCases {
+		result := sys.IsAllowed(policy.Args{
+			AccountName:     testCase.accessKey,
+			Action:          testCase.action,
+			BucketName:      "mybucket",
+			ConditionValues: map[string][]string{},
+			ObjectName:      testCase.objectName,
+		})
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestCheckKeyValid(t *testing.T) {
+	tmpGlobalServerConfig := globalServerConfig
+	defer func() {
+		globalServerConfig = tmpGlobalServerConfig
+	}()
+	globalServerConfig = newServerConfig()
+
+	globalIAMSys = NewIAMSys()
+	validIdentity := newTestIAMIdentity(t, UTCNow().Add(time.Hour), nil)
+	expiredIdentity := newTestIAMIdentity(t, UTCNow().Add(-time.Hour), nil)
+	globalIAMSys.Set(validIdentity)
+	globalIAMSys.Set(expiredIdentity)
+
+	testCases := []struct {
+		accessKey       string
+		expectedOwner   bool
+		expectedErrCode APIErrorCode
+	}{
+		{globalServerConfig.GetCredential().AccessKey, true, ErrNone},
+		{validIdentity.Credentials.AccessKey, false, ErrNone},
+		{expiredIdentity.Credentials.AccessKey, false, ErrExpiredToken},
+		{"unknown", false, ErrInvalidAccessKeyID},
+	}
+
+	for i, testCase := range testCases {
+		cred, owner, errCode := checkKeyValid(testCase.accessKey)
+		if errCode != testCase.expectedErrCode {
+			t.Fatalf("case %v: error code: expected: %v, got: %v", i+1, testCase.expectedErrCode, errCode)
+		}
+		if owner != testCase.expectedOwner {
+			t.Fatalf("case %v: owner: expected: %v, got: %v", i+1, testCase.expectedOwner, owner)
+		}
+		if errCode == ErrNone && cred.AccessKey != testCase.accessKey {
+			t.Fatalf("case %v: access key: expected: %v, got: %v", i+1, testCase.accessKey, cred.AccessKey)
+		}
+	}
+}
+
+func TestIAMSysGroupPolicy(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	globalIAMSys = NewIAMSys()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+
+	ctx := context.Background()
+	if err = setIAMPolicy(ctx, objLayer, "readonly", newTestReadOnlyPolicy(t, "mybucket")); err != nil {
+		t.Fatal(err)
+	}
+	if err = setIAMGroupPolicy(ctx, objLayer, "dev", "readonly"); err != nil {
+		t.Fatal(err)
+	}
+	// Mapping of unknown policy allows nothing.
+	if err = setIAMGroupPolicy(ctx, objLayer, "ops", "unknown"); err != nil {
+		t.Fatal(err)
+	}
+
+	dev := newTestIAMIdentity(t, UTCNow().Add(time.Hour), nil)
+	dev.Groups = []string{"other", "dev"}
+	ops := newTestIAMIdentity(t, UTCNow().Add(time.Hour), nil)
+	ops.Groups = []string{"ops"}
+	for _, identity := range []iamIdentity{dev, ops} {
+		if err = saveIAMIdentity(objLayer, identity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Canned policies and group mappings are loaded from backend.
+	sys := NewIAMSys()
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.GetPolicy("readonly"); !ok {
+		t.Fatalf("canned policy is not loaded")
+	}
+
+	testCases := []struct {
+		accessKey      string
+		action         policy.Action
+		expectedResult bool
+	}{
+		{dev.Credentials.AccessKey, policy.GetObjectAction, true},
+		{dev.Credentials.AccessKey, policy.PutObjectAction, false},
+		{ops.Credentials.AccessKey, policy.GetObjectAction, false},
+	}
+
+	for i, testCase := range testCases {
+		result := sys.IsAllowed(policy.Args{
+			AccountName:     testCase.accessKey,
+			Action:          testCase.action,
+			BucketName:      "mybucket",
+			ConditionValues: map[string][]string{},
+			ObjectName:      "myobject",
+		})
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+
+	// Removed mapping and policy are dropped on next refresh.
+	if err = setIAMGroupPolicy(ctx, objLayer, "dev", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = removeIAMPolicy(ctx, objLayer, "readonly"); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.refresh(objLayer); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sys.GetPolicy("readonly"); ok {
+		t.Fatalf("removed canned policy is loaded")
+	}
+	if sys.IsAllowed(policy.Args{
+		AccountName:     dev.Credentials.AccessKey,
+		Action:          policy.GetObjectAction,
+		BucketName:      "mybucket",
+		ConditionValues: map[string][]string{},
+		ObjectName:      "myobject",
+	}) {
+		t.Fatalf("removed group policy is allowed")
+	}
+}
@@ -0,0 +1,602 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/inventory"
+)
+
+const (
+	// Inventory configuration file.
+	bucketInventoryConfig = "inventory.xml"
+
+	// Directory holding per configuration report status.
+	bucketInventoryStatusPrefix = "inventory"
+
+	// Inventory manifest version as documented by AWS S3.
+	inventoryManifestVersion = "2016-11-30"
+
+	// Maximum number of objects written into one inventory data file.
+	inventoryMaxRecordsPerFile = 100000
+)
+
+var errTooManyInventoryConfigs = errors.New("Maximum number of inventory configurations reached for bucket")
+
+// Interval at which inventory configurations are checked for due reports.
+var globalInventoryCheckInterval = 1 * time.Hour
+
+// inventoryLockTimeout - reports are generated only by the node which is
+// able to get the status lock without waiting.
+var inventoryLockTimeout = newDynamicTimeout(1*time.Second, 1*time.Second)
+
+// InventorySys - bucket inventory subsystem.
+type InventorySys struct {
+	sync.RWMutex
+	bucketConfigsMap map[string]inventory.Configs
+	// signals configuration changes to check for due reports.
+	checkCh chan struct{}
+}
+
+// Set - sets inventory configurations to given bucket name. If there are
+// no configurations, existing entry is removed.
+func (sys *InventorySys) Set(bucketName string, configs inventory.Configs) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	if len(configs.Configs) == 0 {
+		delete(sys.bucketConfigsMap, bucketName)
+		return
+	}
+	sys.bucketConfigsMap[bucketName] = configs
+
+	// New configurations get their first report without
+	// waiting for the next check interval.
+	select {
+	case sys.checkCh <- struct{}{}:
+	default:
+	}
+}
+
+// Remove - removes inventory configurations for given bucket name.
+func (sys *InventorySys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketConfigsMap, bucketName)
+}
+
+// Get - returns inventory configurations of given bucket name.
+func (sys *InventorySys) Get(bucketName string) inventory.Configs {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.bucketConfigsMap[bucketName]
+}
+
+// refresh - reloads inventory configurations of all buckets.
+func (sys *InventorySys) refresh(objAPI ObjectLayer) error {
+	buckets, err := objAPI.ListBuckets(context.Background())
+	if err != nil {
+		logger.LogIf(context.Background(), err)
+		return err
+	}
+
+	bucketConfigsMap := make(map[string]inventory.Configs)
+	for _, bucket := range buckets {
+		ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{BucketName: bucket.Name})
+		configs, err := readInventoryConfig(ctx, objAPI, bucket.Name)
+		if err != nil {
+			if _, ok := err.(BucketInventoryConfigNotFound); !ok {
+				logger.LogIf(ctx, err)
+			}
+			continue
+		}
+
+		if len(configs.Configs) > 0 {
+			bucketConfigsMap[bucket.Name] = *configs
+		}
+	}
+
+	sys.Lock()
+	sys.bucketConfigsMap = bucketConfigsMap
+	sys.Unlock()
+
+	return nil
+}
+
+// generateDueReports - generates reports of all enabled configurations
+// whose last report is older than their schedule frequency.
+func (sys *InventorySys) generateDueReports(objAPI ObjectLayer, now time.Time) {
+	sys.RLock()
+	bucketConfigsMap := make(map[string][]inventory.Config, len(sys.bucketConfigsMap))
+	for bucketName, configs := range sys.bucketConfigsMap {
+		bucketConfigsMap[bucketName] = append([]inventory.Config{}, configs.Configs...)
+	}
+	sys.RUnlock()
+
+	for bucketName, configs := range bucketConfigsMap {
+		for _, config := range configs {
+			if !config.IsEnabled {
+				continue
+			}
+
+			reqInfo := (&logger.ReqInfo{BucketName: bucketName}).AppendTags("inventoryID", config.ID)
+			ctx := logger.SetReqInfo(context.Background(), reqInfo)
+			logger.LogIf(ctx, generateInventoryReportIfDue(ctx, objAPI, bucketName, config, now))
+		}
+	}
+}
+
+// Init - initializes inventory system from inventory.xml of all buckets
+// and starts generating reports in background.
+func (sys *InventorySys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errInvalidArgument
+	}
+
+	// Load InventorySys once during boot.
+	if err := sys.refresh(objAPI); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(globalInventoryCheckInterval)
+		defer ticker.Stop()
+
+		// Generate reports which became due while the server was down.
+		sys.generateDueReports(objAPI, UTCNow())
+		for {
+			select {
+			case <-globalServiceDoneCh:
+				return
+			case <-sys.checkCh:
+				sys.generateDueReports(objAPI, UTCNow())
+			case <-ticker.C:
+				if err := sys.refresh(objAPI); err == nil {
+					sys.generateDueReports(objAPI, UTCNow())
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// NewInventorySys - creates new inventory system.
+func NewInventorySys() *InventorySys {
+	return &InventorySys{
+		bucketConfigsMap: make(map[string]inventory.Configs),
+		checkCh:          make(chan struct{}, 1),
+	}
+}
+
+// readInventoryConfig - reads all inventory configurations of given bucket name.
+func readInventoryConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) (*inventory.Configs, error) {
+	// Construct path to inventory.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketInventoryConfig)
+	reader, err := readConfig(ctx, objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			err = BucketInventoryConfigNotFound{Bucket: bucketName}
+		}
+
+		return nil, err
+	}
+
+	return inventory.ParseConfigs(reader)
+}
+
+func saveInventoryConfig(objAPI ObjectLayer, bucketName string, configs *inventory.Configs) error {
+	data, err := xml.Marshal(configs)
+	if err != nil {
+		return err
+	}
+
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketInventoryConfig)
+	return saveConfig(objAPI, configFile, data)
+}
+
+// updateInventoryConfig - reads, modifies and saves inventory configurations
+// of given bucket name under a transaction lock.
+func updateInventoryConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, update func(configs *inventory.Configs) error) (*inventory.Configs, error) {
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketInventoryConfig)
+	transactionConfigFile := configFile + ".transaction"
+
+	// As object layer's GetObject() and PutObject() take respective lock on minioMetaBucket
+	// and configFile, take a transaction lock to avoid data race between readConfig()
+	// and saveConfig().
+	objLock := globalNSMutex.NewNSLock(minioMetaBucket, transactionConfigFile)
+	if err := objLock.GetLock(globalOperationTimeout); err != nil {
+		return nil, err
+	}
+	defer objLock.Unlock()
+
+	configs, err := readInventoryConfig(ctx, objAPI, bucketName)
+	if err != nil {
+		if _, ok := err.(BucketInventoryConfigNotFound); !ok {
+			return nil, err
+		}
+
+		configs = &inventory.Configs{}
+	}
+
+	if err = update(configs); err != nil {
+		return nil, err
+	}
+
+	if len(configs.Configs) == 0 {
+		if err = objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil && !isErrObjectNotFound(err) {
+			return nil, err
+		}
+
+		return configs, nil
+	}
+
+	if err = saveInventoryConfig(objAPI, bucketName, configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// Remove inventory configuration from storage layer. Used when a bucket is deleted.
+func removeInventoryConfig(ctx context.Context, objAPI ObjectLayer, bucket string) error {
+	icPath := path.Join(bucketConfigPrefix, bucket, bucketInventoryConfig)
+	return objAPI.DeleteObject(ctx, minioMetaBucket, icPath)
+}
+
+// inventoryStatus - persisted state of an inventory configuration.
+type inventoryStatus struct {
+	LastReport time.Time `json:"lastReport"`
+}
+
+func inventoryStatusFile(bucketName, id string) string {
+	return path.Join(bucketConfigPrefix, bucketName, bucketInventoryStatusPrefix, id+".json")
+}
+
+func readInventoryStatus(ctx context.Context, objAPI ObjectLayer, bucketName, id string) (status inventoryStatus, err error) {
+	reader, err := readConfig(ctx, objAPI, inventoryStatusFile(bucketName, id))
+	if err != nil {
+		if err == errConfigNotFound {
+			err = nil
+		}
+
+		return status, err
+	}
+
+	err = json.NewDecoder(reader).Decode(&status)
+	return status, err
+}
+
+func saveInventoryStatus(objAPI ObjectLayer, bucketName, id string, status inventoryStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	return saveConfig(objAPI, inventoryStatusFile(bucketName, id), data)
+}
+
+// generateInventoryReportIfDue - generates inventory report if previous report
+// is older than schedule frequency. Only one node in a distributed setup
+// generates a report as status lock is held while generating it.
+func generateInventoryReportIfDue(ctx context.Context, objAPI ObjectLayer, bucketName string, config inventory.Config, now time.Time) error {
+	statusLock := globalNSMutex.NewNSLock(minioMetaBucket, inventoryStatusFile(bucketName, config.ID)+".lock")
+	if err := statusLock.GetLock(inventoryLockTimeout); err != nil {
+		// Another node is generating this report.
+		return nil
+	}
+	defer statusLock.Unlock()
+
+	status, err := readInventoryStatus(ctx, objAPI, bucketName, config.ID)
+	if err != nil {
+		return err
+	}
+
+	if now.Sub(status.LastReport) < config.Schedule.Frequency.Duration() {
+		return nil
+	}
+
+	if err = generateInventoryReport(ctx, objAPI, bucketName, config, now); err != nil {
+		return err
+	}
+
+	return saveInventoryStatus(objAPI, bucketName, config.ID, inventoryStatus{LastReport: now})
+}
+
+// inventoryManifestFile - represents a data file entry in inventory manifest.
+type inventoryManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// inventoryManifest - inventory manifest.json as described in
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/storage-inventory-location.html
+type inventoryManifest struct {
+	SourceBucket      string                  `json:"sourceBucket"`
+	DestinationBucket string                  `json:"destinationBucket"`
+	Version           string                  `json:"version"`
+	CreationTimestamp string                  `json:"creationTimestamp"`
+	FileFormat        inventory.Format        `json:"fileFormat"`
+	FileSchema        string                  `json:"fileSchema"`
+	Files             []inventoryManifestFile `json:"files"`
+}
+
+// inventoryFields - returns report columns in the order defined by AWS S3.
+func inventoryFields(config inventory.Config) []inventory.Field {
+	selected := make(map[inventory.Field]bool)
+	for _, field := range config.OptionalFields {
+		selected[field] = true
+	}
+
+	fields := []inventory.Field{}
+	for _, field := range []inventory.Field{
+		inventory.SizeField,
+		inventory.LastModifiedDateField,
+		inventory.ETagField,
+		inventory.StorageClassField,
+		inventory.IsMultipartUploadedField,
+		inventory.ReplicationStatusField,
+		inventory.EncryptionStatusField,
+	} {
+		if selected[field] {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// getEncryptionStatus - returns server side encryption type of an object.
+func getEncryptionStatus(metadata map[string]string) string {
+	switch {
+	case crypto.S3.IsEncrypted(metadata):
+		return "SSE-S3"
+	case crypto.SSEC.IsEncrypted(metadata):
+		return "SSE-C"
+	}
+
+	return "NOT-SSE"
+}
+
+// inventoryRecord - returns report columns of given object.
+func inventoryRecord(bucketName string, objInfo ObjectInfo, fields []inventory.Field) []string {
+	record := []string{bucketName, url.QueryEscape(objInfo.Name)}
+	for _, field := range fields {
+		switch field {
+		case inventory.SizeField:
+			record = append(record, strconv.FormatInt(objInfo.Size, 10))
+		case inventory.LastModifiedDateField:
+			record = append(record, objInfo.ModTime.UTC().Format(timeFormatAMZLong))
+		case inventory.ETagField:
+			record = append(record, objInfo.ETag)
+		case inventory.StorageClassField:
+			storageClass := objInfo.StorageClass
+			if storageClass == "" {
+				storageClass = globalMinioDefaultStorageClass
+			}
+			record = append(record, storageClass)
+		case inventory.IsMultipartUploadedField:
+			record = append(record, strconv.FormatBool(strings.Contains(objInfo.ETag, "-")))
+		case inventory.ReplicationStatusField:
+			record = append(record, "")
+		case inventory.EncryptionStatusField:
+			record = append(record, getEncryptionStatus(objInfo.UserDefined))
+		}
+	}
+
+	return record
+}
+
+// inventoryWriter - writes gzip compressed CSV data files into destination bucket.
+type inventoryWriter struct {
+	ctx        context.Context
+	objAPI     ObjectLayer
+	destBucket string
+	dataPrefix string
+
+	buffer  bytes.Buffer
+	gzipW   *gzip.Writer
+	csvW    *csv.Writer
+	records int
+	files   []inventoryManifestFile
+}
+
+func (w *inventoryWriter) Write(record []string) error {
+	if w.gzipW == nil {
+		w.buffer.Reset()
+		w.gzipW = gzip.NewWriter(&w.buffer)
+		w.csvW = csv.NewWriter(w.gzipW)
+	}
+
+	if err := w.csvW.Write(record); err != nil {
+		return err
+	}
+
+	w.records++
+	if w.records >= inventoryMaxRecordsPerFile {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Flush - uploads buffered records as one data file.
+func (w *inventoryWriter) Flush() error {
+	if w.gzipW == nil {
+		return nil
+	}
+
+	w.csvW.Flush()
+	if err := w.csvW.Error(); err != nil {
+		return err
+	}
+	if err := w.gzipW.Close(); err != nil {
+		return err
+	}
+
+	data := w.buffer.Bytes()
+	md5Sum := md5.Sum(data)
+	md5Hex := hex.EncodeToString(md5Sum[:])
+	hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), md5Hex, getSHA256Hash(data))
+	if err != nil {
+		return err
+	}
+
+	key := path.Join(w.dataPrefix, mustGetUUID()+".csv.gz")
+	if _, err = w.objAPI.PutObject(w.ctx, w.destBucket, key, hashReader, map[string]string{"content-type": "application/x-gzip"}); err != nil {
+		return err
+	}
+
+	w.files = append(w.files, inventoryManifestFile{Key: key, Size: int64(len(data)), MD5Checksum: md5Hex})
+	w.gzipW = nil
+	w.csvW = nil
+	w.records = 0
+	return nil
+}
+
+// generateInventoryReport - lists all objects of given bucket matching
+// configuration filter, writes them as data files and a manifest into
+// destination bucket. Files of a failed report are removed again.
+func generateInventoryReport(ctx context.Context, objAPI ObjectLayer, bucketName string, config inventory.Config, now time.Time) (err error) {
+	dest := config.Destination.S3BucketDestination
+	destBucket := dest.BucketName()
+	if _, err = objAPI.GetBucketInfo(ctx, destBucket); err != nil {
+		return err
+	}
+
+	reportPrefix := path.Join(dest.Prefix, bucketName, config.ID)
+	writer := &inventoryWriter{
+		ctx:        ctx,
+		objAPI:     objAPI,
+		destBucket: destBucket,
+		dataPrefix: path.Join(reportPrefix, "data"),
+	}
+
+	manifestPrefix := path.Join(reportPrefix, now.UTC().Format("2006-01-02T15-04Z"))
+	var manifestKeys []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		keys := manifestKeys
+		for _, file := range writer.files {
+			keys = append(keys, file.Key)
+		}
+		for _, key := range keys {
+			logger.LogIf(ctx, objAPI.DeleteObject(ctx, destBucket, key))
+		}
+	}()
+
+	fields := inventoryFields(config)
+	marker := ""
+	for {
+		result, err := objAPI.ListObjects(ctx, bucketName, config.Prefix(), marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+
+		for _, objInfo := range result.Objects {
+			if objInfo.IsDir {
+				continue
+			}
+
+			if err = writer.Write(inventoryRecord(bucketName, objInfo, fields)); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+
+		marker = result.NextMarker
+		if marker == "" && len(result.Objects) > 0 {
+			marker = result.Objects[len(result.Objects)-1].Name
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	schema := []string{"Bucket", "Key"}
+	for _, field := range fields {
+		schema = append(schema, string(field))
+	}
+
+	manifest := inventoryManifest{
+		SourceBucket:      bucketName,
+		DestinationBucket: dest.Bucket,
+		Version:           inventoryManifestVersion,
+		CreationTimestamp: strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+		FileFormat:        dest.Format,
+		FileSchema:        strings.Join(schema, ", "),
+		Files:             writer.files,
+	}
+	if manifest.Files == nil {
+		manifest.Files = []inventoryManifestFile{}
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	md5Sum := md5.Sum(data)
+	checksum := []byte(hex.EncodeToString(md5Sum[:]))
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{"manifest.json", data},
+		{"manifest.checksum", checksum},
+	} {
+		hashReader, err := hash.NewReader(bytes.NewReader(file.data), int64(len(file.data)), "", getSHA256Hash(file.data))
+		if err != nil {
+			return err
+		}
+
+		key := path.Join(manifestPrefix, file.name)
+		if _, err = objAPI.PutObject(ctx, destBucket, key, hashReader, nil); err != nil {
+			return err
+		}
+		manifestKeys = append(manifestKeys, key)
+	}
+
+	return nil
+}
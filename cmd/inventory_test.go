@@ -0,0 +1,241 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/inventory"
+)
+
+func TestInventoryRecord(t *testing.T) {
+	modTime := time.Date(2018, time.October, 1, 10, 20, 30, 0, time.UTC)
+	objInfo := ObjectInfo{
+		Name:    "photos/a b.jpg",
+		Size:    1024,
+		ModTime: modTime,
+		ETag:    "8a2b8bbd2b1f7cf3b1b9b7a5f1f0e7d1-2",
+	}
+
+	testCases := []struct {
+		fields         []inventory.Field
+		expectedResult []string
+	}{
+		{nil, []string{"bucket", "photos%2Fa+b.jpg"}},
+		{
+			[]inventory.Field{inventory.SizeField, inventory.LastModifiedDateField},
+			[]string{"bucket", "photos%2Fa+b.jpg", "1024", "2018-10-01T10:20:30.000Z"},
+		},
+		{
+			[]inventory.Field{inventory.StorageClassField, inventory.IsMultipartUploadedField, inventory.EncryptionStatusField},
+			[]string{"bucket", "photos%2Fa+b.jpg", "STANDARD", "true", "NOT-SSE"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		result := inventoryRecord("bucket", objInfo, testCase.fields)
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestInventoryFields(t *testing.T) {
+	config := inventory.Config{
+		OptionalFields: []inventory.Field{inventory.EncryptionStatusField, inventory.SizeField, inventory.ETagField},
+	}
+
+	expectedResult := []inventory.Field{inventory.SizeField, inventory.ETagField, inventory.EncryptionStatusField}
+	if result := inventoryFields(config); !reflect.DeepEqual(result, expectedResult) {
+		t.Fatalf("expected: %v, got: %v", expectedResult, result)
+	}
+}
+
+func TestGenerateInventoryReport(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	initNSLock(false)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"source", "reports"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects := []string{"photos/1.jpg", "photos/2.jpg", "videos/1.mp4"}
+	for _, object := range objects {
+		if _, err = obj.PutObject(ctx, "source", object, mustGetHashReader(t, bytes.NewReader([]byte("hello")), 5, "", ""), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := inventory.Config{
+		ID:        "report1",
+		IsEnabled: true,
+		Destination: inventory.Destination{
+			S3BucketDestination: inventory.S3BucketDestination{
+				Bucket: "arn:aws:s3:::reports",
+				Format: inventory.CSVFormat,
+				Prefix: "inventory",
+			},
+		},
+		Filter:                 &inventory.Filter{Prefix: "photos/"},
+		IncludedObjectVersions: inventory.CurrentVersion,
+		OptionalFields:         []inventory.Field{inventory.SizeField},
+		Schedule:               inventory.Schedule{Frequency: inventory.Daily},
+	}
+
+	now := time.Date(2018, time.October, 1, 10, 20, 30, 0, time.UTC)
+	if err = generateInventoryReportIfDue(ctx, obj, "source", config, now); err != nil {
+		t.Fatal(err)
+	}
+
+	var manifestData bytes.Buffer
+	manifestFile := path.Join("inventory", "source", "report1", "2018-10-01T10-20Z", "manifest.json")
+	if err = obj.GetObject(ctx, "reports", manifestFile, 0, -1, &manifestData, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var manifest inventoryManifest
+	if err = json.Unmarshal(manifestData.Bytes(), &manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	if manifest.FileSchema != "Bucket, Key, Size" {
+		t.Fatalf("expected: Bucket, Key, Size, got: %v", manifest.FileSchema)
+	}
+	if len(manifest.Files) != 1 {
+		t.Fatalf("expected: 1 data file, got: %v", len(manifest.Files))
+	}
+
+	var data bytes.Buffer
+	if err = obj.GetObject(ctx, "reports", manifest.Files[0].Key, 0, -1, &data, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	gzipReader, err := gzip.NewReader(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(gzipReader).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRecords := [][]string{
+		{"source", "photos%2F1.jpg", "5"},
+		{"source", "photos%2F2.jpg", "5"},
+	}
+	if !reflect.DeepEqual(records, expectedRecords) {
+		t.Fatalf("expected: %v, got: %v", expectedRecords, records)
+	}
+
+	// Report is not due again within a day.
+	if err = generateInventoryReportIfDue(ctx, obj, "source", config, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := obj.ListObjects(ctx, "reports", "inventory/source/report1/2018-10-01T11", "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 0 {
+		t.Fatalf("expected: no new report, got: %v", result.Objects)
+	}
+}
+
+// failingManifestObjects - object layer failing to write manifest checksums.
+type failingManifestObjects struct {
+	ObjectLayer
+}
+
+func (l failingManifestObjects) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (ObjectInfo, error) {
+	if strings.HasSuffix(object, "manifest.checksum") {
+		return ObjectInfo{}, errDiskFull
+	}
+	return l.ObjectLayer.PutObject(ctx, bucket, object, data, metadata)
+}
+
+// Tests files of a failed report are removed.
+func TestGenerateInventoryReportCleanup(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	initNSLock(false)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"source", "reports"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = obj.PutObject(ctx, "source", "object", mustGetHashReader(t, bytes.NewReader([]byte("hello")), 5, "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	config := inventory.Config{
+		ID:        "report1",
+		IsEnabled: true,
+		Destination: inventory.Destination{
+			S3BucketDestination: inventory.S3BucketDestination{
+				Bucket: "arn:aws:s3:::reports",
+				Format: inventory.CSVFormat,
+			},
+		},
+		IncludedObjectVersions: inventory.CurrentVersion,
+		Schedule:               inventory.Schedule{Frequency: inventory.Daily},
+	}
+
+	now := time.Date(2018, time.October, 1, 10, 20, 30, 0, time.UTC)
+	if err = generateInventoryReport(ctx, failingManifestObjects{obj}, "source", config, now); err != errDiskFull {
+		t.Fatalf("expected: %v, got: %v", errDiskFull, err)
+	}
+
+	result, err := obj.ListObjects(ctx, "reports", "", "", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 0 {
+		t.Fatalf("expected: no report files, got: %v", result.Objects)
+	}
+}
@@ -113,28 +113,83 @@ func isHTTPRequestValid(req *http.Request) bool {
 	return webRequestAuthenticate(req) == nil
 }
 
-// Check if the request is authenticated.
+// Check if the request is authenticated by the server owner.
 // Returns nil if the request is authenticated. errNoAuthToken if token missing.
+// errInvalidAccessKeyID if token is not of the server owner.
 // Returns errAuthentication for all other errors.
 func webRequestAuthenticate(req *http.Request) error {
+	_, owner, err := webRequestAuthenticateUser(req)
+	if err != nil {
+		return err
+	}
+	if !owner {
+		return errInvalidAccessKeyID
+	}
+	return nil
+}
+
+// Check if the request is authenticated by the server owner or a user
+// with temporary credentials. Returns credentials of the user and true
+// if the user is the server owner. Errors are same as webRequestAuthenticate.
+func webRequestAuthenticateUser(req *http.Request) (auth.Credentials, bool, error) {
 	var claims jwtgo.StandardClaims
 	jwtToken, err := jwtreq.ParseFromRequestWithClaims(req, jwtreq.AuthorizationHeaderExtractor, &claims, keyFuncCallback)
 	if err != nil {
 		if err == jwtreq.ErrNoTokenInRequest {
-			return errNoAuthToken
+			return auth.Credentials{}, false, errNoAuthToken
 		}
-		return errAuthentication
+		return auth.Credentials{}, false, errAuthentication
 	}
-	if err = claims.Valid(); err != nil {
-		return errAuthentication
+	return authenticateWebClaims(jwtToken, claims)
+}
+
+// authenticateURLToken - validates URL token of the server owner or a user
+// with temporary credentials. Returns credentials of the user and true if
+// the user is the server owner.
+func authenticateURLToken(tokenString string) (auth.Credentials, bool, error) {
+	if tokenString == "" {
+		return auth.Credentials{}, false, errNoAuthToken
 	}
-	if claims.Subject != globalServerConfig.GetCredential().AccessKey {
-		return errInvalidAccessKeyID
+	var claims jwtgo.StandardClaims
+	jwtToken, err := jwtgo.ParseWithClaims(tokenString, &claims, keyFuncCallback)
+	if err != nil {
+		return auth.Credentials{}, false, errAuthentication
+	}
+	return authenticateWebClaims(jwtToken, claims)
+}
+
+// authenticateWebClaims - validates claims of parsed web or URL token.
+func authenticateWebClaims(jwtToken *jwtgo.Token, claims jwtgo.StandardClaims) (auth.Credentials, bool, error) {
+	if err := claims.Valid(); err != nil {
+		return auth.Credentials{}, false, errAuthentication
+	}
+	cred, owner, errCode := checkKeyValid(claims.Subject)
+	switch errCode {
+	case ErrNone:
+	case ErrInvalidAccessKeyID:
+		return auth.Credentials{}, false, errInvalidAccessKeyID
+	default:
+		return auth.Credentials{}, false, errAuthentication
 	}
 	if !jwtToken.Valid {
-		return errAuthentication
+		return auth.Credentials{}, false, errAuthentication
 	}
-	return nil
+	return cred, owner, nil
+}
+
+// authenticateTempJWT - returns token of given temporary credentials expiring
+// at most after given duration.
+func authenticateTempJWT(cred auth.Credentials, expiry time.Duration) (string, error) {
+	expiresAt := UTCNow().Add(expiry)
+	if cred.Expiration != nil && cred.Expiration.Before(expiresAt) {
+		expiresAt = *cred.Expiration
+	}
+
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.StandardClaims{
+		ExpiresAt: expiresAt.Unix(),
+		Subject:   cred.AccessKey,
+	})
+	return jwt.SignedString([]byte(globalServerConfig.GetCredential().SecretKey))
 }
 
 func newAuthToken() string {
@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/minio/minio/pkg/madmin"
 )
 
 // localAdminClient - represents admin operation to be executed locally.
@@ -80,3 +82,31 @@ func (lc localAdminClient) GetConfig() ([]byte, error) {
 
 	return json.Marshal(globalServerConfig)
 }
+
+// ServerDrives - returns details of the drives of the local server.
+func (lc localAdminClient) ServerDrives() ([]madmin.ServerDrive, error) {
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		return nil, errServerNotInitialized
+	}
+
+	zones, err := getErasureZones(objLayer)
+	if err != nil {
+		return nil, err
+	}
+	return getLocalServerDrives(zones), nil
+}
+
+// DriveSpeedtest - runs a speedtest on the drives of the local server.
+func (lc localAdminClient) DriveSpeedtest(size int64) ([]madmin.DriveSpeedtestResult, error) {
+	objLayer := newObjectLayerFn()
+	if objLayer == nil {
+		return nil, errServerNotInitialized
+	}
+
+	zones, err := getErasureZones(objLayer)
+	if err != nil {
+		return nil, err
+	}
+	return localDriveSpeedtest(zones, size), nil
+}
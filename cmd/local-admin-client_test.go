@@ -35,3 +35,11 @@ func TestLocalAdminClientServerInfo(t *testing.T) {
 func TestLocalAdminClientGetConfig(t *testing.T) {
 	testAdminCmdRunnerGetConfig(t, &localAdminClient{})
 }
+
+func TestLocalAdminClientServerDrives(t *testing.T) {
+	testAdminCmdRunnerServerDrives(t, &localAdminClient{})
+}
+
+func TestLocalAdminClientDriveSpeedtest(t *testing.T) {
+	testAdminCmdRunnerDriveSpeedtest(t, &localAdminClient{})
+}
@@ -104,6 +104,30 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 			prometheus.GaugeValue,
 			float64(cs.Free),
 		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_hits_total"),
+				"Total number of reads served from the cache on current Minio server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(cs.Hits),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_misses_total"),
+				"Total number of cacheable reads served from the backend on current Minio server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(cs.Misses),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "disk", "cache_evictions_total"),
+				"Total number of entries evicted from the cache on current Minio server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(cs.Evictions),
+		)
 	}
 
 	// Expose disk stats only if applicable
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +41,11 @@ type NotificationSys struct {
 	bucketRulesMap             map[string]event.RulesMap
 	bucketRemoteTargetRulesMap map[string]map[event.TargetID]event.RulesMap
 	peerRPCClientMap           map[xnet.Host]*PeerRPCClient
+
+	// targetArgs holds the configuration arguments of targets
+	// created from serverConfig, guarded by targetArgsMu.
+	targetArgsMu sync.Mutex
+	targetArgs   map[event.TargetID]interface{}
 }
 
 // GetARNList - returns available ARNs.
@@ -151,6 +157,114 @@ func (sys *NotificationSys) RemoveBucketPolicy(ctx context.Context, bucketName s
 	}()
 }
 
+// SetBucketACL - calls SetBucketACL RPC call on all peers.
+func (sys *NotificationSys) SetBucketACL(ctx context.Context, bucketName string, acl policy.CannedACL) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.SetBucketACL(bucketName, acl); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
+// SetIAMIdentity - calls SetIAMIdentity RPC call on all peers.
+func (sys *NotificationSys) SetIAMIdentity(ctx context.Context, identity iamIdentity) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.SetIAMIdentity(identity); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
+// LoadIAM - calls LoadIAM RPC call on all peers.
+func (sys *NotificationSys) LoadIAM(ctx context.Context) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.LoadIAM(); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
+// ReloadPoolMeta - calls ReloadPoolMeta RPC call on all peers.
+func (sys *NotificationSys) ReloadPoolMeta(ctx context.Context) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.ReloadPoolMeta(); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
+// LoadConfig - calls LoadConfig RPC call on all peers.
+func (sys *NotificationSys) LoadConfig(ctx context.Context) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.LoadConfig(); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
+// ReloadNotificationTargets - calls ReloadNotificationTargets RPC call on all peers.
+func (sys *NotificationSys) ReloadNotificationTargets(ctx context.Context) {
+	go func() {
+		var wg sync.WaitGroup
+		for addr, client := range sys.peerRPCClientMap {
+			wg.Add(1)
+			go func(addr xnet.Host, client *PeerRPCClient) {
+				defer wg.Done()
+				if err := client.ReloadNotificationTargets(); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", addr.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(addr, client)
+		}
+		wg.Wait()
+	}()
+}
+
 // PutBucketNotification - calls PutBucketNotification RPC call on all peers.
 func (sys *NotificationSys) PutBucketNotification(ctx context.Context, bucketName string, rulesMap event.RulesMap) {
 	go func() {
@@ -373,6 +487,50 @@ func (sys *NotificationSys) RemoveRulesMap(bucketName string, rulesMap event.Rul
 	}
 }
 
+// UpdateTargets - updates targets to match notification targets in
+// config. Targets which were removed or whose configuration changed
+// are closed and new ones are started, bucket rules referring to
+// targets are left untouched.
+func (sys *NotificationSys) UpdateTargets(config *serverConfig) {
+	sys.targetArgsMu.Lock()
+	defer sys.targetArgsMu.Unlock()
+
+	newTargetArgs := getNotificationTargetArgs(config)
+
+	var removedIDs []event.TargetID
+	for targetID, args := range sys.targetArgs {
+		if newArgs, ok := newTargetArgs[targetID]; !ok || !reflect.DeepEqual(args, newArgs) {
+			removedIDs = append(removedIDs, targetID)
+			delete(sys.targetArgs, targetID)
+		}
+	}
+
+	for terr := range sys.targetList.Remove(removedIDs...) {
+		reqInfo := (&logger.ReqInfo{}).AppendTags("targetID", terr.ID.String())
+		ctx := logger.SetReqInfo(context.Background(), reqInfo)
+		logger.LogIf(ctx, terr.Err)
+	}
+
+	for targetID, args := range newTargetArgs {
+		if _, ok := sys.targetArgs[targetID]; ok {
+			continue
+		}
+
+		reqInfo := (&logger.ReqInfo{}).AppendTags("targetID", targetID.String())
+		ctx := logger.SetReqInfo(context.Background(), reqInfo)
+		target, err := newNotificationTarget(targetID, args)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		if err = sys.targetList.Add(target); err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		sys.targetArgs[targetID] = args
+	}
+}
+
 // RemoveNotification - removes all notification configuration for bucket name.
 func (sys *NotificationSys) RemoveNotification(bucketName string) {
 	sys.Lock()
@@ -446,12 +604,22 @@ func NewNotificationSys(config *serverConfig, endpoints EndpointList) *Notificat
 	targetList := getNotificationTargets(config)
 	peerRPCClientMap := makeRemoteRPCClients(endpoints)
 
+	// Remember arguments of successfully created targets only, so
+	// that failed ones are retried by UpdateTargets().
+	targetArgs := make(map[event.TargetID]interface{})
+	for targetID, args := range getNotificationTargetArgs(config) {
+		if targetList.Exists(targetID) {
+			targetArgs[targetID] = args
+		}
+	}
+
 	// bucketRulesMap/bucketRemoteTargetRulesMap are initialized by NotificationSys.Init()
 	return &NotificationSys{
 		targetList:                 targetList,
 		bucketRulesMap:             make(map[string]event.RulesMap),
 		bucketRemoteTargetRulesMap: make(map[string]map[event.TargetID]event.RulesMap),
 		peerRPCClientMap:           peerRPCClientMap,
+		targetArgs:                 targetArgs,
 	}
 }
 
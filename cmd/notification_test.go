@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+func TestNotificationSysUpdateTargets(t *testing.T) {
+	config := newServerConfig()
+	config, err := setConfigKV(config, "notify.webhook.1", []byte(`{"enable":true,"endpoint":"http://localhost:8080/1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, err = setConfigKV(config, "notify.webhook.2", []byte(`{"enable":true,"endpoint":"http://localhost:8080/2"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := NewNotificationSys(config, EndpointList{})
+	target1 := event.TargetID{ID: "1", Name: "webhook"}
+	target2 := event.TargetID{ID: "2", Name: "webhook"}
+	target3 := event.TargetID{ID: "3", Name: "webhook"}
+	if !sys.targetList.Exists(target1) || !sys.targetList.Exists(target2) {
+		t.Fatalf("Expected targets %v and %v, got %v", target1, target2, sys.targetList.List())
+	}
+
+	rulesMap := event.NewRulesMap([]event.Name{event.ObjectCreatedAll}, "*", target2)
+	sys.AddRulesMap("bucket", rulesMap)
+
+	// Remove target 1, enable target 3 and leave target 2 unchanged.
+	newConfig, err := delConfigKV(config, "notify.webhook.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newConfig, err = setConfigKV(newConfig, "notify.webhook.3", []byte(`{"enable":true,"endpoint":"http://localhost:8080/3"}`)); err != nil {
+		t.Fatal(err)
+	}
+	sys.UpdateTargets(newConfig)
+
+	if sys.targetList.Exists(target1) {
+		t.Errorf("Expected target %v to be removed", target1)
+	}
+	if !sys.targetList.Exists(target2) || !sys.targetList.Exists(target3) {
+		t.Errorf("Expected targets %v and %v, got %v", target2, target3, sys.targetList.List())
+	}
+	if len(sys.targetArgs) != 2 {
+		t.Errorf("Expected 2 targets, got %d", len(sys.targetArgs))
+	}
+
+	// Rules referring to a surviving target are kept.
+	if _, ok := sys.bucketRulesMap["bucket"].Match(event.ObjectCreatedPut, "object")[target2]; !ok {
+		t.Errorf("Expected rules of bucket to refer to %v", target2)
+	}
+
+	// Changed targets are recreated, disabled targets removed.
+	if newConfig, err = setConfigKV(newConfig, "notify.webhook.2.endpoint", []byte(`"http://localhost:9090/2"`)); err != nil {
+		t.Fatal(err)
+	}
+	if newConfig, err = setConfigKV(newConfig, "notify.webhook.3.enable", []byte(`false`)); err != nil {
+		t.Fatal(err)
+	}
+	sys.UpdateTargets(newConfig)
+
+	if !sys.targetList.Exists(target2) || sys.targetList.Exists(target3) {
+		t.Errorf("Expected only target %v, got %v", target2, sys.targetList.List())
+	}
+	if args := sys.targetArgs[target2]; !reflect.DeepEqual(args, newConfig.Notify.Webhook["2"]) {
+		t.Errorf("Expected target %v to use the new configuration", target2)
+	}
+}
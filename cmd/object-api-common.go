@@ -92,12 +92,22 @@ func deleteBucketMetadata(ctx context.Context, bucket string, objAPI ObjectLayer
 
 	// Delete listener config, if present - ignore any errors.
 	removeListenerConfig(ctx, objAPI, bucket)
+
+	// Delete inventory config, if present - ignore any errors.
+	removeInventoryConfig(ctx, objAPI, bucket)
+
+	// Delete bucket ACL, if present - ignore any errors.
+	removeBucketACL(ctx, objAPI, bucket)
 }
 
 // Depending on the disk type network or local, initialize storage API.
 func newStorageAPI(endpoint Endpoint) (storage StorageAPI, err error) {
 	if endpoint.IsLocal {
-		return newPosix(endpoint.Path)
+		var disk *posix
+		if disk, err = newPosix(endpoint.Path); err != nil {
+			return nil, err
+		}
+		return newStatsStorage(disk, endpoint.Path), nil
 	}
 
 	return newStorageRPC(endpoint), nil
@@ -223,6 +223,13 @@ func (e BucketPolicyNotFound) Error() string {
 	return "No bucket policy found for bucket: " + e.Bucket
 }
 
+// BucketInventoryConfigNotFound - no bucket inventory configuration found.
+type BucketInventoryConfigNotFound GenericError
+
+func (e BucketInventoryConfigNotFound) Error() string {
+	return "No inventory configuration found for bucket: " + e.Bucket
+}
+
 /// Bucket related errors.
 
 // BucketNameInvalid - bucketname provided is invalid.
@@ -100,7 +100,7 @@ func (api objectAPIHandlers) SelectObjectContentHandler(w http.ResponseWriter, r
 			// an HTTP status code 404 ("no such key") error. * if you don’t have the
 			// s3:ListBucket permission, Amazon S3 will return an HTTP status code 403
 			// ("access denied") error.`
-			if globalPolicySys.IsAllowed(policy.Args{
+			if isRequestAllowed(ctx, policy.Args{
 				Action:          policy.ListBucketAction,
 				BucketName:      bucket,
 				ConditionValues: getConditionValues(r, ""),
@@ -317,7 +317,7 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 			// * if you don’t have the s3:ListBucket
 			//   permission, Amazon S3 will return an HTTP
 			//   status code 403 ("access denied") error.`
-			if globalPolicySys.IsAllowed(policy.Args{
+			if isRequestAllowed(ctx, policy.Args{
 				Action:          policy.ListBucketAction,
 				BucketName:      bucket,
 				ConditionValues: getConditionValues(r, ""),
@@ -336,10 +336,11 @@ func (api objectAPIHandlers) GetObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// If object is encrypted, we avoid the cache layer.
+	// If object is encrypted, we avoid the cache layer unless
+	// the cache drives are encrypted.
 	isEncrypted := objectAPI.IsEncryptionSupported() && (crypto.SSEC.IsRequested(r.Header) ||
 		crypto.S3.IsEncrypted(objInfo.UserDefined))
-	if isEncrypted && api.CacheAPI() != nil {
+	if isEncrypted && api.CacheAPI() != nil && !isCacheableSSE(r.Header, objInfo.UserDefined) {
 		// Close the existing reader before re-querying the backend
 		if reader != nil {
 			reader.Close()
@@ -475,7 +476,7 @@ func (api objectAPIHandlers) HeadObjectHandler(w http.ResponseWriter, r *http.Re
 			// If the object you request does not exist, the error Amazon S3 returns depends on whether you also have the s3:ListBucket permission.
 			// * If you have the s3:ListBucket permission on the bucket, Amazon S3 will return an HTTP status code 404 ("no such key") error.
 			// * if you don’t have the s3:ListBucket permission, Amazon S3 will return an HTTP status code 403 ("access denied") error.`
-			if globalPolicySys.IsAllowed(policy.Args{
+			if isRequestAllowed(ctx, policy.Args{
 				Action:          policy.ListBucketAction,
 				BucketName:      bucket,
 				ConditionValues: getConditionValues(r, ""),
@@ -768,6 +769,14 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// ACL of source object is not copied, destination object
+	// gets ACL requested by x-amz-acl header.
+	if s3Error := setRequestObjectACL(r.Header, srcInfo.UserDefined); s3Error != ErrNone {
+		pipeWriter.CloseWithError(errInvalidArgument)
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// We need to preserve the encryption headers set in EncryptRequest,
 	// so we do not want to override them, copy them instead.
 	for k, v := range encMetadata {
@@ -956,6 +965,11 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if s3Error := setRequestObjectACL(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	if rAuthType == authTypeStreamingSigned {
 		if contentEncoding, ok := metadata["content-encoding"]; ok {
 			contentEncoding = trimAwsChunkedContentEncoding(contentEncoding)
@@ -990,7 +1004,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		writeErrorResponse(w, ErrAccessDenied, r.URL)
 		return
 	case authTypeAnonymous:
-		if !globalPolicySys.IsAllowed(policy.Args{
+		if !isRequestAllowed(ctx, policy.Args{
 			Action:          policy.PutObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
@@ -1007,6 +1021,10 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 			writeErrorResponse(w, s3Err, r.URL)
 			return
 		}
+		if s3Err = isPutAllowed(ctx, r, bucket, object); s3Err != ErrNone {
+			writeErrorResponse(w, s3Err, r.URL)
+			return
+		}
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Err = isReqAuthenticatedV2(r)
 		if s3Err != ErrNone {
@@ -1019,6 +1037,10 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 			writeErrorResponse(w, s3Err, r.URL)
 			return
 		}
+		if s3Err = isPutAllowed(ctx, r, bucket, object); s3Err != ErrNone {
+			writeErrorResponse(w, s3Err, r.URL)
+			return
+		}
 		if !skipContentSha256Cksum(r) {
 			sha256hex = getContentSha256Cksum(r)
 		}
@@ -1054,7 +1076,7 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	if api.CacheAPI() != nil && !hasServerSideEncryptionHeader(r.Header) {
+	if api.CacheAPI() != nil && (!hasServerSideEncryptionHeader(r.Header) || isCacheableSSE(r.Header, metadata)) {
 		putObject = api.CacheAPI().PutObject
 	}
 
@@ -1158,6 +1180,11 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	if s3Error := setRequestObjectACL(r.Header, metadata); s3Error != ErrNone {
+		writeErrorResponse(w, s3Error, r.URL)
+		return
+	}
+
 	// We need to preserve the encryption headers set in EncryptRequest,
 	// so we do not want to override them, copy them instead.
 	for k, v := range encMetadata {
@@ -1474,7 +1501,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		writeErrorResponse(w, ErrAccessDenied, r.URL)
 		return
 	case authTypeAnonymous:
-		if !globalPolicySys.IsAllowed(policy.Args{
+		if !isRequestAllowed(ctx, policy.Args{
 			Action:          policy.PutObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
@@ -1492,6 +1519,10 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
+		if s3Error = isPutAllowed(ctx, r, bucket, object); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
 	case authTypeSignedV2, authTypePresignedV2:
 		s3Error := isReqAuthenticatedV2(r)
 		if s3Error != ErrNone {
@@ -1503,6 +1534,10 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 			writeErrorResponse(w, s3Error, r.URL)
 			return
 		}
+		if s3Error := isPutAllowed(ctx, r, bucket, object); s3Error != ErrNone {
+			writeErrorResponse(w, s3Error, r.URL)
+			return
+		}
 
 		if !skipContentSha256Cksum(r) {
 			sha256hex = getContentSha256Cksum(r)
@@ -57,6 +57,53 @@ func (rpcClient *PeerRPCClient) RemoveBucketPolicy(bucketName string) error {
 	return rpcClient.Call(peerServiceName+".RemoveBucketPolicy", &args, &reply)
 }
 
+// SetBucketACL - calls set bucket ACL RPC.
+func (rpcClient *PeerRPCClient) SetBucketACL(bucketName string, acl policy.CannedACL) error {
+	args := SetBucketACLArgs{
+		BucketName: bucketName,
+		ACL:        acl,
+	}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".SetBucketACL", &args, &reply)
+}
+
+// SetIAMIdentity - calls set IAM identity RPC.
+func (rpcClient *PeerRPCClient) SetIAMIdentity(identity iamIdentity) error {
+	args := SetIAMIdentityArgs{
+		Identity: identity,
+	}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".SetIAMIdentity", &args, &reply)
+}
+
+// LoadIAM - calls load IAM RPC.
+func (rpcClient *PeerRPCClient) LoadIAM() error {
+	args := AuthArgs{}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".LoadIAM", &args, &reply)
+}
+
+// ReloadPoolMeta - calls reload pool meta RPC.
+func (rpcClient *PeerRPCClient) ReloadPoolMeta() error {
+	args := AuthArgs{}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".ReloadPoolMeta", &args, &reply)
+}
+
+// LoadConfig - calls load config RPC.
+func (rpcClient *PeerRPCClient) LoadConfig() error {
+	args := AuthArgs{}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".LoadConfig", &args, &reply)
+}
+
+// ReloadNotificationTargets - calls reload notification targets RPC.
+func (rpcClient *PeerRPCClient) ReloadNotificationTargets() error {
+	args := AuthArgs{}
+	reply := VoidReply{}
+	return rpcClient.Call(peerServiceName+".ReloadNotificationTargets", &args, &reply)
+}
+
 // PutBucketNotification - calls put bukcet notification RPC.
 func (rpcClient *PeerRPCClient) PutBucketNotification(bucketName string, rulesMap event.RulesMap) error {
 	args := PutBucketNotificationArgs{
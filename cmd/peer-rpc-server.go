@@ -47,6 +47,10 @@ type DeleteBucketArgs struct {
 func (receiver *peerRPCReceiver) DeleteBucket(args *DeleteBucketArgs, reply *VoidReply) error {
 	globalNotificationSys.RemoveNotification(args.BucketName)
 	globalPolicySys.Remove(args.BucketName)
+	if globalInventorySys != nil {
+		globalInventorySys.Remove(args.BucketName)
+	}
+	globalACLSys.Remove(args.BucketName)
 	return nil
 }
 
@@ -75,6 +79,87 @@ func (receiver *peerRPCReceiver) RemoveBucketPolicy(args *RemoveBucketPolicyArgs
 	return nil
 }
 
+// SetBucketACLArgs - set bucket ACL RPC arguments.
+type SetBucketACLArgs struct {
+	AuthArgs
+	BucketName string
+	ACL        policy.CannedACL
+}
+
+// SetBucketACL - handles set bucket ACL RPC call which sets bucket canned ACL to globalACLSys.
+func (receiver *peerRPCReceiver) SetBucketACL(args *SetBucketACLArgs, reply *VoidReply) error {
+	globalACLSys.Set(args.BucketName, args.ACL)
+	return nil
+}
+
+// SetIAMIdentityArgs - set IAM identity RPC arguments.
+type SetIAMIdentityArgs struct {
+	AuthArgs
+	Identity iamIdentity
+}
+
+// SetIAMIdentity - handles set IAM identity RPC call which adds temporary credentials to globalIAMSys.
+func (receiver *peerRPCReceiver) SetIAMIdentity(args *SetIAMIdentityArgs, reply *VoidReply) error {
+	globalIAMSys.Set(args.Identity)
+	return nil
+}
+
+// LoadIAM - handles load IAM RPC call which reloads canned policies and
+// group policy mappings into globalIAMSys.
+func (receiver *peerRPCReceiver) LoadIAM(args *AuthArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	return globalIAMSys.refresh(objAPI)
+}
+
+// ReloadPoolMeta - handles reload pool meta RPC call which reloads status
+// of server pools after a decommission was started or canceled.
+func (receiver *peerRPCReceiver) ReloadPoolMeta(args *AuthArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// Single pool deployments have no pool status.
+	z, ok := objAPI.(*xlZones)
+	if !ok {
+		return nil
+	}
+
+	return z.reloadPoolMeta(context.Background())
+}
+
+// LoadConfig - handles load config RPC call which reloads config.json
+// after it was changed through the admin API.
+func (receiver *peerRPCReceiver) LoadConfig(args *AuthArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	return loadConfig(objAPI)
+}
+
+// ReloadNotificationTargets - handles reload notification targets RPC
+// call which updates notification targets from config.json.
+func (receiver *peerRPCReceiver) ReloadNotificationTargets(args *AuthArgs, reply *VoidReply) error {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	srvCfg, err := getValidConfig(objAPI)
+	if err != nil {
+		return err
+	}
+
+	globalNotificationSys.UpdateTargets(srvCfg)
+	return nil
+}
+
 // PutBucketNotificationArgs - put bucket notification RPC arguments.
 type PutBucketNotificationArgs struct {
 	AuthArgs
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -90,6 +91,19 @@ func (sys *PolicySys) IsAllowed(args policy.Args) bool {
 	return args.IsOwner
 }
 
+// IsDenied - checks whether bucket policy of given bucket explicitly denies
+// given policy args.
+func (sys *PolicySys) IsDenied(args policy.Args) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if p, found := sys.bucketPolicyMap[args.BucketName]; found {
+		return p.IsDenied(args)
+	}
+
+	return false
+}
+
 // Refresh PolicySys.
 func (sys *PolicySys) refresh(objAPI ObjectLayer) error {
 	buckets, err := objAPI.ListBuckets(context.Background())
@@ -178,12 +192,22 @@ func getConditionValues(request *http.Request, locationConstraint string) map[st
 		}
 	}
 
+	currentTime := UTCNow()
 	args["SourceIp"] = []string{handlers.GetSourceIP(request)}
+	args["SecureTransport"] = []string{strconv.FormatBool(request.TLS != nil)}
+	args["CurrentTime"] = []string{currentTime.Format(time.RFC3339)}
+	args["EpochTime"] = []string{strconv.FormatInt(currentTime.Unix(), 10)}
+	args["UserAgent"] = []string{request.UserAgent()}
 
 	if locationConstraint != "" {
 		args["LocationConstraint"] = []string{locationConstraint}
 	}
 
+	// Storage class is looked up by the name of its condition key.
+	if sc := request.Header.Get(amzStorageClass); sc != "" {
+		args[amzStorageClass] = []string{sc}
+	}
+
 	return args
 }
 
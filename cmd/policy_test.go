@@ -17,8 +17,12 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"net/http"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 
 	miniogopolicy "github.com/minio/minio-go/pkg/policy"
 	"github.com/minio/minio-go/pkg/set"
@@ -281,6 +285,56 @@ func getReadOnlyStatement(bucketName, prefix string) []miniogopolicy.Statement {
 	}
 }
 
+func TestPolicySysIsAllowedStorageClass(t *testing.T) {
+	storageClassFunc, err := condition.NewStringEqualsFunc(condition.S3XAmzStorageClass, "ARCHIVE")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	policySys := NewPolicySys()
+	policySys.Set("mybucket", policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.PutObjectAction),
+				policy.NewResourceSet(policy.NewResource("mybucket", "/archive/*")),
+				condition.NewFunctions(storageClassFunc),
+			),
+		},
+	})
+
+	testCases := []struct {
+		storageClass   string
+		expectedResult bool
+	}{
+		{"ARCHIVE", true},
+		{"STANDARD", false},
+		{"", false},
+	}
+
+	for i, testCase := range testCases {
+		req, err := http.NewRequest("PUT", "http://localhost:9000/mybucket/archive/myobject", nil)
+		if err != nil {
+			t.Fatalf("unexpected error. %v\n", err)
+		}
+		if testCase.storageClass != "" {
+			req.Header.Set(amzStorageClass, testCase.storageClass)
+		}
+
+		result := policySys.IsAllowed(policy.Args{
+			Action:          policy.PutObjectAction,
+			BucketName:      "mybucket",
+			ConditionValues: getConditionValues(req, ""),
+			ObjectName:      "archive/myobject",
+		})
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestPolicyToBucketAccessPolicy(t *testing.T) {
 	case1Policy := &policy.Policy{
 		Version: policy.DefaultVersion,
@@ -422,3 +476,58 @@ func TestBucketAccessPolicyToPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestGetConditionValues(t *testing.T) {
+	req, err := http.NewRequest("GET", "http://localhost:9000/mybucket?prefix=photos/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	req.Header.Set("User-Agent", "Minio")
+	req.RemoteAddr = "192.168.1.10:32768"
+
+	tlsReq := *req
+	tlsReq.TLS = &tls.ConnectionState{}
+
+	testCases := []struct {
+		request                 *http.Request
+		locationConstraint      string
+		expectedSecureTransport string
+		expectedLocation        []string
+	}{
+		{req, "", "false", nil},
+		{&tlsReq, "us-east-1", "true", []string{"us-east-1"}},
+	}
+
+	for i, testCase := range testCases {
+		values := getConditionValues(testCase.request, testCase.locationConstraint)
+
+		if !reflect.DeepEqual(values["SecureTransport"], []string{testCase.expectedSecureTransport}) {
+			t.Fatalf("case %v: SecureTransport: expected: %v, got: %v\n", i+1, testCase.expectedSecureTransport, values["SecureTransport"])
+		}
+
+		if !reflect.DeepEqual(values["LocationConstraint"], testCase.expectedLocation) {
+			t.Fatalf("case %v: LocationConstraint: expected: %v, got: %v\n", i+1, testCase.expectedLocation, values["LocationConstraint"])
+		}
+
+		if !reflect.DeepEqual(values["SourceIp"], []string{"192.168.1.10"}) {
+			t.Fatalf("case %v: SourceIp: expected: 192.168.1.10, got: %v\n", i+1, values["SourceIp"])
+		}
+
+		if !reflect.DeepEqual(values["UserAgent"], []string{"Minio"}) {
+			t.Fatalf("case %v: UserAgent: expected: Minio, got: %v\n", i+1, values["UserAgent"])
+		}
+
+		if !reflect.DeepEqual(values["prefix"], []string{"photos/"}) {
+			t.Fatalf("case %v: prefix: expected: photos/, got: %v\n", i+1, values["prefix"])
+		}
+
+		currentTime, err := time.Parse(time.RFC3339, values["CurrentTime"][0])
+		if err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+
+		if values["EpochTime"][0] != strconv.FormatInt(currentTime.Unix(), 10) {
+			t.Fatalf("case %v: EpochTime: expected: %v, got: %v\n", i+1, currentTime.Unix(), values["EpochTime"])
+		}
+	}
+}
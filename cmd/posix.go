@@ -290,6 +290,11 @@ type DiskInfo struct {
 	Total uint64
 	Free  uint64
 	Used  uint64
+
+	// Total and free inodes, zero when not
+	// reported by the underlying filesystem.
+	Files uint64
+	Ffree uint64
 }
 
 // DiskInfo provides current information about disk space usage,
@@ -307,6 +312,8 @@ func (s *posix) DiskInfo() (info DiskInfo, err error) {
 		Total: di.Total,
 		Free:  di.Free,
 		Used:  used,
+		Files: di.Files,
+		Ffree: di.Ffree,
 	}, nil
 
 }
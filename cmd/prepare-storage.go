@@ -193,14 +193,14 @@ func connectLoadInitFormats(firstDisk bool, endpoints EndpointList, setCount, dr
 	}
 
 	if format.ID == "" {
-		if err = formatXLFixDeploymentID(context.Background(), storageDisks, format); err != nil {
+		if err = formatXLFixDeploymentID(context.Background(), endpoints, storageDisks, format); err != nil {
 			return nil, err
 		}
 	}
 
 	logger.SetDeploymentID(format.ID)
 
-	if err = formatXLFixLocalDeploymentID(context.Background(), storageDisks, format); err != nil {
+	if err = formatXLFixLocalDeploymentID(context.Background(), endpoints, storageDisks, format); err != nil {
 		return nil, err
 	}
 	return format, nil
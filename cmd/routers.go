@@ -118,6 +118,9 @@ func configureServerHandler(endpoints EndpointList) (http.Handler, error) {
 		}
 	}
 
+	// Add STS router, always enabled.
+	registerSTSRouter(router)
+
 	// Add API router.
 	registerAPIRouter(router)
 
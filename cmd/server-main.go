@@ -62,7 +62,9 @@ DIR:
   multiple drives into a single large system, pass one directory per
   filesystem separated by space. You may also use a '...' convention
   to abbreviate the directory arguments. Remote directories in a
-  distributed setup are encoded as HTTP(s) URIs.
+  distributed setup are encoded as HTTP(s) URIs. Each argument with
+  the prefix 'zone:' forms an independent server pool (zone), new
+  objects are written to the zone with most free space.
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -80,6 +82,17 @@ ENVIRONMENT VARIABLES:
      MINIO_CACHE_EXCLUDE: List of cache exclusion patterns delimited by ";".
      MINIO_CACHE_EXPIRY: Cache expiry duration in days.
      MINIO_CACHE_MAXUSE: Maximum permitted usage of the cache in percentage (0-100).
+     MINIO_CACHE_COMMIT: Commit mode of uploads, "writethrough" or "writeback".
+     MINIO_CACHE_RANGE: To enable caching of ranged reads, set this value to "on".
+     MINIO_CACHE_POLICY: Eviction policy of the cache, "lru" or "lfu".
+     MINIO_CACHE_WATERMARK_LOW: Cache usage in percentage of max use at which eviction stops.
+     MINIO_CACHE_WATERMARK_HIGH: Cache usage in percentage of max use at which eviction starts.
+     MINIO_CACHE_INCLUDE: List of cache inclusion patterns delimited by ";".
+     MINIO_CACHE_QUOTA: List of cache quotas of buckets delimited by ";", for example "images=10GiB".
+     MINIO_CACHE_MIN_SIZE: Minimum size of cached objects, for example "1KiB".
+     MINIO_CACHE_MAX_SIZE: Maximum size of cached objects, for example "1GiB".
+     MINIO_CACHE_AFTER_HITS: Number of reads of an object from the backend before it is cached.
+     MINIO_CACHE_ENCRYPTION_MASTER_KEY: Master key encrypting the cache drives, of the form "key-id:hex-key".
 
   DOMAIN:
      MINIO_DOMAIN: To enable virtual-host-style requests, set this value to Minio host domain name.
@@ -91,6 +104,7 @@ ENVIRONMENT VARIABLES:
      MINIO_DOMAIN:    To enable bucket DNS requests, set this value to Minio host domain name.
      MINIO_PUBLIC_IPS: To enable bucket DNS requests, set this value to list of Minio host public IP(s) delimited by ",".
      MINIO_ETCD_ENDPOINTS: To enable bucket DNS requests, set this value to list of etcd endpoints delimited by ",".
+     MINIO_DNS_WEBHOOK_ENDPOINT: To enable bucket DNS requests without etcd, set this value to the URL of a key/value webhook storing the bucket DNS records.
 
    KMS:
      MINIO_SSE_VAULT_ENDPOINT: To enable Vault as KMS,set this value to Vault endpoint.
@@ -117,14 +131,20 @@ EXAMPLES:
      $ export MINIO_SECRET_KEY=miniostorage
      $ {{.HelpName}} http://node{1...8}.example.com/mnt/export/{1...8}
 
-  6. Start minio server with edge caching enabled.
+  6. Expand above distributed minio server by a zone of another 8 nodes with 8 drives each. Run following command on all the 16 nodes.
+     $ export MINIO_ACCESS_KEY=minio
+     $ export MINIO_SECRET_KEY=miniostorage
+     $ {{.HelpName}} http://node{1...8}.example.com/mnt/export/{1...8} \
+         http://node{9...16}.example.com/mnt/export/{1...8}
+
+  7. Start minio server with edge caching enabled.
      $ export MINIO_CACHE_DRIVES="/mnt/drive1;/mnt/drive2;/mnt/drive3;/mnt/drive4"
      $ export MINIO_CACHE_EXCLUDE="bucket1/*;*.png"
      $ export MINIO_CACHE_EXPIRY=40
      $ export MINIO_CACHE_MAXUSE=80
      $ {{.HelpName}} /home/shared
 
-  7. Start minio server with KMS enabled.
+  8. Start minio server with KMS enabled.
      $ export MINIO_SSE_VAULT_APPROLE_ID=9b56cc08-8258-45d5-24a3-679876769126
      $ export MINIO_SSE_VAULT_APPROLE_SECRET=4e30c52f-13e4-a6f5-0763-d50e8cb4321f
      $ export MINIO_SSE_VAULT_ENDPOINT=https://vault-endpoint-ip:8200
@@ -162,12 +182,15 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 
 	endpoints := strings.Fields(os.Getenv("MINIO_ENDPOINTS"))
 	if len(endpoints) > 0 {
-		globalMinioAddr, globalEndpoints, setupType, globalXLSetCount, globalXLSetDriveCount, err = createServerEndpoints(serverAddr, endpoints...)
+		globalMinioAddr, globalEndpointZones, setupType, err = createServerEndpoints(serverAddr, endpoints...)
 	} else {
-		globalMinioAddr, globalEndpoints, setupType, globalXLSetCount, globalXLSetDriveCount, err = createServerEndpoints(serverAddr, ctx.Args()...)
+		globalMinioAddr, globalEndpointZones, setupType, err = createServerEndpoints(serverAddr, ctx.Args()...)
 	}
 	logger.FatalIf(err, "Invalid command line arguments")
 
+	globalEndpoints = globalEndpointZones.Endpoints()
+	globalXLSetDriveCount = globalEndpointZones[0].DrivesPerSet
+
 	globalMinioHost, globalMinioPort = mustSplitHostPort(globalMinioAddr)
 
 	// On macOS, if a process already listens on LOCALIPADDR:PORT, net.Listen() falls back
@@ -304,7 +327,7 @@ func serverMain(ctx *cli.Context) {
 
 	signal.Notify(globalOSSignalCh, os.Interrupt, syscall.SIGTERM)
 
-	newObject, err := newObjectLayer(globalEndpoints)
+	newObject, err := newObjectLayer(globalEndpointZones)
 	if err != nil {
 		// Stop watching for any certificate changes.
 		globalTLSCerts.Stop()
@@ -355,6 +378,33 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(err, "Unable to initialize notification system")
 	}
 
+	// Create new inventory system.
+	globalInventorySys = NewInventorySys()
+
+	// Initialize inventory system.
+	if err := globalInventorySys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize inventory system")
+	}
+
+	// Create new ACL system.
+	globalACLSys = NewACLSys()
+
+	// Initialize ACL system.
+	if err := globalACLSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize ACL system")
+	}
+
+	// Create new token validators of temporary credentials.
+	globalIAMValidators = getAuthValidators(globalServerConfig)
+
+	// Create new IAM system.
+	globalIAMSys = NewIAMSys()
+
+	// Initialize IAM system.
+	if err := globalIAMSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize IAM system")
+	}
+
 	globalObjLayerMutex.Lock()
 	globalObjectAPI = newObject
 	globalObjLayerMutex.Unlock()
@@ -370,19 +420,31 @@ func serverMain(ctx *cli.Context) {
 }
 
 // Initialize object layer with the supplied disks, objectLayer is nil upon any error.
-func newObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err error) {
+func newObjectLayer(endpointZones EndpointZones) (newObject ObjectLayer, err error) {
 	// For FS only, directly use the disk.
 
-	isFS := len(endpoints) == 1
+	isFS := len(endpointZones) == 1 && len(endpointZones[0].Endpoints) == 1
 	if isFS {
 		// Initialize new FS object layer.
-		return NewFSObjectLayer(endpoints[0].Path)
+		return NewFSObjectLayer(endpointZones[0].Endpoints[0].Path)
 	}
 
-	format, err := waitForFormatXL(context.Background(), endpoints[0].IsLocal, endpoints, globalXLSetCount, globalXLSetDriveCount)
-	if err != nil {
-		return nil, err
+	zones := make([]*xlSets, len(endpointZones))
+	for i, zone := range endpointZones {
+		format, err := waitForFormatXL(context.Background(), zone.Endpoints[0].IsLocal, zone.Endpoints, zone.SetCount, zone.DrivesPerSet)
+		if err != nil {
+			return nil, err
+		}
+
+		if zones[i], err = newXLSets(zone.Endpoints, format, len(format.XL.Sets), len(format.XL.Sets[0])); err != nil {
+			return nil, err
+		}
+	}
+
+	// Single zone deployments use erasure coded sets directly.
+	if len(zones) == 1 {
+		return zones[0], nil
 	}
 
-	return newXLSets(endpoints, format, len(format.XL.Sets), len(format.XL.Sets[0]))
+	return newXLZones(endpointZones, zones)
 }
@@ -32,7 +32,7 @@ func TestNewObjectLayer(t *testing.T) {
 	defer removeRoots(disks)
 
 	endpoints := mustGetNewEndpointList(disks...)
-	obj, err := newObjectLayer(endpoints)
+	obj, err := newObjectLayer(EndpointZones{{SetCount: 1, DrivesPerSet: 1, Endpoints: endpoints}})
 	if err != nil {
 		t.Fatal("Unexpected object layer initialization error", err)
 	}
@@ -51,11 +51,10 @@ func TestNewObjectLayer(t *testing.T) {
 	}
 	defer removeRoots(disks)
 
-	globalXLSetCount = 1
 	globalXLSetDriveCount = 16
 
 	endpoints = mustGetNewEndpointList(disks...)
-	obj, err = newObjectLayer(endpoints)
+	obj, err = newObjectLayer(EndpointZones{{SetCount: 1, DrivesPerSet: 16, Endpoints: endpoints}})
 	if err != nil {
 		t.Fatal("Unexpected object layer initialization error", err)
 	}
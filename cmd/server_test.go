@@ -1792,15 +1792,15 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *check) {
 	verifyError(c, response, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.",
 		http.StatusConflict)
 
-	// request for ACL.
-	// Since Minio server doesn't support ACL's the request is expected to fail with  "NotImplemented" error message.
+	// request for ACL without canned ACL or access control policy.
+	// expected to fail with error message "MalformedACLError".
 	request, err = newTestSignedRequest("PUT", s.endPoint+"/"+bucketName+"?acl",
 		0, nil, s.accessKey, s.secretKey, s.signer)
 	c.Assert(err, nil)
 
 	response, err = client.Do(request)
 	c.Assert(err, nil)
-	verifyError(c, response, "NotImplemented", "A header you provided implies functionality that is not implemented", http.StatusNotImplemented)
+	verifyError(c, response, "MalformedACLError", "The XML you provided was not well-formed or did not validate against our published schema.", http.StatusBadRequest)
 }
 
 func (s *TestSuiteCommon) TestGetObjectLarge10MiB(c *check) {
@@ -18,10 +18,12 @@ package cmd
 
 import (
 	"crypto/hmac"
+	"crypto/subtle"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/sha256-simd"
 )
 
@@ -100,6 +102,44 @@ func isValidRegion(reqRegion string, confRegion string) bool {
 	return reqRegion == confRegion
 }
 
+// getReqAccessKeyV4 - returns credential of the access key in signature
+// V4 Authorization header or presigned query. Returned bool is true if
+// the access key is the server owner's.
+func getReqAccessKeyV4(r *http.Request, region string) (auth.Credentials, bool, APIErrorCode) {
+	credElement := r.URL.Query().Get("X-Amz-Credential")
+	if credElement != "" {
+		credElement = "Credential=" + credElement
+	} else {
+		v4Auth := strings.Replace(r.Header.Get("Authorization"), " ", "", -1)
+		authFields := strings.Split(strings.TrimPrefix(v4Auth, signV4Algorithm), ",")
+		if len(authFields) != 3 {
+			return auth.Credentials{}, false, ErrMissingFields
+		}
+		credElement = authFields[0]
+	}
+
+	ch, errCode := parseCredentialHeader(credElement, region)
+	if errCode != ErrNone {
+		return auth.Credentials{}, false, errCode
+	}
+
+	return checkKeyValid(ch.accessKey)
+}
+
+// checkSessionToken - verifies session token of temporary credentials.
+// Session token is not required for the server owner's credentials.
+func checkSessionToken(token string, cred auth.Credentials) APIErrorCode {
+	if !cred.IsTemp() {
+		return ErrNone
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(cred.SessionToken)) != 1 {
+		return ErrInvalidToken
+	}
+
+	return ErrNone
+}
+
 // sumHMAC calculate hmac between two input byte array.
 func sumHMAC(key []byte, data []byte) []byte {
 	hash := hmac.New(sha256.New, key)
@@ -197,9 +197,6 @@ func doesPolicySignatureV4Match(formValues http.Header) APIErrorCode {
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html
 // returns ErrNone if the signature matches.
 func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := globalServerConfig.GetCredential()
-
 	// Copy request
 	req := *r
 
@@ -209,9 +206,15 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 		return err
 	}
 
-	// Verify if the access key id matches.
-	if pSignValues.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	// Verify if the access key id is valid.
+	cred, _, errCode := checkKeyValid(pSignValues.Credential.accessKey)
+	if errCode != ErrNone {
+		return errCode
+	}
+
+	// Verify session token of temporary credentials.
+	if errCode = checkSessionToken(req.URL.Query().Get("X-Amz-Security-Token"), cred); errCode != ErrNone {
+		return errCode
 	}
 
 	// Extract all the signed headers along with its values.
@@ -246,6 +249,9 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 	query.Set("X-Amz-Expires", strconv.Itoa(expireSeconds))
 	query.Set("X-Amz-SignedHeaders", getSignedHeaders(extractedSignedHeaders))
 	query.Set("X-Amz-Credential", cred.AccessKey+"/"+getScope(t, pSignValues.Credential.scope.region))
+	if token := req.URL.Query().Get("X-Amz-Security-Token"); token != "" {
+		query.Set("X-Amz-Security-Token", token)
+	}
 
 	// Save other headers available in the request parameters.
 	for k, v := range req.URL.Query() {
@@ -312,9 +318,6 @@ func doesPresignedSignatureMatch(hashedPayload string, r *http.Request, region s
 //     - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
 // returns ErrNone if signature matches.
 func doesSignatureMatch(hashedPayload string, r *http.Request, region string) APIErrorCode {
-	// Access credentials.
-	cred := globalServerConfig.GetCredential()
-
 	// Copy request.
 	req := *r
 
@@ -333,9 +336,15 @@ func doesSignatureMatch(hashedPayload string, r *http.Request, region string) AP
 		return errCode
 	}
 
-	// Verify if the access key id matches.
-	if signV4Values.Credential.accessKey != cred.AccessKey {
-		return ErrInvalidAccessKeyID
+	// Verify if the access key id is valid.
+	cred, _, errCode := checkKeyValid(signV4Values.Credential.accessKey)
+	if errCode != ErrNone {
+		return errCode
+	}
+
+	// Verify session token of temporary credentials.
+	if errCode = checkSessionToken(req.Header.Get("X-Amz-Security-Token"), cred); errCode != ErrNone {
+		return errCode
 	}
 
 	// Extract date, if not present throw error.
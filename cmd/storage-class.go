@@ -37,6 +37,8 @@ const (
 	reducedRedundancyStorageClassEnv = "MINIO_STORAGE_CLASS_RRS"
 	// Standard storage class environment variable
 	standardStorageClassEnv = "MINIO_STORAGE_CLASS_STANDARD"
+	// Custom storage classes environment variable
+	customStorageClassesEnv = "MINIO_STORAGE_CLASS_CUSTOM"
 	// Supported storage class scheme is EC
 	supportedStorageClassScheme = "EC"
 	// Minimum parity disks
@@ -53,6 +55,8 @@ type storageClass struct {
 type storageClassConfig struct {
 	Standard storageClass `json:"standard"`
 	RRS      storageClass `json:"rrs"`
+	// Additional storage classes by name.
+	Custom map[string]storageClass `json:"custom,omitempty"`
 }
 
 // Validate SS and RRS parity when unmarshalling JSON.
@@ -66,13 +70,31 @@ func (sCfg *storageClassConfig) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	return validateParity(aux.Standard.Parity, aux.RRS.Parity)
+	return validateParity(aux.Standard.Parity, aux.RRS.Parity, aux.Custom)
 }
 
 // Validate if storage class in metadata
-// Only Standard and RRS Storage classes are supported
+// Standard, RRS and configured custom storage classes are supported
 func isValidStorageClassMeta(sc string) bool {
-	return sc == reducedRedundancyStorageClass || sc == standardStorageClass
+	if sc == reducedRedundancyStorageClass || sc == standardStorageClass {
+		return true
+	}
+	_, ok := globalCustomStorageClasses[sc]
+	return ok
+}
+
+// Validates the name of a custom storage class, names are made of
+// upper case letters, digits and underscores like the S3 storage classes.
+func isValidCustomStorageClassName(name string) bool {
+	if name == "" || name == standardStorageClass || name == reducedRedundancyStorageClass {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
 }
 
 func (sc *storageClass) UnmarshalText(b []byte) error {
@@ -89,7 +111,7 @@ func (sc *storageClass) UnmarshalText(b []byte) error {
 	return nil
 }
 
-func (sc *storageClass) MarshalText() ([]byte, error) {
+func (sc storageClass) MarshalText() ([]byte, error) {
 	if sc.Scheme != "" && sc.Parity != 0 {
 		return []byte(fmt.Sprintf("%s:%d", sc.Scheme, sc.Parity)), nil
 	}
@@ -128,9 +150,35 @@ func parseStorageClass(storageClassEnv string) (sc storageClass, err error) {
 	return sc, nil
 }
 
+// Parses the given customStorageClassesEnv and returns the custom storage
+// classes by name. Supported format is a comma separated list of
+// "NAME=Scheme:Number of parity disks", e.g. "ARCHIVE=EC:8,COLD=EC:6".
+func parseCustomStorageClasses(customStorageClassesEnv string) (map[string]storageClass, error) {
+	classes := make(map[string]storageClass)
+	for _, class := range strings.Split(customStorageClassesEnv, ",") {
+		nameValue := strings.SplitN(class, "=", 2)
+		if len(nameValue) != 2 {
+			return nil, uiErrStorageClassValue(nil).Msg("Missing storage class name in %s", class)
+		}
+		name := strings.TrimSpace(nameValue[0])
+		if !isValidCustomStorageClassName(name) {
+			return nil, uiErrStorageClassValue(nil).Msg("Invalid storage class name %s", name)
+		}
+		if _, ok := classes[name]; ok {
+			return nil, uiErrStorageClassValue(nil).Msg("Duplicate storage class name %s", name)
+		}
+		sc, err := parseStorageClass(strings.TrimSpace(nameValue[1]))
+		if err != nil {
+			return nil, err
+		}
+		classes[name] = sc
+	}
+	return classes, nil
+}
+
 // Validates the parity disks.
-func validateParity(ssParity, rrsParity int) (err error) {
-	if ssParity == 0 && rrsParity == 0 {
+func validateParity(ssParity, rrsParity int, customClasses map[string]storageClass) (err error) {
+	if ssParity == 0 && rrsParity == 0 && len(customClasses) == 0 {
 		return nil
 	}
 
@@ -161,6 +209,19 @@ func validateParity(ssParity, rrsParity int) (err error) {
 			return fmt.Errorf("Standard storage class parity disks %d should be greater than or equal to Reduced redundancy storage class parity disks %d", ssParity, rrsParity)
 		}
 	}
+
+	// Custom storage class parity disks should be within the same limits.
+	for name, sc := range customClasses {
+		if !isValidCustomStorageClassName(name) {
+			return fmt.Errorf("Invalid storage class name %s", name)
+		}
+		if sc.Parity < minimumParityDisks {
+			return fmt.Errorf("%s storage class parity %d should be greater than or equal to %d", name, sc.Parity, minimumParityDisks)
+		}
+		if sc.Parity > globalXLSetDriveCount/2 {
+			return fmt.Errorf("%s storage class parity %d should be less than or equal to %d", name, sc.Parity, globalXLSetDriveCount/2)
+		}
+	}
 	return nil
 }
 
@@ -173,6 +234,8 @@ func validateParity(ssParity, rrsParity int) (err error) {
 // -- Default for Standard Storage class is, parity = N/2, data = N/2
 // If storage class is empty
 // -- standard storage class is assumed and corresponding data and parity is returned
+// If storage class is a custom storage class
+// -- its configured parity is returned
 func getRedundancyCount(sc string, totalDisks int) (data, parity int) {
 	parity = totalDisks / 2
 	switch sc {
@@ -189,6 +252,11 @@ func getRedundancyCount(sc string, totalDisks int) (data, parity int) {
 			// set the standard parity if available
 			parity = globalStandardStorageClass.Parity
 		}
+	default:
+		if customClass, ok := globalCustomStorageClasses[sc]; ok && customClass.Parity != 0 {
+			// set the custom parity if configured
+			parity = customClass.Parity
+		}
 	}
 	// data is always totalDisks - parity
 	return totalDisks - parity, parity
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
@@ -93,23 +94,30 @@ func testValidateParity(obj ObjectLayer, instanceType string, dirs []string, t T
 	defer func() {
 		globalXLSetDriveCount = saveSetDriveCount
 	}()
-	globalXLSetCount = len(dirs)
+	globalXLSetDriveCount = len(dirs)
 
 	tests := []struct {
-		rrsParity int
-		ssParity  int
-		success   bool
+		rrsParity     int
+		ssParity      int
+		customClasses map[string]storageClass
+		success       bool
 	}{
-		{2, 4, true},
-		{3, 3, true},
-		{1, 4, false},
-		{7, 6, false},
-		{9, 0, false},
-		{9, 9, false},
-		{2, 9, false},
+		{2, 4, nil, true},
+		{3, 3, nil, true},
+		{1, 4, nil, false},
+		{7, 6, nil, false},
+		{9, 0, nil, false},
+		{9, 9, nil, false},
+		{2, 9, nil, false},
+		{0, 0, map[string]storageClass{"ARCHIVE": {"EC", 8}}, true},
+		{2, 4, map[string]storageClass{"ARCHIVE": {"EC", 8}, "COLD_2": {"EC", 2}}, true},
+		{0, 0, map[string]storageClass{"ARCHIVE": {"EC", 9}}, false},
+		{0, 0, map[string]storageClass{"ARCHIVE": {"EC", 1}}, false},
+		{0, 0, map[string]storageClass{"archive": {"EC", 4}}, false},
+		{0, 0, map[string]storageClass{standardStorageClass: {"EC", 4}}, false},
 	}
 	for i, tt := range tests {
-		err := validateParity(tt.ssParity, tt.rrsParity)
+		err := validateParity(tt.ssParity, tt.rrsParity, tt.customClasses)
 		if err != nil && tt.success {
 			t.Errorf("Test %d, Expected success, got %s", i+1, err)
 		}
@@ -140,7 +148,11 @@ func testGetRedundancyCount(obj ObjectLayer, instanceType string, dirs []string,
 		{reducedRedundancyStorageClass, len(xl.storageDisks), 9, 7},
 		{standardStorageClass, len(xl.storageDisks), 10, 6},
 		{"", len(xl.storageDisks), 9, 7},
+		{"ARCHIVE", len(xl.storageDisks), 11, 5},
+		{"UNKNOWN", len(xl.storageDisks), 8, 8},
 	}
+	globalCustomStorageClasses = map[string]storageClass{"ARCHIVE": {"EC", 5}}
+	defer resetGlobalStorageEnvs()
 	for i, tt := range tests {
 		// Set env var for test case 4
 		if i+1 == 4 {
@@ -336,10 +348,76 @@ func TestIsValidStorageClassMeta(t *testing.T) {
 		{"123", false},
 		{"MINIO_STORAGE_CLASS_RRS", false},
 		{"MINIO_STORAGE_CLASS_STANDARD", false},
+		{"ARCHIVE", true},
+		{"archive", false},
 	}
+	globalCustomStorageClasses = map[string]storageClass{"ARCHIVE": {"EC", 8}}
+	defer resetGlobalStorageEnvs()
 	for i, tt := range tests {
 		if got := isValidStorageClassMeta(tt.sc); got != tt.want {
 			t.Errorf("Test %d, Expected Storage Class to be %t, got %t", i+1, tt.want, got)
 		}
 	}
 }
+
+// Test parsing custom storage classes from the environment.
+func TestParseCustomStorageClasses(t *testing.T) {
+	tests := []struct {
+		customStorageClassesEnv string
+		want                    map[string]storageClass
+		success                 bool
+	}{
+		{"ARCHIVE=EC:8", map[string]storageClass{"ARCHIVE": {"EC", 8}}, true},
+		{"ARCHIVE=EC:8, COLD=EC:6", map[string]storageClass{"ARCHIVE": {"EC", 8}, "COLD": {"EC", 6}}, true},
+		{"ARCHIVE", nil, false},
+		{"ARCHIVE=EC", nil, false},
+		{"archive=EC:8", nil, false},
+		{"STANDARD=EC:8", nil, false},
+		{"ARCHIVE=EC:8,ARCHIVE=EC:6", nil, false},
+	}
+	for i, tt := range tests {
+		got, err := parseCustomStorageClasses(tt.customStorageClassesEnv)
+		if err != nil && tt.success {
+			t.Errorf("Test %d, Expected success, got %s", i+1, err)
+		}
+		if err == nil && !tt.success {
+			t.Errorf("Test %d, Expected failure, got success", i+1)
+		}
+		if tt.success && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Test %d, Expected %v, got %v", i+1, tt.want, got)
+		}
+	}
+}
+
+// Test encoding and decoding custom storage classes in config.
+func TestStorageClassConfigCustomJSON(t *testing.T) {
+	saveIsXL, saveSetDriveCount := globalIsXL, globalXLSetDriveCount
+	defer func() {
+		globalIsXL, globalXLSetDriveCount = saveIsXL, saveSetDriveCount
+	}()
+	globalIsXL, globalXLSetDriveCount = true, 16
+
+	sCfg := storageClassConfig{
+		Standard: storageClass{"EC", 4},
+		Custom:   map[string]storageClass{"ARCHIVE": {"EC", 8}},
+	}
+	data, err := json.Marshal(sCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"standard":"EC:4","rrs":"","custom":{"ARCHIVE":"EC:8"}}`; string(data) != expected {
+		t.Fatalf("Expected %s, got %s", expected, string(data))
+	}
+
+	var got storageClassConfig
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sCfg, got) {
+		t.Fatalf("Expected %v, got %v", sCfg, got)
+	}
+
+	if err = json.Unmarshal([]byte(`{"standard":"EC:4","rrs":"","custom":{"ARCHIVE":"EC:9"}}`), &got); err == nil {
+		t.Fatal("Expected custom storage class parity to be validated")
+	}
+}
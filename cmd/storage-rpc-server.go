@@ -32,7 +32,7 @@ var storageServicePath = path.Join(minioReservedBucketPath, storageServiceSubPat
 
 // storageRPCReceiver - Storage RPC receiver for storage RPC server
 type storageRPCReceiver struct {
-	local *posix
+	local StorageAPI
 }
 
 // VolArgs - generic volume args.
@@ -210,7 +210,7 @@ func NewStorageRPCServer(endpointPath string) (*xrpc.Server, error) {
 	}
 
 	rpcServer := xrpc.NewServer()
-	if err = rpcServer.RegisterName(storageServiceName, &storageRPCReceiver{storage}); err != nil {
+	if err = rpcServer.RegisterName(storageServiceName, &storageRPCReceiver{newStatsStorage(storage, endpointPath)}); err != nil {
 		return nil, err
 	}
 
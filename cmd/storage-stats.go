@@ -0,0 +1,239 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Number of most recent calls used to compute drive latencies.
+const driveStatsWindow = 256
+
+// Errors which are part of regular operation and are not
+// accounted as drive errors.
+var driveStatsIgnoredErrs = []error{
+	errFileNotFound,
+	errVolumeNotFound,
+	errVolumeExists,
+	errVolumeNotEmpty,
+	errIsNotRegular,
+}
+
+// driveStats - latency and error statistics of a local drive.
+type driveStats struct {
+	mu sync.Mutex
+
+	calls  uint64
+	errors uint64
+
+	// Ring buffer of the latencies of the most recent calls.
+	latencies [driveStatsWindow]time.Duration
+	next      int
+
+	lastError     string
+	lastErrorTime time.Time
+}
+
+// record - accounts a call which took duration d and returned err.
+func (s *driveStats) record(d time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	s.latencies[s.next] = d
+	s.next = (s.next + 1) % driveStatsWindow
+	if err != nil && !IsErrIgnored(err, driveStatsIgnoredErrs...) {
+		s.errors++
+		s.lastError = err.Error()
+		s.lastErrorTime = UTCNow()
+	}
+}
+
+// toDriveStats - returns a snapshot of the statistics.
+func (s *driveStats) toDriveStats() madmin.DriveStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := madmin.DriveStats{
+		Calls:         s.calls,
+		Errors:        s.errors,
+		LastError:     s.lastError,
+		LastErrorTime: s.lastErrorTime,
+	}
+
+	n := s.calls
+	if n > driveStatsWindow {
+		n = driveStatsWindow
+	}
+	if n == 0 {
+		return stats
+	}
+
+	var total time.Duration
+	for _, d := range s.latencies[:n] {
+		total += d
+		if d > stats.MaxLatency {
+			stats.MaxLatency = d
+		}
+	}
+	stats.AvgLatency = total / time.Duration(n)
+	return stats
+}
+
+// driveStatsMap - statistics of all local drives by their path, shared
+// between all StorageAPI instances operating on the same drive.
+type driveStatsMap struct {
+	mu    sync.Mutex
+	stats map[string]*driveStats
+}
+
+// get - returns statistics of the drive at path, initializing them if needed.
+func (m *driveStatsMap) get(path string) *driveStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stats == nil {
+		m.stats = make(map[string]*driveStats)
+	}
+	s, ok := m.stats[path]
+	if !ok {
+		s = &driveStats{}
+		m.stats[path] = s
+	}
+	return s
+}
+
+// Statistics of all drives local to this server.
+var globalDriveStats = &driveStatsMap{}
+
+// statsStorage wraps a local StorageAPI and records the latency and
+// the errors of every call made to it.
+type statsStorage struct {
+	disk  StorageAPI
+	stats *driveStats
+}
+
+// newStatsStorage - returns disk recording its statistics under path.
+func newStatsStorage(disk StorageAPI, path string) *statsStorage {
+	return &statsStorage{disk: disk, stats: globalDriveStats.get(path)}
+}
+
+func (s *statsStorage) String() string {
+	return s.disk.String()
+}
+
+func (s *statsStorage) IsOnline() bool {
+	return s.disk.IsOnline()
+}
+
+func (s *statsStorage) Close() error {
+	return s.disk.Close()
+}
+
+func (s *statsStorage) DiskInfo() (info DiskInfo, err error) {
+	start := time.Now()
+	info, err = s.disk.DiskInfo()
+	s.stats.record(time.Since(start), err)
+	return info, err
+}
+
+func (s *statsStorage) MakeVol(volume string) (err error) {
+	start := time.Now()
+	err = s.disk.MakeVol(volume)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) ListVols() (vols []VolInfo, err error) {
+	start := time.Now()
+	vols, err = s.disk.ListVols()
+	s.stats.record(time.Since(start), err)
+	return vols, err
+}
+
+func (s *statsStorage) StatVol(volume string) (vol VolInfo, err error) {
+	start := time.Now()
+	vol, err = s.disk.StatVol(volume)
+	s.stats.record(time.Since(start), err)
+	return vol, err
+}
+
+func (s *statsStorage) DeleteVol(volume string) (err error) {
+	start := time.Now()
+	err = s.disk.DeleteVol(volume)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) ListDir(volume, dirPath string, count int) (entries []string, err error) {
+	start := time.Now()
+	entries, err = s.disk.ListDir(volume, dirPath, count)
+	s.stats.record(time.Since(start), err)
+	return entries, err
+}
+
+func (s *statsStorage) ReadFile(volume string, path string, offset int64, buf []byte, verifier *BitrotVerifier) (n int64, err error) {
+	start := time.Now()
+	n, err = s.disk.ReadFile(volume, path, offset, buf, verifier)
+	s.stats.record(time.Since(start), err)
+	return n, err
+}
+
+func (s *statsStorage) PrepareFile(volume string, path string, length int64) (err error) {
+	start := time.Now()
+	err = s.disk.PrepareFile(volume, path, length)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) AppendFile(volume string, path string, buf []byte) (err error) {
+	start := time.Now()
+	err = s.disk.AppendFile(volume, path, buf)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
+	start := time.Now()
+	err = s.disk.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) StatFile(volume string, path string) (file FileInfo, err error) {
+	start := time.Now()
+	file, err = s.disk.StatFile(volume, path)
+	s.stats.record(time.Since(start), err)
+	return file, err
+}
+
+func (s *statsStorage) DeleteFile(volume string, path string) (err error) {
+	start := time.Now()
+	err = s.disk.DeleteFile(volume, path)
+	s.stats.record(time.Since(start), err)
+	return err
+}
+
+func (s *statsStorage) ReadAll(volume string, path string) (buf []byte, err error) {
+	start := time.Now()
+	buf, err = s.disk.ReadAll(volume, path)
+	s.stats.record(time.Since(start), err)
+	return buf, err
+}
@@ -0,0 +1,84 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests accounting of drive latencies and errors.
+func TestDriveStats(t *testing.T) {
+	var s driveStats
+	if stats := s.toDriveStats(); stats.Calls != 0 || stats.AvgLatency != 0 {
+		t.Fatalf("Expected empty stats, got %#v", stats)
+	}
+
+	s.record(time.Second, nil)
+	s.record(3*time.Second, errFileNotFound)
+	s.record(2*time.Second, errFaultyDisk)
+
+	stats := s.toDriveStats()
+	if stats.Calls != 3 || stats.Errors != 1 {
+		t.Errorf("Expected 3 calls and 1 error, got %d and %d", stats.Calls, stats.Errors)
+	}
+	if stats.AvgLatency != 2*time.Second || stats.MaxLatency != 3*time.Second {
+		t.Errorf("Unexpected latencies avg %s, max %s", stats.AvgLatency, stats.MaxLatency)
+	}
+	if stats.LastError != errFaultyDisk.Error() || stats.LastErrorTime.IsZero() {
+		t.Errorf("Unexpected last error %s at %s", stats.LastError, stats.LastErrorTime)
+	}
+
+	// Only the most recent calls are used for latencies.
+	for i := 0; i < driveStatsWindow; i++ {
+		s.record(time.Millisecond, nil)
+	}
+	stats = s.toDriveStats()
+	if stats.Calls != driveStatsWindow+3 {
+		t.Errorf("Expected %d calls, got %d", driveStatsWindow+3, stats.Calls)
+	}
+	if stats.AvgLatency != time.Millisecond || stats.MaxLatency != time.Millisecond {
+		t.Errorf("Unexpected latencies avg %s, max %s", stats.AvgLatency, stats.MaxLatency)
+	}
+}
+
+// Tests that all StorageAPI instances of a drive share its statistics.
+func TestStatsStorageShared(t *testing.T) {
+	disk, path, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	s1 := newStatsStorage(disk, path)
+	s2 := newStatsStorage(disk, path)
+	if err = s1.MakeVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s2.StatVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if err = s2.MakeVol("bucket"); err != errVolumeExists {
+		t.Fatalf("Expected %v, got %v", errVolumeExists, err)
+	}
+
+	stats := globalDriveStats.get(path).toDriveStats()
+	if stats.Calls != 3 || stats.Errors != 0 {
+		t.Errorf("Expected 3 calls and no errors, got %d and %d", stats.Calls, stats.Errors)
+	}
+}
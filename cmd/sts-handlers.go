@@ -0,0 +1,342 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/iam/ldap"
+	"github.com/minio/minio/pkg/iam/validator"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// STS API version.
+	stsAPIVersion = "2011-06-15"
+
+	// STS API actions.
+	webIdentity  = "AssumeRoleWithWebIdentity"
+	clientGrants = "AssumeRoleWithClientGrants"
+	ldapIdentity = "AssumeRoleWithLDAPIdentity"
+
+	// JWT claim carrying session policy of temporary credentials.
+	stsPolicyClaim = "policy"
+)
+
+// AssumeRoleWithJWTResult - contains the response to a successful
+// AssumeRoleWithWebIdentity or AssumeRoleWithClientGrants request.
+type AssumeRoleWithJWTResult struct {
+	Credentials    auth.Credentials `xml:"Credentials"`
+	SubjectFromJWT string           `xml:"SubjectFromWebIdentityToken,omitempty"`
+}
+
+// ResponseMetadata - contains request ID of STS response.
+type ResponseMetadata struct {
+	RequestID string `xml:"RequestId,omitempty"`
+}
+
+// AssumeRoleWithWebIdentityResponse - response of AssumeRoleWithWebIdentity.
+type AssumeRoleWithWebIdentityResponse struct {
+	XMLName          xml.Name                `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithWebIdentityResponse" json:"-"`
+	Result           AssumeRoleWithJWTResult `xml:"AssumeRoleWithWebIdentityResult"`
+	ResponseMetadata ResponseMetadata        `xml:"ResponseMetadata,omitempty"`
+}
+
+// AssumeRoleWithClientGrantsResponse - response of AssumeRoleWithClientGrants.
+type AssumeRoleWithClientGrantsResponse struct {
+	XMLName          xml.Name                `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithClientGrantsResponse" json:"-"`
+	Result           AssumeRoleWithJWTResult `xml:"AssumeRoleWithClientGrantsResult"`
+	ResponseMetadata ResponseMetadata        `xml:"ResponseMetadata,omitempty"`
+}
+
+// AssumeRoleWithLDAPResult - contains the response to a successful
+// AssumeRoleWithLDAPIdentity request.
+type AssumeRoleWithLDAPResult struct {
+	Credentials auth.Credentials `xml:"Credentials"`
+}
+
+// AssumeRoleWithLDAPResponse - response of AssumeRoleWithLDAPIdentity.
+type AssumeRoleWithLDAPResponse struct {
+	XMLName          xml.Name                 `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleWithLDAPIdentityResponse" json:"-"`
+	Result           AssumeRoleWithLDAPResult `xml:"AssumeRoleWithLDAPIdentityResult"`
+	ResponseMetadata ResponseMetadata         `xml:"ResponseMetadata,omitempty"`
+}
+
+// stsAPIHandlers implements and provides http handlers for AWS STS API.
+type stsAPIHandlers struct{}
+
+// registerSTSRouter - registers AWS STS compatible APIs.
+func registerSTSRouter(router *mux.Router) {
+	// Initialize STS.
+	sts := &stsAPIHandlers{}
+
+	// STS Router
+	stsRouter := router.NewRoute().PathPrefix("/").Subrouter()
+
+	// All STS APIs with parameters in form encoded request body.
+	stsRouter.Methods(http.MethodPost).MatcherFunc(func(r *http.Request, rm *mux.RouteMatch) bool {
+		ctypeOk := strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded")
+		noQueries := len(r.URL.Query()) == 0
+		return ctypeOk && noQueries
+	}).HandlerFunc(httpTraceHdrs(sts.AssumeRoleWithIdentity))
+
+	// AssumeRoleWithWebIdentity and AssumeRoleWithClientGrants
+	stsRouter.Methods(http.MethodPost).Queries("Action", "{Action:AssumeRoleWith(WebIdentity|ClientGrants)}").
+		HandlerFunc(httpTraceAll(sts.AssumeRoleWithJWT))
+
+	// AssumeRoleWithLDAPIdentity
+	stsRouter.Methods(http.MethodPost).Queries("Action", ldapIdentity).
+		HandlerFunc(httpTraceHdrs(sts.AssumeRoleWithLDAPIdentity))
+}
+
+// AssumeRoleWithIdentity - dispatches STS request with form encoded
+// request body to the handler of its action.
+func (sts *stsAPIHandlers) AssumeRoleWithIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AssumeRoleWithIdentity")
+
+	if err := r.ParseForm(); err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		return
+	}
+
+	switch r.Form.Get("Action") {
+	case ldapIdentity:
+		sts.AssumeRoleWithLDAPIdentity(w, r)
+	default:
+		sts.AssumeRoleWithJWT(w, r)
+	}
+}
+
+// AssumeRoleWithJWT - implements AssumeRoleWithWebIdentity and
+// AssumeRoleWithClientGrants APIs. Token provided by the client is validated
+// by the configured OpenID provider's JWKS and temporary credentials are
+// returned, session policy is taken from "policy" claim of the token.
+//
+// Eg:-
+//     $ curl -X POST "http://localhost:9000/?Action=AssumeRoleWithWebIdentity&Version=2011-06-15&WebIdentityToken=<jwt>"
+func (sts *stsAPIHandlers) AssumeRoleWithJWT(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AssumeRoleWithJWT")
+
+	if err := r.ParseForm(); err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		return
+	}
+
+	if r.Form.Get("Version") != stsAPIVersion {
+		logger.LogIf(ctx, fmt.Errorf("invalid STS API version %s, expecting %s", r.Form.Get("Version"), stsAPIVersion))
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	}
+
+	action := r.Form.Get("Action")
+	var token string
+	switch action {
+	case webIdentity:
+		token = r.Form.Get("WebIdentityToken")
+	case clientGrants:
+		token = r.Form.Get("Token")
+	default:
+		logger.LogIf(ctx, fmt.Errorf("unsupported action %s", action))
+		writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		return
+	}
+
+	if token == "" {
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	}
+
+	// globalIAMValidators and globalIAMSys are not initialized in gateway mode.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil || globalIAMValidators == nil || globalIAMSys == nil {
+		writeSTSErrorResponse(w, ErrSTSNotInitialized)
+		return
+	}
+
+	v, err := globalIAMValidators.Get(validator.JWTID)
+	if err != nil {
+		writeSTSErrorResponse(w, ErrSTSNotInitialized)
+		return
+	}
+
+	m, err := v.Validate(token, r.Form.Get("DurationSeconds"))
+	if err != nil {
+		switch err {
+		case validator.ErrTokenExpired:
+			writeSTSErrorResponse(w, ErrSTSExpiredToken)
+		case validator.ErrInvalidDuration:
+			writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		default:
+			logger.LogIf(ctx, err)
+			writeSTSErrorResponse(w, ErrSTSInvalidIdentityToken)
+		}
+		return
+	}
+
+	// Session policy of temporary credentials is taken from "policy"
+	// claim, absence of it allows nothing beyond bucket policies.
+	var sessionPolicy *policy.Policy
+	if policyStr, ok := m[stsPolicyClaim].(string); ok && policyStr != "" {
+		sessionPolicy = &policy.Policy{}
+		if err = json.Unmarshal([]byte(policyStr), sessionPolicy); err != nil {
+			logger.LogIf(ctx, err)
+			writeSTSErrorResponse(w, ErrSTSMalformedPolicyDocument)
+			return
+		}
+	}
+
+	secret := globalServerConfig.GetCredential().SecretKey
+	cred, err := auth.GetNewCredentialsWithMetadata(m, secret)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInternalError)
+		return
+	}
+
+	identity := iamIdentity{
+		Version:     iamIdentityVersion,
+		Credentials: cred,
+		Policy:      sessionPolicy,
+	}
+	if err = setIAMIdentity(ctx, objectAPI, identity); err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInternalError)
+		return
+	}
+
+	subject, _ := m["sub"].(string)
+	result := AssumeRoleWithJWTResult{
+		Credentials:    cred,
+		SubjectFromJWT: subject,
+	}
+	metadata := ResponseMetadata{
+		RequestID: w.Header().Get(responseRequestIDKey),
+	}
+
+	var encodedSuccessResponse []byte
+	switch action {
+	case webIdentity:
+		encodedSuccessResponse = encodeResponse(&AssumeRoleWithWebIdentityResponse{
+			Result:           result,
+			ResponseMetadata: metadata,
+		})
+	case clientGrants:
+		encodedSuccessResponse = encodeResponse(&AssumeRoleWithClientGrantsResponse{
+			Result:           result,
+			ResponseMetadata: metadata,
+		})
+	}
+
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+}
+
+// AssumeRoleWithLDAPIdentity - implements AssumeRoleWithLDAPIdentity API.
+// Username and password provided by the client are authenticated with the
+// configured LDAP server and temporary credentials are returned, which
+// are granted canned policies mapped to LDAP groups of the user.
+//
+// Eg:-
+//     $ curl -X POST "http://localhost:9000/?Action=AssumeRoleWithLDAPIdentity&Version=2011-06-15&LDAPUsername=foo&LDAPPassword=bar"
+func (sts *stsAPIHandlers) AssumeRoleWithLDAPIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AssumeRoleWithLDAPIdentity")
+
+	if err := r.ParseForm(); err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInvalidParameterValue)
+		return
+	}
+
+	if r.Form.Get("Version") != stsAPIVersion {
+		logger.LogIf(ctx, fmt.Errorf("invalid STS API version %s, expecting %s", r.Form.Get("Version"), stsAPIVersion))
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	}
+
+	username := r.Form.Get("LDAPUsername")
+	password := r.Form.Get("LDAPPassword")
+	if username == "" || password == "" {
+		writeSTSErrorResponse(w, ErrSTSMissingParameter)
+		return
+	}
+
+	// globalIAMSys is not initialized in gateway mode.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil || globalIAMSys == nil || !globalLDAPConfig.IsEnabled() {
+		writeSTSErrorResponse(w, ErrSTSNotInitialized)
+		return
+	}
+
+	cred, groups, err := newLDAPCredentials(username, password)
+	if err != nil {
+		if err == ldap.ErrInvalidCredentials {
+			writeSTSErrorResponse(w, ErrSTSAccessDenied)
+			return
+		}
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInternalError)
+		return
+	}
+
+	identity := iamIdentity{
+		Version:     iamIdentityVersion,
+		Credentials: cred,
+		Groups:      groups,
+	}
+	if err = setIAMIdentity(ctx, objectAPI, identity); err != nil {
+		logger.LogIf(ctx, err)
+		writeSTSErrorResponse(w, ErrSTSInternalError)
+		return
+	}
+
+	encodedSuccessResponse := encodeResponse(&AssumeRoleWithLDAPResponse{
+		Result: AssumeRoleWithLDAPResult{
+			Credentials: cred,
+		},
+		ResponseMetadata: ResponseMetadata{
+			RequestID: w.Header().Get(responseRequestIDKey),
+		},
+	})
+
+	writeSuccessResponseXML(w, encodedSuccessResponse)
+}
+
+// newLDAPCredentials - authenticates user with the configured LDAP server
+// and returns new temporary credentials along with LDAP groups of the user.
+func newLDAPCredentials(username, password string) (auth.Credentials, []string, error) {
+	userDN, groups, err := globalLDAPConfig.Authenticate(username, password)
+	if err != nil {
+		return auth.Credentials{}, nil, err
+	}
+
+	m := map[string]interface{}{
+		"exp":          UTCNow().Add(globalLDAPConfig.GetSTSExpiry()).Unix(),
+		"ldapUser":     userDN,
+		"ldapUsername": username,
+	}
+
+	cred, err := auth.GetNewCredentialsWithMetadata(m, globalServerConfig.GetCredential().SecretKey)
+	return cred, groups, err
+}
@@ -0,0 +1,559 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/iam/ldap"
+	"github.com/minio/minio/pkg/iam/ldap/ldaptest"
+	"github.com/minio/minio/pkg/iam/validator"
+	xnet "github.com/minio/minio/pkg/net"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// newTestIdentityProvider - starts JWKS server of a test identity provider
+// and returns its signing key.
+func newTestIdentityProvider(t *testing.T) (*rsa.PrivateKey, *httptest.Server) {
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(validator.JWKS{
+		Keys: []*validator.JWKS{{
+			Kty: "RSA",
+			Kid: "minio",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(privKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privKey.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+
+	return privKey, server
+}
+
+// newTestIdentityToken - returns JWT signed by the test identity provider.
+func newTestIdentityToken(t *testing.T, privKey *rsa.PrivateKey, expiry time.Time, sessionPolicy string) string {
+	claims := jwtgo.MapClaims{
+		"exp": expiry.Unix(),
+		"sub": "minio-user",
+	}
+	if sessionPolicy != "" {
+		claims["policy"] = sessionPolicy
+	}
+
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+	jwt.Header["kid"] = "minio"
+
+	token, err := jwt.SignedString(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// newTestLDAPServer - starts a test LDAP server with users "alice" of group
+// "dev" and "bob" of no group, returns the server and its configuration.
+func newTestLDAPServer() (*ldaptest.Server, ldap.Config) {
+	server := ldaptest.NewServer(
+		ldaptest.Entry{
+			DN:         "cn=admin,dc=min,dc=io",
+			Attributes: map[string][]string{"userPassword": {"adminpassword"}},
+		},
+		ldaptest.Entry{
+			DN:         "uid=alice,ou=people,dc=min,dc=io",
+			Attributes: map[string][]string{"uid": {"alice"}, "userPassword": {"alicepassword"}},
+		},
+		ldaptest.Entry{
+			DN:         "uid=bob,ou=people,dc=min,dc=io",
+			Attributes: map[string][]string{"uid": {"bob"}, "userPassword": {"bobpassword"}},
+		},
+		ldaptest.Entry{
+			DN: "cn=dev,ou=groups,dc=min,dc=io",
+			Attributes: map[string][]string{
+				"cn":     {"dev"},
+				"member": {"uid=alice,ou=people,dc=min,dc=io"},
+			},
+		},
+	)
+
+	return server, ldap.Config{
+		ServerAddr:        server.Addr,
+		Insecure:          true,
+		BindDN:            "cn=admin,dc=min,dc=io",
+		BindPassword:      "adminpassword",
+		UserSearchBaseDN:  "ou=people,dc=min,dc=io",
+		UserSearchFilter:  "(uid=%s)",
+		GroupSearchBaseDN: "ou=groups,dc=min,dc=io",
+		GroupSearchFilter: "(member=%d)",
+	}
+}
+
+// newTestReadOnlyPolicy - returns policy allowing GetObject on given bucket.
+func newTestReadOnlyPolicy(t TestErrHandler, bucketName string) policy.Policy {
+	var p policy.Policy
+	readOnlyPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject","s3:ListBucket"],"Resource":["arn:aws:s3:::` + bucketName + `","arn:aws:s3:::` + bucketName + `/*"]}]}`
+	if err := json.Unmarshal([]byte(readOnlyPolicy), &p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// newTestSTSRequest - returns STS request with given form values in the body.
+func newTestSTSRequest(values url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9000/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSTSHandlers(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = nil
+		globalObjLayerMutex.Unlock()
+	}()
+
+	globalPolicySys = NewPolicySys()
+	globalACLSys = NewACLSys()
+	globalIAMSys = NewIAMSys()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+
+	privKey, idpServer := newTestIdentityProvider(t)
+	defer idpServer.Close()
+
+	jwksURL, err := xnet.ParseURL(idpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalIAMValidators = validator.NewValidators()
+	if err = globalIAMValidators.Add(validator.NewJWT(validator.JWKSArgs{URL: jwksURL})); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		globalIAMValidators = nil
+	}()
+
+	bucketName := getRandomBucketName()
+	if err = objLayer.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	stsRouter := mux.NewRouter()
+	registerSTSRouter(stsRouter)
+	apiRouter := initTestAPIEndPoints(objLayer, []string{"GetObject", "PutObject"})
+
+	owner := globalServerConfig.GetCredential()
+	data := []byte("hello")
+	req, err := newTestSignedRequestV4(http.MethodPut, getPutObjectURL("", bucketName, "object"),
+		int64(len(data)), bytes.NewReader(data), owner.AccessKey, owner.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner PutObject: expected: %v, got: %v", http.StatusOK, rec.Code)
+	}
+
+	readOnlyPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::` + bucketName + `/*"]}]}`
+	now := UTCNow()
+
+	// Test STS API responses.
+	testCases := []struct {
+		values       url.Values
+		expectedCode int
+		expectedErr  string
+	}{
+		// Web identity token with session policy.
+		{url.Values{
+			"Action":           {webIdentity},
+			"Version":          {stsAPIVersion},
+			"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(time.Hour), readOnlyPolicy)},
+		}, http.StatusOK, ""},
+		// Client grants token with requested duration.
+		{url.Values{
+			"Action":          {clientGrants},
+			"Version":         {stsAPIVersion},
+			"Token":           {newTestIdentityToken(t, privKey, now.Add(time.Hour), "")},
+			"DurationSeconds": {"900"},
+		}, http.StatusOK, ""},
+		// Missing version.
+		{url.Values{
+			"Action":           {webIdentity},
+			"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(time.Hour), "")},
+		}, http.StatusBadRequest, "MissingParameter"},
+		// Missing token.
+		{url.Values{
+			"Action":  {webIdentity},
+			"Version": {stsAPIVersion},
+		}, http.StatusBadRequest, "MissingParameter"},
+		// Unsupported action.
+		{url.Values{
+			"Action":  {"AssumeRole"},
+			"Version": {stsAPIVersion},
+		}, http.StatusBadRequest, "InvalidParameterValue"},
+		// Expired token.
+		{url.Values{
+			"Action":           {webIdentity},
+			"Version":          {stsAPIVersion},
+			"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(-time.Hour), "")},
+		}, http.StatusBadRequest, "ExpiredToken"},
+		// Invalid token.
+		{url.Values{
+			"Action":           {webIdentity},
+			"Version":          {stsAPIVersion},
+			"WebIdentityToken": {"invalid-token"},
+		}, http.StatusBadRequest, "InvalidIdentityToken"},
+		// Out of range duration.
+		{url.Values{
+			"Action":           {webIdentity},
+			"Version":          {stsAPIVersion},
+			"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(time.Hour), "")},
+			"DurationSeconds":  {"60"},
+		}, http.StatusBadRequest, "InvalidParameterValue"},
+		// Malformed session policy.
+		{url.Values{
+			"Action":           {webIdentity},
+			"Version":          {stsAPIVersion},
+			"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(time.Hour), `{"Version":"2012-10-17"`)},
+		}, http.StatusBadRequest, "MalformedPolicyDocument"},
+	}
+
+	var creds []auth.Credentials
+	for i, testCase := range testCases {
+		rec := httptest.NewRecorder()
+		stsRouter.ServeHTTP(rec, newTestSTSRequest(testCase.values))
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v, %s", i+1, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+
+		if testCase.expectedErr != "" {
+			var errResp STSErrorResponse
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("case %v: %v", i+1, err)
+			}
+			if errResp.Error.Code != testCase.expectedErr {
+				t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, errResp.Error.Code)
+			}
+			continue
+		}
+
+		var result AssumeRoleWithJWTResult
+		switch testCase.values.Get("Action") {
+		case webIdentity:
+			var resp AssumeRoleWithWebIdentityResponse
+			err = xml.Unmarshal(rec.Body.Bytes(), &resp)
+			result = resp.Result
+		case clientGrants:
+			var resp AssumeRoleWithClientGrantsResponse
+			err = xml.Unmarshal(rec.Body.Bytes(), &resp)
+			result = resp.Result
+		}
+		if err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		if !result.Credentials.IsValid() || !result.Credentials.IsTemp() {
+			t.Fatalf("case %v: invalid temporary credentials %v", i+1, result.Credentials)
+		}
+		if result.SubjectFromJWT != "minio-user" {
+			t.Fatalf("case %v: subject: expected: minio-user, got: %v", i+1, result.SubjectFromJWT)
+		}
+		creds = append(creds, result.Credentials)
+	}
+
+	// Action given in query parameters.
+	query := url.Values{
+		"Action":           {webIdentity},
+		"Version":          {stsAPIVersion},
+		"WebIdentityToken": {newTestIdentityToken(t, privKey, now.Add(time.Hour), "")},
+	}
+	rec = httptest.NewRecorder()
+	stsRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9000/?"+query.Encode(), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("query STS request: expected: %v, got: %v", http.StatusOK, rec.Code)
+	}
+
+	readOnlyCred, noPolicyCred := creds[0], creds[1]
+
+	// Expiry of temporary credentials is capped by requested duration.
+	if d := noPolicyCred.Expiration.Sub(now); d > 16*time.Minute || d < 14*time.Minute {
+		t.Fatalf("unexpected expiration %v", noPolicyCred.Expiration)
+	}
+
+	// Test S3 API with temporary credentials.
+	s3TestCases := []struct {
+		method       string
+		cred         auth.Credentials
+		sessionToken string
+		expectedCode int
+	}{
+		// Session policy allows GetObject.
+		{http.MethodGet, readOnlyCred, readOnlyCred.SessionToken, http.StatusOK},
+		// Session policy does not allow PutObject.
+		{http.MethodPut, readOnlyCred, readOnlyCred.SessionToken, http.StatusForbidden},
+		// Session token is missing.
+		{http.MethodGet, readOnlyCred, "", http.StatusBadRequest},
+		// Session token of other credentials.
+		{http.MethodGet, readOnlyCred, noPolicyCred.SessionToken, http.StatusBadRequest},
+		// No session policy.
+		{http.MethodGet, noPolicyCred, noPolicyCred.SessionToken, http.StatusForbidden},
+	}
+
+	for i, testCase := range s3TestCases {
+		req, err := newTestRequest(testCase.method, getGetObjectURL("", bucketName, "object"),
+			int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		if testCase.sessionToken != "" {
+			req.Header.Set("X-Amz-Security-Token", testCase.sessionToken)
+		}
+		if err = signRequestV4(req, testCase.cred.AccessKey, testCase.cred.SecretKey); err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v, %s", i+1, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Temporary credentials are persisted in the backend.
+	identity, err := readIAMIdentity(context.Background(), objLayer, readOnlyCred.AccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Credentials.SessionToken != readOnlyCred.SessionToken || identity.Policy == nil {
+		t.Fatalf("unexpected identity %v", identity)
+	}
+}
+
+func TestSTSHandlersLDAP(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	if err = newTestConfig(globalMinioDefaultRegion, objLayer); err != nil {
+		t.Fatal(err)
+	}
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+	defer func() {
+		globalObjLayerMutex.Lock()
+		globalObjectAPI = nil
+		globalObjLayerMutex.Unlock()
+	}()
+
+	globalPolicySys = NewPolicySys()
+	globalACLSys = NewACLSys()
+	globalIAMSys = NewIAMSys()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+
+	ldapServer, ldapConfig := newTestLDAPServer()
+	defer ldapServer.Close()
+	defer func() {
+		globalLDAPConfig = ldap.Config{}
+	}()
+
+	bucketName := getRandomBucketName()
+	if err = objLayer.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Canned policy "readonly" is granted to group "dev".
+	if err = setIAMPolicy(context.Background(), objLayer, "readonly", newTestReadOnlyPolicy(t, bucketName)); err != nil {
+		t.Fatal(err)
+	}
+	if err = setIAMGroupPolicy(context.Background(), objLayer, "dev", "readonly"); err != nil {
+		t.Fatal(err)
+	}
+
+	stsRouter := mux.NewRouter()
+	registerSTSRouter(stsRouter)
+	apiRouter := initTestAPIEndPoints(objLayer, []string{"GetObject", "PutObject"})
+
+	owner := globalServerConfig.GetCredential()
+	data := []byte("hello")
+	req, err := newTestSignedRequestV4(http.MethodPut, getPutObjectURL("", bucketName, "object"),
+		int64(len(data)), bytes.NewReader(data), owner.AccessKey, owner.SecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	apiRouter.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner PutObject: expected: %v, got: %v", http.StatusOK, rec.Code)
+	}
+
+	ldapValues := func(username, password string) url.Values {
+		return url.Values{
+			"Action":       {ldapIdentity},
+			"Version":      {stsAPIVersion},
+			"LDAPUsername": {username},
+			"LDAPPassword": {password},
+		}
+	}
+
+	// LDAP identity backend is not configured.
+	rec = httptest.NewRecorder()
+	stsRouter.ServeHTTP(rec, newTestSTSRequest(ldapValues("alice", "alicepassword")))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected: %v, got: %v", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	globalLDAPConfig = ldapConfig
+
+	testCases := []struct {
+		values       url.Values
+		inQuery      bool
+		expectedCode int
+		expectedErr  string
+	}{
+		{ldapValues("alice", "alicepassword"), false, http.StatusOK, ""},
+		{ldapValues("bob", "bobpassword"), true, http.StatusOK, ""},
+		// Wrong password.
+		{ldapValues("alice", "bobpassword"), false, http.StatusForbidden, "AccessDenied"},
+		// Unknown user.
+		{ldapValues("carol", "carolpassword"), true, http.StatusForbidden, "AccessDenied"},
+		// Missing password.
+		{ldapValues("alice", ""), false, http.StatusBadRequest, "MissingParameter"},
+		// Missing version.
+		{url.Values{"Action": {ldapIdentity}, "LDAPUsername": {"alice"}, "LDAPPassword": {"alicepassword"}},
+			false, http.StatusBadRequest, "MissingParameter"},
+	}
+
+	var creds []auth.Credentials
+	for i, testCase := range testCases {
+		req := newTestSTSRequest(testCase.values)
+		if testCase.inQuery {
+			req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9000/?"+testCase.values.Encode(), nil)
+		}
+
+		rec := httptest.NewRecorder()
+		stsRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v, %s", i+1, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+
+		if testCase.expectedErr != "" {
+			var errResp STSErrorResponse
+			if err = xml.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("case %v: %v", i+1, err)
+			}
+			if errResp.Error.Code != testCase.expectedErr {
+				t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, errResp.Error.Code)
+			}
+			continue
+		}
+
+		var resp AssumeRoleWithLDAPResponse
+		if err = xml.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		if !resp.Result.Credentials.IsValid() || !resp.Result.Credentials.IsTemp() {
+			t.Fatalf("case %v: invalid temporary credentials %v", i+1, resp.Result.Credentials)
+		}
+		creds = append(creds, resp.Result.Credentials)
+	}
+
+	aliceCred, bobCred := creds[0], creds[1]
+
+	// Test S3 API with temporary credentials.
+	s3TestCases := []struct {
+		method       string
+		cred         auth.Credentials
+		expectedCode int
+	}{
+		// Policy of group "dev" allows GetObject.
+		{http.MethodGet, aliceCred, http.StatusOK},
+		// Policy of group "dev" does not allow PutObject.
+		{http.MethodPut, aliceCred, http.StatusForbidden},
+		// No group policy.
+		{http.MethodGet, bobCred, http.StatusForbidden},
+	}
+
+	for i, testCase := range s3TestCases {
+		req, err := newTestRequest(testCase.method, getGetObjectURL("", bucketName, "object"),
+			int64(len(data)), bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+		req.Header.Set("X-Amz-Security-Token", testCase.cred.SessionToken)
+		if err = signRequestV4(req, testCase.cred.AccessKey, testCase.cred.SecretKey); err != nil {
+			t.Fatalf("case %v: %v", i+1, err)
+		}
+
+		rec := httptest.NewRecorder()
+		apiRouter.ServeHTTP(rec, req)
+		if rec.Code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v, %s", i+1, testCase.expectedCode, rec.Code, rec.Body.String())
+		}
+	}
+
+	// LDAP groups are persisted along with temporary credentials.
+	identity, err := readIAMIdentity(context.Background(), objLayer, aliceCred.AccessKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identity.Groups) != 1 || identity.Groups[0] != "dev" {
+		t.Fatalf("unexpected identity groups %v", identity.Groups)
+	}
+}
@@ -358,6 +358,11 @@ func UnstartedTestServer(t TestErrHandler, instanceType string) TestServer {
 	// Create new policy system.
 	globalPolicySys = NewPolicySys()
 
+	// Create new inventory system.
+	globalInventorySys = NewInventorySys()
+	globalACLSys = NewACLSys()
+	globalIAMSys = NewIAMSys()
+
 	return testServer
 }
 
@@ -443,6 +448,7 @@ func resetGlobalIsEnvs() {
 func resetGlobalStorageEnvs() {
 	globalStandardStorageClass = storageClass{}
 	globalRRStorageClass = storageClass{}
+	globalCustomStorageClasses = nil
 }
 
 // reset global heal state
@@ -1617,6 +1623,11 @@ func newTestObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err erro
 	// Create new policy system.
 	globalPolicySys = NewPolicySys()
 
+	// Create new inventory system.
+	globalInventorySys = NewInventorySys()
+	globalACLSys = NewACLSys()
+	globalIAMSys = NewIAMSys()
+
 	return xl, nil
 }
 
@@ -2081,6 +2092,30 @@ func registerBucketLevelFunc(bucket *mux.Router, api objectAPIHandlers, apiFunct
 		case "ListenBucketNotification":
 			// Register ListenBucketNotification Handler.
 			bucket.Methods("GET").HandlerFunc(api.ListenBucketNotificationHandler).Queries("events", "{events:.*}")
+		case "GetBucketACL":
+			// Register GetBucketACL Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketACLHandler).Queries("acl", "")
+		case "PutBucketACL":
+			// Register PutBucketACL Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketACLHandler).Queries("acl", "")
+		case "GetObjectACL":
+			// Register GetObjectACL Handler.
+			bucket.Methods("GET").Path("/{object:.+}").HandlerFunc(api.GetObjectACLHandler).Queries("acl", "")
+		case "PutObjectACL":
+			// Register PutObjectACL Handler.
+			bucket.Methods("PUT").Path("/{object:.+}").HandlerFunc(api.PutObjectACLHandler).Queries("acl", "")
+		case "GetBucketInventoryConfiguration":
+			// Register GetBucketInventoryConfiguration Handler.
+			bucket.Methods("GET").HandlerFunc(api.GetBucketInventoryConfigurationHandler).Queries("inventory", "", "id", "{id:.*}")
+		case "ListBucketInventoryConfigurations":
+			// Register ListBucketInventoryConfigurations Handler.
+			bucket.Methods("GET").HandlerFunc(api.ListBucketInventoryConfigurationsHandler).Queries("inventory", "")
+		case "PutBucketInventoryConfiguration":
+			// Register PutBucketInventoryConfiguration Handler.
+			bucket.Methods("PUT").HandlerFunc(api.PutBucketInventoryConfigurationHandler).Queries("inventory", "", "id", "{id:.*}")
+		case "DeleteBucketInventoryConfiguration":
+			// Register DeleteBucketInventoryConfiguration Handler.
+			bucket.Methods("DELETE").HandlerFunc(api.DeleteBucketInventoryConfigurationHandler).Queries("inventory", "", "id", "{id:.*}")
 		}
 	}
 }
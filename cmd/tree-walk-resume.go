@@ -0,0 +1,609 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	// Persisted directory listings of resumable tree walks are saved in
+	// segments `.minio.sys/list-walks/<walk-id>/<listing-id>/<segment>`.
+	listWalksPrefix = "list-walks"
+
+	// Persisted listings of abandoned walks are removed after expiry.
+	listWalkExpiry          = 24 * time.Hour
+	listWalkCleanupInterval = time.Hour
+
+	// Current version of the continuation token format.
+	listContinuationVersion = 3
+)
+
+var (
+	// Listings of directories with more entries are persisted, smaller
+	// directories are listed again when the walk is resumed.
+	listWalkPersistThreshold = maxObjectList
+
+	// Number of entries of a persisted listing saved in a segment, a
+	// resumed walk reads at most a segment ahead of its position.
+	listWalkSegmentSize = maxObjectList
+
+	// Maximum number of walks with persisted listings, the oldest walk
+	// is removed when a new walk persists its listings.
+	listWalkMaxPersisted = 100
+)
+
+// listWalkLevel - position of a resumable tree walk in a directory.
+type listWalkLevel struct {
+	Dir         string `json:"dir"`
+	PrefixMatch string `json:"prefixMatch,omitempty"`
+	// Last entry of the directory processed by the walk.
+	After       string `json:"after,omitempty"`
+	DelayIsLeaf bool   `json:"delayIsLeaf,omitempty"`
+	// Index of the next entry in the persisted listing of Count entries.
+	Listing string `json:"listing,omitempty"`
+	Index   int    `json:"index,omitempty"`
+	Count   int    `json:"count,omitempty"`
+}
+
+// listContinuation - state of a resumable tree walk, encoded in
+// the continuation tokens of ListObjectsV2 so that a listing can
+// be continued on any server without walking the tree again.
+type listContinuation struct {
+	Version   int             `json:"version"`
+	ID        string          `json:"id,omitempty"`
+	Prefix    string          `json:"prefix"`
+	Recursive bool            `json:"recursive"`
+	Levels    []listWalkLevel `json:"levels"`
+	// Last listed entry, the walk is started again after the
+	// marker if it cannot be resumed.
+	Marker string `json:"marker"`
+}
+
+// Returns the signature of an encoded walk state, walk states refer
+// to persisted listings and must not be forged by clients.
+func listContinuationMAC(buf []byte) []byte {
+	var key []byte
+	if globalServerConfig != nil {
+		key = []byte(globalServerConfig.GetCredential().SecretKey)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(buf)
+	return mac.Sum(nil)
+}
+
+// encodeListContinuation - returns an opaque continuation token.
+func encodeListContinuation(c listContinuation) string {
+	c.Version = listContinuationVersion
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return c.Marker
+	}
+	return base64.RawURLEncoding.EncodeToString(append(buf, listContinuationMAC(buf)...))
+}
+
+// decodeListContinuation - decodes a continuation token, tokens which
+// are not signed walk states are treated as markers.
+func decodeListContinuation(token string) (c listContinuation, ok bool) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) <= sha256.Size || buf[0] != '{' {
+		return listContinuation{Marker: token}, false
+	}
+	buf, sum := buf[:len(buf)-sha256.Size], buf[len(buf)-sha256.Size:]
+	if !hmac.Equal(sum, listContinuationMAC(buf)) {
+		return listContinuation{Marker: token}, false
+	}
+	if err = json.Unmarshal(buf, &c); err != nil || c.Version != listContinuationVersion {
+		return listContinuation{Marker: token}, false
+	}
+	return c, true
+}
+
+// newListWalkID - returns a new walk id, ids sort by creation time.
+func newListWalkID() string {
+	return fmt.Sprintf("%016x-%s", UTCNow().UnixNano(), mustGetUUID())
+}
+
+// Returns the path of a segment of a persisted listing in minioMetaBucket.
+func listWalkPath(id, listing string, segment int) string {
+	return pathJoin(listWalksPrefix, id, listing, strconv.Itoa(segment))
+}
+
+// listWalkDir - directory being walked by a resumable tree walk.
+type listWalkDir struct {
+	listWalkLevel
+
+	// Entries of a directory listed by this walker.
+	entries []string
+
+	// Reader of a segment of the persisted listing when the
+	// walk is resumed.
+	reader *bufio.Reader
+	closer io.Closer
+
+	// Position before the last entry, to unread it.
+	prevAfter string
+	prevIndex int
+}
+
+// unread - moves back before the last entry, the directory
+// is only positioned for suspending the walk afterwards.
+func (d *listWalkDir) unread() {
+	d.After, d.Index = d.prevAfter, d.prevIndex
+}
+
+func (d *listWalkDir) close() {
+	if d.closer != nil {
+		d.closer.Close()
+		d.reader, d.closer = nil, nil
+	}
+}
+
+// resumableTreeWalk - tree walk which can be suspended into a
+// continuation token and resumed from it, on any server.
+type resumableTreeWalk struct {
+	ctx       context.Context
+	obj       ObjectLayer // Stores persisted listings.
+	bucket    string
+	prefix    string
+	recursive bool
+
+	listDir   listDirFunc
+	isLeaf    isLeafFunc
+	isLeafDir isLeafDirFunc
+
+	id     string
+	levels []*listWalkDir
+}
+
+// newResumableTreeWalk - starts a new walk after the marker, or
+// resumes the walk of a continuation token.
+func newResumableTreeWalk(ctx context.Context, obj ObjectLayer, bucket, prefix, token string, recursive bool, listDir listDirFunc, isLeaf isLeafFunc, isLeafDir isLeafDirFunc) *resumableTreeWalk {
+	w := &resumableTreeWalk{
+		ctx:       ctx,
+		obj:       obj,
+		bucket:    bucket,
+		prefix:    prefix,
+		recursive: recursive,
+		listDir:   listDir,
+		isLeaf:    isLeaf,
+		isLeafDir: isLeafDir,
+	}
+
+	c, ok := decodeListContinuation(token)
+	if ok && c.Prefix == prefix && c.Recursive == recursive {
+		w.id = c.ID
+		for _, level := range c.Levels {
+			w.levels = append(w.levels, &listWalkDir{listWalkLevel: level})
+		}
+		return w
+	}
+
+	// Start a new walk, same as startTreeWalk().
+	prefixMatch := prefix
+	prefixDir := ""
+	if lastIndex := strings.LastIndex(prefix, slashSeparator); lastIndex != -1 {
+		prefixMatch = prefix[lastIndex+1:]
+		prefixDir = prefix[:lastIndex+1]
+	}
+	w.open(prefixDir, prefixMatch, strings.TrimPrefix(c.Marker, prefixDir))
+	return w
+}
+
+// classify - returns the entry without trailing slash for objects,
+// and whether it is a directory to be walked into.
+func (w *resumableTreeWalk) classify(d *listWalkDir, entry string) (string, bool) {
+	var leaf, leafDir bool
+	if d.DelayIsLeaf {
+		leaf = w.isLeaf(w.bucket, pathJoin(d.Dir, entry))
+		if leaf {
+			entry = strings.TrimSuffix(entry, slashSeparator)
+		}
+	} else {
+		leaf = !strings.HasSuffix(entry, slashSeparator)
+	}
+	if strings.HasSuffix(entry, slashSeparator) {
+		leafDir = w.isLeafDir(w.bucket, pathJoin(d.Dir, entry))
+	}
+	return entry, !leafDir && !leaf
+}
+
+// open - lists a directory and pushes it on the walk, positioned
+// after the marker just like doTreeWalk().
+func (w *resumableTreeWalk) open(dir, prefixMatch, marker string) {
+	entries, delayIsLeaf := w.listDir(w.bucket, dir, prefixMatch)
+	d := &listWalkDir{
+		listWalkLevel: listWalkLevel{Dir: dir, PrefixMatch: prefixMatch, DelayIsLeaf: delayIsLeaf},
+		entries:       entries,
+	}
+	w.levels = append(w.levels, d)
+
+	if marker == "" {
+		return
+	}
+	markerDir, markerBase := marker, ""
+	if markerSplit := strings.SplitN(marker, slashSeparator, 2); len(markerSplit) == 2 {
+		markerDir, markerBase = markerSplit[0]+slashSeparator, markerSplit[1]
+	}
+
+	// Skip the entries before the marker.
+	d.Index = sort.Search(len(entries), func(i int) bool { return entries[i] >= markerDir })
+	if d.Index > 0 {
+		d.After = entries[d.Index-1]
+	}
+	if d.Index == len(entries) {
+		return
+	}
+
+	entry, isDir := w.classify(d, entries[d.Index])
+	if entry != markerDir {
+		return
+	}
+	// Marker was listed in the previous listing, or listing
+	// continues inside the marker directory.
+	d.After = entries[d.Index]
+	d.Index++
+	if w.recursive && isDir {
+		w.open(pathJoin(dir, entry), "", markerBase)
+	}
+}
+
+// load - reads the entries of a directory restored from a token from
+// the segment of its persisted listing holding the next entry, or lists
+// the directory again if the persisted listing is not available.
+func (w *resumableTreeWalk) load(d *listWalkDir) {
+	if d.Listing != "" {
+		segment := d.Index / listWalkSegmentSize
+		_, reader, err := w.obj.GetObjectNInfo(w.ctx, minioMetaBucket, listWalkPath(w.id, d.Listing, segment), nil)
+		if err == nil {
+			d.reader, d.closer = bufio.NewReader(reader), reader
+			// Skip the entries of the segment before the position.
+			for i := segment * listWalkSegmentSize; i < d.Index && err == nil; i++ {
+				_, err = d.reader.ReadBytes('\n')
+			}
+			if err == nil {
+				return
+			}
+			d.close()
+		}
+		if !isErrObjectNotFound(err) && err != io.EOF {
+			logger.LogIf(w.ctx, err)
+		}
+		// Segment is gone or not saved yet, list the directory again.
+		d.Listing, d.Count = "", 0
+	}
+
+	entries, delayIsLeaf := w.listDir(w.bucket, d.Dir, d.PrefixMatch)
+	d.entries, d.DelayIsLeaf = entries, delayIsLeaf
+	d.Index = 0
+	if d.After != "" {
+		d.Index = sort.Search(len(entries), func(i int) bool { return entries[i] > d.After })
+	}
+}
+
+// next - returns the next entry of the directory.
+func (w *resumableTreeWalk) next(d *listWalkDir) (string, bool, error) {
+	for {
+		if d.entries == nil && d.reader == nil {
+			if d.Listing != "" && d.Index >= d.Count {
+				return "", false, nil
+			}
+			w.load(d)
+		}
+
+		var entry string
+		if d.reader != nil {
+			line, err := d.reader.ReadBytes('\n')
+			if err == io.EOF {
+				// End of the segment, a segment ending before
+				// its size is not trusted.
+				d.close()
+				if d.Index%listWalkSegmentSize != 0 {
+					d.Listing, d.Count = "", 0
+				}
+				continue
+			}
+			if err != nil {
+				return "", false, err
+			}
+			if err = json.Unmarshal(line, &entry); err != nil {
+				return "", false, err
+			}
+		} else {
+			if d.Index >= len(d.entries) {
+				return "", false, nil
+			}
+			entry = d.entries[d.Index]
+		}
+
+		d.prevAfter, d.prevIndex = d.After, d.Index
+		d.After = entry
+		d.Index++
+		return entry, true, nil
+	}
+}
+
+// saveListWalkSegments - saves the given segments of a directory listing.
+func saveListWalkSegments(ctx context.Context, obj ObjectLayer, id, listing string, entries []string, segments ...int) error {
+	for _, segment := range segments {
+		start := segment * listWalkSegmentSize
+		end := start + listWalkSegmentSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, entry := range entries[start:end] {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+
+		reader, err := hash.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), "", "")
+		if err != nil {
+			return err
+		}
+		if _, err = obj.PutObject(ctx, minioMetaBucket, listWalkPath(id, listing, segment), reader, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persist - saves the listing of a large directory listed by this page,
+// so that the walk is resumed without listing the directory again. Only
+// the segment holding the next entry is saved before the page is returned,
+// later segments are saved in the background.
+func (w *resumableTreeWalk) persist(d *listWalkDir) {
+	if d.entries == nil || len(d.entries) <= listWalkPersistThreshold || d.Index >= len(d.entries) {
+		return
+	}
+	if w.id == "" {
+		evictListWalks(w.ctx, w.obj, listWalkMaxPersisted)
+		w.id = newListWalkID()
+	}
+
+	listing := mustGetUUID()
+	segment := d.Index / listWalkSegmentSize
+	if err := saveListWalkSegments(w.ctx, w.obj, w.id, listing, d.entries, segment); err != nil {
+		// Walk goes on, the directory is listed again on resumption.
+		logger.LogIf(w.ctx, err)
+		return
+	}
+	d.Listing, d.Count = listing, len(d.entries)
+
+	var segments []int
+	for i := segment + 1; i*listWalkSegmentSize < len(d.entries); i++ {
+		segments = append(segments, i)
+	}
+	if len(segments) == 0 {
+		return
+	}
+	go func(id string, entries []string) {
+		ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{BucketName: w.bucket})
+		logger.LogIf(ctx, saveListWalkSegments(ctx, w.obj, id, listing, entries, segments...))
+	}(w.id, d.entries)
+}
+
+// Next - returns the next entry of the walk, false at the end.
+func (w *resumableTreeWalk) Next() (string, bool, error) {
+	for len(w.levels) > 0 {
+		d := w.levels[len(w.levels)-1]
+		entry, ok, err := w.next(d)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			d.close()
+			w.levels = w.levels[:len(w.levels)-1]
+			continue
+		}
+
+		entry, isDir := w.classify(d, entry)
+		if w.recursive && isDir {
+			w.open(pathJoin(d.Dir, entry), "", "")
+			continue
+		}
+		return pathJoin(d.Dir, entry), true, nil
+	}
+	return "", false, nil
+}
+
+// Suspend - returns a continuation token resuming the walk before the
+// last entry returned by Next(), or after the marker. Large directories
+// listed by this page are persisted.
+func (w *resumableTreeWalk) Suspend(marker string) string {
+	if len(w.levels) > 0 {
+		w.levels[len(w.levels)-1].unread()
+	}
+	c := listContinuation{
+		Prefix:    w.prefix,
+		Recursive: w.recursive,
+		Marker:    marker,
+	}
+	for _, d := range w.levels {
+		w.persist(d)
+		c.Levels = append(c.Levels, d.listWalkLevel)
+		d.close()
+	}
+	c.ID = w.id
+	return encodeListContinuation(c)
+}
+
+// Close - ends the walk, removing its persisted listings.
+func (w *resumableTreeWalk) Close() {
+	for _, d := range w.levels {
+		d.close()
+	}
+	if w.id != "" {
+		deleteListWalk(w.ctx, w.obj, w.id)
+	}
+}
+
+// evictListWalks - removes the oldest walk with persisted listings once
+// maxWalks walks have persisted listings.
+func evictListWalks(ctx context.Context, obj ObjectLayer, maxWalks int) {
+	result, err := obj.ListObjects(ctx, minioMetaBucket, listWalksPrefix+slashSeparator, "", slashSeparator, maxWalks)
+	if err != nil || len(result.Prefixes) < maxWalks {
+		return
+	}
+	oldest := strings.TrimSuffix(strings.TrimPrefix(result.Prefixes[0], listWalksPrefix+slashSeparator), slashSeparator)
+	deleteListWalk(ctx, obj, oldest)
+}
+
+// deleteListWalk - removes all persisted listings of a walk.
+func deleteListWalk(ctx context.Context, obj ObjectLayer, id string) {
+	marker := ""
+	for {
+		result, err := obj.ListObjects(ctx, minioMetaBucket, pathJoin(listWalksPrefix, id)+slashSeparator, marker, "", maxObjectList)
+		if err != nil {
+			return
+		}
+		for _, objInfo := range result.Objects {
+			obj.DeleteObject(ctx, minioMetaBucket, objInfo.Name)
+		}
+		if !result.IsTruncated {
+			return
+		}
+		marker = result.NextMarker
+	}
+}
+
+// walkEntryInfoFunc - returns the object info of an entry found by a tree walk.
+type walkEntryInfoFunc func(ctx context.Context, bucket, entry string) (ObjectInfo, error)
+
+// listObjectsV2Resumable - lists objects in bucket filtered by prefix with a
+// resumable tree walk, the continuation token holds the state of the walk.
+func listObjectsV2Resumable(ctx context.Context, obj ObjectLayer, bucket, prefix, continuationToken, delimiter string, maxKeys int, startAfter string,
+	listDir listDirFunc, isLeaf isLeafFunc, isLeafDir isLeafDirFunc, getEntryInfo walkEntryInfoFunc) (result ListObjectsV2Info, err error) {
+	token := continuationToken
+	if token == "" {
+		token = startAfter
+	}
+
+	// Only the marker of the token is validated, walk state
+	// which does not match the prefix is ignored.
+	c, _ := decodeListContinuation(token)
+	if err = checkListObjsArgs(ctx, bucket, prefix, c.Marker, delimiter, obj); err != nil {
+		return result, err
+	}
+
+	result.ContinuationToken = continuationToken
+	if maxKeys == 0 {
+		return result, nil
+	}
+
+	recursive := true
+	if delimiter == slashSeparator {
+		recursive = false
+	}
+
+	walk := newResumableTreeWalk(ctx, obj, bucket, prefix, token, recursive, listDir, isLeaf, isLeafDir)
+
+	var nextMarker string
+	for i := 0; i < maxKeys; {
+		entry, ok, err := walk.Next()
+		if err != nil {
+			return result, toObjectErr(err, bucket, prefix)
+		}
+		if !ok {
+			walk.Close()
+			return result, nil
+		}
+
+		objInfo, err := getEntryInfo(ctx, bucket, entry)
+		if err != nil {
+			// Ignore errFileNotFound as the object might have got
+			// deleted in the interim period of listing and getObjectInfo(),
+			// ignore quorum error as it might be an entry from an outdated disk.
+			if IsErrIgnored(err, []error{
+				errFileNotFound,
+				errXLReadQuorum,
+			}...) {
+				continue
+			}
+			return result, toObjectErr(err, bucket, prefix)
+		}
+		nextMarker = objInfo.Name
+		if objInfo.IsDir && delimiter == slashSeparator {
+			result.Prefixes = append(result.Prefixes, objInfo.Name)
+		} else {
+			result.Objects = append(result.Objects, objInfo)
+		}
+		i++
+	}
+
+	// Look ahead to know whether the listing is truncated.
+	if _, ok, err := walk.Next(); err != nil {
+		return result, toObjectErr(err, bucket, prefix)
+	} else if !ok {
+		walk.Close()
+		return result, nil
+	}
+
+	result.IsTruncated = true
+	result.NextContinuationToken = walk.Suspend(nextMarker)
+	return result, nil
+}
+
+// cleanupStaleListWalks - removes persisted listings of walks which were
+// not continued until the end for every cleanupInterval, this function
+// is blocking and should be run in a go-routine.
+func cleanupStaleListWalks(ctx context.Context, obj ObjectLayer, cleanupInterval, expiry time.Duration, doneCh chan struct{}) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-doneCh:
+			return
+		case <-ticker.C:
+			marker := ""
+			for {
+				result, err := obj.ListObjects(ctx, minioMetaBucket, listWalksPrefix+slashSeparator, marker, "", maxObjectList)
+				if err != nil {
+					break
+				}
+				for _, objInfo := range result.Objects {
+					if time.Since(objInfo.ModTime) > expiry {
+						obj.DeleteObject(ctx, minioMetaBucket, objInfo.Name)
+					}
+				}
+				if !result.IsTruncated {
+					break
+				}
+				marker = result.NextMarker
+			}
+		}
+	}
+}
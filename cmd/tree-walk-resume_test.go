@@ -0,0 +1,269 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Tests encoding and decoding of continuation tokens.
+func TestListContinuationToken(t *testing.T) {
+	c := listContinuation{
+		ID:        mustGetUUID(),
+		Prefix:    "photos/",
+		Recursive: true,
+		Levels: []listWalkLevel{
+			{Dir: "photos/", After: "2018/", Listing: mustGetUUID(), Index: 120, Count: 5000},
+			{Dir: "photos/2018/", After: "march.jpg", DelayIsLeaf: true},
+		},
+		Marker: "photos/2018/march.jpg",
+	}
+
+	token := encodeListContinuation(c)
+	decoded, ok := decodeListContinuation(token)
+	if !ok {
+		t.Fatalf("Expected token %s to be decoded", token)
+	}
+	c.Version = listContinuationVersion
+	if !reflect.DeepEqual(c, decoded) {
+		t.Fatalf("Expected %#v, got %#v", c, decoded)
+	}
+
+	// Tokens which are not signed walks are markers.
+	forged := base64.RawURLEncoding.EncodeToString(append([]byte(`{"version":2,"id":"../config","levels":[]}`), make([]byte, sha256.Size)...))
+	tampered := []byte(token)
+	tampered[len(tampered)/2] ^= 1
+	for _, marker := range []string{"", "photos/2018/march.jpg", "e30", "eyJ2ZXJzaW9uIjo5fQ", forged, string(tampered)} {
+		decoded, ok = decodeListContinuation(marker)
+		if ok {
+			t.Fatalf("Expected %s not to be decoded", marker)
+		}
+		if decoded.Marker != marker {
+			t.Fatalf("Expected marker %s, got %s", marker, decoded.Marker)
+		}
+	}
+}
+
+// Returns the names of persisted listings of resumable walks.
+func listWalkObjects(t *testing.T, obj ObjectLayer) []string {
+	result, err := obj.ListObjects(context.Background(), minioMetaBucket, listWalksPrefix+slashSeparator, "", "", maxObjectList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, objInfo := range result.Objects {
+		names = append(names, objInfo.Name)
+	}
+	return names
+}
+
+// Tests paging of ListObjectsV2 with resumable continuation tokens.
+func TestXLSetsListObjectsV2Resume(t *testing.T) {
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	defer func(threshold int) { listWalkPersistThreshold = threshold }(listWalkPersistThreshold)
+	listWalkPersistThreshold = 4
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var objects []string
+	for i := 0; i < 12; i++ {
+		objects = append(objects, fmt.Sprintf("a/%02d", i))
+	}
+	for i := 0; i < 3; i++ {
+		objects = append(objects, fmt.Sprintf("a/sub/%d", i), fmt.Sprintf("b-%d", i))
+	}
+	objects = append(objects, "a/sub/deep/x", "c/d/e/f", "z")
+	for _, object := range objects {
+		data := []byte(object)
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type listing struct {
+		objects, prefixes []string
+	}
+	listV1 := func(prefix, delimiter string) (l listing) {
+		result, err := obj.ListObjects(ctx, bucket, prefix, "", delimiter, maxObjectList)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, objInfo := range result.Objects {
+			l.objects = append(l.objects, objInfo.Name)
+		}
+		l.prefixes = result.Prefixes
+		return l
+	}
+	listV2 := func(prefix, delimiter, startAfter string, maxKeys int, interrupt func()) (l listing) {
+		token := ""
+		for {
+			result, err := obj.ListObjectsV2(ctx, bucket, prefix, token, delimiter, maxKeys, false, startAfter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Objects)+len(result.Prefixes) > maxKeys {
+				t.Fatalf("Expected at most %d entries, got %d", maxKeys, len(result.Objects)+len(result.Prefixes))
+			}
+			for _, objInfo := range result.Objects {
+				l.objects = append(l.objects, objInfo.Name)
+			}
+			l.prefixes = append(l.prefixes, result.Prefixes...)
+			if !result.IsTruncated {
+				return l
+			}
+			if result.NextContinuationToken == "" {
+				t.Fatal("Expected a continuation token for a truncated listing")
+			}
+			token = result.NextContinuationToken
+			if interrupt != nil {
+				interrupt()
+			}
+		}
+	}
+
+	testCases := []struct {
+		prefix, delimiter string
+	}{
+		{"", ""},
+		{"", "/"},
+		{"a/", ""},
+		{"a/", "/"},
+		{"a/0", ""},
+		{"a/s", "/"},
+		{"b", ""},
+		{"c/", ""},
+		{"nothing", ""},
+	}
+	for i, testCase := range testCases {
+		expected := listV1(testCase.prefix, testCase.delimiter)
+		for _, maxKeys := range []int{1, 3, 5, 1000} {
+			if got := listV2(testCase.prefix, testCase.delimiter, "", maxKeys, nil); !reflect.DeepEqual(expected, got) {
+				t.Fatalf("Test %d, max keys %d: expected %v, got %v", i+1, maxKeys, expected, got)
+			}
+			// Walks which are listed until the end remove their listings.
+			if names := listWalkObjects(t, obj); len(names) != 0 {
+				t.Fatalf("Test %d: expected persisted listings to be removed, found %v", i+1, names)
+			}
+		}
+	}
+
+	// Start after an object inside a large directory.
+	expected := listV1("", "")
+	for i, name := range expected.objects {
+		if name == "a/05" {
+			expected.objects = expected.objects[i+1:]
+			break
+		}
+	}
+	if got := listV2("", "", "a/05", 2, nil); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	// Walks are resumed when their persisted listings are removed.
+	expected = listV1("", "")
+	removeListings := func() {
+		for _, name := range listWalkObjects(t, obj) {
+			if err := obj.DeleteObject(ctx, minioMetaBucket, name); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if got := listV2("", "", "", 3, removeListings); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	// Abandoned walks keep their persisted listings.
+	result, err := obj.ListObjectsV2(ctx, bucket, "a/", "", "", 2, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsTruncated {
+		t.Fatal("Expected a truncated listing")
+	}
+	if names := listWalkObjects(t, obj); len(names) != 1 {
+		t.Fatalf("Expected one persisted listing, found %v", names)
+	}
+
+	// Token of a different prefix is used as a marker.
+	result, err = obj.ListObjectsV2(ctx, bucket, "", result.NextContinuationToken, "", 1000, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) == 0 || result.Objects[0].Name != "a/02" {
+		t.Fatalf("Expected listing to continue after a/01, got %v", result.Objects)
+	}
+
+	// Oldest walk is removed once enough walks persist listings.
+	defer func(maxPersisted int) { listWalkMaxPersisted = maxPersisted }(listWalkMaxPersisted)
+	listWalkMaxPersisted = 1
+	abandoned := listWalkObjects(t, obj)
+	expected = listV1("", "")
+	if got := listV2("", "", "", 3, func() {
+		names := listWalkObjects(t, obj)
+		if len(names) == 0 || reflect.DeepEqual(names, abandoned) {
+			t.Fatalf("Expected the abandoned listing to be replaced, found %v", names)
+		}
+		for _, name := range names {
+			if name == abandoned[0] {
+				t.Fatalf("Expected the abandoned listing to be removed, found %v", names)
+			}
+		}
+	}); !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	// Forged walk states cannot remove other persisted data.
+	data := []byte("config")
+	if _, err = obj.PutObject(ctx, minioMetaBucket, "config/forged", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	forged := base64.RawURLEncoding.EncodeToString(append([]byte(`{"version":2,"id":"../config","prefix":"","recursive":true,"levels":[],"marker":""}`), make([]byte, sha256.Size)...))
+	if _, err = obj.ListObjectsV2(ctx, bucket, "", forged, "", 1000, false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.GetObjectInfo(ctx, minioMetaBucket, "config/forged"); err != nil {
+		t.Fatalf("Expected forged token not to remove config, got %v", err)
+	}
+
+	// Persisted listings are read in segments, missing segments are
+	// listed again.
+	defer func(segmentSize int) { listWalkSegmentSize = segmentSize }(listWalkSegmentSize)
+	listWalkSegmentSize = 2
+	for _, maxKeys := range []int{1, 3, 5} {
+		if got := listV2("", "", "", maxKeys, nil); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("Max keys %d: expected %v, got %v", maxKeys, expected, got)
+		}
+		if got := listV2("", "", "", maxKeys, removeListings); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("Max keys %d: expected %v, got %v", maxKeys, expected, got)
+		}
+	}
+}
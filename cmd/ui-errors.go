@@ -65,6 +65,60 @@ var (
 		"MINIO_CACHE_MAXUSE: Valid cache max-use value between 0-100.",
 	)
 
+	uiErrInvalidCacheCommitValue = newUIErrFn(
+		"Invalid cache commit value",
+		"Please check the passed value",
+		"MINIO_CACHE_COMMIT: Valid cache commit modes are `writethrough` and `writeback`.",
+	)
+
+	uiErrInvalidCacheRangeValue = newUIErrFn(
+		"Invalid cache range value",
+		"Please check the passed value",
+		"MINIO_CACHE_RANGE: Range caching can only accept `on` and `off` values.",
+	)
+
+	uiErrInvalidCachePolicyValue = newUIErrFn(
+		"Invalid cache policy value",
+		"Please check the passed value",
+		"MINIO_CACHE_POLICY: Valid cache eviction policies are `lru` and `lfu`.",
+	)
+
+	uiErrInvalidCacheWatermarkValue = newUIErrFn(
+		"Invalid cache watermark value",
+		"Please check the passed value",
+		"MINIO_CACHE_WATERMARK_LOW, MINIO_CACHE_WATERMARK_HIGH: Valid cache watermarks are between 0-100, low watermark should be less than high watermark.",
+	)
+
+	uiErrInvalidCacheIncludesValue = newUIErrFn(
+		"Invalid cache includes value",
+		"Please check the passed value",
+		"MINIO_CACHE_INCLUDE: Cache inclusion patterns are delimited by `;`",
+	)
+
+	uiErrInvalidCacheQuotaValue = newUIErrFn(
+		"Invalid cache quota value",
+		"Please check the passed value",
+		"MINIO_CACHE_QUOTA: Cache quotas of the form `bucket=size` are delimited by `;`, for example `images=10GiB;videos=100GiB`.",
+	)
+
+	uiErrInvalidCacheSizeValue = newUIErrFn(
+		"Invalid cache object size value",
+		"Please check the passed value",
+		"MINIO_CACHE_MIN_SIZE, MINIO_CACHE_MAX_SIZE: Valid object sizes are for example `1KiB` or `10MB`, min size should not be larger than max size.",
+	)
+
+	uiErrInvalidCacheAfterHitsValue = newUIErrFn(
+		"Invalid cache after hits value",
+		"Please check the passed value",
+		"MINIO_CACHE_AFTER_HITS: Valid number of reads before an object is cached is 0 or more.",
+	)
+
+	uiErrInvalidCacheEncryptionKey = newUIErrFn(
+		"Invalid cache encryption master key",
+		"Please check the passed value",
+		"MINIO_CACHE_ENCRYPTION_MASTER_KEY: The cache master key should be of the form `key-id:hex-key`, where hex-key is a hex-encoded 256 bit key.",
+	)
+
 	uiErrInvalidCredentials = newUIErrFn(
 		"Invalid credentials",
 		"Please provide correct credentials",
@@ -43,6 +43,7 @@ import (
 	"github.com/minio/minio/pkg/dns"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/iam/ldap"
 	"github.com/minio/minio/pkg/ioutil"
 	"github.com/minio/minio/pkg/policy"
 )
@@ -129,7 +130,11 @@ func (web *webAPIHandlers) MakeBucket(r *http.Request, args *MakeBucketArgs, rep
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr != nil {
+		return toJSONError(errAuthentication)
+	}
+	if !owner && !isWebUserAllowed(r, cred, policy.CreateBucketAction, args.BucketName, "") {
 		return toJSONError(errAuthentication)
 	}
 
@@ -177,7 +182,11 @@ func (web *webAPIHandlers) DeleteBucket(r *http.Request, args *RemoveBucketArgs,
 	if objectAPI == nil {
 		return toJSONError(errServerNotInitialized)
 	}
-	if !isHTTPRequestValid(r) {
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr != nil {
+		return toJSONError(errAuthentication)
+	}
+	if !owner && !isWebUserAllowed(r, cred, policy.DeleteBucketAction, args.BucketName, "") {
 		return toJSONError(errAuthentication)
 	}
 
@@ -232,34 +241,38 @@ func (web *webAPIHandlers) ListBuckets(r *http.Request, args *WebGenericArgs, re
 	if web.CacheAPI() != nil {
 		listBuckets = web.CacheAPI().ListBuckets
 	}
-	authErr := webRequestAuthenticate(r)
+	cred, owner, authErr := webRequestAuthenticateUser(r)
 	if authErr != nil {
 		return toJSONError(authErr)
 	}
-	// If etcd, dns federation configured list buckets from etcd.
+
+	// Users with temporary credentials see only the buckets their
+	// policies allow listing.
+	isListable := func(bucketName string) bool {
+		return owner ||
+			isWebUserAllowed(r, cred, policy.ListAllMyBucketsAction, "", "") ||
+			isWebUserAllowed(r, cred, policy.ListBucketAction, bucketName, "")
+	}
+
+	// If etcd, dns federation configured list buckets of all clusters.
 	if globalDNSConfig != nil {
-		dnsBuckets, err := globalDNSConfig.List()
-		if err != nil {
-			return toJSONError(err)
-		}
-		for _, dnsRecord := range dnsBuckets {
-			bucketName := strings.Trim(dnsRecord.Key, "/")
-			reply.Buckets = append(reply.Buckets, WebBucketInfo{
-				Name:         bucketName,
-				CreationDate: dnsRecord.CreationDate,
-			})
-		}
-	} else {
-		buckets, err := listBuckets(context.Background())
-		if err != nil {
-			return toJSONError(err)
+		listLocalBuckets := listBuckets
+		listBuckets = func(ctx context.Context) ([]BucketInfo, error) {
+			return listFederatedBuckets(ctx, globalDNSConfig, listLocalBuckets)
 		}
-		for _, bucket := range buckets {
-			reply.Buckets = append(reply.Buckets, WebBucketInfo{
-				Name:         bucket.Name,
-				CreationDate: bucket.Created,
-			})
+	}
+	buckets, err := listBuckets(context.Background())
+	if err != nil {
+		return toJSONError(err)
+	}
+	for _, bucket := range buckets {
+		if !isListable(bucket.Name) {
+			continue
 		}
+		reply.Buckets = append(reply.Buckets, WebBucketInfo{
+			Name:         bucket.Name,
+			CreationDate: bucket.Created,
+		})
 	}
 
 	reply.UIVersion = browser.UIVersion
@@ -323,12 +336,20 @@ func (web *webAPIHandlers) ListObjects(r *http.Request, args *ListObjectsArgs, r
 		ObjectName:      args.Prefix + "/",
 	})
 
-	if authErr := webRequestAuthenticate(r); authErr != nil {
-		if authErr == errAuthentication {
-			return toJSONError(authErr)
-		}
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr == errAuthentication {
+		return toJSONError(authErr)
+	}
 
-		// Error out anonymous (non-owner) has no access download or upload objects.
+	if authErr == nil && !owner {
+		// Check if user of temporary credentials has access to list
+		// or upload objects.
+		readable = readable || isWebUserAllowed(r, cred, policy.ListBucketAction, args.BucketName, "")
+		writable = writable || isWebUserAllowed(r, cred, policy.PutObjectAction, args.BucketName, args.Prefix+"/")
+	}
+
+	if !owner {
+		// Error out non-owner has no access download or upload objects.
 		if !readable && !writable {
 			return errAuthentication
 		}
@@ -392,7 +413,8 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 	if web.CacheAPI() != nil {
 		listObjects = web.CacheAPI().ListObjects
 	}
-	if !isHTTPRequestValid(r) {
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr != nil {
 		return toJSONError(errAuthentication)
 	}
 
@@ -400,6 +422,14 @@ func (web *webAPIHandlers) RemoveObject(r *http.Request, args *RemoveObjectArgs,
 		return toJSONError(errInvalidArgument)
 	}
 
+	if !owner {
+		for _, objectName := range args.Objects {
+			if !isWebUserAllowed(r, cred, policy.DeleteObjectAction, args.BucketName, objectName) {
+				return toJSONError(errAuthentication)
+			}
+		}
+	}
+
 	var err error
 next:
 	for _, objectName := range args.Objects {
@@ -462,7 +492,14 @@ type LoginRep struct {
 
 // Login - user login handler.
 func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginRep) error {
-	token, err := authenticateWeb(args.Username, args.Password)
+	var token string
+	var err error
+	if args.Username != globalServerConfig.GetCredential().AccessKey && globalLDAPConfig.IsEnabled() {
+		// Not the server owner, authenticate with LDAP identity backend.
+		token, err = web.loginLDAP(args.Username, args.Password)
+	} else {
+		token, err = authenticateWeb(args.Username, args.Password)
+	}
 	if err != nil {
 		return toJSONError(err)
 	}
@@ -472,6 +509,35 @@ func (web *webAPIHandlers) Login(r *http.Request, args *LoginArgs, reply *LoginR
 	return nil
 }
 
+// loginLDAP - authenticates user with LDAP identity backend and returns web
+// token of new temporary credentials of the user.
+func (web *webAPIHandlers) loginLDAP(username, password string) (string, error) {
+	// globalIAMSys is not initialized in gateway mode.
+	objectAPI := web.ObjectAPI()
+	if objectAPI == nil || globalIAMSys == nil {
+		return "", errServerNotInitialized
+	}
+
+	cred, groups, err := newLDAPCredentials(username, password)
+	if err != nil {
+		if err != ldap.ErrInvalidCredentials {
+			logger.LogIf(context.Background(), err)
+		}
+		return "", errAuthentication
+	}
+
+	identity := iamIdentity{
+		Version:     iamIdentityVersion,
+		Credentials: cred,
+		Groups:      groups,
+	}
+	if err = setIAMIdentity(context.Background(), objectAPI, identity); err != nil {
+		return "", err
+	}
+
+	return authenticateTempJWT(cred, defaultJWTExpiry)
+}
+
 // GenerateAuthReply - reply for GenerateAuth
 type GenerateAuthReply struct {
 	AccessKey string `json:"accessKey"`
@@ -579,13 +645,19 @@ type URLTokenReply struct {
 
 // CreateURLToken creates a URL token (short-lived) for GET requests.
 func (web *webAPIHandlers) CreateURLToken(r *http.Request, args *WebGenericArgs, reply *URLTokenReply) error {
-	if !isHTTPRequestValid(r) {
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr != nil {
 		return toJSONError(errAuthentication)
 	}
 
-	creds := globalServerConfig.GetCredential()
-
-	token, err := authenticateURL(creds.AccessKey, creds.SecretKey)
+	var token string
+	var err error
+	if owner {
+		creds := globalServerConfig.GetCredential()
+		token, err = authenticateURL(creds.AccessKey, creds.SecretKey)
+	} else {
+		token, err = authenticateTempJWT(cred, defaultURLJWTExpiry)
+	}
 	if err != nil {
 		return toJSONError(err)
 	}
@@ -611,20 +683,22 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 	bucket := vars["bucket"]
 	object := vars["object"]
 
-	if authErr := webRequestAuthenticate(r); authErr != nil {
-		if authErr == errAuthentication {
-			writeWebErrorResponse(w, errAuthentication)
-			return
-		}
+	cred, owner, authErr := webRequestAuthenticateUser(r)
+	if authErr == errAuthentication {
+		writeWebErrorResponse(w, errAuthentication)
+		return
+	}
 
-		// Check if anonymous (non-owner) has access to upload objects.
+	if !owner {
+		// Check if anonymous (non-owner) or user of temporary
+		// credentials has access to upload objects.
 		if !globalPolicySys.IsAllowed(policy.Args{
 			Action:          policy.PutObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
 			IsOwner:         false,
 			ObjectName:      object,
-		}) {
+		}) && (authErr != nil || !isWebUserAllowed(r, cred, policy.PutObjectAction, bucket, object)) {
 			writeWebErrorResponse(w, errAuthentication)
 			return
 		}
@@ -686,15 +760,17 @@ func (web *webAPIHandlers) Download(w http.ResponseWriter, r *http.Request) {
 	object := vars["object"]
 	token := r.URL.Query().Get("token")
 
-	if !isAuthTokenValid(token) {
-		// Check if anonymous (non-owner) has access to download objects.
+	cred, owner, authErr := authenticateURLToken(token)
+	if !owner {
+		// Check if anonymous (non-owner) or user of temporary
+		// credentials has access to download objects.
 		if !globalPolicySys.IsAllowed(policy.Args{
 			Action:          policy.GetObjectAction,
 			BucketName:      bucket,
 			ConditionValues: getConditionValues(r, ""),
 			IsOwner:         false,
 			ObjectName:      object,
-		}) {
+		}) && (authErr != nil || !isWebUserAllowed(r, cred, policy.GetObjectAction, bucket, object)) {
 			writeWebErrorResponse(w, errAuthentication)
 			return
 		}
@@ -788,16 +864,19 @@ func (web *webAPIHandlers) DownloadZip(w http.ResponseWriter, r *http.Request) {
 	}
 
 	token := r.URL.Query().Get("token")
-	if !isAuthTokenValid(token) {
+	cred, owner, authErr := authenticateURLToken(token)
+	if !owner {
 		for _, object := range args.Objects {
-			// Check if anonymous (non-owner) has access to download objects.
+			// Check if anonymous (non-owner) or user of temporary
+			// credentials has access to download objects.
+			objectName := pathJoin(args.Prefix, object)
 			if !globalPolicySys.IsAllowed(policy.Args{
 				Action:          policy.GetObjectAction,
 				BucketName:      args.BucketName,
 				ConditionValues: getConditionValues(r, ""),
 				IsOwner:         false,
-				ObjectName:      pathJoin(args.Prefix, object),
-			}) {
+				ObjectName:      objectName,
+			}) && (authErr != nil || !isWebUserAllowed(r, cred, policy.GetObjectAction, args.BucketName, objectName)) {
 				writeWebErrorResponse(w, errAuthentication)
 				return
 			}
@@ -1138,6 +1217,24 @@ func presignedGet(host, bucket, object string, expiry int64) string {
 	return host + s3utils.EncodePath(path) + "?" + queryStr + "&" + "X-Amz-Signature=" + signature
 }
 
+// isWebUserAllowed - checks whether policies granted to temporary
+// credentials allow given action on bucket and object.
+func isWebUserAllowed(r *http.Request, cred auth.Credentials, action policy.Action, bucketName, objectName string) bool {
+	// globalIAMSys is not initialized in gateway mode.
+	if globalIAMSys == nil {
+		return false
+	}
+
+	return globalIAMSys.IsAllowed(policy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          action,
+		BucketName:      bucketName,
+		ConditionValues: getConditionValues(r, ""),
+		IsOwner:         false,
+		ObjectName:      objectName,
+	})
+}
+
 // toJSONError converts regular errors into more user friendly
 // and consumable error message for the browser UI.
 func toJSONError(err error, params ...string) (jerr *json2.Error) {
@@ -1243,6 +1340,8 @@ func toWebAPIError(err error) APIError {
 		return getAPIError(ErrObjectTampered)
 	} else if err == errMethodNotAllowed {
 		return getAPIError(ErrMethodNotAllowed)
+	} else if err == dns.ErrBucketConflict {
+		return getAPIError(ErrBucketAlreadyExists)
 	}
 
 	// Convert error type to api error code.
@@ -37,6 +37,7 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	miniogopolicy "github.com/minio/minio-go/pkg/policy"
 	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/iam/ldap"
 	"github.com/minio/minio/pkg/policy"
 	"github.com/minio/minio/pkg/policy/condition"
 )
@@ -170,6 +171,124 @@ func testLoginWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler)
 	}
 }
 
+// Wrapper for calling Login Web Handler with LDAP users
+func TestWebHandlerLoginLDAP(t *testing.T) {
+	ExecObjectLayerTest(t, testLoginLDAPWebHandler)
+}
+
+// testLoginLDAPWebHandler - Test login web handler authenticating users
+// with LDAP identity backend and their access to buckets.
+func testLoginLDAPWebHandler(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	// Register the API end points with XL/FS object layer.
+	apiRouter := initTestWebRPCEndPoint(obj)
+
+	globalIAMSys = NewIAMSys()
+	globalNotificationSys = NewNotificationSys(globalServerConfig, EndpointList{})
+
+	ldapServer, ldapConfig := newTestLDAPServer()
+	defer ldapServer.Close()
+	globalLDAPConfig = ldapConfig
+	defer func() {
+		globalLDAPConfig = ldap.Config{}
+	}()
+
+	readableBucket := getRandomBucketName()
+	otherBucket := getRandomBucketName()
+	for _, bucketName := range []string{readableBucket, otherBucket} {
+		if err := obj.MakeBucketWithLocation(context.Background(), bucketName, ""); err != nil {
+			t.Fatalf("%s : %s", instanceType, err)
+		}
+	}
+
+	p := newTestReadOnlyPolicy(t, readableBucket)
+	if err := setIAMPolicy(context.Background(), obj, "readonly", p); err != nil {
+		t.Fatal(err)
+	}
+	if err := setIAMGroupPolicy(context.Background(), obj, "dev", "readonly"); err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(username, password string) (string, error) {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest("Web.Login", "", LoginArgs{Username: username, Password: password})
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		reply := &LoginRep{}
+		if err = getTestWebRPCResponse(rec, &reply); err != nil {
+			return "", err
+		}
+		return reply.Token, nil
+	}
+
+	listBuckets := func(token string) ([]string, error) {
+		rec := httptest.NewRecorder()
+		req, err := newTestWebRPCRequest("Web.ListBuckets", token, WebGenericArgs{})
+		if err != nil {
+			t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+		}
+		apiRouter.ServeHTTP(rec, req)
+		reply := &ListBucketsRep{}
+		if err = getTestWebRPCResponse(rec, &reply); err != nil {
+			return nil, err
+		}
+		var buckets []string
+		for _, bucket := range reply.Buckets {
+			buckets = append(buckets, bucket.Name)
+		}
+		return buckets, nil
+	}
+
+	if _, err := login("alice", "bobpassword"); err == nil {
+		t.Fatalf("Expected login with wrong password to fail")
+	}
+
+	aliceToken, err := login("alice", "alicepassword")
+	if err != nil {
+		t.Fatalf("Expected to succeed but it failed, %v", err)
+	}
+
+	// Only buckets allowed by group policy are listed.
+	buckets, err := listBuckets(aliceToken)
+	if err != nil {
+		t.Fatalf("Failed, %v", err)
+	}
+	if len(buckets) != 1 || buckets[0] != readableBucket {
+		t.Fatalf("Expected buckets %v, got %v", []string{readableBucket}, buckets)
+	}
+
+	bobToken, err := login("bob", "bobpassword")
+	if err != nil {
+		t.Fatalf("Expected to succeed but it failed, %v", err)
+	}
+	if buckets, err = listBuckets(bobToken); err != nil || len(buckets) != 0 {
+		t.Fatalf("Expected no buckets, got %v, %v", buckets, err)
+	}
+
+	// Creating buckets is not allowed by group policy.
+	rec := httptest.NewRecorder()
+	req, err := newTestWebRPCRequest("Web.MakeBucket", aliceToken, MakeBucketArgs{BucketName: getRandomBucketName()})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if err = getTestWebRPCResponse(rec, &WebGenericRep{}); err == nil {
+		t.Fatalf("Expected MakeBucket to fail for LDAP user")
+	}
+
+	// Server owner only APIs are not allowed.
+	rec = httptest.NewRecorder()
+	req, err = newTestWebRPCRequest("Web.GetAuth", aliceToken, WebGenericArgs{})
+	if err != nil {
+		t.Fatalf("Failed to create HTTP request: <ERROR> %v", err)
+	}
+	apiRouter.ServeHTTP(rec, req)
+	if err = getTestWebRPCResponse(rec, &GetAuthReply{}); err == nil {
+		t.Fatalf("Expected GetAuth to fail for LDAP user")
+	}
+}
+
 // Wrapper for calling StorageInfo Web Handler
 func TestWebHandlerStorageInfo(t *testing.T) {
 	ExecObjectLayerTest(t, testStorageInfoWebHandler)
@@ -0,0 +1,204 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Size of the temporary file written by a drive speedtest by default.
+	driveSpeedtestDefaultSize = 64 * humanize.MiByte
+
+	// Largest allowed speedtest size, keeps a test of all
+	// drives of a server well within the admin RPC timeout.
+	driveSpeedtestMaxSize = 256 * humanize.MiByte
+
+	// Size of each write performed by a drive speedtest.
+	driveSpeedtestBlockSize = 4 * humanize.MiByte
+)
+
+var errDrivesNotSupported = errors.New("drive information is only available for erasure coded backends")
+
+// serverDrive - returns details of the drive at a local endpoint.
+func (s *xlSets) serverDrive(pool int, endpoint Endpoint) madmin.ServerDrive {
+	drive := madmin.ServerDrive{
+		Endpoint:  endpoint.String(),
+		Path:      endpoint.Path,
+		State:     madmin.DriveStateOffline,
+		Pool:      pool,
+		SetIndex:  -1,
+		DiskIndex: -1,
+		Stats:     globalDriveStats.get(endpoint.Path).toDriveStats(),
+	}
+
+	disk, i, j := s.getConnectedDisk(endpoint)
+	if disk == nil {
+		return drive
+	}
+	drive.SetIndex, drive.DiskIndex = i, j
+	drive.UUID = s.format.XL.Sets[i][j]
+
+	if !disk.IsOnline() {
+		return drive
+	}
+
+	info, err := disk.DiskInfo()
+	if err != nil {
+		drive.Error = err.Error()
+		return drive
+	}
+
+	drive.State = madmin.DriveStateOk
+	drive.TotalSpace = info.Total
+	drive.UsedSpace = info.Used
+	drive.FreeSpace = info.Free
+	if info.Files > info.Ffree {
+		drive.UsedInodes = info.Files - info.Ffree
+	}
+	drive.FreeInodes = info.Ffree
+	return drive
+}
+
+// localEndpoints - returns endpoints of all drives local to this server.
+func (s *xlSets) localEndpoints() (endpoints []Endpoint) {
+	for _, endpoint := range s.endpoints {
+		if endpoint.IsLocal {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// getErasureZones - returns the erasure coded zones of objLayer.
+func getErasureZones(objLayer ObjectLayer) ([]*xlSets, error) {
+	switch obj := objLayer.(type) {
+	case *xlZones:
+		return obj.zones, nil
+	case *xlSets:
+		return []*xlSets{obj}, nil
+	}
+	return nil, errDrivesNotSupported
+}
+
+// getLocalServerDrives - returns details of all drives local to this server.
+func getLocalServerDrives(zones []*xlSets) []madmin.ServerDrive {
+	drives := []madmin.ServerDrive{}
+	for pool, zone := range zones {
+		for _, endpoint := range zone.localEndpoints() {
+			drives = append(drives, zone.serverDrive(pool, endpoint))
+		}
+	}
+	return drives
+}
+
+// localDriveSpeedtest - runs a speedtest of given size concurrently
+// on all drives local to this server.
+func localDriveSpeedtest(zones []*xlSets, size int64) []madmin.DriveSpeedtestResult {
+	var disks []StorageAPI
+	results := []madmin.DriveSpeedtestResult{}
+	for _, zone := range zones {
+		for _, endpoint := range zone.localEndpoints() {
+			disk, _, _ := zone.getConnectedDisk(endpoint)
+			disks = append(disks, disk)
+			results = append(results, madmin.DriveSpeedtestResult{
+				Endpoint: endpoint.String(),
+				Path:     endpoint.Path,
+				Size:     size,
+			})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range disks {
+		if disks[i] == nil || !disks[i].IsOnline() {
+			results[i].Error = errDiskNotFound.Error()
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			driveSpeedtest(disks[i], &results[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// driveSpeedtest - sequentially writes a temporary file of result.Size
+// bytes in the temporary volume of disk and syncs it to the drive,
+// recording the duration and throughput in result. The file is not read
+// back as reads would be served by the page cache.
+func driveSpeedtest(disk StorageAPI, result *madmin.DriveSpeedtestResult) {
+	info, err := disk.DiskInfo()
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if info.Free < uint64(result.Size) {
+		result.Error = errDiskFull.Error()
+		return
+	}
+
+	buf := make([]byte, driveSpeedtestBlockSize)
+	rand.Read(buf)
+
+	// Drive is local, the file is written directly to be synced.
+	tmpPath := pathJoin(result.Path, minioMetaTmpBucket, mustGetUUID())
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0666)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	defer os.Remove(tmpPath)
+	defer f.Close()
+
+	start := time.Now()
+	for written := int64(0); written < result.Size; {
+		n := result.Size - written
+		if n > driveSpeedtestBlockSize {
+			n = driveSpeedtestBlockSize
+		}
+		if _, err = f.Write(buf[:n]); err != nil {
+			result.Error = err.Error()
+			return
+		}
+		written += n
+	}
+	if err = f.Sync(); err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.WriteDuration = time.Since(start)
+	result.WriteThroughput = bytesPerSecond(result.Size, result.WriteDuration)
+}
+
+// bytesPerSecond - returns bytes per second of transferring size bytes in d.
+func bytesPerSecond(size int64, d time.Duration) uint64 {
+	if d <= 0 {
+		return 0
+	}
+	return uint64(float64(size) / d.Seconds())
+}
@@ -0,0 +1,134 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests reporting details of the local drives of all zones.
+func TestGetLocalServerDrives(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	if err := z.MakeBucketWithLocation(context.Background(), "bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Take one drive of the second zone offline.
+	offline := z.zones[1].xlDisks[0][3]
+	z.zones[1].xlDisks[0][3] = nil
+	defer func() {
+		z.zones[1].xlDisks[0][3] = offline
+	}()
+
+	zones, err := getErasureZones(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drives := getLocalServerDrives(zones)
+	if len(drives) != len(fsDirs) {
+		t.Fatalf("Expected %d drives, got %d", len(fsDirs), len(drives))
+	}
+
+	for i, drive := range drives {
+		pool, diskIndex := i/16, i%16
+		if drive.Path != fsDirs[i] {
+			t.Errorf("drive %d: expected path %s, got %s", i, fsDirs[i], drive.Path)
+		}
+		if drive.Pool != pool {
+			t.Errorf("drive %d: expected pool %d, got %d", i, pool, drive.Pool)
+		}
+		if pool == 1 && diskIndex == 3 {
+			if drive.State != madmin.DriveStateOffline || drive.SetIndex != -1 || drive.UUID != "" {
+				t.Errorf("drive %d: expected offline drive, got %#v", i, drive)
+			}
+			continue
+		}
+		if drive.State != madmin.DriveStateOk {
+			t.Errorf("drive %d: expected state %s, got %s", i, madmin.DriveStateOk, drive.State)
+		}
+		if drive.UUID != z.zones[pool].format.XL.Sets[drive.SetIndex][drive.DiskIndex] {
+			t.Errorf("drive %d: unexpected uuid %s", i, drive.UUID)
+		}
+		if drive.TotalSpace == 0 || drive.FreeSpace > drive.TotalSpace {
+			t.Errorf("drive %d: unexpected space total %d, free %d", i, drive.TotalSpace, drive.FreeSpace)
+		}
+		if drive.Stats.Calls == 0 {
+			t.Errorf("drive %d: expected calls to be recorded", i)
+		}
+	}
+
+	if _, err = getErasureZones(&DummyObjectLayer{}); err != errDrivesNotSupported {
+		t.Errorf("Expected %v, got %v", errDrivesNotSupported, err)
+	}
+}
+
+// Tests running a speedtest on the local drives.
+func TestLocalDriveSpeedtest(t *testing.T) {
+	objLayer, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	sets := objLayer.(*xlSets)
+	offline := sets.xlDisks[0][0]
+	sets.xlDisks[0][0] = nil
+	defer func() {
+		sets.xlDisks[0][0] = offline
+	}()
+
+	// Use a size which is not a multiple of the block size.
+	size := int64(driveSpeedtestBlockSize + 1)
+	results := localDriveSpeedtest([]*xlSets{sets}, size)
+	if len(results) != len(fsDirs) {
+		t.Fatalf("Expected %d results, got %d", len(fsDirs), len(results))
+	}
+
+	for i, result := range results {
+		if result.Path != fsDirs[i] || result.Size != size {
+			t.Errorf("result %d: unexpected %#v", i, result)
+		}
+		if i == 0 {
+			if result.Error != errDiskNotFound.Error() {
+				t.Errorf("result %d: expected error %v, got %s", i, errDiskNotFound, result.Error)
+			}
+			continue
+		}
+		if result.Error != "" {
+			t.Errorf("result %d: unexpected error %s", i, result.Error)
+		}
+		if result.WriteThroughput == 0 {
+			t.Errorf("result %d: expected non zero throughput, got %#v", i, result)
+		}
+
+		// Temporary file must be removed.
+		entries, err := ioutil.ReadDir(filepath.Join(fsDirs[i], minioMetaTmpBucket))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("result %d: expected no temporary files, found %d", i, len(entries))
+		}
+	}
+}
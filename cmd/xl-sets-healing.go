@@ -0,0 +1,218 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Tracker of healing a freshly formatted disk, saved on the disk itself.
+	healingTrackerFile    = "healing.json"
+	healingTrackerFileTmp = "healing.json.tmp"
+
+	// Current version of the healing tracker.
+	healingTrackerVersion = "1"
+
+	// Interval between saving the progress of healing a disk.
+	healingTrackerSaveInterval = 10 * time.Second
+)
+
+// healingTracker - progress of healing all the objects of an erasure
+// set onto a freshly formatted disk, saved on the disk such that
+// healing is resumed after a restart.
+type healingTracker struct {
+	Version string `json:"version"`
+	madmin.HealingDisk
+}
+
+// saveHealingTracker - saves the healing tracker on the disk.
+func saveHealingTracker(disk StorageAPI, tracker *healingTracker) error {
+	tracker.Version = healingTrackerVersion
+	tracker.LastUpdate = UTCNow()
+	trackerBytes, err := json.Marshal(tracker)
+	if err != nil {
+		return err
+	}
+
+	// Purge any existing temporary file, okay to ignore errors here.
+	defer disk.DeleteFile(minioMetaBucket, healingTrackerFileTmp)
+
+	if err = disk.AppendFile(minioMetaBucket, healingTrackerFileTmp, trackerBytes); err != nil {
+		return err
+	}
+	return disk.RenameFile(minioMetaBucket, healingTrackerFileTmp, minioMetaBucket, healingTrackerFile)
+}
+
+// loadHealingTracker - loads the healing tracker of the disk, returns
+// errFileNotFound if the disk is not being healed.
+func loadHealingTracker(disk StorageAPI) (*healingTracker, error) {
+	trackerBytes, err := disk.ReadAll(minioMetaBucket, healingTrackerFile)
+	if err != nil {
+		return nil, err
+	}
+	tracker := &healingTracker{}
+	if err = json.Unmarshal(trackerBytes, tracker); err != nil {
+		return nil, err
+	}
+	if tracker.Version != healingTrackerVersion {
+		return nil, errCorruptedFormat
+	}
+	return tracker, nil
+}
+
+// getHealingTrackers - returns the healing progress of the disks by
+// endpoint. Trackers are saved at most once per healingTrackerSaveInterval,
+// hence they are loaded from the disks at most as often.
+func (s *xlSets) getHealingTrackers(storageDisks []StorageAPI, sErrs []error) map[string]*madmin.HealingDisk {
+	s.healingTrackersMu.Lock()
+	defer s.healingTrackersMu.Unlock()
+	if s.healingTrackers != nil && time.Since(s.healingTrackersUpdated) < healingTrackerSaveInterval {
+		return s.healingTrackers
+	}
+
+	healingTrackers := make(map[string]*madmin.HealingDisk)
+	for index, disk := range storageDisks {
+		if disk == nil || sErrs[index] != nil {
+			continue
+		}
+		tracker, err := loadHealingTracker(disk)
+		if err != nil {
+			continue
+		}
+		healingTrackers[s.endpoints.GetString(index)] = &tracker.HealingDisk
+	}
+	s.healingTrackers, s.healingTrackersUpdated = healingTrackers, UTCNow()
+	return healingTrackers
+}
+
+// healFreshDisk - starts healing the objects of the set onto the disk at
+// the given position, if it was freshly formatted and it is not being
+// healed already. Only local disks are healed by the server.
+func (s *xlSets) healFreshDisk(endpoint Endpoint, setIndex, diskIndex int, disk StorageAPI) {
+	if !endpoint.IsLocal {
+		return
+	}
+	tracker, err := loadHealingTracker(disk)
+	if err != nil {
+		if err != errFileNotFound {
+			logger.LogIf(context.Background(), err)
+		}
+		return
+	}
+
+	s.healingMu.Lock()
+	defer s.healingMu.Unlock()
+	if s.healingDisks == nil {
+		s.healingDisks = make(map[string]struct{})
+	}
+	if _, ok := s.healingDisks[endpoint.String()]; ok {
+		return
+	}
+	s.healingDisks[endpoint.String()] = struct{}{}
+
+	tracker.Endpoint = endpoint.String()
+	tracker.SetIndex, tracker.DiskIndex = setIndex, diskIndex
+	go func() {
+		s.healDisk(context.Background(), disk, tracker, globalServiceDoneCh)
+
+		s.healingMu.Lock()
+		delete(s.healingDisks, endpoint.String())
+		s.healingMu.Unlock()
+	}()
+}
+
+// healDisk - heals all buckets and objects of the set of the tracker onto
+// the disk, resuming after the last object processed. The tracker is
+// removed from the disk once all the objects are processed, it is kept
+// if the objects cannot be listed such that healing is resumed later.
+func (s *xlSets) healDisk(ctx context.Context, disk StorageAPI, tracker *healingTracker, doneCh chan struct{}) {
+	set := s.sets[tracker.SetIndex]
+
+	buckets, _, err := listAllBuckets(set.getDisks())
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+	var bucketNames []string
+	for bucket := range buckets {
+		if bucket >= tracker.Bucket {
+			bucketNames = append(bucketNames, bucket)
+		}
+	}
+	sort.Strings(bucketNames)
+
+	lastSave := UTCNow()
+	for _, bucket := range bucketNames {
+		marker := ""
+		if bucket == tracker.Bucket {
+			marker = tracker.Object
+		} else {
+			if _, err = set.HealBucket(ctx, bucket, false); err != nil {
+				logger.LogIf(ctx, err)
+			}
+			tracker.Bucket, tracker.Object = bucket, ""
+		}
+
+		isLeaf := func(bucket, entry string) bool {
+			return set.isObject(bucket, strings.TrimSuffix(entry, slashSeparator))
+		}
+		listDir := listDirSetsFactory(ctx, isLeaf, set.isObjectDir, set.getLoadBalancedDisks())
+		endWalkCh := make(chan struct{})
+		walkResultCh := startTreeWalk(ctx, bucket, "", marker, true, listDir, isLeaf, set.isObjectDir, endWalkCh)
+		for walkResult := range walkResultCh {
+			select {
+			case <-doneCh:
+				close(endWalkCh)
+				saveHealingTracker(disk, tracker)
+				return
+			default:
+			}
+			if walkResult.err != nil {
+				close(endWalkCh)
+				logger.LogIf(ctx, walkResult.err)
+				logger.LogIf(ctx, saveHealingTracker(disk, tracker))
+				return
+			}
+
+			result, err := set.HealObject(ctx, bucket, walkResult.entry, false)
+			if err != nil {
+				tracker.ObjectsFailed++
+				tracker.BytesFailed += uint64(result.ObjectSize)
+			} else {
+				tracker.ObjectsHealed++
+				tracker.BytesDone += uint64(result.ObjectSize)
+			}
+			tracker.Object = walkResult.entry
+
+			if time.Since(lastSave) > healingTrackerSaveInterval {
+				logger.LogIf(ctx, saveHealingTracker(disk, tracker))
+				lastSave = UTCNow()
+			}
+		}
+		close(endWalkCh)
+	}
+
+	logger.LogIf(ctx, disk.DeleteFile(minioMetaBucket, healingTrackerFile))
+}
@@ -0,0 +1,200 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Prepares XL sets with objects, the first disk is replaced with
+// an empty one.
+func prepareXLSetsFreshDisk(t *testing.T) (ObjectLayer, []string, string, []string) {
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	objects := []string{"a", "b/c", "b/d/e", "f", "g/h", "i", "j/k/l", "m"}
+	for _, object := range objects {
+		data := bytes.Repeat([]byte(object), 1024)
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Replace the first disk with an empty one.
+	if err = os.RemoveAll(fsDirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(fsDirs[0], 0755); err != nil {
+		t.Fatal(err)
+	}
+	return obj, fsDirs, bucket, objects
+}
+
+// Waits for the healing of the first disk to complete and checks
+// that the objects of its set are healed.
+func waitXLSetsFreshDiskHealed(t *testing.T, obj ObjectLayer, fsDirs []string, bucket string, objects []string) {
+	// Tracker is removed once all the objects are healed.
+	trackerPath := filepath.Join(fsDirs[0], minioMetaBucket, healingTrackerFile)
+	for i := 0; ; i++ {
+		if _, err := os.Stat(trackerPath); os.IsNotExist(err) {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Expected healing of the disk to complete")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Only objects of the first set are healed onto the disk.
+	sets := obj.(*xlSets)
+	var healed int
+	for _, object := range objects {
+		if sets.getHashedSet(object) != sets.sets[0] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(fsDirs[0], bucket, object, xlMetaJSONFile)); err != nil {
+			t.Fatalf("Expected object %s to be healed: %v", object, err)
+		}
+		healed++
+	}
+	if healed == 0 {
+		t.Fatal("Expected objects in the first set")
+	}
+}
+
+// Tests healing objects onto a replaced disk after formatting it.
+func TestXLSetsHealFreshDisk(t *testing.T) {
+	obj, fsDirs, bucket, objects := prepareXLSetsFreshDisk(t)
+	defer removeRoots(fsDirs)
+
+	if _, err := obj.HealFormat(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	waitXLSetsFreshDiskHealed(t, obj, fsDirs, bucket, objects)
+}
+
+// Tests healing objects onto a local disk formatted by another server,
+// which is only seen by reloading the format.
+func TestXLSetsReloadFormatHealFreshDisk(t *testing.T) {
+	obj, fsDirs, bucket, objects := prepareXLSetsFreshDisk(t)
+	defer removeRoots(fsDirs)
+
+	// Format the disk and save the tracker as the server healing
+	// the format does.
+	format := *obj.(*xlSets).format
+	format.XL.This = format.XL.Sets[0][0]
+	disk, err := newPosix(fsDirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = initFormatXLMetaVolume([]StorageAPI{disk}, []*formatXLV3{&format}); err != nil {
+		t.Fatal(err)
+	}
+	if err = saveFormatXL(disk, &format); err != nil {
+		t.Fatal(err)
+	}
+	tracker := &healingTracker{
+		HealingDisk: madmin.HealingDisk{
+			ID:      format.XL.This,
+			Started: UTCNow(),
+		},
+	}
+	if err = saveHealingTracker(disk, tracker); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = obj.ReloadFormat(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	waitXLSetsFreshDiskHealed(t, obj, fsDirs, bucket, objects)
+}
+
+// Tests reporting the progress of healing a disk in storage info.
+func TestXLSetsHealingStorageInfo(t *testing.T) {
+	obj, fsDirs, err := prepareXL32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	disk, err := newPosix(fsDirs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := &healingTracker{
+		HealingDisk: madmin.HealingDisk{
+			ID:            mustGetUUID(),
+			Started:       UTCNow(),
+			ObjectsHealed: 10,
+			Bucket:        "bucket",
+			Object:        "object",
+		},
+	}
+	if err = saveHealingTracker(disk, tracker); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadHealingTracker(disk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ObjectsHealed != 10 || loaded.Object != "object" {
+		t.Fatalf("Unexpected healing tracker %#v", loaded)
+	}
+
+	healingDrives := func() (drives []madmin.DriveInfo) {
+		for _, set := range obj.StorageInfo(context.Background()).Backend.Sets {
+			for _, drive := range set {
+				if drive.Healing != nil {
+					drives = append(drives, drive)
+				}
+			}
+		}
+		return drives
+	}
+	drives := healingDrives()
+	if len(drives) != 1 || drives[0].Endpoint != fsDirs[1] || drives[0].Healing.ObjectsHealed != 10 {
+		t.Fatalf("Unexpected healing drives %#v", drives)
+	}
+
+	// Trackers are not loaded again until they may have been saved.
+	if err = disk.DeleteFile(minioMetaBucket, healingTrackerFile); err != nil {
+		t.Fatal(err)
+	}
+	if drives = healingDrives(); len(drives) != 1 {
+		t.Fatalf("Expected cached healing drive, got %#v", drives)
+	}
+	sets := obj.(*xlSets)
+	sets.healingTrackersUpdated = sets.healingTrackersUpdated.Add(-healingTrackerSaveInterval)
+	if drives = healingDrives(); len(drives) != 0 {
+		t.Fatalf("Expected no healing drives, got %#v", drives)
+	}
+}
@@ -78,31 +78,49 @@ type xlSets struct {
 
 	// Pack level listObjects pool management.
 	listPool *treeWalkPool
+
+	// Endpoints of local disks being healed after being formatted.
+	healingMu    sync.Mutex
+	healingDisks map[string]struct{}
+
+	// Healing progress of the disks by endpoint, reloaded from
+	// the disks at most once per healingTrackerSaveInterval.
+	healingTrackersMu      sync.Mutex
+	healingTrackers        map[string]*madmin.HealingDisk
+	healingTrackersUpdated time.Time
 }
 
-// isConnected - checks if the endpoint is connected or not.
-func (s *xlSets) isConnected(endpoint Endpoint) bool {
+// getConnectedDisk - returns the disk connected for the endpoint along
+// with its set and disk index, disk is nil if the endpoint is not connected.
+func (s *xlSets) getConnectedDisk(endpoint Endpoint) (disk StorageAPI, setIndex, diskIndex int) {
 	s.xlDisksMu.RLock()
 	defer s.xlDisksMu.RUnlock()
 
+	var endpointStr string
+	if endpoint.IsLocal {
+		endpointStr = endpoint.Path
+	} else {
+		endpointStr = endpoint.String()
+	}
+
 	for i := 0; i < s.setCount; i++ {
 		for j := 0; j < s.drivesPerSet; j++ {
 			if s.xlDisks[i][j] == nil {
 				continue
 			}
-			var endpointStr string
-			if endpoint.IsLocal {
-				endpointStr = endpoint.Path
-			} else {
-				endpointStr = endpoint.String()
-			}
 			if s.xlDisks[i][j].String() != endpointStr {
 				continue
 			}
-			return s.xlDisks[i][j].IsOnline()
+			return s.xlDisks[i][j], i, j
 		}
 	}
-	return false
+	return nil, -1, -1
+}
+
+// isConnected - checks if the endpoint is connected or not.
+func (s *xlSets) isConnected(endpoint Endpoint) bool {
+	disk, _, _ := s.getConnectedDisk(endpoint)
+	return disk != nil && disk.IsOnline()
 }
 
 // Initializes a new StorageAPI from the endpoint argument, returns
@@ -190,6 +208,9 @@ func (s *xlSets) connectDisks() {
 		s.xlDisksMu.Lock()
 		s.xlDisks[i][j] = disk
 		s.xlDisksMu.Unlock()
+
+		// Populate the disk if it was freshly formatted.
+		s.healFreshDisk(endpoint, i, j, disk)
 	}
 }
 
@@ -227,7 +248,7 @@ func (s *xlSets) GetDisks(setIndex int) func() []StorageAPI {
 const defaultMonitorConnectEndpointInterval = time.Second * 10 // Set to 10 secs.
 
 // Initialize new set of erasure coded sets.
-func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesPerSet int) (ObjectLayer, error) {
+func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesPerSet int) (*xlSets, error) {
 
 	// Initialize the XL sets instance.
 	s := &xlSets{
@@ -260,6 +281,9 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 		go s.sets[i].cleanupStaleMultipartUploads(context.Background(), globalMultipartCleanupInterval, globalMultipartExpiry, globalServiceDoneCh)
 	}
 
+	// Start the cleanup of abandoned resumable listings.
+	go cleanupStaleListWalks(context.Background(), s, listWalkCleanupInterval, listWalkExpiry, globalServiceDoneCh)
+
 	// Connect disks right away.
 	s.connectDisks()
 
@@ -269,6 +293,23 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 	return s, nil
 }
 
+// availableSpace - returns free space across all online disks of all sets.
+func (s *xlSets) availableSpace() (available uint64) {
+	for _, set := range s.sets {
+		for _, disk := range set.getDisks() {
+			if disk == nil {
+				continue
+			}
+			info, err := disk.DiskInfo()
+			if err != nil {
+				continue
+			}
+			available += info.Free
+		}
+	}
+	return available
+}
+
 // StorageInfo - combines output of StorageInfo across all erasure coded object sets.
 func (s *xlSets) StorageInfo(ctx context.Context) StorageInfo {
 	var storageInfo StorageInfo
@@ -323,6 +364,16 @@ func (s *xlSets) StorageInfo(ctx context.Context) StorageInfo {
 		}
 	}
 
+	// fill the progress of the disks being healed.
+	healingTrackers := s.getHealingTrackers(storageDisks, sErrs)
+	for i := range storageInfo.Backend.Sets {
+		for j := range storageInfo.Backend.Sets[i] {
+			if tracker, ok := healingTrackers[storageInfo.Backend.Sets[i][j].Endpoint]; ok {
+				storageInfo.Backend.Sets[i][j].Healing = tracker
+			}
+		}
+	}
+
 	// fill all the offline, missing endpoints as well.
 	for _, drive := range drivesInfo {
 		if drive.UUID == "" {
@@ -443,26 +494,12 @@ func (s *xlSets) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo B
 	return s.getHashedSet(bucket).GetBucketInfo(ctx, bucket)
 }
 
-// ListObjectsV2 lists all objects in bucket filtered by prefix
+// ListObjectsV2 lists all objects in bucket filtered by prefix, the
+// continuation token holds the state of the tree walk such that the
+// listing is resumed on any server without walking the tree again.
 func (s *xlSets) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
-	marker := continuationToken
-	if marker == "" {
-		marker = startAfter
-	}
-
-	loi, err := s.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
-	if err != nil {
-		return result, err
-	}
-
-	listObjectsV2Info := ListObjectsV2Info{
-		IsTruncated:           loi.IsTruncated,
-		ContinuationToken:     continuationToken,
-		NextContinuationToken: loi.NextMarker,
-		Objects:               loi.Objects,
-		Prefixes:              loi.Prefixes,
-	}
-	return listObjectsV2Info, err
+	listDir, isLeaf, isLeafDir := s.listFuncs(ctx)
+	return listObjectsV2Resumable(ctx, s, bucket, prefix, continuationToken, delimiter, maxKeys, startAfter, listDir, isLeaf, isLeafDir, s.getWalkEntryInfo)
 }
 
 // SetBucketPolicy persist the new policy on the bucket.
@@ -689,6 +726,51 @@ func listDirSetsFactory(ctx context.Context, isLeaf isLeafFunc, isLeafDir isLeaf
 	return listDir
 }
 
+// listFuncs - returns the functions used by tree walks to list the
+// entries of all sets and to check whether an entry is a leaf.
+func (s *xlSets) listFuncs(ctx context.Context) (listDirFunc, isLeafFunc, isLeafDirFunc) {
+	isLeaf := func(bucket, entry string) bool {
+		entry = strings.TrimSuffix(entry, slashSeparator)
+		// Verify if we are at the leaf, a leaf is where we
+		// see `xl.json` inside a directory.
+		return s.getHashedSet(entry).isObject(bucket, entry)
+	}
+
+	isLeafDir := func(bucket, entry string) bool {
+		// Verify prefixes in all sets.
+		var ok bool
+		for _, set := range s.sets {
+			ok = set.isObjectDir(bucket, entry)
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var setDisks = make([][]StorageAPI, len(s.sets))
+	for _, set := range s.sets {
+		setDisks = append(setDisks, set.getLoadBalancedDisks())
+	}
+
+	return listDirSetsFactory(ctx, isLeaf, isLeafDir, setDisks...), isLeaf, isLeafDir
+}
+
+// getWalkEntryInfo - returns the object info of an entry found by a tree walk.
+func (s *xlSets) getWalkEntryInfo(ctx context.Context, bucket, entry string) (objInfo ObjectInfo, err error) {
+	if hasSuffix(entry, slashSeparator) {
+		// Verify prefixes in all sets.
+		for _, set := range s.sets {
+			objInfo, err = set.getObjectInfoDir(ctx, bucket, entry)
+			if err == nil {
+				break
+			}
+		}
+		return objInfo, err
+	}
+	return s.getHashedSet(entry).getObjectInfo(ctx, bucket, entry)
+}
+
 // ListObjects - implements listing of objects across sets, each set is independently
 // listed and subsequently merge lexically sorted inside listDirSetsFactory(). Resulting
 // value through the walk channel receives the data properly lexically sorted.
@@ -711,31 +793,7 @@ func (s *xlSets) ListObjects(ctx context.Context, bucket, prefix, marker, delimi
 	walkResultCh, endWalkCh := s.listPool.Release(listParams{bucket, recursive, marker, prefix, false})
 	if walkResultCh == nil {
 		endWalkCh = make(chan struct{})
-		isLeaf := func(bucket, entry string) bool {
-			entry = strings.TrimSuffix(entry, slashSeparator)
-			// Verify if we are at the leaf, a leaf is where we
-			// see `xl.json` inside a directory.
-			return s.getHashedSet(entry).isObject(bucket, entry)
-		}
-
-		isLeafDir := func(bucket, entry string) bool {
-			// Verify prefixes in all sets.
-			var ok bool
-			for _, set := range s.sets {
-				ok = set.isObjectDir(bucket, entry)
-				if ok {
-					return true
-				}
-			}
-			return false
-		}
-
-		var setDisks = make([][]StorageAPI, len(s.sets))
-		for _, set := range s.sets {
-			setDisks = append(setDisks, set.getLoadBalancedDisks())
-		}
-
-		listDir := listDirSetsFactory(ctx, isLeaf, isLeafDir, setDisks...)
+		listDir, isLeaf, isLeafDir := s.listFuncs(ctx)
 		walkResultCh = startTreeWalk(ctx, bucket, prefix, marker, recursive, listDir, isLeaf, isLeafDir, endWalkCh)
 	}
 
@@ -752,19 +810,7 @@ func (s *xlSets) ListObjects(ctx context.Context, bucket, prefix, marker, delimi
 			return result, toObjectErr(walkResult.err, bucket, prefix)
 		}
 
-		var objInfo ObjectInfo
-		var err error
-		if hasSuffix(walkResult.entry, slashSeparator) {
-			// Verify prefixes in all sets.
-			for _, set := range s.sets {
-				objInfo, err = set.getObjectInfoDir(ctx, bucket, walkResult.entry)
-				if err == nil {
-					break
-				}
-			}
-		} else {
-			objInfo, err = s.getHashedSet(walkResult.entry).getObjectInfo(ctx, bucket, walkResult.entry)
-		}
+		objInfo, err := s.getWalkEntryInfo(ctx, bucket, walkResult.entry)
 		if err != nil {
 			// Ignore errFileNotFound as the object might have got
 			// deleted in the interim period of listing and getObjectInfo(),
@@ -1004,6 +1050,19 @@ func (s *xlSets) ReloadFormat(ctx context.Context, dryRun bool) (err error) {
 	}
 	s.xlDisksMu.Unlock()
 
+	// Start healing the objects onto the local disks which were
+	// freshly formatted by the server healing the format.
+	for index, format := range formats {
+		if storageDisks[index] == nil || format == nil {
+			continue
+		}
+		i, j, ferr := findDiskIndex(refFormat, format)
+		if ferr != nil {
+			continue
+		}
+		s.healFreshDisk(s.endpoints[index], i, j, storageDisks[index])
+	}
+
 	// Restart monitoring loop to monitor reformatted disks again.
 	go s.monitorAndConnectEndpoints(defaultMonitorConnectEndpointInterval)
 
@@ -1147,6 +1206,21 @@ func (s *xlSets) HealFormat(ctx context.Context, dryRun bool) (res madmin.HealRe
 			return madmin.HealResultItem{}, err
 		}
 
+		// Track healing of the objects onto the newly formatted disks.
+		for index, format := range tmpNewFormats {
+			if format == nil || sErrs[index] != errUnformattedDisk {
+				continue
+			}
+			tracker := &healingTracker{
+				HealingDisk: madmin.HealingDisk{
+					ID:       format.XL.This,
+					Endpoint: s.endpoints.GetString(index),
+					Started:  UTCNow(),
+				},
+			}
+			logger.LogIf(ctx, saveHealingTracker(storageDisks[index], tracker))
+		}
+
 		// kill the monitoring loop such that we stop writing
 		// to indicate that we will re-initialize everything
 		// with new format.
@@ -1165,6 +1239,14 @@ func (s *xlSets) HealFormat(ctx context.Context, dryRun bool) (res madmin.HealRe
 		}
 		s.xlDisksMu.Unlock()
 
+		// Start healing the objects onto the newly formatted disks.
+		for index, format := range tmpNewFormats {
+			if format == nil || sErrs[index] != errUnformattedDisk {
+				continue
+			}
+			s.healFreshDisk(s.endpoints[index], index/s.drivesPerSet, index%s.drivesPerSet, storageDisks[index])
+		}
+
 		// Restart our monitoring loop to start monitoring newly formatted disks.
 		go s.monitorAndConnectEndpoints(defaultMonitorConnectEndpointInterval)
 	}
@@ -721,7 +721,7 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 
 	// Save the final object size and modtime.
 	xlMeta.Stat.Size = objectSize
-	xlMeta.Stat.ModTime = UTCNow()
+	xlMeta.Stat.ModTime = popModTime(xlMeta.Meta)
 
 	// Save successfully calculated md5sum.
 	xlMeta.Meta["etag"] = s3MD5
@@ -746,7 +746,7 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 	}
 
 	// Save additional erasureMetadata.
-	modTime := UTCNow()
+	modTime := popModTime(metadata)
 	metadata["etag"] = hex.EncodeToString(data.MD5Current())
 
 	// Guess content-type from the extension if possible.
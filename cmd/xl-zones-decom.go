@@ -0,0 +1,630 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Status of all zones, saved on every zone in minioMetaBucket.
+	poolMetaFile = "pool.json"
+
+	poolMetaVersion1 = 1
+	poolMetaVersion  = poolMetaVersion1
+
+	// Internal metadata carrying the modification time of a moved
+	// object, it is consumed by the zone writing the object.
+	xlModTimeKey = ReservedMetadataPrefix + "Mod-Time"
+)
+
+var (
+	errNoSuchPool                 = errors.New("Server pool does not exist")
+	errDecommissionAlreadyRunning = errors.New("Server pool is already being decommissioned")
+	errDecommissionNotRunning     = errors.New("Server pool is not being decommissioned")
+	errDecommissionLastPool       = errors.New("Last active server pool cannot be decommissioned")
+)
+
+// poolMeta - status of all zones, zones are identified by their command
+// line argument as their order may change when a zone is removed.
+type poolMeta struct {
+	Version int                 `json:"version"`
+	Pools   []madmin.PoolStatus `json:"pools"`
+}
+
+// clone - returns a deep copy of pool meta.
+func (p poolMeta) clone() poolMeta {
+	meta := poolMeta{Version: p.Version, Pools: make([]madmin.PoolStatus, len(p.Pools))}
+	for i, pool := range p.Pools {
+		if pool.Decommission != nil {
+			info := *pool.Decommission
+			pool.Decommission = &info
+		}
+		meta.Pools[i] = pool
+	}
+	return meta
+}
+
+// isDraining - returns true if the pool does not receive new objects,
+// which holds from the start of a decommission until it is canceled.
+func (p poolMeta) isDraining(idx int) bool {
+	if idx >= len(p.Pools) {
+		return false
+	}
+	info := p.Pools[idx].Decommission
+	return info != nil && !info.Canceled
+}
+
+// isDecommissionRunning - returns true if objects are still being moved
+// off the pool.
+func (p poolMeta) isDecommissionRunning(idx int) bool {
+	if idx >= len(p.Pools) {
+		return false
+	}
+	info := p.Pools[idx].Decommission
+	return info != nil && !info.Canceled && !info.Complete && !info.Failed
+}
+
+// isDraining - returns true if the zone is being decommissioned.
+func (z *xlZones) isDraining(idx int) bool {
+	z.poolMetaMutex.RLock()
+	defer z.poolMetaMutex.RUnlock()
+	return z.poolMeta.isDraining(idx)
+}
+
+// getPoolIdx - returns index of the zone with given command line argument.
+func (z *xlZones) getPoolIdx(cmdLine string) (int, error) {
+	for i, zone := range z.endpointZones {
+		if zone.CmdLine == cmdLine {
+			return i, nil
+		}
+	}
+	return -1, errNoSuchPool
+}
+
+// readPoolMeta - reads pool meta from all zones and returns the most
+// recently updated one.
+func (z *xlZones) readPoolMeta(ctx context.Context) (poolMeta, error) {
+	latest := poolMeta{Version: poolMetaVersion}
+	var latestUpdate int64
+	for _, zone := range z.zones {
+		var buffer bytes.Buffer
+		if err := zone.GetObject(ctx, minioMetaBucket, poolMetaFile, 0, -1, &buffer, ""); err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return latest, err
+		}
+
+		var meta poolMeta
+		if err := json.Unmarshal(buffer.Bytes(), &meta); err != nil {
+			return latest, err
+		}
+		if meta.Version != poolMetaVersion1 {
+			return latest, fmt.Errorf("Unknown pool meta version %d", meta.Version)
+		}
+
+		for _, pool := range meta.Pools {
+			if update := pool.LastUpdate.UnixNano(); update > latestUpdate {
+				latest, latestUpdate = meta, update
+			}
+		}
+	}
+	return latest, nil
+}
+
+// savePoolMeta - saves pool meta on all zones.
+func (z *xlZones) savePoolMeta(ctx context.Context, meta poolMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	for _, zone := range z.zones {
+		hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", getSHA256Hash(data))
+		if err != nil {
+			return err
+		}
+		if _, err = zone.PutObject(ctx, minioMetaBucket, poolMetaFile, hashReader, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergePoolMeta - matches saved pool meta with the current zones, a zone
+// may only be removed from the command line after its decommission
+// completed.
+func (z *xlZones) mergePoolMeta(saved poolMeta) (meta poolMeta, changed bool, err error) {
+	meta = poolMeta{Version: poolMetaVersion, Pools: make([]madmin.PoolStatus, len(z.endpointZones))}
+	found := make(map[string]bool)
+	for i, zone := range z.endpointZones {
+		meta.Pools[i] = madmin.PoolStatus{ID: i, CmdLine: zone.CmdLine, LastUpdate: UTCNow()}
+		zoneChanged := true
+		for _, pool := range saved.Pools {
+			if pool.CmdLine != zone.CmdLine {
+				continue
+			}
+			found[pool.CmdLine] = true
+			zoneChanged = pool.ID != i
+			pool.ID = i
+			meta.Pools[i] = pool
+		}
+		changed = changed || zoneChanged
+	}
+
+	for _, pool := range saved.Pools {
+		if found[pool.CmdLine] {
+			continue
+		}
+		if pool.Decommission != nil && !pool.Decommission.Complete {
+			return meta, false, fmt.Errorf("Server pool %s was removed before its decommission completed", pool.CmdLine)
+		}
+		changed = true
+	}
+
+	return meta.clone(), changed, nil
+}
+
+// initPoolMeta - loads status of all zones and resumes pending
+// decommissions.
+func (z *xlZones) initPoolMeta(ctx context.Context) error {
+	saved, err := z.readPoolMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	meta, changed, err := z.mergePoolMeta(saved)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if err = z.savePoolMeta(ctx, meta); err != nil {
+			return err
+		}
+	}
+
+	z.poolMetaMutex.Lock()
+	z.poolMeta = meta
+	z.poolMetaMutex.Unlock()
+
+	z.resumeDecommission()
+	return nil
+}
+
+// reloadPoolMeta - reloads status of all zones after it was updated by
+// another server.
+func (z *xlZones) reloadPoolMeta(ctx context.Context) error {
+	saved, err := z.readPoolMeta(ctx)
+	if err != nil {
+		return err
+	}
+
+	meta, _, err := z.mergePoolMeta(saved)
+	if err != nil {
+		return err
+	}
+
+	z.poolMetaMutex.Lock()
+	z.poolMeta = meta
+	z.poolMetaMutex.Unlock()
+
+	z.resumeDecommission()
+	return nil
+}
+
+// resumeDecommission - starts moving objects off the zones being
+// decommissioned and stops canceled ones. Objects of a zone are moved
+// only by the server holding the first disk of the zone.
+func (z *xlZones) resumeDecommission() {
+	z.poolMetaMutex.Lock()
+	defer z.poolMetaMutex.Unlock()
+
+	for idx := range z.zones {
+		if !z.poolMeta.isDecommissionRunning(idx) {
+			if cancel := z.decommissionCancelers[idx]; cancel != nil {
+				cancel()
+				z.decommissionCancelers[idx] = nil
+			}
+			continue
+		}
+
+		if z.decommissionCancelers[idx] != nil || !z.endpointZones[idx].Endpoints[0].IsLocal {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		z.decommissionCancelers[idx] = cancel
+		go z.decommissionPool(ctx, idx)
+	}
+}
+
+// ListPools - returns status of all zones.
+func (z *xlZones) ListPools(ctx context.Context) ([]madmin.PoolStatus, error) {
+	// Status is read from disk, as objects may be moved by another server.
+	saved, err := z.readPoolMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, _, err := z.mergePoolMeta(saved)
+	if err != nil {
+		return nil, err
+	}
+	return meta.Pools, nil
+}
+
+// StatusPool - returns status of the zone with given command line argument.
+func (z *xlZones) StatusPool(ctx context.Context, cmdLine string) (madmin.PoolStatus, error) {
+	idx, err := z.getPoolIdx(cmdLine)
+	if err != nil {
+		return madmin.PoolStatus{}, err
+	}
+
+	pools, err := z.ListPools(ctx)
+	if err != nil {
+		return madmin.PoolStatus{}, err
+	}
+	return pools[idx], nil
+}
+
+// StartDecommission - stops writing new objects to the zone with given
+// command line argument and starts moving its objects to other zones.
+func (z *xlZones) StartDecommission(ctx context.Context, cmdLine string) error {
+	idx, err := z.getPoolIdx(cmdLine)
+	if err != nil {
+		return err
+	}
+
+	z.poolMetaMutex.Lock()
+	if z.poolMeta.isDecommissionRunning(idx) {
+		z.poolMetaMutex.Unlock()
+		return errDecommissionAlreadyRunning
+	}
+
+	var activePools int
+	for i := range z.zones {
+		if i != idx && !z.poolMeta.isDraining(i) {
+			activePools++
+		}
+	}
+	if activePools == 0 {
+		z.poolMetaMutex.Unlock()
+		return errDecommissionLastPool
+	}
+
+	meta := z.poolMeta.clone()
+	meta.Pools[idx].LastUpdate = UTCNow()
+	meta.Pools[idx].Decommission = &madmin.PoolDecommissionInfo{StartTime: UTCNow()}
+	if err = z.savePoolMeta(ctx, meta); err != nil {
+		z.poolMetaMutex.Unlock()
+		return err
+	}
+	z.poolMeta = meta
+	z.poolMetaMutex.Unlock()
+
+	// globalNotificationSys is not initialized in some tests.
+	if globalNotificationSys != nil {
+		globalNotificationSys.ReloadPoolMeta(ctx)
+	}
+	z.resumeDecommission()
+	return nil
+}
+
+// CancelDecommission - stops moving objects off the zone with given
+// command line argument, the zone receives new objects again.
+func (z *xlZones) CancelDecommission(ctx context.Context, cmdLine string) error {
+	idx, err := z.getPoolIdx(cmdLine)
+	if err != nil {
+		return err
+	}
+
+	if err = z.updateDecommission(ctx, idx, func(info *madmin.PoolDecommissionInfo) error {
+		if info.Canceled || info.Complete || info.Failed {
+			return errDecommissionNotRunning
+		}
+		info.Canceled = true
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// globalNotificationSys is not initialized in some tests.
+	if globalNotificationSys != nil {
+		globalNotificationSys.ReloadPoolMeta(ctx)
+	}
+	z.resumeDecommission()
+	return nil
+}
+
+// updateDecommission - updates decommission status of the zone and saves it.
+func (z *xlZones) updateDecommission(ctx context.Context, idx int, update func(info *madmin.PoolDecommissionInfo) error) error {
+	z.poolMetaMutex.Lock()
+	defer z.poolMetaMutex.Unlock()
+
+	meta := z.poolMeta.clone()
+	info := meta.Pools[idx].Decommission
+	if info == nil {
+		return errDecommissionNotRunning
+	}
+	if err := update(info); err != nil {
+		return err
+	}
+	meta.Pools[idx].LastUpdate = UTCNow()
+
+	if err := z.savePoolMeta(ctx, meta); err != nil {
+		return err
+	}
+	z.poolMeta = meta
+	return nil
+}
+
+// decommissionPool - moves all objects off the zone and records whether
+// the decommission completed or failed.
+func (z *xlZones) decommissionPool(ctx context.Context, idx int) {
+	err := z.decommissionObjects(ctx, idx)
+
+	// Decommission was canceled or the server is shutting down.
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		logger.GetReqInfo(ctx).AppendTags("pool", z.endpointZones[idx].CmdLine)
+		logger.LogIf(ctx, err)
+	}
+
+	if uerr := z.updateDecommission(ctx, idx, func(info *madmin.PoolDecommissionInfo) error {
+		info.Failed = err != nil || info.ObjectsFailed > 0
+		info.Complete = !info.Failed
+		return nil
+	}); uerr != nil {
+		logger.LogIf(ctx, uerr)
+	}
+
+	z.poolMetaMutex.Lock()
+	if cancel := z.decommissionCancelers[idx]; cancel != nil {
+		cancel()
+		z.decommissionCancelers[idx] = nil
+	}
+	z.poolMetaMutex.Unlock()
+}
+
+// decommissionObjects - moves objects of all buckets, bucket metadata and
+// server configuration off the zone. Progress is saved after every
+// listed page of objects, a restarted server resumes after the last
+// listed object.
+func (z *xlZones) decommissionObjects(ctx context.Context, idx int) error {
+	zone := z.zones[idx]
+
+	buckets, err := zone.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Sort(byBucketName(buckets))
+
+	type listTarget struct {
+		bucket, prefix string
+	}
+
+	var targets []listTarget
+	for _, bucket := range buckets {
+		targets = append(targets, listTarget{bucket.Name, ""})
+	}
+	targets = append(targets,
+		listTarget{minioMetaBucket, bucketConfigPrefix + slashSeparator},
+		listTarget{minioMetaBucket, minioConfigPrefix + slashSeparator},
+	)
+
+	z.poolMetaMutex.RLock()
+	progress := *z.poolMeta.Pools[idx].Decommission
+	z.poolMetaMutex.RUnlock()
+
+	// Skip targets moved before the server was restarted.
+	var marker string
+	for i, target := range targets {
+		if target.bucket == progress.Bucket && target.prefix == progress.Prefix {
+			targets = targets[i:]
+			marker = progress.Object
+			break
+		}
+	}
+
+	for _, target := range targets {
+		for {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+
+			result, lerr := zone.ListObjects(ctx, target.bucket, target.prefix, marker, "", maxObjectList)
+			if lerr != nil {
+				// Bucket was removed in the meantime.
+				if _, ok := lerr.(BucketNotFound); ok {
+					break
+				}
+				return lerr
+			}
+
+			var moved, movedBytes, failed int64
+			for _, objInfo := range result.Objects {
+				if err = z.moveObject(ctx, idx, target.bucket, objInfo.Name); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("object", pathJoin(target.bucket, objInfo.Name))
+					logger.LogIf(ctx, err)
+					failed++
+					continue
+				}
+				moved++
+				movedBytes += objInfo.Size
+			}
+
+			if len(result.Objects) > 0 {
+				marker = result.Objects[len(result.Objects)-1].Name
+			}
+
+			if err = z.updateDecommission(ctx, idx, func(info *madmin.PoolDecommissionInfo) error {
+				info.Bucket, info.Prefix, info.Object = target.bucket, target.prefix, marker
+				info.ObjectsMoved += moved
+				info.BytesMoved += movedBytes
+				info.ObjectsFailed += failed
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if !result.IsTruncated {
+				break
+			}
+		}
+		marker = ""
+	}
+
+	return nil
+}
+
+// popModTime - removes and returns the modification time carried by
+// metadata of a moved object, current time is returned otherwise.
+func popModTime(metadata map[string]string) time.Time {
+	value, ok := metadata[xlModTimeKey]
+	if !ok {
+		return UTCNow()
+	}
+	delete(metadata, xlModTimeKey)
+	modTime, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return UTCNow()
+	}
+	return modTime
+}
+
+// moveObject - moves an object off the zone to the zone with most free
+// space, metadata, modification time and part layout of multipart
+// objects are preserved so that the object keeps its ETag.
+func (z *xlZones) moveObject(ctx context.Context, srcIdx int, bucket, object string) error {
+	objectLock := z.newObjectLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	return z.moveObjectLocked(ctx, srcIdx, bucket, object)
+}
+
+// moveObjectLocked - moves an object off the zone, the caller holds the
+// zone lock of the object.
+func (z *xlZones) moveObjectLocked(ctx context.Context, srcIdx int, bucket, object string) error {
+	srcZone := z.zones[srcIdx]
+	objInfo, err := srcZone.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		// Object was removed in the meantime.
+		if isErrObjectNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Object was overwritten on another zone in the meantime, or it was
+	// copied before the server restarted, only the stale copy is removed.
+	for i, zone := range z.zones {
+		if i == srcIdx {
+			continue
+		}
+		if info, err := zone.GetObjectInfo(ctx, bucket, object); err == nil && !info.ModTime.Before(objInfo.ModTime) {
+			return srcZone.DeleteObject(ctx, bucket, object)
+		}
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined)+1)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	metadata[xlModTimeKey] = objInfo.ModTime.UTC().Format(time.RFC3339Nano)
+
+	destZone := z.zones[z.getAvailableZoneIdx(ctx)]
+	if len(objInfo.Parts) > 1 {
+		err = moveMultipartObject(ctx, srcZone, destZone, bucket, object, objInfo, metadata)
+	} else {
+		err = streamObject(ctx, srcZone, bucket, object, 0, objInfo.Size, objInfo.ETag, func(reader *hash.Reader) error {
+			_, perr := destZone.PutObject(ctx, bucket, object, reader, metadata)
+			return perr
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	return srcZone.DeleteObject(ctx, bucket, object)
+}
+
+// moveMultipartObject - copies an object part by part to another zone.
+func moveMultipartObject(ctx context.Context, srcZone, destZone *xlSets, bucket, object string, objInfo ObjectInfo, metadata map[string]string) error {
+	uploadID, err := destZone.NewMultipartUpload(ctx, bucket, object, metadata)
+	if err != nil {
+		return err
+	}
+
+	var parts []CompletePart
+	var offset int64
+	for _, part := range objInfo.Parts {
+		err = streamObject(ctx, srcZone, bucket, object, offset, part.Size, objInfo.ETag, func(reader *hash.Reader) error {
+			partInfo, perr := destZone.PutObjectPart(ctx, bucket, object, uploadID, part.Number, reader)
+			if perr != nil {
+				return perr
+			}
+			parts = append(parts, CompletePart{PartNumber: partInfo.PartNumber, ETag: partInfo.ETag})
+			return nil
+		})
+		if err != nil {
+			logger.LogIf(ctx, destZone.AbortMultipartUpload(ctx, bucket, object, uploadID))
+			return err
+		}
+		offset += part.Size
+	}
+
+	if _, err = destZone.CompleteMultipartUpload(ctx, bucket, object, uploadID, parts); err != nil {
+		logger.LogIf(ctx, destZone.AbortMultipartUpload(ctx, bucket, object, uploadID))
+		return err
+	}
+	return nil
+}
+
+// streamObject - streams a range of an object to the consumer.
+func streamObject(ctx context.Context, zone *xlSets, bucket, object string, offset, length int64, etag string, consumer func(*hash.Reader) error) error {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		pipeWriter.CloseWithError(zone.GetObject(ctx, bucket, object, offset, length, pipeWriter, etag))
+	}()
+	// Unblocks the writer if the consumer returns early.
+	defer pipeReader.Close()
+
+	reader, err := hash.NewReader(pipeReader, length, "", "")
+	if err != nil {
+		return err
+	}
+	return consumer(reader)
+}
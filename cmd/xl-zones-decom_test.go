@@ -0,0 +1,159 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Tests matching saved pool status with the zones on the command line.
+func TestMergePoolMeta(t *testing.T) {
+	z := &xlZones{endpointZones: EndpointZones{{CmdLine: "zone1"}, {CmdLine: "zone2"}}}
+
+	meta, changed, err := z.mergePoolMeta(poolMeta{Version: poolMetaVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(meta.Pools) != 2 || meta.Pools[1].CmdLine != "zone2" {
+		t.Fatalf("Unexpected pool meta %v", meta)
+	}
+
+	// Unchanged zones need not be saved again.
+	if _, changed, err = z.mergePoolMeta(meta); err != nil || changed {
+		t.Fatalf("Expected unchanged pool meta, got %v, %v", changed, err)
+	}
+
+	// A decommissioned zone may be removed.
+	saved := meta.clone()
+	saved.Pools = append(saved.Pools, madmin.PoolStatus{
+		ID:           2,
+		CmdLine:      "zone3",
+		Decommission: &madmin.PoolDecommissionInfo{Complete: true},
+	})
+	if meta, changed, err = z.mergePoolMeta(saved); err != nil || !changed || len(meta.Pools) != 2 {
+		t.Fatalf("Unexpected result %v, %v, %v", meta, changed, err)
+	}
+
+	// A zone being decommissioned must not be removed.
+	saved.Pools[2].Decommission.Complete = false
+	if _, _, err = z.mergePoolMeta(saved); err == nil {
+		t.Fatal("Expected removing a zone being decommissioned to fail")
+	}
+}
+
+// Tests moving all objects off a decommissioned zone.
+func TestXLZonesDecommission(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Place a regular and a multipart object on the second zone.
+	data := bytes.Repeat([]byte("a"), 5*humanize.MiByte)
+	zone := z.zones[1]
+	objInfo, err := zone.PutObject(ctx, bucket, "object", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""),
+		map[string]string{"content-type": "application/json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uploadID, err := zone.NewMultipartUpload(ctx, bucket, "multipart", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []CompletePart
+	for i := 1; i <= 2; i++ {
+		partInfo, perr := zone.PutObjectPart(ctx, bucket, "multipart", uploadID, i, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""))
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		parts = append(parts, CompletePart{PartNumber: i, ETag: partInfo.ETag})
+	}
+	multipartInfo, err := zone.CompleteMultipartUpload(ctx, bucket, "multipart", uploadID, parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = z.StartDecommission(ctx, "zone3"); err != errNoSuchPool {
+		t.Fatalf("Expected %v, got %v", errNoSuchPool, err)
+	}
+	if err = z.StartDecommission(ctx, "zone2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = z.StartDecommission(ctx, "zone1"); err != errDecommissionLastPool {
+		t.Fatalf("Expected %v, got %v", errDecommissionLastPool, err)
+	}
+
+	// New objects must not be written to the draining zone.
+	if _, err = z.PutObject(ctx, bucket, "new-object", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.zones[0].GetObjectInfo(ctx, bucket, "new-object"); err != nil {
+		t.Fatal(err)
+	}
+
+	var status madmin.PoolStatus
+	for i := 0; i < 100; i++ {
+		if status, err = z.StatusPool(ctx, "zone2"); err != nil {
+			t.Fatal(err)
+		}
+		if status.Decommission.Complete || status.Decommission.Failed {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if !status.Decommission.Complete {
+		t.Fatalf("Expected decommission to complete, got %v", status.Decommission)
+	}
+	if status.Decommission.ObjectsMoved != 2 {
+		t.Errorf("Expected 2 objects moved, got %d", status.Decommission.ObjectsMoved)
+	}
+
+	for _, expected := range []ObjectInfo{objInfo, multipartInfo} {
+		if _, err = z.zones[1].GetObjectInfo(ctx, bucket, expected.Name); !isErrObjectNotFound(err) {
+			t.Errorf("%s: expected object to be removed from decommissioned zone, got %v", expected.Name, err)
+		}
+		movedInfo, err := z.zones[0].GetObjectInfo(ctx, bucket, expected.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if movedInfo.ETag != expected.ETag || movedInfo.Size != expected.Size || movedInfo.ContentType != expected.ContentType {
+			t.Errorf("%s: expected %s/%d/%s, got %s/%d/%s", expected.Name, expected.ETag, expected.Size, expected.ContentType,
+				movedInfo.ETag, movedInfo.Size, movedInfo.ContentType)
+		}
+		if !movedInfo.ModTime.Equal(expected.ModTime) {
+			t.Errorf("%s: expected modtime %s, got %s", expected.Name, expected.ModTime, movedInfo.ModTime)
+		}
+		if _, ok := movedInfo.UserDefined[xlModTimeKey]; ok {
+			t.Errorf("%s: expected internal modtime metadata not to be saved", expected.Name)
+		}
+	}
+
+	if err = z.CancelDecommission(ctx, "zone2"); err != errDecommissionNotRunning {
+		t.Fatalf("Expected %v, got %v", errDecommissionNotRunning, err)
+	}
+}
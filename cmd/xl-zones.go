@@ -0,0 +1,802 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// Namespace under minioMetaBucket of the locks serializing
+	// placement of objects on the zones.
+	zonesLockPrefix = "zones"
+
+	// Free space of the zones is refreshed at most this often.
+	zoneSpaceCacheTTL = 30 * time.Second
+)
+
+// xlZones implements ObjectLayer combining independent server pools
+// (zones), each zone is a static list of erasure coded sets. New objects
+// are written to the zone with most free space, existing objects are
+// always looked up and overwritten in the zone holding them. Zones being
+// decommissioned do not receive new objects.
+type xlZones struct {
+	zones         []*xlSets
+	endpointZones EndpointZones
+
+	// Serializes placement and moves of objects across zones.
+	nsMutex *nsLockMap
+
+	// Guards the cached free space of the zones.
+	spaceMutex   sync.Mutex
+	space        []uint64
+	spaceUpdated time.Time
+
+	// Guards poolMeta and decommissionCancelers.
+	poolMetaMutex         sync.RWMutex
+	poolMeta              poolMeta
+	decommissionCancelers []context.CancelFunc
+}
+
+// newXLZones - returns new object layer combining given zones, status
+// of the zones is loaded and pending decommissions are resumed.
+func newXLZones(endpointZones EndpointZones, zones []*xlSets) (ObjectLayer, error) {
+	z := &xlZones{
+		zones:                 zones,
+		endpointZones:         endpointZones,
+		nsMutex:               newNSLock(globalIsDistXL),
+		decommissionCancelers: make([]context.CancelFunc, len(zones)),
+	}
+
+	if err := z.initPoolMeta(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// newObjectLock - returns the lock serializing placement of an object on
+// the zones. The lock is distinct from the object locks taken by the
+// zones, which are held only while a zone writes the object.
+func (z *xlZones) newObjectLock(bucket, object string) RWLocker {
+	return z.nsMutex.NewNSLock(minioMetaBucket, pathJoin(zonesLockPrefix, bucket, object))
+}
+
+// availableSpace - returns free space of all zones, which is cached
+// for zoneSpaceCacheTTL.
+func (z *xlZones) availableSpace() []uint64 {
+	z.spaceMutex.Lock()
+	defer z.spaceMutex.Unlock()
+	if z.space == nil || time.Since(z.spaceUpdated) > zoneSpaceCacheTTL {
+		z.space = make([]uint64, len(z.zones))
+		for i, zone := range z.zones {
+			z.space[i] = zone.availableSpace()
+		}
+		z.spaceUpdated = time.Now()
+	}
+	return z.space
+}
+
+// getAvailableZoneIdx - returns index of the zone with most free space,
+// zones being decommissioned are skipped.
+func (z *xlZones) getAvailableZoneIdx(ctx context.Context) int {
+	zoneIdx := -1
+	var maxAvailable uint64
+	for i, available := range z.availableSpace() {
+		if z.isDraining(i) {
+			continue
+		}
+		if zoneIdx == -1 || available > maxAvailable {
+			zoneIdx, maxAvailable = i, available
+		}
+	}
+	// Decommissioning all zones is not allowed, fall back to the first
+	// zone nevertheless.
+	if zoneIdx == -1 {
+		zoneIdx = 0
+	}
+	return zoneIdx
+}
+
+// getZoneIdx - returns index of the zone holding given object,
+// ObjectNotFound is returned if no zone has it.
+func (z *xlZones) getZoneIdx(ctx context.Context, bucket, object string) (int, error) {
+	for i, zone := range z.zones {
+		_, err := zone.GetObjectInfo(ctx, bucket, object)
+		if err == nil {
+			return i, nil
+		}
+		if !isErrObjectNotFound(err) {
+			return -1, err
+		}
+	}
+	return -1, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// getWriteZoneIdx - returns index of the zone holding given object, or
+// of the zone with most free space if the object does not exist yet or
+// its zone is being decommissioned.
+func (z *xlZones) getWriteZoneIdx(ctx context.Context, bucket, object string) (int, error) {
+	zoneIdx, err := z.getZoneIdx(ctx, bucket, object)
+	if err != nil {
+		if !isErrObjectNotFound(err) {
+			return -1, err
+		}
+		return z.getAvailableZoneIdx(ctx), nil
+	}
+	if z.isDraining(zoneIdx) {
+		return z.getAvailableZoneIdx(ctx), nil
+	}
+	return zoneIdx, nil
+}
+
+// deleteFromDrainingZones - removes stale copies of an object, written
+// to another zone, from the zones being decommissioned.
+func (z *xlZones) deleteFromDrainingZones(ctx context.Context, bucket, object string, zoneIdx int) {
+	for i, zone := range z.zones {
+		if i == zoneIdx || !z.isDraining(i) {
+			continue
+		}
+		if err := zone.DeleteObject(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+// deleteFromOtherZones - removes older copies of an object, written to
+// given zone, from all other zones.
+func (z *xlZones) deleteFromOtherZones(ctx context.Context, bucket, object string, zoneIdx int) {
+	for i, zone := range z.zones {
+		if i == zoneIdx {
+			continue
+		}
+		if err := zone.DeleteObject(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+// getUploadZoneIdx - returns index of the zone holding given multipart upload.
+func (z *xlZones) getUploadZoneIdx(ctx context.Context, bucket, object, uploadID string) (int, error) {
+	for i, zone := range z.zones {
+		_, err := zone.ListObjectParts(ctx, bucket, object, uploadID, 0, 1)
+		if err == nil {
+			return i, nil
+		}
+		if _, ok := err.(InvalidUploadID); !ok {
+			return -1, err
+		}
+	}
+	return -1, InvalidUploadID{UploadID: uploadID}
+}
+
+// Shutdown - shuts down all zones, returns upon first error.
+func (z *xlZones) Shutdown(ctx context.Context) error {
+	z.poolMetaMutex.Lock()
+	for _, cancel := range z.decommissionCancelers {
+		if cancel != nil {
+			cancel()
+		}
+	}
+	z.poolMetaMutex.Unlock()
+
+	for _, zone := range z.zones {
+		if err := zone.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorageInfo - combines output of StorageInfo across all zones.
+func (z *xlZones) StorageInfo(ctx context.Context) StorageInfo {
+	var storageInfo StorageInfo
+	for i, zone := range z.zones {
+		zoneStorageInfo := zone.StorageInfo(ctx)
+		if i == 0 {
+			storageInfo.Backend = zoneStorageInfo.Backend
+			storageInfo.Backend.Sets = nil
+			storageInfo.Backend.OnlineDisks = 0
+			storageInfo.Backend.OfflineDisks = 0
+		}
+		storageInfo.Used += zoneStorageInfo.Used
+		storageInfo.Backend.OnlineDisks += zoneStorageInfo.Backend.OnlineDisks
+		storageInfo.Backend.OfflineDisks += zoneStorageInfo.Backend.OfflineDisks
+		storageInfo.Backend.Sets = append(storageInfo.Backend.Sets, zoneStorageInfo.Backend.Sets...)
+	}
+	return storageInfo
+}
+
+// MakeBucketWithLocation - creates a new bucket on all zones, buckets
+// created on previous zones are removed upon failure.
+func (z *xlZones) MakeBucketWithLocation(ctx context.Context, bucket, location string) error {
+	for i, zone := range z.zones {
+		if err := zone.MakeBucketWithLocation(ctx, bucket, location); err != nil {
+			for _, createdZone := range z.zones[:i] {
+				logger.LogIf(ctx, createdZone.DeleteBucket(ctx, bucket))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBucketInfo - returns bucket info from the first zone, as all
+// buckets are present on all zones.
+func (z *xlZones) GetBucketInfo(ctx context.Context, bucket string) (BucketInfo, error) {
+	return z.zones[0].GetBucketInfo(ctx, bucket)
+}
+
+// ListBuckets - lists buckets from the first zone, as all buckets are
+// present on all zones.
+func (z *xlZones) ListBuckets(ctx context.Context) ([]BucketInfo, error) {
+	return z.zones[0].ListBuckets(ctx)
+}
+
+// DeleteBucket - deletes an empty bucket from all zones, buckets deleted
+// on previous zones are created again upon failure.
+func (z *xlZones) DeleteBucket(ctx context.Context, bucket string) error {
+	// A bucket is empty only if it is empty on all zones.
+	for _, zone := range z.zones {
+		result, err := zone.ListObjects(ctx, bucket, "", "", "", 1)
+		if err != nil {
+			return err
+		}
+		if len(result.Objects) > 0 || len(result.Prefixes) > 0 {
+			return BucketNotEmpty{Bucket: bucket}
+		}
+	}
+
+	for i, zone := range z.zones {
+		if err := zone.DeleteBucket(ctx, bucket); err != nil {
+			for _, deletedZone := range z.zones[:i] {
+				logger.LogIf(ctx, deletedZone.MakeBucketWithLocation(ctx, bucket, globalServerRegion))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeListObjectsInfo - merges listings of all zones into a single
+// lexically sorted listing of at most maxKeys entries.
+func mergeListObjectsInfo(zoneResults []ListObjectsInfo, maxKeys int) (result ListObjectsInfo) {
+	type listEntry struct {
+		name     string
+		isPrefix bool
+		objInfo  ObjectInfo
+	}
+
+	var entries []listEntry
+	found := make(map[string]struct{})
+	for _, zoneResult := range zoneResults {
+		result.IsTruncated = result.IsTruncated || zoneResult.IsTruncated
+		for _, objInfo := range zoneResult.Objects {
+			if _, ok := found[objInfo.Name]; !ok {
+				found[objInfo.Name] = struct{}{}
+				entries = append(entries, listEntry{name: objInfo.Name, objInfo: objInfo})
+			}
+		}
+		for _, prefix := range zoneResult.Prefixes {
+			if _, ok := found[prefix]; !ok {
+				found[prefix] = struct{}{}
+				entries = append(entries, listEntry{name: prefix, isPrefix: true})
+			}
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	if len(entries) > maxKeys {
+		entries = entries[:maxKeys]
+		result.IsTruncated = true
+	}
+
+	for _, entry := range entries {
+		if entry.isPrefix {
+			result.Prefixes = append(result.Prefixes, entry.name)
+		} else {
+			result.Objects = append(result.Objects, entry.objInfo)
+		}
+	}
+
+	if result.IsTruncated && len(entries) > 0 {
+		result.NextMarker = entries[len(entries)-1].name
+	}
+
+	return result
+}
+
+// ListObjects - lists objects of all zones, each zone is listed
+// independently and the results are merged.
+func (z *xlZones) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	zoneResults := make([]ListObjectsInfo, len(z.zones))
+	for i, zone := range z.zones {
+		var err error
+		if zoneResults[i], err = zone.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys); err != nil {
+			return ListObjectsInfo{}, err
+		}
+	}
+	return mergeListObjectsInfo(zoneResults, maxKeys), nil
+}
+
+// listFuncs - returns the functions walking all sets of all zones.
+func (z *xlZones) listFuncs(ctx context.Context) (listDirFunc, isLeafFunc, isLeafDirFunc) {
+	var isLeafFuncs []isLeafFunc
+	var isLeafDirFuncs []isLeafDirFunc
+	for _, zone := range z.zones {
+		_, isLeaf, isLeafDir := zone.listFuncs(ctx)
+		isLeafFuncs = append(isLeafFuncs, isLeaf)
+		isLeafDirFuncs = append(isLeafDirFuncs, isLeafDir)
+	}
+
+	// Objects and directories may be found in any zone.
+	isLeaf := func(bucket, entry string) bool {
+		for _, isLeaf := range isLeafFuncs {
+			if isLeaf(bucket, entry) {
+				return true
+			}
+		}
+		return false
+	}
+
+	isLeafDir := func(bucket, entry string) bool {
+		for _, isLeafDir := range isLeafDirFuncs {
+			if isLeafDir(bucket, entry) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var setDisks [][]StorageAPI
+	for _, zone := range z.zones {
+		for _, set := range zone.sets {
+			setDisks = append(setDisks, set.getLoadBalancedDisks())
+		}
+	}
+
+	return listDirSetsFactory(ctx, isLeaf, isLeafDir, setDisks...), isLeaf, isLeafDir
+}
+
+// getWalkEntryInfo - returns the object info of an entry found by a tree
+// walk from the first zone holding it.
+func (z *xlZones) getWalkEntryInfo(ctx context.Context, bucket, entry string) (objInfo ObjectInfo, err error) {
+	for _, zone := range z.zones {
+		objInfo, err = zone.getWalkEntryInfo(ctx, bucket, entry)
+		if err != errFileNotFound {
+			break
+		}
+	}
+	return objInfo, err
+}
+
+// ListObjectsV2 - lists objects of all zones, the continuation token
+// holds the state of the tree walk just like for a single zone.
+func (z *xlZones) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
+	listDir, isLeaf, isLeafDir := z.listFuncs(ctx)
+	return listObjectsV2Resumable(ctx, z, bucket, prefix, continuationToken, delimiter, maxKeys, startAfter, listDir, isLeaf, isLeafDir, z.getWalkEntryInfo)
+}
+
+// --- Object Operations ---
+
+// GetObjectNInfo - returns object info and a reader from the zone holding the object.
+func (z *xlZones) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec) (ObjectInfo, io.ReadCloser, error) {
+	for _, zone := range z.zones {
+		objInfo, reader, err := zone.GetObjectNInfo(ctx, bucket, object, rs)
+		if err == nil || !isErrObjectNotFound(err) {
+			return objInfo, reader, err
+		}
+	}
+	return ObjectInfo{}, nil, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// GetObject - reads an object from the zone holding it.
+func (z *xlZones) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) error {
+	zoneIdx, err := z.getZoneIdx(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	return z.zones[zoneIdx].GetObject(ctx, bucket, object, startOffset, length, writer, etag)
+}
+
+// GetObjectInfo - reads object metadata from the zone holding the object.
+func (z *xlZones) GetObjectInfo(ctx context.Context, bucket, object string) (ObjectInfo, error) {
+	for _, zone := range z.zones {
+		objInfo, err := zone.GetObjectInfo(ctx, bucket, object)
+		if err == nil || !isErrObjectNotFound(err) {
+			return objInfo, err
+		}
+	}
+	return ObjectInfo{}, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// PutObject - writes an object to the zone holding it, new objects are
+// written to the zone with most free space.
+func (z *xlZones) PutObject(ctx context.Context, bucket string, object string, data *hash.Reader, metadata map[string]string) (ObjectInfo, error) {
+	objectLock := z.newObjectLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer objectLock.Unlock()
+
+	zoneIdx, err := z.getWriteZoneIdx(ctx, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	objInfo, err := z.zones[zoneIdx].PutObject(ctx, bucket, object, data, metadata)
+	if err != nil {
+		return objInfo, err
+	}
+
+	z.deleteFromDrainingZones(ctx, bucket, object, zoneIdx)
+	return objInfo, nil
+}
+
+// DeleteObject - deletes an object from all zones holding it, an object
+// is present on two zones while it is moved off a decommissioned zone.
+func (z *xlZones) DeleteObject(ctx context.Context, bucket string, object string) error {
+	objectLock := z.newObjectLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	var found bool
+	for _, zone := range z.zones {
+		err := zone.DeleteObject(ctx, bucket, object)
+		if err == nil {
+			found = true
+			continue
+		}
+		if !isErrObjectNotFound(err) {
+			return err
+		}
+	}
+	if !found {
+		return ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return nil
+}
+
+// CopyObject - copies an object within its zone or across zones.
+func (z *xlZones) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo) (ObjectInfo, error) {
+	objectLock := z.newObjectLock(destBucket, destObject)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer objectLock.Unlock()
+
+	srcZoneIdx, err := z.getZoneIdx(ctx, srcBucket, srcObject)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	// Check if this request is only metadata update, objects of
+	// zones being decommissioned are copied to another zone instead.
+	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(destBucket, destObject))
+	if cpSrcDstSame && srcInfo.metadataOnly && !z.isDraining(srcZoneIdx) {
+		return z.zones[srcZoneIdx].CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo)
+	}
+
+	destZoneIdx, err := z.getWriteZoneIdx(ctx, destBucket, destObject)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	if srcZoneIdx == destZoneIdx {
+		return z.zones[srcZoneIdx].CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo)
+	}
+
+	go func() {
+		if gerr := z.zones[srcZoneIdx].GetObject(ctx, srcBucket, srcObject, 0, srcInfo.Size, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
+			return
+		}
+		// Close writer explicitly signaling we wrote all data.
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+		}
+	}()
+
+	objInfo, err := z.zones[destZoneIdx].PutObject(ctx, destBucket, destObject, srcInfo.Reader, srcInfo.UserDefined)
+	if err != nil {
+		return objInfo, err
+	}
+
+	z.deleteFromDrainingZones(ctx, destBucket, destObject, destZoneIdx)
+	return objInfo, nil
+}
+
+// --- Multipart Operations ---
+
+// ListMultipartUploads - lists multipart uploads of all zones.
+func (z *xlZones) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	var result ListMultipartsInfo
+	for i, zone := range z.zones {
+		zoneResult, err := zone.ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+		if err != nil {
+			return result, err
+		}
+		if i == 0 {
+			result = zoneResult
+			continue
+		}
+		result.IsTruncated = result.IsTruncated || zoneResult.IsTruncated
+		result.Uploads = append(result.Uploads, zoneResult.Uploads...)
+	}
+
+	sort.SliceStable(result.Uploads, func(i, j int) bool {
+		return result.Uploads[i].Object < result.Uploads[j].Object
+	})
+	if maxUploads >= 0 && len(result.Uploads) > maxUploads {
+		result.Uploads = result.Uploads[:maxUploads]
+		result.IsTruncated = true
+	}
+	if result.IsTruncated && len(result.Uploads) > 0 {
+		lastUpload := result.Uploads[len(result.Uploads)-1]
+		result.NextKeyMarker = lastUpload.Object
+		result.NextUploadIDMarker = lastUpload.UploadID
+	}
+	return result, nil
+}
+
+// NewMultipartUpload - initiates a multipart upload on the zone holding
+// the object, or on the zone with most free space for new objects.
+func (z *xlZones) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (string, error) {
+	objectLock := z.newObjectLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return "", err
+	}
+	defer objectLock.Unlock()
+
+	zoneIdx, err := z.getWriteZoneIdx(ctx, bucket, object)
+	if err != nil {
+		return "", err
+	}
+	return z.zones[zoneIdx].NewMultipartUpload(ctx, bucket, object, metadata)
+}
+
+// CopyObjectPart - copies a part of an object to the zone holding the multipart upload.
+func (z *xlZones) CopyObjectPart(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int,
+	startOffset int64, length int64, srcInfo ObjectInfo) (PartInfo, error) {
+
+	srcZoneIdx, err := z.getZoneIdx(ctx, srcBucket, srcObject)
+	if err != nil {
+		return PartInfo{}, err
+	}
+
+	destZoneIdx, err := z.getUploadZoneIdx(ctx, destBucket, destObject, uploadID)
+	if err != nil {
+		return PartInfo{}, err
+	}
+
+	if srcZoneIdx == destZoneIdx {
+		return z.zones[srcZoneIdx].CopyObjectPart(ctx, srcBucket, srcObject, destBucket, destObject, uploadID, partID,
+			startOffset, length, srcInfo)
+	}
+
+	go func() {
+		if gerr := z.zones[srcZoneIdx].GetObject(ctx, srcBucket, srcObject, startOffset, length, srcInfo.Writer, srcInfo.ETag); gerr != nil {
+			if gerr = srcInfo.Writer.Close(); gerr != nil {
+				logger.LogIf(ctx, gerr)
+			}
+			return
+		}
+		if gerr := srcInfo.Writer.Close(); gerr != nil {
+			logger.LogIf(ctx, gerr)
+		}
+	}()
+
+	return z.zones[destZoneIdx].PutObjectPart(ctx, destBucket, destObject, uploadID, partID, srcInfo.Reader)
+}
+
+// PutObjectPart - writes a part to the zone holding the multipart upload.
+func (z *xlZones) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *hash.Reader) (PartInfo, error) {
+	zoneIdx, err := z.getUploadZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	return z.zones[zoneIdx].PutObjectPart(ctx, bucket, object, uploadID, partID, data)
+}
+
+// ListObjectParts - lists parts from the zone holding the multipart upload.
+func (z *xlZones) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (ListPartsInfo, error) {
+	zoneIdx, err := z.getUploadZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return ListPartsInfo{}, err
+	}
+	return z.zones[zoneIdx].ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts)
+}
+
+// AbortMultipartUpload - aborts a multipart upload on the zone holding it.
+func (z *xlZones) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	zoneIdx, err := z.getUploadZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return err
+	}
+	return z.zones[zoneIdx].AbortMultipartUpload(ctx, bucket, object, uploadID)
+}
+
+// CompleteMultipartUpload - completes a multipart upload on the zone
+// holding it, objects completed on a zone being decommissioned are
+// moved to another zone right away.
+func (z *xlZones) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []CompletePart) (ObjectInfo, error) {
+	objectLock := z.newObjectLock(bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer objectLock.Unlock()
+
+	zoneIdx, err := z.getUploadZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	objInfo, err := z.zones[zoneIdx].CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts)
+	if err != nil {
+		return objInfo, err
+	}
+
+	// Upload may have been created on a zone other than the one holding
+	// the object, older copies are removed from every other zone.
+	z.deleteFromOtherZones(ctx, bucket, object, zoneIdx)
+
+	if z.isDraining(zoneIdx) {
+		if err = z.moveObjectLocked(ctx, zoneIdx, bucket, object); err != nil {
+			logger.LogIf(ctx, err)
+		}
+	}
+	return objInfo, nil
+}
+
+// --- Healing Operations ---
+
+// ReloadFormat - reloads format of all zones.
+func (z *xlZones) ReloadFormat(ctx context.Context, dryRun bool) error {
+	for _, zone := range z.zones {
+		if err := zone.ReloadFormat(ctx, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealFormat - heals format of all zones, errNoHealRequired is returned
+// only if no zone required healing.
+func (z *xlZones) HealFormat(ctx context.Context, dryRun bool) (madmin.HealResultItem, error) {
+	res := madmin.HealResultItem{
+		Type:   madmin.HealItemMetadata,
+		Detail: "disk-format",
+	}
+
+	var healed bool
+	for _, zone := range z.zones {
+		zoneResult, err := zone.HealFormat(ctx, dryRun)
+		if err != nil && err != errNoHealRequired {
+			return res, err
+		}
+		healed = healed || err == nil
+		res.DiskCount += zoneResult.DiskCount
+		res.SetCount += zoneResult.SetCount
+		res.Before.Drives = append(res.Before.Drives, zoneResult.Before.Drives...)
+		res.After.Drives = append(res.After.Drives, zoneResult.After.Drives...)
+	}
+
+	if !healed {
+		return res, errNoHealRequired
+	}
+	return res, nil
+}
+
+// HealBucket - heals a bucket on all zones.
+func (z *xlZones) HealBucket(ctx context.Context, bucket string, dryRun bool) ([]madmin.HealResultItem, error) {
+	var results []madmin.HealResultItem
+	for _, zone := range z.zones {
+		zoneResults, err := zone.HealBucket(ctx, bucket, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, zoneResults...)
+	}
+	return results, nil
+}
+
+// HealObject - heals an object on the zone holding it.
+func (z *xlZones) HealObject(ctx context.Context, bucket, object string, dryRun bool) (madmin.HealResultItem, error) {
+	for _, zone := range z.zones {
+		result, err := zone.HealObject(ctx, bucket, object, dryRun)
+		if err == nil || !isErrObjectNotFound(err) {
+			return result, err
+		}
+	}
+	return madmin.HealResultItem{}, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// ListBucketsHeal - lists buckets which need healing on any zone.
+func (z *xlZones) ListBucketsHeal(ctx context.Context) ([]BucketInfo, error) {
+	var healBuckets []BucketInfo
+	found := make(map[string]struct{})
+	for _, zone := range z.zones {
+		buckets, err := zone.ListBucketsHeal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range buckets {
+			if _, ok := found[bucket.Name]; !ok {
+				found[bucket.Name] = struct{}{}
+				healBuckets = append(healBuckets, bucket)
+			}
+		}
+	}
+
+	sort.Sort(byBucketName(healBuckets))
+	return healBuckets, nil
+}
+
+// ListObjectsHeal - lists objects which need healing on any zone.
+func (z *xlZones) ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	zoneResults := make([]ListObjectsInfo, len(z.zones))
+	for i, zone := range z.zones {
+		var err error
+		if zoneResults[i], err = zone.ListObjectsHeal(ctx, bucket, prefix, marker, delimiter, maxKeys); err != nil {
+			return ListObjectsInfo{}, err
+		}
+	}
+	return mergeListObjectsInfo(zoneResults, maxKeys), nil
+}
+
+// --- Policy Operations ---
+
+// SetBucketPolicy persist the new policy on the bucket.
+func (z *xlZones) SetBucketPolicy(ctx context.Context, bucket string, policy *policy.Policy) error {
+	return savePolicyConfig(z, bucket, policy)
+}
+
+// GetBucketPolicy will return a policy on a bucket
+func (z *xlZones) GetBucketPolicy(ctx context.Context, bucket string) (*policy.Policy, error) {
+	return getPolicyConfig(z, bucket)
+}
+
+// DeleteBucketPolicy deletes all policies on bucket
+func (z *xlZones) DeleteBucketPolicy(ctx context.Context, bucket string) error {
+	return removePolicyConfig(ctx, z, bucket)
+}
+
+// IsNotificationSupported returns whether bucket notification is applicable for this layer.
+func (z *xlZones) IsNotificationSupported() bool {
+	return z.zones[0].IsNotificationSupported()
+}
+
+// IsEncryptionSupported returns whether server side encryption is applicable for this layer.
+func (z *xlZones) IsEncryptionSupported() bool {
+	return z.zones[0].IsEncryptionSupported()
+}
@@ -0,0 +1,312 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// prepareXLZones - initializes two zones of 16 disks each.
+func prepareXLZones(t *testing.T) (*xlZones, []string) {
+	var zones []*xlSets
+	var endpointZones EndpointZones
+	var fsDirs []string
+	for i := 0; i < 2; i++ {
+		zoneDirs, err := getRandomDisks(16)
+		if err != nil {
+			removeRoots(fsDirs)
+			t.Fatal(err)
+		}
+		fsDirs = append(fsDirs, zoneDirs...)
+
+		endpoints := mustGetNewEndpointList(zoneDirs...)
+		format, err := waitForFormatXL(context.Background(), true, endpoints, 1, 16)
+		if err != nil {
+			removeRoots(fsDirs)
+			t.Fatal(err)
+		}
+		zone, err := newXLSets(endpoints, format, 1, 16)
+		if err != nil {
+			removeRoots(fsDirs)
+			t.Fatal(err)
+		}
+		zones = append(zones, zone)
+		endpointZones = append(endpointZones, ZoneEndpoints{
+			CmdLine:      fmt.Sprintf("zone%d", i+1),
+			SetCount:     1,
+			DrivesPerSet: 16,
+			Endpoints:    endpoints,
+		})
+	}
+
+	objLayer, err := newXLZones(endpointZones, zones)
+	if err != nil {
+		removeRoots(fsDirs)
+		t.Fatal(err)
+	}
+	return objLayer.(*xlZones), fsDirs
+}
+
+// Tests merging of listings from multiple zones.
+func TestMergeListObjectsInfo(t *testing.T) {
+	zoneResults := []ListObjectsInfo{
+		{
+			Objects:  []ObjectInfo{{Name: "a"}, {Name: "d"}},
+			Prefixes: []string{"c/"},
+		},
+		{
+			Objects:  []ObjectInfo{{Name: "b"}, {Name: "d"}},
+			Prefixes: []string{"c/", "e/"},
+		},
+	}
+
+	result := mergeListObjectsInfo(zoneResults, 1000)
+	if result.IsTruncated {
+		t.Fatal("Expected listing not to be truncated")
+	}
+	var names []string
+	for _, objInfo := range result.Objects {
+		names = append(names, objInfo.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b", "d"}) {
+		t.Errorf("Unexpected objects %v", names)
+	}
+	if !reflect.DeepEqual(result.Prefixes, []string{"c/", "e/"}) {
+		t.Errorf("Unexpected prefixes %v", result.Prefixes)
+	}
+
+	result = mergeListObjectsInfo(zoneResults, 3)
+	if !result.IsTruncated {
+		t.Fatal("Expected listing to be truncated")
+	}
+	if len(result.Objects) != 2 || len(result.Prefixes) != 1 {
+		t.Errorf("Unexpected listing %v", result)
+	}
+	if result.NextMarker != "c/" {
+		t.Errorf("Expected next marker c/, got %s", result.NextMarker)
+	}
+}
+
+// Tests object operations across zones.
+func TestXLZonesObjectOperations(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	for i, zone := range z.zones {
+		if _, err := zone.GetBucketInfo(ctx, bucket); err != nil {
+			t.Fatalf("Zone %d: bucket not created, %s", i+1, err)
+		}
+	}
+
+	// Place one object on each zone.
+	data := []byte("hello")
+	for i, zone := range z.zones {
+		object := []string{"object1", "object2"}[i]
+		if _, err := zone.PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := z.ListObjects(ctx, bucket, "", "", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Objects) != 2 || result.Objects[0].Name != "object1" || result.Objects[1].Name != "object2" {
+		t.Fatalf("Unexpected listing %v", result.Objects)
+	}
+
+	var buf bytes.Buffer
+	if err = z.GetObject(ctx, bucket, "object2", 0, int64(len(data)), &buf, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("Unexpected object content %s", buf.String())
+	}
+
+	// Overwrites must stay on the zone holding the object.
+	newData := []byte("hello, world")
+	if _, err = z.PutObject(ctx, bucket, "object2", mustGetHashReader(t, bytes.NewReader(newData), int64(len(newData)), "", ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.zones[0].GetObjectInfo(ctx, bucket, "object2"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected object not to be present on first zone, got %v", err)
+	}
+	objInfo, err := z.zones[1].GetObjectInfo(ctx, bucket, "object2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(newData)) {
+		t.Fatalf("Expected size %d, got %d", len(newData), objInfo.Size)
+	}
+
+	// Multipart uploads are looked up on the zone they were created on.
+	uploadID, err := z.NewMultipartUpload(ctx, bucket, "object1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partInfo, err := z.PutObjectPart(ctx, bucket, "object1", uploadID, 1, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.CompleteMultipartUpload(ctx, bucket, "object1", uploadID, []CompletePart{{PartNumber: 1, ETag: partInfo.ETag}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.zones[0].GetObjectInfo(ctx, bucket, "object1"); err != nil {
+		t.Fatal(err)
+	}
+	// Completing an upload created on another zone removes the older copy.
+	uploadID, err = z.zones[0].NewMultipartUpload(ctx, bucket, "object2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partInfo, err = z.PutObjectPart(ctx, bucket, "object2", uploadID, 1, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.CompleteMultipartUpload(ctx, bucket, "object2", uploadID, []CompletePart{{PartNumber: 1, ETag: partInfo.ETag}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.zones[1].GetObjectInfo(ctx, bucket, "object2"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected older copy to be removed from second zone, got %v", err)
+	}
+	if objInfo, err = z.GetObjectInfo(ctx, bucket, "object2"); err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.Size != int64(len(data)) {
+		t.Fatalf("Expected size %d, got %d", len(data), objInfo.Size)
+	}
+
+	if _, err = z.PutObjectPart(ctx, bucket, "object1", "invalid-upload-id", 1, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", "")); err == nil {
+		t.Fatal("Expected invalid upload id to fail")
+	}
+
+	if err = z.DeleteBucket(ctx, bucket); err == nil {
+		t.Fatal("Expected deleting non empty bucket to fail")
+	}
+	for _, object := range []string{"object1", "object2"} {
+		if err = z.DeleteObject(ctx, bucket, object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = z.DeleteObject(ctx, bucket, "object1"); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected object not found, got %v", err)
+	}
+	if err = z.DeleteBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests concurrent creates of a new object land on a single zone.
+func TestXLZonesConcurrentPutObject(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("hello")
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = z.PutObject(ctx, bucket, "object", mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var found int
+	for _, zone := range z.zones {
+		if _, err := zone.GetObjectInfo(ctx, bucket, "object"); err == nil {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Fatalf("Expected object on one zone, found on %d", found)
+	}
+}
+
+// Tests paging of ListObjectsV2 across zones with resumable continuation tokens.
+func TestXLZonesListObjectsV2Resume(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	defer func(threshold int) { listWalkPersistThreshold = threshold }(listWalkPersistThreshold)
+	listWalkPersistThreshold = 4
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Spread the objects of a large directory over both zones.
+	var expected []string
+	for i := 0; i < 10; i++ {
+		object := fmt.Sprintf("a/%02d", i)
+		data := []byte(object)
+		if _, err := z.zones[i%2].PutObject(ctx, bucket, object, mustGetHashReader(t, bytes.NewReader(data), int64(len(data)), "", ""), nil); err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, object)
+	}
+
+	var got []string
+	token := ""
+	for {
+		result, err := z.ListObjectsV2(ctx, bucket, "", token, "", 3, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, objInfo := range result.Objects {
+			got = append(got, objInfo.Name)
+		}
+		if !result.IsTruncated {
+			break
+		}
+		// Listing of the large directory is persisted.
+		if names := listWalkObjects(t, z); len(names) != 1 {
+			t.Fatalf("Expected one persisted listing, found %v", names)
+		}
+		token = result.NextContinuationToken
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	if names := listWalkObjects(t, z); len(names) != 0 {
+		t.Fatalf("Expected persisted listings to be removed, found %v", names)
+	}
+}
@@ -21,6 +21,9 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
 )
 
 const (
@@ -62,10 +65,28 @@ func isSecretKeyValid(secretKey string) bool {
 	return len(secretKey) >= secretKeyMinLen
 }
 
-// Credentials holds access and secret keys.
+// Credentials holds access and secret keys. Temporary credentials
+// additionally hold session token and expiration time.
 type Credentials struct {
-	AccessKey string `json:"accessKey,omitempty"`
-	SecretKey string `json:"secretKey,omitempty"`
+	AccessKey    string     `xml:"AccessKeyId" json:"accessKey,omitempty"`
+	SecretKey    string     `xml:"SecretAccessKey" json:"secretKey,omitempty"`
+	Expiration   *time.Time `xml:"Expiration" json:"expiration,omitempty"`
+	SessionToken string     `xml:"SessionToken" json:"sessionToken,omitempty"`
+}
+
+// IsExpired - returns whether temporary credential is expired or not.
+// Credential without expiration time never expires.
+func (cred Credentials) IsExpired() bool {
+	if cred.Expiration == nil {
+		return false
+	}
+
+	return time.Now().UTC().After(*cred.Expiration)
+}
+
+// IsTemp - returns whether credential is a temporary credential or not.
+func (cred Credentials) IsTemp() bool {
+	return cred.SessionToken != ""
 }
 
 // IsValid - returns whether credential is valid or not.
@@ -114,6 +135,35 @@ func GetNewCredentials() (cred Credentials, err error) {
 	return cred, nil
 }
 
+// GetNewCredentialsWithMetadata generates and returns new temporary
+// credential. Given metadata are signed with tokenSecret into session
+// token. Expiration time is taken from "exp" claim of the metadata.
+func GetNewCredentialsWithMetadata(m map[string]interface{}, tokenSecret string) (cred Credentials, err error) {
+	if cred, err = GetNewCredentials(); err != nil {
+		return cred, err
+	}
+
+	expiry, ok := m["exp"].(int64)
+	if !ok {
+		return cred, fmt.Errorf("invalid expiry '%v'", m["exp"])
+	}
+	expiration := time.Unix(expiry, 0).UTC()
+	cred.Expiration = &expiration
+
+	claims := jwtgo.MapClaims{}
+	for k, v := range m {
+		claims[k] = v
+	}
+	claims["accessKey"] = cred.AccessKey
+
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, claims)
+	if cred.SessionToken, err = jwt.SignedString([]byte(tokenSecret)); err != nil {
+		return cred, err
+	}
+
+	return cred, nil
+}
+
 // CreateCredentials returns new credential with the given access key and secret key.
 // Error is returned if given access key or secret key are invalid length.
 func CreateCredentials(accessKey, secretKey string) (cred Credentials, err error) {
@@ -16,7 +16,13 @@
 
 package auth
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+)
 
 func TestIsAccessKeyValid(t *testing.T) {
 	testCases := []struct {
@@ -70,6 +76,64 @@ func TestGetNewCredentials(t *testing.T) {
 	}
 }
 
+func TestGetNewCredentialsWithMetadata(t *testing.T) {
+	expiry := time.Now().UTC().Add(time.Hour).Unix()
+	cred, err := GetNewCredentialsWithMetadata(map[string]interface{}{"exp": expiry, "sub": "minio"}, "tokensecret")
+	if err != nil {
+		t.Fatalf("Failed to get a new temporary credential. %v", err)
+	}
+	if !cred.IsValid() || !cred.IsTemp() {
+		t.Fatalf("Failed to get new valid temporary credential")
+	}
+	if cred.Expiration.Unix() != expiry {
+		t.Fatalf("expiration: expected: %v, got: %v", expiry, cred.Expiration.Unix())
+	}
+
+	claims := jwtgo.MapClaims{}
+	_, err = jwtgo.ParseWithClaims(cred.SessionToken, claims, func(*jwtgo.Token) (interface{}, error) {
+		return []byte("tokensecret"), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to parse session token. %v", err)
+	}
+	if claims["accessKey"] != cred.AccessKey || claims["sub"] != "minio" {
+		t.Fatalf("unexpected session token claims %v", claims)
+	}
+
+	if _, err = GetNewCredentialsWithMetadata(map[string]interface{}{"sub": "minio"}, "tokensecret"); err == nil {
+		t.Fatalf("expected error for missing expiry")
+	}
+}
+
+func TestCredentialsIsExpired(t *testing.T) {
+	future := time.Now().UTC().Add(time.Hour)
+	past := time.Now().UTC().Add(-time.Hour)
+	testCases := []struct {
+		cred           Credentials
+		expectedResult bool
+	}{
+		{Credentials{}, false},
+		{Credentials{Expiration: &future}, false},
+		{Credentials{Expiration: &past}, true},
+	}
+
+	for i, testCase := range testCases {
+		if result := testCase.cred.IsExpired(); result != testCase.expectedResult {
+			t.Fatalf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestCredentialsJSON(t *testing.T) {
+	data, err := json.Marshal(Credentials{AccessKey: "minio", SecretKey: "minio123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"accessKey":"minio","secretKey":"minio123"}`; string(data) != expected {
+		t.Fatalf("expected: %v, got: %v", expected, string(data))
+	}
+}
+
 func TestCreateCredentials(t *testing.T) {
 	testCases := []struct {
 		accessKey   string
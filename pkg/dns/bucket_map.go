@@ -0,0 +1,173 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// bucketMap - in-memory map of the DNS records of the buckets of all
+// clusters of a domain, which serves bucket lookups without a round
+// trip to etcd. Records are stored in etcd below '<prefix>/<bucket>/<ip>'.
+type bucketMap struct {
+	sync.RWMutex
+	prefix string
+	// records of each bucket by their etcd key.
+	buckets map[string]map[string]SrvRecord
+	// true while the map is in sync with etcd.
+	synced bool
+}
+
+// newBucketMap - returns an empty bucket map of the records below prefix.
+func newBucketMap(prefix string) *bucketMap {
+	return &bucketMap{
+		prefix:  prefix,
+		buckets: make(map[string]map[string]SrvRecord),
+	}
+}
+
+// Returns the bucket of the record stored at key. The labels of bucket
+// names containing dots are stored in reverse order like domain names,
+// the last element of key is the IP address of the record.
+func (m *bucketMap) bucketOf(key string) (string, bool) {
+	if !strings.HasPrefix(key, m.prefix+"/") {
+		return "", false
+	}
+	path := strings.TrimPrefix(key, m.prefix+"/")
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "", false
+	}
+	labels := strings.Split(path[:i], "/")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return strings.Join(labels, "."), true
+}
+
+// Adds the record stored at key, records which are not valid are ignored.
+func (m *bucketMap) put(key string, value []byte) {
+	bucket, ok := m.bucketOf(key)
+	if !ok {
+		return
+	}
+	var record SrvRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return
+	}
+	record.Key = "/" + bucket + "/"
+
+	m.Lock()
+	defer m.Unlock()
+	if m.buckets[bucket] == nil {
+		m.buckets[bucket] = make(map[string]SrvRecord)
+	}
+	m.buckets[bucket][key] = record
+}
+
+// Removes the record stored at key.
+func (m *bucketMap) delete(key string) {
+	bucket, ok := m.bucketOf(key)
+	if !ok {
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	delete(m.buckets[bucket], key)
+	if len(m.buckets[bucket]) == 0 {
+		delete(m.buckets, bucket)
+	}
+}
+
+// Replaces the map with the records of a full listing of the domain and
+// marks the map as synced.
+func (m *bucketMap) reset(kvs map[string][]byte) {
+	m.Lock()
+	m.buckets = make(map[string]map[string]SrvRecord)
+	m.Unlock()
+
+	for key, value := range kvs {
+		m.put(key, value)
+	}
+	m.setSynced(true)
+}
+
+// Replaces the records of the map with the records of other and marks the
+// map as synced.
+func (m *bucketMap) replace(other *bucketMap) {
+	other.RLock()
+	buckets := other.buckets
+	other.RUnlock()
+
+	m.Lock()
+	defer m.Unlock()
+	m.buckets = buckets
+	m.synced = true
+}
+
+// Marks whether the map is in sync with etcd.
+func (m *bucketMap) setSynced(synced bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.synced = synced
+}
+
+// Returns the records of bucket sorted by key. ok is false if the map
+// is not in sync with etcd, in which case etcd has to be queried.
+func (m *bucketMap) get(bucket string) (records []SrvRecord, ok bool) {
+	m.RLock()
+	defer m.RUnlock()
+	if !m.synced {
+		return nil, false
+	}
+	for _, record := range m.buckets[bucket] {
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, true
+}
+
+// Returns the records of all buckets sorted by key. ok is false if the
+// map is not in sync with etcd, in which case etcd has to be queried.
+func (m *bucketMap) list() (records []SrvRecord, ok bool) {
+	m.RLock()
+	defer m.RUnlock()
+	if !m.synced {
+		return nil, false
+	}
+	for _, bucketRecords := range m.buckets {
+		for _, record := range bucketRecords {
+			records = append(records, record)
+		}
+	}
+	sortRecords(records)
+	return records, true
+}
+
+// Sorts records by key and host.
+func sortRecords(records []SrvRecord) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Key != records[j].Key {
+			return records[i].Key < records[j].Key
+		}
+		return records[i].Host < records[j].Host
+	})
+}
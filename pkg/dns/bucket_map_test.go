@@ -0,0 +1,102 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBucketMapBucketOf(t *testing.T) {
+	m := newBucketMap("/skydns/com/mydomain")
+	testCases := []struct {
+		key    string
+		bucket string
+		ok     bool
+	}{
+		{"/skydns/com/mydomain/mybucket/10.0.0.1", "mybucket", true},
+		{"/skydns/com/mydomain/com/example/my/10.0.0.1", "my.example.com", true},
+		{"/skydns/com/mydomain/mybucket", "", false},
+		{"/skydns/com/otherdomain/mybucket/10.0.0.1", "", false},
+		{"/skydns/com/mydomainbucket/10.0.0.1", "", false},
+	}
+	for i, testCase := range testCases {
+		bucket, ok := m.bucketOf(testCase.key)
+		if bucket != testCase.bucket || ok != testCase.ok {
+			t.Errorf("Test %d: expected (%s, %v), got (%s, %v)", i+1, testCase.bucket, testCase.ok, bucket, ok)
+		}
+	}
+}
+
+func TestBucketMap(t *testing.T) {
+	m := newBucketMap("/skydns/com/mydomain")
+	value := func(host string) []byte {
+		b, err := json.Marshal(SrvRecord{Host: host, Port: 9000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	if _, ok := m.list(); ok {
+		t.Fatal("Expected map not to be synced")
+	}
+	m.reset(map[string][]byte{
+		"/skydns/com/mydomain/bucket/10.0.0.2": value("10.0.0.2"),
+		"/skydns/com/mydomain/bucket/10.0.0.1": value("10.0.0.1"),
+		"/skydns/com/mydomain/invalid/":        []byte("invalid"),
+	})
+	m.put("/skydns/com/mydomain/com/example/my/10.0.1.1", value("10.0.1.1"))
+
+	records, ok := m.get("bucket")
+	if !ok {
+		t.Fatal("Expected map to be synced")
+	}
+	expected := []SrvRecord{
+		{Host: "10.0.0.1", Port: 9000, Key: "/bucket/"},
+		{Host: "10.0.0.2", Port: 9000, Key: "/bucket/"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+
+	records, _ = m.list()
+	expected = append(expected, SrvRecord{Host: "10.0.1.1", Port: 9000, Key: "/my.example.com/"})
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+
+	m.delete("/skydns/com/mydomain/bucket/10.0.0.1")
+	m.delete("/skydns/com/mydomain/bucket/10.0.0.2")
+	if records, _ = m.get("bucket"); len(records) != 0 {
+		t.Fatalf("Expected no records, got %v", records)
+	}
+
+	m.setSynced(false)
+	if _, ok = m.get("my.example.com"); ok {
+		t.Fatal("Expected map not to be synced")
+	}
+	other := newBucketMap("/skydns/com/mydomain")
+	other.put("/skydns/com/mydomain/bucket/10.0.0.3", value("10.0.0.3"))
+	m.replace(other)
+	records, ok = m.list()
+	expected = []SrvRecord{{Host: "10.0.0.3", Port: 9000, Key: "/bucket/"}}
+	if !ok || !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+}
@@ -21,20 +21,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/minio/minio-go/pkg/set"
 
 	"github.com/coredns/coredns/plugin/etcd/msg"
 	etcd "github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
 )
 
 // ErrNoEntriesFound - Indicates no entries were found for the given key (directory)
 var ErrNoEntriesFound = errors.New("No entries found for this key")
 
+// ErrBucketConflict - Indicates the bucket has entries of another cluster.
+var ErrBucketConflict = errors.New("Bucket entries exist for another cluster")
+
 // create a new coredns service record for the bucket.
 func newCoreDNSMsg(bucket, ip string, port int, ttl uint32) ([]byte, error) {
 	return json.Marshal(&SrvRecord{
@@ -45,88 +47,167 @@ func newCoreDNSMsg(bucket, ip string, port int, ttl uint32) ([]byte, error) {
 	})
 }
 
+// Returns the etcd key below which the entries of the domain are stored.
+func (c *coreDNS) domainKey() string {
+	return msg.Path(fmt.Sprintf("%s.", c.domainName), defaultPrefixPath)
+}
+
+// Returns the etcd key below which the entries of a bucket are stored.
+func (c *coreDNS) bucketKey(bucket string) string {
+	return msg.Path(fmt.Sprintf("%s.%s.", bucket, c.domainName), defaultPrefixPath) + "/"
+}
+
 // Retrieves list of DNS entries for the domain.
 func (c *coreDNS) List() ([]SrvRecord, error) {
-	key := msg.Path(fmt.Sprintf("%s.", c.domainName), defaultPrefixPath)
-	return c.list(key)
+	records, ok := c.buckets.list()
+	if !ok {
+		m, _, err := c.read(c.domainKey() + "/")
+		if err != nil {
+			return nil, err
+		}
+		records, _ = m.list()
+	}
+	if len(records) == 0 {
+		return nil, ErrNoEntriesFound
+	}
+	return records, nil
 }
 
 // Retrieves DNS records for a bucket.
 func (c *coreDNS) Get(bucket string) ([]SrvRecord, error) {
-	key := msg.Path(fmt.Sprintf("%s.%s.", bucket, c.domainName), defaultPrefixPath)
-	return c.list(key)
+	records, ok := c.buckets.get(bucket)
+	if !ok {
+		m, _, err := c.read(c.bucketKey(bucket))
+		if err != nil {
+			return nil, err
+		}
+		records, _ = m.get(bucket)
+	}
+	if len(records) == 0 {
+		return nil, ErrNoEntriesFound
+	}
+	return records, nil
 }
 
-// Retrieves list of entries under the key passed.
-// Note that this method fetches entries upto only two levels deep.
-func (c *coreDNS) list(key string) ([]SrvRecord, error) {
+// Reads the entries below key from etcd into a bucket map, and returns
+// the etcd revision of the read.
+func (c *coreDNS) read(key string) (*bucketMap, int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultContextTimeout)
-	r, err := c.etcdClient.Get(ctx, key, etcd.WithPrefix())
 	defer cancel()
+	r, err := c.etcdClient.Get(ctx, key, etcd.WithPrefix())
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	if r.Count == 0 {
-		key = strings.TrimSuffix(key, "/")
-		r, err = c.etcdClient.Get(ctx, key)
-		if err != nil {
-			return nil, err
-		}
-		if r.Count == 0 {
-			return nil, ErrNoEntriesFound
-		}
+	kvs := make(map[string][]byte, len(r.Kvs))
+	for _, kv := range r.Kvs {
+		kvs[string(kv.Key)] = kv.Value
 	}
+	m := newBucketMap(c.domainKey())
+	m.reset(kvs)
+	return m, r.Header.Revision, nil
+}
 
-	var srvRecords []SrvRecord
-	for _, n := range r.Kvs {
-		var srvRecord SrvRecord
-		if err = json.Unmarshal([]byte(n.Value), &srvRecord); err != nil {
-			return nil, err
+// Keeps the bucket map in sync with etcd. The map is loaded from a full
+// read of the domain and updated from the watch events following the
+// read, it is reloaded whenever the watch fails.
+func (c *coreDNS) watchBuckets() {
+	key := c.domainKey() + "/"
+	for c.etcdClient.Ctx().Err() == nil {
+		m, revision, err := c.read(key)
+		if err == nil {
+			c.buckets.replace(m)
+			ctx, cancel := context.WithCancel(etcd.WithRequireLeader(context.Background()))
+			for resp := range c.etcdClient.Watch(ctx, key, etcd.WithPrefix(), etcd.WithRev(revision+1)) {
+				if resp.Err() != nil {
+					break
+				}
+				for _, ev := range resp.Events {
+					switch ev.Type {
+					case mvccpb.PUT:
+						c.buckets.put(string(ev.Kv.Key), ev.Kv.Value)
+					case mvccpb.DELETE:
+						c.buckets.delete(string(ev.Kv.Key))
+					}
+				}
+			}
+			cancel()
+			c.buckets.setSynced(false)
 		}
-		srvRecord.Key = strings.TrimPrefix(string(n.Key), key)
-		srvRecord.Key = strings.TrimSuffix(srvRecord.Key, srvRecord.Host)
-		srvRecords = append(srvRecords, srvRecord)
-
-	}
-	if srvRecords != nil {
-		sort.Slice(srvRecords, func(i int, j int) bool {
-			return srvRecords[i].Key < srvRecords[j].Key
-		})
-	} else {
-		return nil, ErrNoEntriesFound
+		time.Sleep(defaultRetryInterval)
 	}
-	return srvRecords, nil
 }
 
 // Adds DNS entries into etcd endpoint in CoreDNS etcd message format.
+// The entries are only added if the bucket has no entries yet, entries
+// of another cluster indicate a bucket name collision.
 func (c *coreDNS) Put(bucket string) error {
+	key := c.bucketKey(bucket)
+	kvs := make(map[string][]byte, len(c.domainIPs))
+	var ops []etcd.Op
 	for ip := range c.domainIPs {
 		bucketMsg, err := newCoreDNSMsg(bucket, ip, c.domainPort, defaultTTL)
 		if err != nil {
 			return err
 		}
-		key := msg.Path(fmt.Sprintf("%s.%s", bucket, c.domainName), defaultPrefixPath)
-		key = key + "/" + ip
+		kvs[key+ip] = bucketMsg
+		ops = append(ops, etcd.OpPut(key+ip, string(bucketMsg)))
+	}
+
+	for {
+		// Entries of buckets with dots below the key of the bucket, e.g.
+		// 'foo.bucket' below 'bucket', are not entries of the bucket.
+		m, revision, err := c.read(key)
+		if err != nil {
+			return err
+		}
+		records, _ := m.get(bucket)
+		for _, record := range records {
+			if !c.domainIPs.Contains(record.Host) {
+				return ErrBucketConflict
+			}
+		}
+		if len(records) > 0 {
+			// The bucket is already registered by this cluster.
+			return nil
+		}
+
+		// Entries are only added if nothing changed below the key since
+		// the read, otherwise the entries are read again.
 		ctx, cancel := context.WithTimeout(context.Background(), defaultContextTimeout)
-		_, err = c.etcdClient.Put(ctx, key, string(bucketMsg))
-		defer cancel()
+		r, err := c.etcdClient.Txn(ctx).
+			If(etcd.Compare(etcd.ModRevision(key), "<", revision+1).WithPrefix()).
+			Then(ops...).
+			Commit()
+		cancel()
 		if err != nil {
-			ctx, cancel = context.WithTimeout(context.Background(), defaultContextTimeout)
-			c.etcdClient.Delete(ctx, key)
-			defer cancel()
 			return err
 		}
+		if r.Succeeded {
+			break
+		}
+	}
+	for k, v := range kvs {
+		c.buckets.put(k, v)
 	}
 	return nil
 }
 
 // Removes DNS entries added in Put().
 func (c *coreDNS) Delete(bucket string) error {
-	key := msg.Path(fmt.Sprintf("%s.%s.", bucket, c.domainName), defaultPrefixPath)
+	key := c.bucketKey(bucket)
+	var ops []etcd.Op
+	for ip := range c.domainIPs {
+		ops = append(ops, etcd.OpDelete(key+ip))
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), defaultContextTimeout)
-	_, err := c.etcdClient.Delete(ctx, key)
 	defer cancel()
-	return err
+	if _, err := c.etcdClient.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return err
+	}
+	for ip := range c.domainIPs {
+		c.buckets.delete(key + ip)
+	}
+	return nil
 }
 
 // CoreDNS - represents dns config for coredns server.
@@ -135,6 +216,8 @@ type coreDNS struct {
 	domainIPs  set.StringSet
 	domainPort int
 	etcdClient *etcd.Client
+	// entries of all buckets of the domain, kept in sync with etcd
+	buckets *bucketMap
 }
 
 // NewCoreDNS - initialize a new coreDNS set/unset values.
@@ -148,10 +231,13 @@ func NewCoreDNS(domainName string, domainIPs set.StringSet, domainPort string, e
 		return nil, err
 	}
 
-	return &coreDNS{
+	c := &coreDNS{
 		domainName: domainName,
 		domainIPs:  domainIPs,
 		domainPort: port,
 		etcdClient: etcdClient,
-	}, nil
+	}
+	c.buckets = newBucketMap(c.domainKey())
+	go c.watchBuckets()
+	return c, nil
 }
@@ -0,0 +1,469 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	etcd "github.com/coreos/etcd/clientv3"
+	pb "github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// testEtcd - in-memory etcd keyspace shared by the clients of several
+// clusters. It keeps the revisions, the prefix compares of transactions
+// and the watches from a revision which coreDNS relies on.
+type testEtcd struct {
+	etcd.KV
+	sync.Mutex
+	revision int64
+	kvs      map[string]*mvccpb.KeyValue
+	// all events in the order of their revisions.
+	events []*etcd.Event
+	// closed and replaced on every change.
+	changedCh chan struct{}
+}
+
+func newTestEtcd() *testEtcd {
+	return &testEtcd{
+		revision:  1,
+		kvs:       make(map[string]*mvccpb.KeyValue),
+		changedCh: make(chan struct{}),
+	}
+}
+
+// Returns true if key is in the range of op.
+func inRange(op etcd.Op, key string) bool {
+	if len(op.RangeBytes()) == 0 {
+		return key == string(op.KeyBytes())
+	}
+	return key >= string(op.KeyBytes()) && key < string(op.RangeBytes())
+}
+
+// Returns the keys in the range of op, the caller holds the lock.
+func (e *testEtcd) keys(op etcd.Op) []string {
+	var keys []string
+	for key := range e.kvs {
+		if inRange(op, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *testEtcd) Get(ctx context.Context, key string, opts ...etcd.OpOption) (*etcd.GetResponse, error) {
+	e.Lock()
+	defer e.Unlock()
+	r := &etcd.GetResponse{Header: &pb.ResponseHeader{Revision: e.revision}}
+	for _, key := range e.keys(etcd.OpGet(key, opts...)) {
+		r.Kvs = append(r.Kvs, e.kvs[key])
+	}
+	r.Count = int64(len(r.Kvs))
+	return r, nil
+}
+
+func (e *testEtcd) Txn(ctx context.Context) etcd.Txn {
+	return &testEtcdTxn{e: e}
+}
+
+// Applies the put and delete operations in a new revision, the caller
+// holds the lock.
+func (e *testEtcd) apply(ops []etcd.Op) error {
+	revision := e.revision + 1
+	var events []*etcd.Event
+	for _, op := range ops {
+		switch {
+		case op.IsPut():
+			kv := &mvccpb.KeyValue{Key: op.KeyBytes(), Value: op.ValueBytes(), ModRevision: revision, CreateRevision: revision, Version: 1}
+			if prev, ok := e.kvs[string(op.KeyBytes())]; ok {
+				kv.CreateRevision, kv.Version = prev.CreateRevision, prev.Version+1
+			}
+			e.kvs[string(kv.Key)] = kv
+			events = append(events, &etcd.Event{Type: mvccpb.PUT, Kv: kv})
+		case op.IsDelete():
+			for _, key := range e.keys(op) {
+				delete(e.kvs, key)
+				events = append(events, &etcd.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: revision}})
+			}
+		default:
+			return errors.New("unsupported operation")
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	e.revision = revision
+	e.events = append(e.events, events...)
+	close(e.changedCh)
+	e.changedCh = make(chan struct{})
+	return nil
+}
+
+// testEtcdTxn - transaction of which the conditions compare the
+// modification revision of a key or range.
+type testEtcdTxn struct {
+	e           *testEtcd
+	cmps        []etcd.Cmp
+	thens, elss []etcd.Op
+}
+
+func (t *testEtcdTxn) If(cs ...etcd.Cmp) etcd.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *testEtcdTxn) Then(ops ...etcd.Op) etcd.Txn {
+	t.thens = append(t.thens, ops...)
+	return t
+}
+
+func (t *testEtcdTxn) Else(ops ...etcd.Op) etcd.Txn {
+	t.elss = append(t.elss, ops...)
+	return t
+}
+
+// Returns true if the modification revision of a key compares to
+// cmp, a range without keys compares as a key of revision 0.
+func (t *testEtcdTxn) compare(cmp etcd.Cmp) (bool, error) {
+	target, ok := cmp.TargetUnion.(*pb.Compare_ModRevision)
+	if cmp.Target != pb.Compare_MOD || !ok {
+		return false, errors.New("unsupported comparison")
+	}
+	revisions := []int64{0}
+	if keys := t.e.keys(etcd.OpGet(string(cmp.Key), etcd.WithRange(string(cmp.RangeEnd)))); len(keys) > 0 {
+		revisions = revisions[:0]
+		for _, key := range keys {
+			revisions = append(revisions, t.e.kvs[key].ModRevision)
+		}
+	}
+	for _, revision := range revisions {
+		var result bool
+		switch cmp.Result {
+		case pb.Compare_EQUAL:
+			result = revision == target.ModRevision
+		case pb.Compare_NOT_EQUAL:
+			result = revision != target.ModRevision
+		case pb.Compare_LESS:
+			result = revision < target.ModRevision
+		case pb.Compare_GREATER:
+			result = revision > target.ModRevision
+		}
+		if !result {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (t *testEtcdTxn) Commit() (*etcd.TxnResponse, error) {
+	t.e.Lock()
+	defer t.e.Unlock()
+	succeeded := true
+	for _, cmp := range t.cmps {
+		ok, err := t.compare(cmp)
+		if err != nil {
+			return nil, err
+		}
+		succeeded = succeeded && ok
+	}
+	ops := t.thens
+	if !succeeded {
+		ops = t.elss
+	}
+	if err := t.e.apply(ops); err != nil {
+		return nil, err
+	}
+	return &etcd.TxnResponse{Header: &pb.ResponseHeader{Revision: t.e.revision}, Succeeded: succeeded}, nil
+}
+
+// testEtcdWatcher - watches the keyspace of a test etcd for a client.
+type testEtcdWatcher struct {
+	etcd.Watcher
+	e      *testEtcd
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (e *testEtcd) newWatcher() *testEtcdWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &testEtcdWatcher{e: e, ctx: ctx, cancel: cancel}
+}
+
+// Sends the events of the range of the watch from the requested
+// revision, until the watch or the watcher is closed.
+func (w *testEtcdWatcher) Watch(ctx context.Context, key string, opts ...etcd.OpOption) etcd.WatchChan {
+	op := etcd.OpGet(key, opts...)
+	ch := make(chan etcd.WatchResponse)
+	go func() {
+		defer close(ch)
+		// index of the next event in the events of the test etcd.
+		w.e.Lock()
+		next := sort.Search(len(w.e.events), func(i int) bool {
+			return w.e.events[i].Kv.ModRevision >= op.Rev()
+		})
+		w.e.Unlock()
+		for {
+			w.e.Lock()
+			var resp etcd.WatchResponse
+			for ; next < len(w.e.events); next++ {
+				if inRange(op, string(w.e.events[next].Kv.Key)) {
+					resp.Events = append(resp.Events, w.e.events[next])
+				}
+			}
+			resp.Header.Revision = w.e.revision
+			changedCh := w.e.changedCh
+			w.e.Unlock()
+
+			if len(resp.Events) > 0 {
+				select {
+				case ch <- resp:
+				case <-ctx.Done():
+					return
+				case <-w.ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-changedCh:
+			case <-ctx.Done():
+				return
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (w *testEtcdWatcher) Close() error {
+	w.cancel()
+	return nil
+}
+
+// Returns a coreDNS of a cluster with the IPs of domainIPs, connected to
+// the test etcd e, and a function closing it.
+func newTestCoreDNS(t *testing.T, e *testEtcd, domainIPs ...string) (*coreDNS, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client := etcd.NewCtxClient(ctx)
+	watcher := e.newWatcher()
+	client.KV, client.Watcher = e, watcher
+	c, err := NewCoreDNS("mydomain.com", set.CreateStringSet(domainIPs...), "9000", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*coreDNS), func() {
+		cancel()
+		watcher.Close()
+	}
+}
+
+// Waits until the bucket map of c is synced and holds the records of
+// bucket of the hosts.
+func waitBucketMap(t *testing.T, c *coreDNS, bucket string, hosts ...string) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		records, ok := c.buckets.get(bucket)
+		if ok {
+			found := set.NewStringSet()
+			for _, record := range records {
+				found.Add(record.Host)
+			}
+			if found.Equals(set.CreateStringSet(hosts...)) {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected records of %s in the bucket map of %v to be %v, got %v (synced %v)", bucket, c.domainIPs, hosts, records, ok)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Tests registering the same bucket from two clusters.
+func TestCoreDNSPutConflict(t *testing.T) {
+	e := newTestEtcd()
+	c1, close1 := newTestCoreDNS(t, e, "10.0.0.1", "10.0.0.2")
+	defer close1()
+	c2, close2 := newTestCoreDNS(t, e, "10.0.1.1")
+	defer close2()
+
+	if err := c1.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	// Registering a bucket again from the same cluster succeeds.
+	if err := c1.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.Put("bucket"); err != ErrBucketConflict {
+		t.Fatalf("Expected %v, got %v", ErrBucketConflict, err)
+	}
+	records, err := c2.Get("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !c1.domainIPs.Contains(records[0].Host) || !c1.domainIPs.Contains(records[1].Host) {
+		t.Fatalf("Expected records of the first cluster, got %v", records)
+	}
+
+	// Only one of two clusters registering a bucket concurrently succeeds.
+	for _, bucket := range []string{"bucket1", "bucket2", "bucket3", "bucket4"} {
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, c := range []*coreDNS{c1, c2} {
+			wg.Add(1)
+			go func(i int, c *coreDNS) {
+				defer wg.Done()
+				errs[i] = c.Put(bucket)
+			}(i, c)
+		}
+		wg.Wait()
+		if !(errs[0] == nil && errs[1] == ErrBucketConflict) && !(errs[0] == ErrBucketConflict && errs[1] == nil) {
+			t.Fatalf("%s: expected one registration to conflict, got %v", bucket, errs)
+		}
+		owner := c1
+		if errs[0] != nil {
+			owner = c2
+		}
+		m, _, err := c1.read(c1.bucketKey(bucket))
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, _ := m.get(bucket)
+		if len(records) != len(owner.domainIPs) {
+			t.Fatalf("%s: expected only the records of %v, got %v", bucket, owner.domainIPs, records)
+		}
+		for _, record := range records {
+			if !owner.domainIPs.Contains(record.Host) {
+				t.Fatalf("%s: expected only the records of %v, got %v", bucket, owner.domainIPs, records)
+			}
+		}
+	}
+}
+
+// Tests registering buckets with dots, of which the entries are stored
+// below the key of another bucket.
+func TestCoreDNSPutDottedBucket(t *testing.T) {
+	e := newTestEtcd()
+	c1, close1 := newTestCoreDNS(t, e, "10.0.0.1")
+	defer close1()
+	c2, close2 := newTestCoreDNS(t, e, "10.0.1.1")
+	defer close2()
+
+	if err := c1.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	// 'foo.bucket' is stored below 'bucket' but does not conflict with it.
+	if err := c2.Put("foo.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	// 'bar.bucket2' is registered before 'bucket2' by another cluster.
+	if err := c1.Put("bar.bucket2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.Put("bucket2"); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		bucket string
+		host   string
+	}{
+		{"bucket", "10.0.0.1"},
+		{"foo.bucket", "10.0.1.1"},
+		{"bar.bucket2", "10.0.0.1"},
+		{"bucket2", "10.0.1.1"},
+	}
+	for i, testCase := range testCases {
+		for _, c := range []*coreDNS{c1, c2} {
+			records, err := c.Get(testCase.bucket)
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if len(records) != 1 || records[0].Host != testCase.host {
+				t.Fatalf("Test %d: expected the record of %s, got %v", i+1, testCase.host, records)
+			}
+			waitBucketMap(t, c, testCase.bucket, testCase.host)
+		}
+	}
+
+	// Deleting a bucket keeps the entries of the buckets below it.
+	if err := c1.Delete("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	waitBucketMap(t, c2, "bucket")
+	if _, err := c2.Get("bucket"); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+	}
+	records, err := c2.Get("foo.bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Host != "10.0.1.1" {
+		t.Fatalf("Expected the record of 10.0.1.1, got %v", records)
+	}
+}
+
+// Tests that the bucket map follows the changes of other clusters.
+func TestCoreDNSWatchBuckets(t *testing.T) {
+	e := newTestEtcd()
+	c1, close1 := newTestCoreDNS(t, e, "10.0.0.1")
+	defer close1()
+	c2, close2 := newTestCoreDNS(t, e, "10.0.1.1")
+	defer close2()
+
+	waitBucketMap(t, c1, "bucket")
+	if err := c2.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	waitBucketMap(t, c1, "bucket", "10.0.1.1")
+	if err := c2.Put("foo.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	waitBucketMap(t, c1, "foo.bucket", "10.0.1.1")
+
+	records, err := c1.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %v", records)
+	}
+
+	if err = c2.Delete("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	waitBucketMap(t, c1, "bucket")
+	waitBucketMap(t, c1, "foo.bucket", "10.0.1.1")
+	if _, err = c1.Get("bucket"); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+	}
+
+	// A cluster connecting later loads the existing entries.
+	c3, close3 := newTestCoreDNS(t, e, "10.0.2.1")
+	defer close3()
+	waitBucketMap(t, c3, "foo.bucket", "10.0.1.1")
+	if err = c3.Put("foo.bucket"); err != ErrBucketConflict {
+		t.Fatalf("Expected %v, got %v", ErrBucketConflict, err)
+	}
+}
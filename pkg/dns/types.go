@@ -24,6 +24,10 @@ const (
 	defaultTTL            = 30
 	defaultPrefixPath     = "/skydns"
 	defaultContextTimeout = 5 * time.Minute
+	defaultRetryInterval  = 3 * time.Second
+	defaultWebhookTimeout = 30 * time.Second
+	// interval of the listings refreshing the bucket map of the webhook.
+	defaultWebhookRefreshInterval = defaultTTL * time.Second
 )
 
 // SrvRecord - represents a DNS service record
@@ -0,0 +1,287 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coredns/coredns/plugin/etcd/msg"
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// webhookDNS - represents dns config storing the bucket entries through
+// a HTTP key/value webhook. The entries are stored with the same keys
+// and in the same message format as the CoreDNS etcd entries:
+//
+//	PUT    <endpoint><key>            - stores the JSON entry of the body at key.
+//	DELETE <endpoint><key>            - removes the entry at key.
+//	GET    <endpoint>?prefix=<prefix> - returns a JSON object of all entries
+//	                                    whose key starts with prefix.
+type webhookDNS struct {
+	domainName string
+	domainIPs  set.StringSet
+	domainPort int
+	endpoint   *url.URL
+	httpClient *http.Client
+	// entries of all buckets of the domain, refreshed periodically
+	// from a listing of the webhook.
+	buckets *bucketMap
+	// serializes refreshes of the bucket map with the updates of Put()
+	// and Delete(), so a listing started before an update does not
+	// replace it.
+	bucketsMu sync.Mutex
+}
+
+// Interval of the refreshes of the bucket map, variable for testing.
+var webhookRefreshInterval = defaultWebhookRefreshInterval
+
+// Returns the key below which the entries of the domain are stored.
+func (c *webhookDNS) domainKey() string {
+	return msg.Path(fmt.Sprintf("%s.", c.domainName), defaultPrefixPath)
+}
+
+// Returns the key below which the entries of a bucket are stored.
+func (c *webhookDNS) bucketKey(bucket string) string {
+	return msg.Path(fmt.Sprintf("%s.%s.", bucket, c.domainName), defaultPrefixPath) + "/"
+}
+
+// Sends a request for key to the webhook, and returns the response if
+// the webhook replied with one of the expected status codes.
+func (c *webhookDNS) do(method, key string, query url.Values, body []byte, statusCodes ...int) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + key
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, statusCode := range statusCodes {
+		if resp.StatusCode == statusCode {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return nil, fmt.Errorf("%s %s on DNS webhook failed with %v", method, key, resp.Status)
+}
+
+// Lists the entries below key into a bucket map.
+func (c *webhookDNS) read(key string) (*bucketMap, error) {
+	resp, err := c.do(http.MethodGet, "", url.Values{"prefix": []string{key}}, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	kvs := make(map[string][]byte)
+	if resp.StatusCode == http.StatusOK {
+		var entries map[string]json.RawMessage
+		if err = json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			return nil, err
+		}
+		for k, v := range entries {
+			// Only keep the entries below key, in case the
+			// webhook ignores the prefix.
+			if strings.HasPrefix(k, key) {
+				kvs[k] = v
+			}
+		}
+	}
+	m := newBucketMap(c.domainKey())
+	m.reset(kvs)
+	return m, nil
+}
+
+// Replaces the bucket map with a listing of the domain. Lookups query
+// the webhook while the last listing failed.
+func (c *webhookDNS) refresh() {
+	c.bucketsMu.Lock()
+	defer c.bucketsMu.Unlock()
+	m, err := c.read(c.domainKey() + "/")
+	if err != nil {
+		c.buckets.setSynced(false)
+		return
+	}
+	c.buckets.replace(m)
+}
+
+// Refreshes the bucket map every interval, entries of other clusters
+// are seen by the lookups of this cluster after the next refresh.
+func (c *webhookDNS) refreshBuckets(interval time.Duration) {
+	for {
+		c.refresh()
+		time.Sleep(interval)
+	}
+}
+
+// Retrieves list of DNS entries for the domain.
+func (c *webhookDNS) List() ([]SrvRecord, error) {
+	records, ok := c.buckets.list()
+	if !ok {
+		m, err := c.read(c.domainKey() + "/")
+		if err != nil {
+			return nil, err
+		}
+		records, _ = m.list()
+	}
+	if len(records) == 0 {
+		return nil, ErrNoEntriesFound
+	}
+	return records, nil
+}
+
+// Retrieves DNS records for a bucket.
+func (c *webhookDNS) Get(bucket string) ([]SrvRecord, error) {
+	records, ok := c.buckets.get(bucket)
+	if !ok {
+		m, err := c.read(c.bucketKey(bucket))
+		if err != nil {
+			return nil, err
+		}
+		records, _ = m.get(bucket)
+	}
+	if len(records) == 0 {
+		return nil, ErrNoEntriesFound
+	}
+	return records, nil
+}
+
+// Adds DNS entries through the webhook in CoreDNS etcd message format.
+// The entries are only added if the bucket has no entries of another
+// cluster. Unlike etcd the webhook offers no transactions, so buckets
+// created concurrently on two clusters are not detected as conflict.
+func (c *webhookDNS) Put(bucket string) error {
+	// The entries are read from the webhook, the bucket map may not
+	// have the entries of other clusters yet.
+	key := c.bucketKey(bucket)
+	m, err := c.read(key)
+	if err != nil {
+		return err
+	}
+	records, _ := m.get(bucket)
+	for _, record := range records {
+		if !c.domainIPs.Contains(record.Host) {
+			return ErrBucketConflict
+		}
+	}
+	if len(records) > 0 {
+		// The bucket is already registered by this cluster.
+		return nil
+	}
+
+	kvs := make(map[string][]byte, len(c.domainIPs))
+	for ip := range c.domainIPs {
+		bucketMsg, err := newCoreDNSMsg(bucket, ip, c.domainPort, defaultTTL)
+		if err != nil {
+			return err
+		}
+		resp, err := c.do(http.MethodPut, key+ip, nil, bucketMsg, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		kvs[key+ip] = bucketMsg
+	}
+
+	c.bucketsMu.Lock()
+	defer c.bucketsMu.Unlock()
+	for k, v := range kvs {
+		c.buckets.put(k, v)
+	}
+	return nil
+}
+
+// Removes DNS entries added in Put().
+func (c *webhookDNS) Delete(bucket string) error {
+	key := c.bucketKey(bucket)
+	for ip := range c.domainIPs {
+		resp, err := c.do(http.MethodDelete, key+ip, nil, nil, http.StatusOK, http.StatusNoContent, http.StatusNotFound)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+
+	c.bucketsMu.Lock()
+	defer c.bucketsMu.Unlock()
+	for ip := range c.domainIPs {
+		c.buckets.delete(key + ip)
+	}
+	return nil
+}
+
+// NewWebhookDNS - initialize a new webhookDNS storing the entries through
+// the webhook at endpoint.
+func NewWebhookDNS(domainName string, domainIPs set.StringSet, domainPort string, endpoint string) (Config, error) {
+	if domainName == "" || domainIPs.IsEmpty() {
+		return nil, errors.New("invalid argument")
+	}
+
+	port, err := strconv.Atoi(domainPort)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid DNS webhook endpoint %s", endpoint)
+	}
+
+	c := &webhookDNS{
+		domainName: domainName,
+		domainIPs:  domainIPs,
+		domainPort: port,
+		endpoint:   u,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   5 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 10 * time.Second,
+				ExpectContinueTimeout: 1 * time.Second,
+			},
+			Timeout: defaultWebhookTimeout,
+		},
+	}
+	c.buckets = newBucketMap(c.domainKey())
+	go c.refreshBuckets(webhookRefreshInterval)
+	return c, nil
+}
@@ -0,0 +1,289 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dns
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// kvWebhook - in-memory key/value webhook handler.
+type kvWebhook struct {
+	sync.Mutex
+	kvs map[string]json.RawMessage
+	// number of listings served.
+	gets int
+}
+
+func (h *kvWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.Lock()
+	defer h.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/dns")
+	switch r.Method {
+	case http.MethodPut:
+		value, err := ioutil.ReadAll(r.Body)
+		if err != nil || !json.Valid(value) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.kvs[key] = value
+	case http.MethodDelete:
+		if _, ok := h.kvs[key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(h.kvs, key)
+	case http.MethodGet:
+		h.gets++
+		prefix := r.URL.Query().Get("prefix")
+		entries := make(map[string]json.RawMessage)
+		for k, v := range h.kvs {
+			if strings.HasPrefix(k, prefix) {
+				entries[k] = v
+			}
+		}
+		json.NewEncoder(w).Encode(entries)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestNewWebhookDNS(t *testing.T) {
+	ips := set.CreateStringSet("10.0.0.1")
+	testCases := []struct {
+		domainName string
+		domainIPs  set.StringSet
+		port       string
+		endpoint   string
+		success    bool
+	}{
+		{"mydomain.com", ips, "9000", "http://localhost:8080/dns", true},
+		{"mydomain.com", ips, "9000", "https://localhost:8080", true},
+		{"", ips, "9000", "http://localhost:8080", false},
+		{"mydomain.com", set.NewStringSet(), "9000", "http://localhost:8080", false},
+		{"mydomain.com", ips, "port", "http://localhost:8080", false},
+		{"mydomain.com", ips, "9000", "localhost:8080", false},
+		{"mydomain.com", ips, "9000", "ftp://localhost:8080", false},
+	}
+	for i, testCase := range testCases {
+		_, err := NewWebhookDNS(testCase.domainName, testCase.domainIPs, testCase.port, testCase.endpoint)
+		if success := err == nil; success != testCase.success {
+			t.Errorf("Test %d: expected success %v, got error %v", i+1, testCase.success, err)
+		}
+	}
+}
+
+func TestWebhookDNS(t *testing.T) {
+	handler := &kvWebhook{kvs: make(map[string]json.RawMessage)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	cluster1, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.0.1", "10.0.0.2"), "9000", server.URL+"/dns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster2, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.1.1"), "9000", server.URL+"/dns/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = cluster1.List(); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+	}
+	if err = cluster1.Put("my.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := handler.kvs["/skydns/com/mydomain/bucket/my/10.0.0.1"]; !ok {
+		t.Fatalf("Expected entry in CoreDNS key format, got %v", handler.kvs)
+	}
+	// Registering the bucket again on the same cluster succeeds.
+	if err = cluster1.Put("my.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if err = cluster2.Put("my.bucket"); err != ErrBucketConflict {
+		t.Fatalf("Expected %v, got %v", ErrBucketConflict, err)
+	}
+	if err = cluster2.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bucket maps only have the entries of the other cluster after
+	// a refresh.
+	cluster1.(*webhookDNS).refresh()
+	cluster2.(*webhookDNS).refresh()
+	records, err := cluster2.Get("my.bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Host != "10.0.0.1" || records[1].Host != "10.0.0.2" ||
+		records[0].Key != "/my.bucket/" || records[0].Port != 9000 {
+		t.Fatalf("Unexpected records %v", records)
+	}
+	records, err = cluster1.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].Key != "/bucket/" || records[0].Host != "10.0.1.1" {
+		t.Fatalf("Unexpected records %v", records)
+	}
+
+	if err = cluster1.Delete("my.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	// Deleting entries which do not exist succeeds.
+	if err = cluster1.Delete("my.bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cluster1.Get("my.bucket"); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+	}
+	if err = cluster1.Put("my.bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Close()
+	cluster1.(*webhookDNS).refresh()
+	if _, err = cluster1.Get("bucket"); err == nil {
+		t.Fatal("Expected error on unreachable webhook")
+	}
+}
+
+// Tests that lookups are served from the bucket map without querying the
+// webhook.
+func TestWebhookDNSBucketMap(t *testing.T) {
+	handler := &kvWebhook{kvs: make(map[string]json.RawMessage)}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	defer func(interval time.Duration) {
+		webhookRefreshInterval = interval
+	}(webhookRefreshInterval)
+	webhookRefreshInterval = time.Hour
+	cluster1, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.0.1"), "9000", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster2, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.1.1"), "9000", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2 := cluster2.(*webhookDNS)
+	c2.refresh()
+
+	gets := func() int {
+		handler.Lock()
+		defer handler.Unlock()
+		return handler.gets
+	}
+
+	// Entries of this cluster are seen right after they are added, and
+	// lookups do not query the webhook.
+	if err = cluster2.Put("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	n := gets()
+	for i := 0; i < 10; i++ {
+		records, err := cluster2.Get("bucket")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].Host != "10.0.1.1" {
+			t.Fatalf("Unexpected records %v", records)
+		}
+		if _, err = cluster2.Get("nobucket"); err != ErrNoEntriesFound {
+			t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+		}
+		if _, err = cluster2.List(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gets() != n {
+		t.Fatalf("Expected lookups not to query the webhook, got %d queries", gets()-n)
+	}
+
+	// Entries of other clusters are seen after a refresh.
+	if err = cluster1.Put("bucket1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cluster2.Get("bucket1"); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v before a refresh, got %v", ErrNoEntriesFound, err)
+	}
+	c2.refresh()
+	if _, err = cluster2.Get("bucket1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = cluster2.Delete("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cluster2.Get("bucket"); err != ErrNoEntriesFound {
+		t.Fatalf("Expected %v, got %v", ErrNoEntriesFound, err)
+	}
+
+	// The bucket map is refreshed periodically.
+	webhookRefreshInterval = 10 * time.Millisecond
+	cluster3, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.2.1"), "9000", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cluster3.Get("bucket1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = cluster1.Put("bucket3"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		records, ok := cluster3.(*webhookDNS).buckets.get("bucket3")
+		if ok && len(records) == 1 && records[0].Host == "10.0.0.1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the bucket map to be refreshed, got %v", records)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhookDNSErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte("invalid"))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c, err := NewWebhookDNS("mydomain.com", set.CreateStringSet("10.0.0.1"), "9000", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.List(); err == nil {
+		t.Fatal("Expected error on invalid listing")
+	}
+	if err = c.Delete("bucket"); err == nil {
+		t.Fatal("Expected error on failed delete")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	ldap "gopkg.in/ldap.v3"
+)
+
+const (
+	// Default expiry of temporary credentials of LDAP users.
+	defaultSTSExpiry = time.Hour
+
+	// Default attribute of group entries carrying group name.
+	defaultGroupNameAttribute = "cn"
+
+	// Timeout of connecting to LDAP server.
+	dialTimeout = 30 * time.Second
+)
+
+// ErrInvalidCredentials - returned when username or password is invalid.
+var ErrInvalidCredentials = errors.New("invalid LDAP username or password")
+
+// Config - LDAP identity backend configuration.
+//
+// UserSearchFilter may contain "%s" which is replaced by the username.
+// GroupSearchFilter may contain "%s" which is replaced by the username and
+// "%d" which is replaced by the DN of the user.
+type Config struct {
+	ServerAddr         string
+	Insecure           bool
+	SkipTLSVerify      bool
+	BindDN             string
+	BindPassword       string
+	UserSearchBaseDN   string
+	UserSearchFilter   string
+	GroupSearchBaseDN  string
+	GroupSearchFilter  string
+	GroupNameAttribute string
+	STSExpiry          time.Duration
+}
+
+// IsEnabled - returns whether LDAP identity backend is configured or not.
+func (c Config) IsEnabled() bool {
+	return c.ServerAddr != ""
+}
+
+// Validate - validates LDAP configuration.
+func (c Config) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(c.ServerAddr); err != nil {
+		return fmt.Errorf("invalid LDAP server address %v, %v", c.ServerAddr, err)
+	}
+
+	if c.UserSearchBaseDN == "" || c.UserSearchFilter == "" {
+		return errors.New("LDAP user search base DN and filter must be set")
+	}
+
+	if (c.GroupSearchBaseDN == "") != (c.GroupSearchFilter == "") {
+		return errors.New("LDAP group search base DN and filter must be set together")
+	}
+
+	if c.STSExpiry < 0 {
+		return fmt.Errorf("invalid LDAP STS expiry %v", c.STSExpiry)
+	}
+
+	return nil
+}
+
+// GetSTSExpiry - returns expiry duration of temporary credentials.
+func (c Config) GetSTSExpiry() time.Duration {
+	if c.STSExpiry == 0 {
+		return defaultSTSExpiry
+	}
+	return c.STSExpiry
+}
+
+// connect - connects to LDAP server.
+func (c Config) connect() (*ldap.Conn, error) {
+	if c.Insecure {
+		conn, err := net.DialTimeout("tcp", c.ServerAddr, dialTimeout)
+		if err != nil {
+			return nil, err
+		}
+		l := ldap.NewConn(conn, false)
+		l.Start()
+		return l, nil
+	}
+
+	host, _, err := net.SplitHostPort(c.ServerAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", c.ServerAddr, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: c.SkipTLSVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+	l := ldap.NewConn(conn, true)
+	l.Start()
+	return l, nil
+}
+
+// search - returns entries matching filter under baseDN.
+func search(l *ldap.Conn, baseDN, filter string, attributes []string) ([]*ldap.Entry, error) {
+	result, err := l.Search(ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0, 0, false,
+		filter,
+		attributes,
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+// Authenticate - authenticates given user with LDAP server and returns DN
+// of the user and names of the groups the user belongs to.
+func (c Config) Authenticate(username, password string) (userDN string, groups []string, err error) {
+	if !c.IsEnabled() {
+		return "", nil, errors.New("LDAP identity backend is not configured")
+	}
+
+	// Empty password results in unauthenticated bind, which always succeeds.
+	if username == "" || password == "" {
+		return "", nil, ErrInvalidCredentials
+	}
+
+	l, err := c.connect()
+	if err != nil {
+		return "", nil, err
+	}
+	defer l.Close()
+
+	// Bind as service account to search the user.
+	if c.BindDN != "" {
+		if err = l.Bind(c.BindDN, c.BindPassword); err != nil {
+			return "", nil, fmt.Errorf("unable to bind LDAP service account, %v", err)
+		}
+	}
+
+	filter := strings.Replace(c.UserSearchFilter, "%s", ldap.EscapeFilter(username), -1)
+	entries, err := search(l, c.UserSearchBaseDN, filter, []string{"dn"})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(entries) != 1 {
+		// Unknown or ambiguous user.
+		return "", nil, ErrInvalidCredentials
+	}
+	userDN = entries[0].DN
+
+	// Bind as the user to verify the password.
+	if err = l.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return "", nil, ErrInvalidCredentials
+		}
+		return "", nil, err
+	}
+
+	if c.GroupSearchFilter == "" {
+		return userDN, nil, nil
+	}
+
+	// Search groups with service account again as the user might not
+	// have access to group entries.
+	if c.BindDN != "" {
+		if err = l.Bind(c.BindDN, c.BindPassword); err != nil {
+			return "", nil, fmt.Errorf("unable to bind LDAP service account, %v", err)
+		}
+	}
+
+	groupNameAttribute := c.GroupNameAttribute
+	if groupNameAttribute == "" {
+		groupNameAttribute = defaultGroupNameAttribute
+	}
+
+	filter = strings.Replace(c.GroupSearchFilter, "%s", ldap.EscapeFilter(username), -1)
+	filter = strings.Replace(filter, "%d", ldap.EscapeFilter(userDN), -1)
+	entries, err = search(l, c.GroupSearchBaseDN, filter, []string{groupNameAttribute})
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, entry := range entries {
+		if name := entry.GetAttributeValue(groupNameAttribute); name != "" {
+			groups = append(groups, name)
+		}
+	}
+
+	return userDN, groups, nil
+}
@@ -0,0 +1,166 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/iam/ldap/ldaptest"
+)
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config    Config
+		expectErr bool
+	}{
+		// Disabled configuration is always valid.
+		{Config{}, false},
+		{Config{ServerAddr: "localhost:389", UserSearchBaseDN: "ou=people,dc=min,dc=io", UserSearchFilter: "(uid=%s)"}, false},
+		{Config{ServerAddr: "localhost:389", UserSearchBaseDN: "ou=people,dc=min,dc=io", UserSearchFilter: "(uid=%s)",
+			GroupSearchBaseDN: "ou=groups,dc=min,dc=io", GroupSearchFilter: "(member=%d)"}, false},
+		// Missing port.
+		{Config{ServerAddr: "localhost", UserSearchBaseDN: "ou=people,dc=min,dc=io", UserSearchFilter: "(uid=%s)"}, true},
+		// Missing user search filter.
+		{Config{ServerAddr: "localhost:389", UserSearchBaseDN: "ou=people,dc=min,dc=io"}, true},
+		// Group search base DN without filter.
+		{Config{ServerAddr: "localhost:389", UserSearchBaseDN: "ou=people,dc=min,dc=io", UserSearchFilter: "(uid=%s)",
+			GroupSearchBaseDN: "ou=groups,dc=min,dc=io"}, true},
+		// Negative expiry.
+		{Config{ServerAddr: "localhost:389", UserSearchBaseDN: "ou=people,dc=min,dc=io", UserSearchFilter: "(uid=%s)",
+			STSExpiry: -time.Hour}, true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestConfigGetSTSExpiry(t *testing.T) {
+	if expiry := (Config{}).GetSTSExpiry(); expiry != defaultSTSExpiry {
+		t.Fatalf("expected: %v, got: %v", defaultSTSExpiry, expiry)
+	}
+	if expiry := (Config{STSExpiry: time.Minute}).GetSTSExpiry(); expiry != time.Minute {
+		t.Fatalf("expected: %v, got: %v", time.Minute, expiry)
+	}
+}
+
+func TestConfigAuthenticate(t *testing.T) {
+	server := ldaptest.NewServer(
+		ldaptest.Entry{
+			DN:         "cn=admin,dc=min,dc=io",
+			Attributes: map[string][]string{"userPassword": {"adminpassword"}},
+		},
+		ldaptest.Entry{
+			DN: "uid=alice,ou=people,dc=min,dc=io",
+			Attributes: map[string][]string{
+				"objectClass":  {"inetOrgPerson"},
+				"uid":          {"alice"},
+				"userPassword": {"alicepassword"},
+			},
+		},
+		ldaptest.Entry{
+			DN: "uid=bob,ou=people,dc=min,dc=io",
+			Attributes: map[string][]string{
+				"objectClass":  {"inetOrgPerson"},
+				"uid":          {"bob"},
+				"userPassword": {"bobpassword"},
+			},
+		},
+		ldaptest.Entry{
+			DN: "cn=dev,ou=groups,dc=min,dc=io",
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"dev"},
+				"member":      {"uid=alice,ou=people,dc=min,dc=io", "uid=bob,ou=people,dc=min,dc=io"},
+			},
+		},
+		ldaptest.Entry{
+			DN: "cn=ops,ou=groups,dc=min,dc=io",
+			Attributes: map[string][]string{
+				"objectClass": {"groupOfNames"},
+				"cn":          {"ops"},
+				"member":      {"uid=alice,ou=people,dc=min,dc=io"},
+			},
+		},
+	)
+	defer server.Close()
+
+	config := Config{
+		ServerAddr:        server.Addr,
+		Insecure:          true,
+		BindDN:            "cn=admin,dc=min,dc=io",
+		BindPassword:      "adminpassword",
+		UserSearchBaseDN:  "ou=people,dc=min,dc=io",
+		UserSearchFilter:  "(&(objectClass=inetOrgPerson)(uid=%s))",
+		GroupSearchBaseDN: "ou=groups,dc=min,dc=io",
+		GroupSearchFilter: "(&(objectClass=groupOfNames)(member=%d))",
+	}
+
+	noGroupConfig := config
+	noGroupConfig.GroupSearchBaseDN = ""
+	noGroupConfig.GroupSearchFilter = ""
+
+	badBindConfig := config
+	badBindConfig.BindPassword = "wrongpassword"
+
+	testCases := []struct {
+		config         Config
+		username       string
+		password       string
+		expectedDN     string
+		expectedGroups []string
+		expectedErr    error
+		expectErr      bool
+	}{
+		{config, "alice", "alicepassword", "uid=alice,ou=people,dc=min,dc=io", []string{"dev", "ops"}, nil, false},
+		{config, "bob", "bobpassword", "uid=bob,ou=people,dc=min,dc=io", []string{"dev"}, nil, false},
+		{noGroupConfig, "alice", "alicepassword", "uid=alice,ou=people,dc=min,dc=io", nil, nil, false},
+		// Wrong password.
+		{config, "alice", "bobpassword", "", nil, ErrInvalidCredentials, true},
+		// Empty password.
+		{config, "alice", "", "", nil, ErrInvalidCredentials, true},
+		// Unknown user.
+		{config, "carol", "carolpassword", "", nil, ErrInvalidCredentials, true},
+		// Filter injection is escaped.
+		{config, "*", "alicepassword", "", nil, ErrInvalidCredentials, true},
+		// Service account bind fails.
+		{badBindConfig, "alice", "alicepassword", "", nil, nil, true},
+		// Disabled configuration.
+		{Config{}, "alice", "alicepassword", "", nil, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		userDN, groups, err := testCase.config.Authenticate(testCase.username, testCase.password)
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if testCase.expectedErr != nil && err != testCase.expectedErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+		}
+		if userDN != testCase.expectedDN {
+			t.Fatalf("case %v: user DN: expected: %v, got: %v", i+1, testCase.expectedDN, userDN)
+		}
+		if !reflect.DeepEqual(groups, testCase.expectedGroups) {
+			t.Fatalf("case %v: groups: expected: %v, got: %v", i+1, testCase.expectedGroups, groups)
+		}
+	}
+}
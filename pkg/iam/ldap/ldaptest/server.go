@@ -0,0 +1,314 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ldaptest provides an in-process LDAP server for testing. It
+// understands plain text simple bind, search and unbind operations only.
+package ldaptest
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	ber "gopkg.in/asn1-ber.v1"
+	ldap "gopkg.in/ldap.v3"
+)
+
+// PasswordAttribute - attribute of an entry holding its bind password.
+const PasswordAttribute = "userPassword"
+
+// Entry - a directory entry served by Server.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// get - returns values of given attribute, attribute names are case
+// insensitive.
+func (e Entry) get(name string) []string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Server - in-process LDAP server listening on loopback interface.
+type Server struct {
+	Addr string
+
+	listener net.Listener
+	entries  []Entry
+
+	mutex sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// NewServer - starts and returns a new LDAP server serving given entries.
+func NewServer(entries ...Entry) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("ldaptest: failed to listen on a port: " + err.Error())
+	}
+
+	s := &Server{
+		Addr:     l.Addr().String(),
+		listener: l,
+		entries:  entries,
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close - shuts down the server and closes all its connections.
+func (s *Server) Close() {
+	s.listener.Close()
+
+	s.mutex.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(conn)
+
+			s.mutex.Lock()
+			delete(s.conns, conn)
+			s.mutex.Unlock()
+			conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value
+		op := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = []*ber.Packet{s.bind(op)}
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(op)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			return
+		}
+
+		for _, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+			envelope.AppendChild(response)
+			if _, err = conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// findEntry - returns entry of given DN.
+func (s *Server) findEntry(dn string) (Entry, bool) {
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (s *Server) bind(op *ber.Packet) *ber.Packet {
+	resultCode := ldap.LDAPResultInvalidCredentials
+	if len(op.Children) == 3 {
+		dn, _ := op.Children[1].Value.(string)
+		password := op.Children[2].Data.String()
+		if entry, ok := s.findEntry(dn); ok {
+			for _, p := range entry.get(PasswordAttribute) {
+				if password != "" && p == password {
+					resultCode = ldap.LDAPResultSuccess
+				}
+			}
+		}
+	}
+
+	return newResult(ldap.ApplicationBindResponse, resultCode)
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) != 8 {
+		return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError)}
+	}
+
+	baseDN, _ := op.Children[0].Value.(string)
+	filter := op.Children[6]
+	var attributes []string
+	for _, attr := range op.Children[7].Children {
+		if name, ok := attr.Value.(string); ok {
+			attributes = append(attributes, name)
+		}
+	}
+
+	var responses []*ber.Packet
+	for _, entry := range s.entries {
+		if !isUnder(entry.DN, baseDN) || !match(entry, filter) {
+			continue
+		}
+
+		packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "Object Name"))
+		attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for _, name := range attributes {
+			values := entry.get(name)
+			if len(values) == 0 || strings.EqualFold(name, PasswordAttribute) {
+				continue
+			}
+			attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attr.AppendChild(set)
+			attrs.AppendChild(attr)
+		}
+		packet.AppendChild(attrs)
+		responses = append(responses, packet)
+	}
+
+	return append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+}
+
+// newResult - returns LDAPResult of given operation with result code.
+func newResult(tag ber.Tag, resultCode int) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return packet
+}
+
+// isUnder - returns whether dn is same as or a descendant of baseDN.
+func isUnder(dn, baseDN string) bool {
+	dn = strings.ToLower(strings.Replace(dn, " ", "", -1))
+	baseDN = strings.ToLower(strings.Replace(baseDN, " ", "", -1))
+	return baseDN == "" || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+}
+
+// match - evaluates filter on entry. Only and, or, not, equality, present
+// and substrings filters are supported.
+func match(entry Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !match(entry, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if match(entry, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !match(entry, filter.Children[0])
+	case ldap.FilterPresent:
+		name := filter.Data.String()
+		return strings.EqualFold(name, "objectClass") || len(entry.get(name)) > 0
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, _ := filter.Children[0].Value.(string)
+		value, _ := filter.Children[1].Value.(string)
+		for _, v := range entry.get(name) {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+		name, _ := filter.Children[0].Value.(string)
+		for _, v := range entry.get(name) {
+			if matchSubstrings(strings.ToLower(v), filter.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+func matchSubstrings(value string, substrings []*ber.Packet) bool {
+	for _, substring := range substrings {
+		s := strings.ToLower(substring.Data.String())
+		switch substring.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, s) {
+				return false
+			}
+			value = value[len(s):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(value, s)
+			if i < 0 {
+				return false
+			}
+			value = value[i+len(s):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, s) {
+				return false
+			}
+			value = ""
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodePublicKey(t *testing.T) {
+	const jsonkey = `{"keys":
+       [
+         {"kty":"EC",
+          "crv":"P-256",
+          "x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+          "y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+          "use":"enc",
+          "kid":"1"},
+
+         {"kty":"RSA",
+          "n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+          "e":"AQAB",
+          "alg":"RS256",
+          "kid":"2011-04-29"}
+       ]
+     }`
+
+	var jk JWKS
+	if err := json.Unmarshal([]byte(jsonkey), &jk); err != nil {
+		t.Fatal("Unmarshal: ", err)
+	}
+
+	if len(jk.Keys) != 2 {
+		t.Fatalf("keys: expected: 2, got: %v", len(jk.Keys))
+	}
+
+	pubKey, err := jk.Keys[0].DecodePublicKey()
+	if err != nil {
+		t.Fatalf("EC key: unexpected error: %v", err)
+	}
+	if _, ok := pubKey.(*ecdsa.PublicKey); !ok {
+		t.Fatalf("EC key: expected: *ecdsa.PublicKey, got: %T", pubKey)
+	}
+
+	pubKey, err = jk.Keys[1].DecodePublicKey()
+	if err != nil {
+		t.Fatalf("RSA key: unexpected error: %v", err)
+	}
+	rsaKey, ok := pubKey.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("RSA key: expected: *rsa.PublicKey, got: %T", pubKey)
+	}
+	if rsaKey.E != 65537 {
+		t.Fatalf("RSA key: exponent: expected: 65537, got: %v", rsaKey.E)
+	}
+
+	testCases := []*JWKS{
+		{Kty: "RSA", E: "AQAB"},
+		{Kty: "RSA", N: "0vx7", E: "AQAB!"},
+		{Kty: "EC", Crv: "P-256", X: "MKBC"},
+		{Kty: "EC", Crv: "P-999", X: "MKBC", Y: "4Etl"},
+		{Kty: "EC", Crv: "P-256", X: "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4", Y: "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"},
+		{Kty: "oct", K: "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"},
+	}
+
+	for i, key := range testCases {
+		if _, err := key.DecodePublicKey(); err == nil {
+			t.Fatalf("test %v: expected error, got none", i+1)
+		}
+	}
+}
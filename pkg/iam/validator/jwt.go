@@ -0,0 +1,323 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+// JWTID - identifier of JWT validator.
+const JWTID ID = "jwt"
+
+const (
+	// Default duration of temporary credentials.
+	defaultDuration = time.Hour
+
+	// Minimum and maximum duration of temporary credentials.
+	minDuration = 15 * time.Minute
+	maxDuration = 12 * time.Hour
+
+	// Timeout for fetching JWKS from a URL.
+	jwksFetchTimeout = 10 * time.Second
+
+	// Maximum size of JWKS document.
+	maxJWKSSize = 1024 * 1024
+)
+
+// Minimum interval between reloads of JWKS for tokens of unknown key IDs.
+var jwksMinRefreshInterval = 5 * time.Minute
+
+// JWKSArgs - JWKS arguments. URL is either HTTP(S) URL or path to
+// local file containing JSON web key set. If set, the "aud" claim of
+// tokens has to contain Audience and the "iss" claim has to be Issuer.
+type JWKSArgs struct {
+	URL      *xnet.URL `json:"url"`
+	Audience string    `json:"audience,omitempty"`
+	Issuer   string    `json:"issuer,omitempty"`
+}
+
+// IsEmpty - returns whether JWKS URL is configured or not.
+func (args JWKSArgs) IsEmpty() bool {
+	return args.URL == nil || args.URL.IsEmpty()
+}
+
+// Validate - validates JWKS arguments.
+func (args JWKSArgs) Validate() error {
+	if args.IsEmpty() {
+		return nil
+	}
+
+	switch args.URL.Scheme {
+	case "http", "https":
+	case "":
+		if args.URL.Path == "" {
+			return errors.New("empty JWKS file path")
+		}
+	default:
+		return fmt.Errorf("unsupported JWKS URL scheme %v", args.URL.Scheme)
+	}
+
+	return nil
+}
+
+// read - returns reader of JWKS document.
+func (args JWKSArgs) read() (io.ReadCloser, error) {
+	if args.URL.Scheme == "" {
+		return os.Open(args.URL.Path)
+	}
+
+	client := &http.Client{Timeout: jwksFetchTimeout}
+	resp, err := client.Get(args.URL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to fetch JWKS from %v, %v", args.URL, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// PublicKeys - reads JWKS and returns public keys indexed by key ID.
+func (args JWKSArgs) PublicKeys() (map[string]crypto.PublicKey, error) {
+	if args.IsEmpty() {
+		return nil, errors.New("JWKS URL is not configured")
+	}
+
+	reader, err := args.read()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(reader, maxJWKSSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks JWKS
+	if err = json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+
+	publicKeys := make(map[string]crypto.PublicKey)
+	for _, key := range jwks.Keys {
+		pubKey, err := key.DecodePublicKey()
+		if err != nil {
+			return nil, err
+		}
+		publicKeys[key.Kid] = pubKey
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no public keys found in JWKS")
+	}
+
+	return publicKeys, nil
+}
+
+// JWT - validator of JSON web tokens signed by keys of an OpenID
+// provider's JWKS.
+type JWT struct {
+	sync.RWMutex
+	args       JWKSArgs
+	publicKeys map[string]crypto.PublicKey
+	// time of last reload for an unknown key ID.
+	lastRefresh time.Time
+}
+
+// ID - returns JWT validator ID.
+func (p *JWT) ID() ID {
+	return JWTID
+}
+
+// refresh - reloads public keys from JWKS.
+func (p *JWT) refresh() error {
+	publicKeys, err := p.args.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	p.publicKeys = publicKeys
+	p.Unlock()
+	return nil
+}
+
+// refreshUnknownKey - reloads public keys from JWKS for a token of an
+// unknown key ID, at most once per jwksMinRefreshInterval, so tokens of
+// made up key IDs cannot flood the identity provider with requests.
+func (p *JWT) refreshUnknownKey() error {
+	p.Lock()
+	if time.Since(p.lastRefresh) < jwksMinRefreshInterval {
+		p.Unlock()
+		return errors.New("JWKS was reloaded recently")
+	}
+	p.lastRefresh = time.Now()
+	p.Unlock()
+
+	return p.refresh()
+}
+
+// getPublicKey - returns public key of given key ID. If key ID is
+// empty and only one key is available, that key is returned.
+func (p *JWT) getPublicKey(kid string) (crypto.PublicKey, bool) {
+	p.RLock()
+	defer p.RUnlock()
+
+	if kid == "" && len(p.publicKeys) == 1 {
+		for _, key := range p.publicKeys {
+			return key, true
+		}
+	}
+
+	key, ok := p.publicKeys[kid]
+	return key, ok
+}
+
+// getDuration - parses duration in seconds, default duration is
+// returned if empty.
+func getDuration(dsecs string) (time.Duration, error) {
+	if dsecs == "" {
+		return defaultDuration, nil
+	}
+
+	secs, err := strconv.ParseInt(dsecs, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidDuration
+	}
+
+	duration := time.Duration(secs) * time.Second
+	if duration < minDuration || duration > maxDuration {
+		return 0, ErrInvalidDuration
+	}
+
+	return duration, nil
+}
+
+// hasAudience - returns whether "aud" claim, which is either a string or
+// an array of strings, contains given audience.
+func hasAudience(claims jwtgo.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate - validates JSON web token and returns its claims. The "exp"
+// claim is updated to expiry of temporary credentials, which is the
+// smaller of requested duration and expiry of the token.
+func (p *JWT) Validate(token, dsecs string) (map[string]interface{}, error) {
+	duration, err := getDuration(dsecs)
+	if err != nil {
+		return nil, err
+	}
+
+	keyFunc := func(jwtToken *jwtgo.Token) (interface{}, error) {
+		kid, _ := jwtToken.Header["kid"].(string)
+		if key, ok := p.getPublicKey(kid); ok {
+			return key, nil
+		}
+
+		// Key might have been rotated, reload JWKS.
+		if err := p.refreshUnknownKey(); err != nil {
+			return nil, fmt.Errorf("unknown key ID %v, %v", kid, err)
+		}
+
+		if key, ok := p.getPublicKey(kid); ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("unknown key ID %v", kid)
+	}
+
+	parser := &jwtgo.Parser{
+		ValidMethods: []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"},
+	}
+
+	claims := jwtgo.MapClaims{}
+	jwtToken, err := parser.ParseWithClaims(token, claims, keyFunc)
+	if err != nil {
+		if verr, ok := err.(*jwtgo.ValidationError); ok && verr.Errors&jwtgo.ValidationErrorExpired != 0 {
+			return nil, ErrTokenExpired
+		}
+		return nil, err
+	}
+
+	if !jwtToken.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if p.args.Audience != "" && !hasAudience(claims, p.args.Audience) {
+		return nil, errors.New("token is not issued for this audience")
+	}
+
+	if p.args.Issuer != "" && !claims.VerifyIssuer(p.args.Issuer, true) {
+		return nil, errors.New("token is not issued by this issuer")
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("exp claim missing in token")
+	}
+
+	now := time.Now().UTC()
+	expiry := time.Unix(int64(exp), 0).UTC()
+	if !expiry.After(now) {
+		return nil, ErrTokenExpired
+	}
+
+	if expiry.Sub(now) > duration {
+		expiry = now.Add(duration)
+	}
+	claims["exp"] = expiry.Unix()
+
+	return claims, nil
+}
+
+// NewJWT - creates new JWT validator. Public keys are loaded lazily on
+// first validation if JWKS is not reachable now.
+func NewJWT(args JWKSArgs) *JWT {
+	p := &JWT{
+		args:       args,
+		publicKeys: make(map[string]crypto.PublicKey),
+	}
+	p.refresh()
+	return p
+}
@@ -0,0 +1,269 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+func newTestRSAJWKS(t *testing.T, kid string) (*rsa.PrivateKey, []byte) {
+	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := JWKS{
+		Keys: []*JWKS{{
+			Kty: "RSA",
+			Kid: kid,
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(privKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privKey.E)).Bytes()),
+		}},
+	}
+
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return privKey, data
+}
+
+func newTestToken(t *testing.T, privKey *rsa.PrivateKey, kid string, expiry time.Time) string {
+	return newTestTokenWithClaims(t, privKey, kid, jwtgo.MapClaims{
+		"exp": expiry.Unix(),
+		"sub": "minio",
+	})
+}
+
+func newTestTokenWithClaims(t *testing.T, privKey *rsa.PrivateKey, kid string, claims jwtgo.MapClaims) string {
+	jwt := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+	jwt.Header["kid"] = kid
+
+	token, err := jwt.SignedString(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestJWKSArgsValidate(t *testing.T) {
+	testCases := []struct {
+		url       string
+		expectErr bool
+	}{
+		{"", false},
+		{"https://example.com/.well-known/jwks.json", false},
+		{"/etc/minio/jwks.json", false},
+		{"ftp://example.com/jwks.json", true},
+	}
+
+	for i, testCase := range testCases {
+		var args JWKSArgs
+		if testCase.url != "" {
+			u, err := xnet.ParseURL(testCase.url)
+			if err != nil {
+				t.Fatalf("test %v: unexpected error: %v", i+1, err)
+			}
+			args.URL = u
+		}
+
+		err := args.Validate()
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestJWT(t *testing.T) {
+	privKey, jwksData := newTestRSAJWKS(t, "key1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwksData)
+	}))
+	defer server.Close()
+
+	u, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwt := NewJWT(JWKSArgs{URL: u})
+	if jwt.ID() != JWTID {
+		t.Fatalf("ID: expected: %v, got: %v", JWTID, jwt.ID())
+	}
+
+	otherKey, _ := newTestRSAJWKS(t, "key1")
+	now := time.Now().UTC()
+
+	testCases := []struct {
+		token          string
+		duration       string
+		expectedExpiry time.Time
+		expectedErr    error
+		expectErr      bool
+	}{
+		{newTestToken(t, privKey, "key1", now.Add(24*time.Hour)), "", now.Add(defaultDuration), nil, false},
+		{newTestToken(t, privKey, "key1", now.Add(24*time.Hour)), "900", now.Add(15 * time.Minute), nil, false},
+		{newTestToken(t, privKey, "key1", now.Add(20*time.Minute)), "", now.Add(20 * time.Minute), nil, false},
+		{newTestToken(t, privKey, "key1", now.Add(24*time.Hour)), "60", time.Time{}, ErrInvalidDuration, true},
+		{newTestToken(t, privKey, "key1", now.Add(-time.Hour)), "", time.Time{}, ErrTokenExpired, true},
+		{newTestToken(t, privKey, "key2", now.Add(time.Hour)), "", time.Time{}, nil, true},
+		{newTestToken(t, otherKey, "key1", now.Add(time.Hour)), "", time.Time{}, nil, true},
+		{"invalid-token", "", time.Time{}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		claims, err := jwt.Validate(testCase.token, testCase.duration)
+		if testCase.expectErr {
+			if err == nil {
+				t.Fatalf("test %v: expected error, got none", i+1)
+			}
+			if testCase.expectedErr != nil && err != testCase.expectedErr {
+				t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test %v: unexpected error: %v", i+1, err)
+		}
+
+		if claims["sub"] != "minio" {
+			t.Fatalf("test %v: sub: expected: minio, got: %v", i+1, claims["sub"])
+		}
+
+		expiry := time.Unix(claims["exp"].(int64), 0)
+		if d := expiry.Sub(testCase.expectedExpiry); d > 5*time.Second || d < -5*time.Second {
+			t.Fatalf("test %v: exp: expected: %v, got: %v", i+1, testCase.expectedExpiry, expiry)
+		}
+	}
+}
+
+func TestJWTLocalFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	privKey, jwksData := newTestRSAJWKS(t, "key1")
+	jwksFile := filepath.Join(dir, "jwks.json")
+	if err = ioutil.WriteFile(jwksFile, jwksData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := xnet.ParseURL(jwksFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwt := NewJWT(JWKSArgs{URL: u})
+	if _, err = jwt.Validate(newTestToken(t, privKey, "key1", time.Now().UTC().Add(time.Hour)), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Rotated key is picked up by reloading JWKS.
+	privKey, jwksData = newTestRSAJWKS(t, "key2")
+	if err = ioutil.WriteFile(jwksFile, jwksData, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = jwt.Validate(newTestToken(t, privKey, "key2", time.Now().UTC().Add(time.Hour)), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJWTUnknownKeyRefresh(t *testing.T) {
+	privKey, jwksData := newTestRSAJWKS(t, "key1")
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write(jwksData)
+	}))
+	defer server.Close()
+
+	u, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwt := NewJWT(JWKSArgs{URL: u})
+	for i := 0; i < 10; i++ {
+		if _, err = jwt.Validate(newTestToken(t, privKey, "unknown", time.Now().UTC().Add(time.Hour)), ""); err == nil {
+			t.Fatalf("expected error for unknown key ID")
+		}
+	}
+
+	// JWKS is loaded once initially and reloaded once for unknown key IDs.
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Fatalf("JWKS requests: expected: 2, got: %v", n)
+	}
+}
+
+func TestJWTAudienceIssuer(t *testing.T) {
+	privKey, jwksData := newTestRSAJWKS(t, "key1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwksData)
+	}))
+	defer server.Close()
+
+	u, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwt := NewJWT(JWKSArgs{URL: u, Audience: "minio", Issuer: "https://idp.example.com"})
+	exp := time.Now().UTC().Add(time.Hour).Unix()
+	testCases := []struct {
+		claims    jwtgo.MapClaims
+		expectErr bool
+	}{
+		{jwtgo.MapClaims{"exp": exp, "aud": "minio", "iss": "https://idp.example.com"}, false},
+		{jwtgo.MapClaims{"exp": exp, "aud": []string{"other", "minio"}, "iss": "https://idp.example.com"}, false},
+		{jwtgo.MapClaims{"exp": exp, "aud": "other", "iss": "https://idp.example.com"}, true},
+		{jwtgo.MapClaims{"exp": exp, "aud": []string{"other"}, "iss": "https://idp.example.com"}, true},
+		{jwtgo.MapClaims{"exp": exp, "iss": "https://idp.example.com"}, true},
+		{jwtgo.MapClaims{"exp": exp, "aud": "minio", "iss": "https://other.example.com"}, true},
+		{jwtgo.MapClaims{"exp": exp, "aud": "minio"}, true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := jwt.Validate(newTestTokenWithClaims(t, privKey, "key1", testCase.claims), "")
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
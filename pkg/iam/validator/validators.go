@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ID - identifier of an authentication validator.
+type ID string
+
+// Validator - authentication provider which validates an identity
+// token and returns its claims.
+type Validator interface {
+	// Validate validates the token and returns its claims. The "exp"
+	// claim of the returned claims is the expiry of temporary
+	// credentials requested for given duration in seconds.
+	Validate(token, duration string) (map[string]interface{}, error)
+
+	// ID returns the identifier of this validator.
+	ID() ID
+}
+
+// Common errors generated by validators.
+var (
+	ErrTokenExpired    = errors.New("token expired")
+	ErrInvalidDuration = errors.New("invalid duration")
+)
+
+// Validators - list of validators indexed by their ID.
+type Validators struct {
+	sync.RWMutex
+	providers map[ID]Validator
+}
+
+// Add - adds unique validator to the list.
+func (list *Validators) Add(provider Validator) error {
+	list.Lock()
+	defer list.Unlock()
+
+	if _, ok := list.providers[provider.ID()]; ok {
+		return fmt.Errorf("validator %v already exists", provider.ID())
+	}
+
+	list.providers[provider.ID()] = provider
+	return nil
+}
+
+// List - returns IDs of all validators.
+func (list *Validators) List() []ID {
+	list.RLock()
+	defer list.RUnlock()
+
+	keys := []ID{}
+	for k := range list.providers {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Get - returns validator of given ID.
+func (list *Validators) Get(id ID) (Validator, error) {
+	list.RLock()
+	defer list.RUnlock()
+
+	provider, ok := list.providers[id]
+	if !ok {
+		return nil, fmt.Errorf("validator %v not found", id)
+	}
+
+	return provider, nil
+}
+
+// NewValidators - creates empty validator list.
+func NewValidators() *Validators {
+	return &Validators{
+		providers: make(map[ID]Validator),
+	}
+}
@@ -0,0 +1,297 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/pkg/set"
+)
+
+// ResourceARNPrefix - destination bucket ARN prefix.
+const ResourceARNPrefix = "arn:aws:s3:::"
+
+// Format - inventory report file format.
+type Format string
+
+const (
+	// CSVFormat - gzip compressed comma separated values.
+	CSVFormat Format = "CSV"
+
+	// ORCFormat - Apache ORC format, not supported.
+	ORCFormat Format = "ORC"
+
+	// ParquetFormat - Apache Parquet format, not supported.
+	ParquetFormat Format = "Parquet"
+)
+
+// Frequency - inventory report schedule frequency.
+type Frequency string
+
+const (
+	// Daily - report generated once in a day.
+	Daily Frequency = "Daily"
+
+	// Weekly - report generated once in a week.
+	Weekly Frequency = "Weekly"
+)
+
+// Duration - returns time interval between two reports.
+func (f Frequency) Duration() time.Duration {
+	if f == Weekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// Field - optional field included in inventory report.
+type Field string
+
+const (
+	// SizeField - object size in bytes.
+	SizeField Field = "Size"
+
+	// LastModifiedDateField - object last modified time.
+	LastModifiedDateField Field = "LastModifiedDate"
+
+	// ETagField - object entity tag.
+	ETagField Field = "ETag"
+
+	// StorageClassField - object storage class.
+	StorageClassField Field = "StorageClass"
+
+	// IsMultipartUploadedField - whether object was uploaded as multipart.
+	IsMultipartUploadedField Field = "IsMultipartUploaded"
+
+	// ReplicationStatusField - object replication status, always empty.
+	ReplicationStatusField Field = "ReplicationStatus"
+
+	// EncryptionStatusField - server side encryption type of object.
+	EncryptionStatusField Field = "EncryptionStatus"
+)
+
+// IsValid - checks if field is valid or not.
+func (field Field) IsValid() bool {
+	switch field {
+	case SizeField, LastModifiedDateField, ETagField, StorageClassField:
+		fallthrough
+	case IsMultipartUploadedField, ReplicationStatusField, EncryptionStatusField:
+		return true
+	}
+
+	return false
+}
+
+const (
+	// AllVersions - include all object versions.
+	AllVersions = "All"
+
+	// CurrentVersion - include only current object version.
+	CurrentVersion = "Current"
+)
+
+// Refer https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTInventoryConfig.html
+var validID = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// S3BucketDestination - represents elements inside <S3BucketDestination>...</S3BucketDestination>
+type S3BucketDestination struct {
+	AccountID string `xml:"AccountId,omitempty"`
+	Bucket    string `xml:"Bucket"`
+	Format    Format `xml:"Format"`
+	Prefix    string `xml:"Prefix,omitempty"`
+}
+
+// BucketName - returns bucket name of destination ARN.
+func (dest S3BucketDestination) BucketName() string {
+	return strings.TrimPrefix(dest.Bucket, ResourceARNPrefix)
+}
+
+// Validate - checks whether destination has valid values or not.
+func (dest S3BucketDestination) Validate() error {
+	if !strings.HasPrefix(dest.Bucket, ResourceARNPrefix) || dest.BucketName() == "" {
+		return &ErrInvalidDestination{dest.Bucket}
+	}
+
+	if strings.Contains(dest.BucketName(), "/") {
+		return &ErrInvalidDestination{dest.Bucket}
+	}
+
+	switch dest.Format {
+	case CSVFormat:
+		return nil
+	case ORCFormat, ParquetFormat:
+		return &ErrUnsupportedFormat{dest.Format}
+	}
+
+	return &ErrInvalidFormat{dest.Format}
+}
+
+// Destination - represents elements inside <Destination>...</Destination>
+type Destination struct {
+	S3BucketDestination S3BucketDestination `xml:"S3BucketDestination"`
+}
+
+// Filter - represents elements inside <Filter>...</Filter>
+type Filter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// Schedule - represents elements inside <Schedule>...</Schedule>
+type Schedule struct {
+	Frequency Frequency `xml:"Frequency"`
+}
+
+// Config - inventory configuration described in
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/storage-inventory.html
+type Config struct {
+	XMLName                xml.Name    `xml:"InventoryConfiguration"`
+	Destination            Destination `xml:"Destination"`
+	IsEnabled              bool        `xml:"IsEnabled"`
+	Filter                 *Filter     `xml:"Filter,omitempty"`
+	ID                     string      `xml:"Id"`
+	IncludedObjectVersions string      `xml:"IncludedObjectVersions"`
+	OptionalFields         []Field     `xml:"OptionalFields>Field,omitempty"`
+	Schedule               Schedule    `xml:"Schedule"`
+}
+
+// Prefix - returns object name prefix to be listed in the report.
+func (config Config) Prefix() string {
+	if config.Filter == nil {
+		return ""
+	}
+
+	return config.Filter.Prefix
+}
+
+// Validate - checks whether config has valid values or not.
+func (config Config) Validate() error {
+	if !validID.MatchString(config.ID) {
+		return &ErrInvalidID{config.ID}
+	}
+
+	if err := config.Destination.S3BucketDestination.Validate(); err != nil {
+		return err
+	}
+
+	if config.IncludedObjectVersions != AllVersions && config.IncludedObjectVersions != CurrentVersion {
+		return &ErrInvalidObjectVersions{config.IncludedObjectVersions}
+	}
+
+	if config.Schedule.Frequency != Daily && config.Schedule.Frequency != Weekly {
+		return &ErrInvalidFrequency{config.Schedule.Frequency}
+	}
+
+	fieldSet := set.NewStringSet()
+	for _, field := range config.OptionalFields {
+		if !field.IsValid() {
+			return &ErrInvalidField{field}
+		}
+
+		if fieldSet.Contains(string(field)) {
+			return &ErrDuplicateField{field}
+		}
+
+		fieldSet.Add(string(field))
+	}
+
+	return nil
+}
+
+// ParseConfig - parses data in reader to inventory configuration and
+// validates that it is stored under given ID.
+func ParseConfig(reader io.Reader, id string) (*Config, error) {
+	var config Config
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.ID != id {
+		return nil, &ErrIDMismatch{config.ID, id}
+	}
+
+	return &config, nil
+}
+
+// Configs - list of inventory configurations of a bucket.
+type Configs struct {
+	XMLName xml.Name `xml:"ListInventoryConfigurationsResult"`
+	Configs []Config `xml:"InventoryConfiguration"`
+
+	// IsTruncated is always false as all configurations are
+	// returned in a single response.
+	IsTruncated bool `xml:"IsTruncated"`
+}
+
+// Get - returns configuration of given ID.
+func (configs Configs) Get(id string) (Config, bool) {
+	for _, config := range configs.Configs {
+		if config.ID == id {
+			return config, true
+		}
+	}
+
+	return Config{}, false
+}
+
+// Set - adds or replaces configuration of same ID.
+func (configs *Configs) Set(config Config) {
+	for i := range configs.Configs {
+		if configs.Configs[i].ID == config.ID {
+			configs.Configs[i] = config
+			return
+		}
+	}
+
+	configs.Configs = append(configs.Configs, config)
+}
+
+// Remove - removes configuration of given ID, returns false if not found.
+func (configs *Configs) Remove(id string) bool {
+	for i := range configs.Configs {
+		if configs.Configs[i].ID == id {
+			configs.Configs = append(configs.Configs[:i], configs.Configs[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseConfigs - parses data in reader to list of inventory configurations.
+func ParseConfigs(reader io.Reader) (*Configs, error) {
+	var configs Configs
+	if err := xml.NewDecoder(reader).Decode(&configs); err != nil {
+		return nil, err
+	}
+
+	for _, config := range configs.Configs {
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &configs, nil
+}
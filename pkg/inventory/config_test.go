@@ -0,0 +1,242 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrequencyDuration(t *testing.T) {
+	testCases := []struct {
+		frequency      Frequency
+		expectedResult time.Duration
+	}{
+		{Daily, 24 * time.Hour},
+		{Weekly, 7 * 24 * time.Hour},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.frequency.Duration()
+
+		if result != testCase.expectedResult {
+			t.Fatalf("test %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestS3BucketDestinationValidate(t *testing.T) {
+	testCases := []struct {
+		dest        S3BucketDestination
+		expectedErr error
+	}{
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: CSVFormat}, nil},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: CSVFormat, Prefix: "daily"}, nil},
+		{S3BucketDestination{Bucket: "reports", Format: CSVFormat}, &ErrInvalidDestination{"reports"}},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::", Format: CSVFormat}, &ErrInvalidDestination{"arn:aws:s3:::"}},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports/daily", Format: CSVFormat}, &ErrInvalidDestination{"arn:aws:s3:::reports/daily"}},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: ORCFormat}, &ErrUnsupportedFormat{ORCFormat}},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: ParquetFormat}, &ErrUnsupportedFormat{ParquetFormat}},
+		{S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: "JSON"}, &ErrInvalidFormat{"JSON"}},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.dest.Validate()
+
+		if !reflect.DeepEqual(err, testCase.expectedErr) {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	newConfig := func(id, versions string, frequency Frequency, fields ...Field) Config {
+		return Config{
+			ID: id,
+			Destination: Destination{
+				S3BucketDestination: S3BucketDestination{
+					Bucket: "arn:aws:s3:::reports",
+					Format: CSVFormat,
+				},
+			},
+			IncludedObjectVersions: versions,
+			OptionalFields:         fields,
+			Schedule:               Schedule{frequency},
+		}
+	}
+
+	testCases := []struct {
+		config    Config
+		expectErr bool
+	}{
+		{newConfig("report1", CurrentVersion, Daily), false},
+		{newConfig("report1", AllVersions, Weekly, SizeField, ETagField, EncryptionStatusField), false},
+		{newConfig("", CurrentVersion, Daily), true},
+		{newConfig("report/1", CurrentVersion, Daily), true},
+		{newConfig(strings.Repeat("a", 65), CurrentVersion, Daily), true},
+		{newConfig("report1", "Latest", Daily), true},
+		{newConfig("report1", CurrentVersion, "Monthly"), true},
+		{newConfig("report1", CurrentVersion, Daily, "Owner"), true},
+		{newConfig("report1", CurrentVersion, Daily, SizeField, SizeField), true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.config.Validate()
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+
+		if expectErr && !IsInventoryError(err) {
+			t.Fatalf("test %v: expected inventory error, got: %v", i+1, err)
+		}
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	validData := `<InventoryConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+   <Destination>
+      <S3BucketDestination>
+         <Bucket>arn:aws:s3:::reports</Bucket>
+         <Format>CSV</Format>
+         <Prefix>inventory</Prefix>
+      </S3BucketDestination>
+   </Destination>
+   <IsEnabled>true</IsEnabled>
+   <Filter>
+      <Prefix>photos/</Prefix>
+   </Filter>
+   <Id>report1</Id>
+   <IncludedObjectVersions>Current</IncludedObjectVersions>
+   <OptionalFields>
+      <Field>Size</Field>
+      <Field>LastModifiedDate</Field>
+      <Field>ETag</Field>
+   </OptionalFields>
+   <Schedule>
+      <Frequency>Daily</Frequency>
+   </Schedule>
+</InventoryConfiguration>`
+
+	expectedConfig := &Config{
+		XMLName: xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "InventoryConfiguration"},
+		Destination: Destination{
+			S3BucketDestination: S3BucketDestination{
+				Bucket: "arn:aws:s3:::reports",
+				Format: CSVFormat,
+				Prefix: "inventory",
+			},
+		},
+		IsEnabled:              true,
+		Filter:                 &Filter{"photos/"},
+		ID:                     "report1",
+		IncludedObjectVersions: CurrentVersion,
+		OptionalFields:         []Field{SizeField, LastModifiedDateField, ETagField},
+		Schedule:               Schedule{Daily},
+	}
+
+	testCases := []struct {
+		data           string
+		id             string
+		expectedResult *Config
+		expectErr      bool
+	}{
+		{validData, "report1", expectedConfig, false},
+		{validData, "report2", nil, true},
+		{strings.Replace(validData, "Daily", "Hourly", 1), "report1", nil, true},
+		{strings.Replace(validData, "CSV", "ORC", 1), "report1", nil, true},
+		{"<InventoryConfiguration>", "report1", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseConfig(strings.NewReader(testCase.data), testCase.id)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if !reflect.DeepEqual(result, testCase.expectedResult) {
+				t.Fatalf("test %v: data: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+			}
+
+			if result.Prefix() != "photos/" {
+				t.Fatalf("test %v: prefix: expected: photos/, got: %v", i+1, result.Prefix())
+			}
+		}
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	config1 := Config{
+		ID: "report1",
+		Destination: Destination{
+			S3BucketDestination: S3BucketDestination{Bucket: "arn:aws:s3:::reports", Format: CSVFormat},
+		},
+		IncludedObjectVersions: CurrentVersion,
+		Schedule:               Schedule{Daily},
+	}
+	config2 := config1
+	config2.ID = "report2"
+	config2.Schedule = Schedule{Weekly}
+
+	configs := &Configs{}
+	configs.Set(config1)
+	configs.Set(config2)
+	config1.IsEnabled = true
+	configs.Set(config1)
+
+	if len(configs.Configs) != 2 {
+		t.Fatalf("expected: 2 configs, got: %v", len(configs.Configs))
+	}
+
+	if config, ok := configs.Get("report1"); !ok || !config.IsEnabled {
+		t.Fatalf("expected: updated report1, got: %v, %v", config, ok)
+	}
+
+	data, err := xml.Marshal(configs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsedConfigs, err := ParseConfigs(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(parsedConfigs.Configs) != 2 {
+		t.Fatalf("expected: 2 configs, got: %v", len(parsedConfigs.Configs))
+	}
+
+	if !configs.Remove("report1") {
+		t.Fatalf("expected: report1 to be removed")
+	}
+
+	if configs.Remove("report1") {
+		t.Fatalf("expected: report1 to be not found")
+	}
+
+	if _, ok := configs.Get("report2"); !ok {
+		t.Fatalf("expected: report2 to be found")
+	}
+}
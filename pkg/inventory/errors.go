@@ -0,0 +1,127 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import "fmt"
+
+// IsInventoryError - checks whether given error is inventory error or not.
+func IsInventoryError(err error) bool {
+	switch err.(type) {
+	case ErrInvalidID, *ErrInvalidID:
+		return true
+	case ErrIDMismatch, *ErrIDMismatch:
+		return true
+	case ErrInvalidDestination, *ErrInvalidDestination:
+		return true
+	case ErrInvalidFormat, *ErrInvalidFormat:
+		return true
+	case ErrUnsupportedFormat, *ErrUnsupportedFormat:
+		return true
+	case ErrInvalidFrequency, *ErrInvalidFrequency:
+		return true
+	case ErrInvalidField, *ErrInvalidField:
+		return true
+	case ErrDuplicateField, *ErrDuplicateField:
+		return true
+	case ErrInvalidObjectVersions, *ErrInvalidObjectVersions:
+		return true
+	}
+
+	return false
+}
+
+// ErrInvalidID - invalid inventory configuration ID error.
+type ErrInvalidID struct {
+	ID string
+}
+
+func (err ErrInvalidID) Error() string {
+	return fmt.Sprintf("invalid inventory configuration id '%v'", err.ID)
+}
+
+// ErrIDMismatch - configuration ID does not match ID in request error.
+type ErrIDMismatch struct {
+	ID        string
+	RequestID string
+}
+
+func (err ErrIDMismatch) Error() string {
+	return fmt.Sprintf("inventory configuration id '%v' does not match requested id '%v'", err.ID, err.RequestID)
+}
+
+// ErrInvalidDestination - invalid destination bucket ARN error.
+type ErrInvalidDestination struct {
+	Bucket string
+}
+
+func (err ErrInvalidDestination) Error() string {
+	return fmt.Sprintf("invalid destination bucket '%v'", err.Bucket)
+}
+
+// ErrInvalidFormat - invalid report format error.
+type ErrInvalidFormat struct {
+	Format Format
+}
+
+func (err ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("invalid inventory format '%v'", err.Format)
+}
+
+// ErrUnsupportedFormat - valid but unsupported report format error.
+type ErrUnsupportedFormat struct {
+	Format Format
+}
+
+func (err ErrUnsupportedFormat) Error() string {
+	return fmt.Sprintf("inventory format '%v' is not supported", err.Format)
+}
+
+// ErrInvalidFrequency - invalid schedule frequency error.
+type ErrInvalidFrequency struct {
+	Frequency Frequency
+}
+
+func (err ErrInvalidFrequency) Error() string {
+	return fmt.Sprintf("invalid schedule frequency '%v'", err.Frequency)
+}
+
+// ErrInvalidField - invalid optional field error.
+type ErrInvalidField struct {
+	Field Field
+}
+
+func (err ErrInvalidField) Error() string {
+	return fmt.Sprintf("invalid optional field '%v'", err.Field)
+}
+
+// ErrDuplicateField - duplicate optional field error.
+type ErrDuplicateField struct {
+	Field Field
+}
+
+func (err ErrDuplicateField) Error() string {
+	return fmt.Sprintf("duplicate optional field '%v' found", err.Field)
+}
+
+// ErrInvalidObjectVersions - invalid included object versions error.
+type ErrInvalidObjectVersions struct {
+	Versions string
+}
+
+func (err ErrInvalidObjectVersions) Error() string {
+	return fmt.Sprintf("invalid included object versions '%v'", err.Versions)
+}
@@ -0,0 +1,158 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ConfigAppliedHeader - set to "true" by the server when a config
+// change was applied without the need of a server restart.
+const ConfigAppliedHeader = "X-Minio-Config-Applied"
+
+// ConfigHistoryEntry - a previous configuration which can be restored.
+type ConfigHistoryEntry struct {
+	RestoreID  string    `json:"restoreId"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// GetConfigKV - returns the JSON value of a config key such as
+// 'region' or 'notify.webhook.1', incoming data is encrypted.
+func (adm *AdminClient) GetConfigKV(key string) ([]byte, error) {
+	queryValues := url.Values{}
+	queryValues.Set("key", key)
+
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/config-kv",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	return DecryptServerConfigData(adm.secretAccessKey, resp.Body)
+}
+
+// SetConfigKV - sets a config key to the given JSON value, the value
+// is validated by the server before the change is committed. Returns
+// true if the server needs to be restarted to apply the change.
+func (adm *AdminClient) SetConfigKV(key string, value []byte) (restart bool, err error) {
+	evalue, err := EncryptServerConfigData(adm.secretAccessKey, value)
+	if err != nil {
+		return false, err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("key", key)
+
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/config-kv",
+		queryValues: queryValues,
+		content:     evalue,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, httpRespToErrorResponse(resp)
+	}
+
+	return resp.Header.Get(ConfigAppliedHeader) != "true", nil
+}
+
+// DelConfigKV - removes a config key, removing a whole subsystem
+// such as 'cache' resets it to its default value. Returns true if
+// the server needs to be restarted to apply the change.
+func (adm *AdminClient) DelConfigKV(key string) (restart bool, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("key", key)
+
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/config-kv",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, httpRespToErrorResponse(resp)
+	}
+
+	return resp.Header.Get(ConfigAppliedHeader) != "true", nil
+}
+
+// ListConfigHistory - returns the previous configurations which can
+// be restored, oldest first.
+func (adm *AdminClient) ListConfigHistory() ([]ConfigHistoryEntry, error) {
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/config-history"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ConfigHistoryEntry
+	if err = json.Unmarshal(respBytes, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// RestoreConfigHistory - restores the configuration saved as restoreID,
+// the current configuration is saved to history first. Returns true
+// if the server needs to be restarted to apply the change.
+func (adm *AdminClient) RestoreConfigHistory(restoreID string) (restart bool, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("restoreId", restoreID)
+
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath:     "/v1/config-history/restore",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return false, httpRespToErrorResponse(resp)
+	}
+
+	return resp.Header.Get(ConfigAppliedHeader) != "true", nil
+}
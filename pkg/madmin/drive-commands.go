@@ -0,0 +1,147 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DriveStats - latency and error statistics of a drive, Calls and
+// Errors are counted since server startup while latencies are
+// computed over the most recent calls.
+type DriveStats struct {
+	Calls         uint64        `json:"calls"`
+	Errors        uint64        `json:"errors"`
+	AvgLatency    time.Duration `json:"avgLatency"`
+	MaxLatency    time.Duration `json:"maxLatency"`
+	LastError     string        `json:"lastError,omitempty"`
+	LastErrorTime time.Time     `json:"lastErrorTime,omitempty"`
+}
+
+// ServerDrive - details of a drive local to a server.
+type ServerDrive struct {
+	Endpoint string `json:"endpoint"`
+	Path     string `json:"path"`
+	UUID     string `json:"uuid,omitempty"`
+	State    string `json:"state"`
+
+	// Position of the drive in the cluster topology, set and
+	// disk indices are -1 when the drive is not connected.
+	Pool      int `json:"pool"`
+	SetIndex  int `json:"setIndex"`
+	DiskIndex int `json:"diskIndex"`
+
+	TotalSpace uint64 `json:"totalSpace"`
+	UsedSpace  uint64 `json:"usedSpace"`
+	FreeSpace  uint64 `json:"freeSpace"`
+	UsedInodes uint64 `json:"usedInodes"`
+	FreeInodes uint64 `json:"freeInodes"`
+
+	Stats DriveStats `json:"stats"`
+	Error string     `json:"error,omitempty"`
+}
+
+// ServerDrivesInfo - drives of one server.
+type ServerDrivesInfo struct {
+	Addr   string        `json:"addr"`
+	Error  string        `json:"error,omitempty"`
+	Drives []ServerDrive `json:"drives"`
+}
+
+// DriveSpeedtestResult - result of a sequential write and read of
+// a temporary file on a drive, throughputs are in bytes per second.
+type DriveSpeedtestResult struct {
+	Endpoint        string        `json:"endpoint"`
+	Path            string        `json:"path"`
+	Size            int64         `json:"size"`
+	WriteDuration   time.Duration `json:"writeDuration"`
+	WriteThroughput uint64        `json:"writeThroughput"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// ServerDriveSpeedtest - drive speedtest results of one server.
+type ServerDriveSpeedtest struct {
+	Addr   string                 `json:"addr"`
+	Error  string                 `json:"error,omitempty"`
+	Drives []DriveSpeedtestResult `json:"drives"`
+}
+
+// ServerDrives - returns details of all drives of all servers.
+func (adm *AdminClient) ServerDrives() ([]ServerDrivesInfo, error) {
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/drives"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []ServerDrivesInfo
+	if err = json.Unmarshal(respBytes, &servers); err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+// DriveSpeedtest - sequentially writes and syncs a temporary file
+// of given size on every drive of all servers, drives of a
+// server are tested concurrently. A size of zero uses the server
+// default.
+func (adm *AdminClient) DriveSpeedtest(size int64) ([]ServerDriveSpeedtest, error) {
+	queryValues := url.Values{}
+	if size > 0 {
+		queryValues.Set("size", strconv.FormatInt(size, 10))
+	}
+
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath:     "/v1/drives/speedtest",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []ServerDriveSpeedtest
+	if err = json.Unmarshal(respBytes, &servers); err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
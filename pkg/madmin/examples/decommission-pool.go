@@ -0,0 +1,62 @@
+// +build ignore
+
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func main() {
+	// Note: YOUR-ACCESSKEYID, YOUR-SECRETACCESSKEY and the pool argument are
+	// dummy values, please replace them with original values.
+
+	// API requests are secure (HTTPS) if secure=true and insecure (HTTPS) otherwise.
+	// New returns an Minio Admin client object.
+	madmClnt, err := madmin.New("your-minio.example.com:9000", "YOUR-ACCESSKEYID", "YOUR-SECRETACCESSKEY", true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	pool := "http://server{5...8}/disk{1...4}"
+	if err = madmClnt.DecommissionPool(pool); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Wait until all objects are moved off the pool.
+	for {
+		status, err := madmClnt.StatusPool(pool)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		info := status.Decommission
+		if info.Failed || info.Canceled {
+			log.Fatalln("Decommission did not complete", info)
+		}
+		if info.Complete {
+			break
+		}
+		log.Printf("Moved %d objects (%d bytes)\n", info.ObjectsMoved, info.BytesMoved)
+		time.Sleep(10 * time.Second)
+	}
+	log.Println("Pool can be removed from the command line")
+}
@@ -0,0 +1,61 @@
+// +build ignore
+
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package main
+
+import (
+	"log"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func main() {
+	// Note: YOUR-ACCESSKEYID, YOUR-SECRETACCESSKEY and my-bucketname are
+	// dummy values, please replace them with original values.
+
+	// API requests are secure (HTTPS) if secure=true and insecure (HTTPS) otherwise.
+	// New returns an Minio Admin client object.
+	madmClnt, err := madmin.New("your-minio.example.com:9000", "YOUR-ACCESSKEYID", "YOUR-SECRETACCESSKEY", true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	servers, err := madmClnt.ServerDrives()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, server := range servers {
+		for _, drive := range server.Drives {
+			log.Printf("%s: %s state: %s, free: %d, avg latency: %s, errors: %d\n", server.Addr,
+				drive.Endpoint, drive.State, drive.FreeSpace, drive.Stats.AvgLatency, drive.Stats.Errors)
+		}
+	}
+
+	// Write and sync 64MiB on every drive.
+	results, err := madmClnt.DriveSpeedtest(64 * 1024 * 1024)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, server := range results {
+		for _, drive := range server.Drives {
+			log.Printf("%s: %s write: %d B/s %s\n", server.Addr,
+				drive.Endpoint, drive.WriteThroughput, drive.Error)
+		}
+	}
+}
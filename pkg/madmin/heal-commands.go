@@ -76,6 +76,30 @@ type HealDriveInfo struct {
 	UUID     string `json:"uuid"`
 	Endpoint string `json:"endpoint"`
 	State    string `json:"state"`
+
+	// Progress of healing a freshly formatted drive, only
+	// set while the drive is being healed.
+	Healing *HealingDisk `json:"healing,omitempty"`
+}
+
+// HealingDisk - progress of healing all the objects of an
+// erasure set onto a freshly formatted drive.
+type HealingDisk struct {
+	ID         string    `json:"id"`
+	Endpoint   string    `json:"endpoint"`
+	SetIndex   int       `json:"setIndex"`
+	DiskIndex  int       `json:"diskIndex"`
+	Started    time.Time `json:"started"`
+	LastUpdate time.Time `json:"lastUpdate"`
+
+	ObjectsHealed uint64 `json:"objectsHealed"`
+	ObjectsFailed uint64 `json:"objectsFailed"`
+	BytesDone     uint64 `json:"bytesDone"`
+	BytesFailed   uint64 `json:"bytesFailed"`
+
+	// Last object processed.
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
 }
 
 // HealResultItem - struct for an individual heal result item
@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// ListCannedPolicies - returns all canned policies as a map of policy
+// name to policy JSON.
+func (adm *AdminClient) ListCannedPolicies() (map[string]json.RawMessage, error) {
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/list-canned-policies"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies map[string]json.RawMessage
+	if err = json.Unmarshal(respBytes, &policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// AddCannedPolicy - adds or replaces canned policy of given name.
+func (adm *AdminClient) AddCannedPolicy(policyName, policy string) error {
+	queryValues := url.Values{}
+	queryValues.Set("policyName", policyName)
+
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/add-canned-policy",
+		queryValues: queryValues,
+		content:     []byte(policy),
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// RemoveCannedPolicy - removes canned policy of given name.
+func (adm *AdminClient) RemoveCannedPolicy(policyName string) error {
+	queryValues := url.Values{}
+	queryValues.Set("policyName", policyName)
+
+	resp, err := adm.executeMethod("DELETE", requestData{
+		relPath:     "/v1/remove-canned-policy",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// SetGroupPolicy - maps canned policy to an identity provider group,
+// empty policy name removes the mapping.
+func (adm *AdminClient) SetGroupPolicy(group, policyName string) error {
+	queryValues := url.Values{}
+	queryValues.Set("group", group)
+	queryValues.Set("policyName", policyName)
+
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/set-group-policy",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
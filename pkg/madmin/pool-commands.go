@@ -0,0 +1,142 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PoolDecommissionInfo - progress of a server pool decommission.
+type PoolDecommissionInfo struct {
+	StartTime time.Time `json:"startTime"`
+	Complete  bool      `json:"complete"`
+	Failed    bool      `json:"failed"`
+	Canceled  bool      `json:"canceled"`
+
+	// Bucket, Prefix and Object point to the last object moved,
+	// decommission resumes after it upon restart.
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	Object string `json:"object"`
+
+	ObjectsMoved  int64 `json:"objectsMoved"`
+	BytesMoved    int64 `json:"bytesMoved"`
+	ObjectsFailed int64 `json:"objectsFailed"`
+}
+
+// PoolStatus - status of a server pool, pools are identified by
+// their command line argument.
+type PoolStatus struct {
+	ID           int                   `json:"id"`
+	CmdLine      string                `json:"cmdline"`
+	LastUpdate   time.Time             `json:"lastUpdate"`
+	Decommission *PoolDecommissionInfo `json:"decommissionInfo,omitempty"`
+}
+
+// ListPools - returns status of all server pools.
+func (adm *AdminClient) ListPools() ([]PoolStatus, error) {
+	resp, err := adm.executeMethod("GET", requestData{relPath: "/v1/pools/list"})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools []PoolStatus
+	if err = json.Unmarshal(respBytes, &pools); err != nil {
+		return nil, err
+	}
+
+	return pools, nil
+}
+
+// StatusPool - returns status of the server pool defined by given
+// command line argument.
+func (adm *AdminClient) StatusPool(pool string) (PoolStatus, error) {
+	queryValues := url.Values{}
+	queryValues.Set("pool", pool)
+
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/pools/status",
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return PoolStatus{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return PoolStatus{}, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return PoolStatus{}, err
+	}
+
+	var status PoolStatus
+	if err = json.Unmarshal(respBytes, &status); err != nil {
+		return PoolStatus{}, err
+	}
+
+	return status, nil
+}
+
+// DecommissionPool - starts draining the server pool defined by given
+// command line argument, the pool stops receiving new objects and its
+// objects are moved to the remaining pools.
+func (adm *AdminClient) DecommissionPool(pool string) error {
+	return adm.poolAction("/v1/pools/decommission", pool)
+}
+
+// CancelDecommissionPool - cancels an ongoing decommission of the
+// server pool, the pool receives new objects again.
+func (adm *AdminClient) CancelDecommissionPool(pool string) error {
+	return adm.poolAction("/v1/pools/cancel", pool)
+}
+
+func (adm *AdminClient) poolAction(relPath, pool string) error {
+	queryValues := url.Values{}
+	queryValues.Set("pool", pool)
+
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath:     relPath,
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
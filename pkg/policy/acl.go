@@ -0,0 +1,212 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"fmt"
+)
+
+// CannedACL - canned access control list.
+// Refer https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
+// for more information about canned ACLs.
+type CannedACL string
+
+const (
+	// PrivateACL - owner gets FULL_CONTROL, no one else has access rights.
+	PrivateACL CannedACL = "private"
+
+	// PublicReadACL - owner gets FULL_CONTROL, AllUsers group gets READ access.
+	PublicReadACL = "public-read"
+
+	// PublicReadWriteACL - owner gets FULL_CONTROL, AllUsers group gets READ and WRITE access.
+	PublicReadWriteACL = "public-read-write"
+
+	// AuthenticatedReadACL - owner gets FULL_CONTROL, AuthenticatedUsers group gets READ access.
+	AuthenticatedReadACL = "authenticated-read"
+
+	// BucketOwnerFullControlACL - both object owner and bucket owner get FULL_CONTROL.
+	// As object owner is always bucket owner in minio, this is same as private.
+	BucketOwnerFullControlACL = "bucket-owner-full-control"
+)
+
+// IsValid - checks if canned ACL is valid or not.
+func (acl CannedACL) IsValid() bool {
+	switch acl {
+	case PrivateACL, PublicReadACL, PublicReadWriteACL:
+		fallthrough
+	case AuthenticatedReadACL, BucketOwnerFullControlACL:
+		return true
+	}
+
+	return false
+}
+
+// ParseCannedACL - parses string to CannedACL.
+func ParseCannedACL(s string) (CannedACL, error) {
+	acl := CannedACL(s)
+	if !acl.IsValid() {
+		return "", fmt.Errorf("invalid canned ACL '%v'", s)
+	}
+
+	return acl, nil
+}
+
+// Permission - ACL permission granted to a grantee.
+type Permission string
+
+const (
+	// ReadPermission - READ permission.
+	ReadPermission Permission = "READ"
+
+	// WritePermission - WRITE permission.
+	WritePermission = "WRITE"
+
+	// FullControlPermission - FULL_CONTROL permission.
+	FullControlPermission = "FULL_CONTROL"
+)
+
+// Group - predefined ACL grantee group.
+type Group string
+
+const (
+	// AllUsersGroup - anyone in the world, authenticated or not.
+	AllUsersGroup Group = "http://acs.amazonaws.com/groups/global/AllUsers"
+
+	// AuthenticatedUsersGroup - any signed request.
+	AuthenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// Grant - permission granted to a group by canned ACL.
+type Grant struct {
+	Group      Group
+	Permission Permission
+}
+
+// Grants - returns grants of canned ACL. Owner's FULL_CONTROL is implicit
+// and not part of returned grants.
+func (acl CannedACL) Grants() []Grant {
+	switch acl {
+	case PublicReadACL:
+		return []Grant{{AllUsersGroup, ReadPermission}}
+	case PublicReadWriteACL:
+		return []Grant{{AllUsersGroup, ReadPermission}, {AllUsersGroup, WritePermission}}
+	case AuthenticatedReadACL:
+		return []Grant{{AuthenticatedUsersGroup, ReadPermission}}
+	}
+
+	return nil
+}
+
+// containsGrant - checks whether grant exists in given grants.
+func containsGrant(grants []Grant, grant Grant) bool {
+	for _, g := range grants {
+		if g == grant {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CannedACLFromGrants - returns canned ACL equivalent to given grants.
+func CannedACLFromGrants(grants []Grant) (CannedACL, bool) {
+	for _, acl := range []CannedACL{PrivateACL, PublicReadACL, PublicReadWriteACL, AuthenticatedReadACL} {
+		aclGrants := acl.Grants()
+		if len(aclGrants) != len(grants) {
+			continue
+		}
+
+		found := true
+		for i := range aclGrants {
+			if !containsGrant(grants, aclGrants[i]) || !containsGrant(aclGrants, grants[i]) {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			return acl, true
+		}
+	}
+
+	return "", false
+}
+
+// Actions allowed by READ and WRITE permissions on bucket.
+var (
+	bucketReadActions = NewActionSet(
+		ListBucketAction,
+		ListBucketMultipartUploadsAction,
+	)
+
+	bucketWriteActions = NewActionSet(
+		AbortMultipartUploadAction,
+		DeleteObjectAction,
+		ListMultipartUploadPartsAction,
+		PutObjectAction,
+	)
+)
+
+// Actions allowed by READ permission on object.
+var objectReadActions = NewActionSet(
+	GetObjectAction,
+)
+
+// isGranted - checks whether grant is applicable for given args.
+func (grant Grant) isGranted(args Args) bool {
+	switch grant.Group {
+	case AllUsersGroup:
+		return true
+	case AuthenticatedUsersGroup:
+		return args.AccountName != ""
+	}
+
+	return false
+}
+
+// IsBucketAllowed - checks given policy args is allowed by canned ACL set on bucket.
+func (acl CannedACL) IsBucketAllowed(args Args) bool {
+	for _, grant := range acl.Grants() {
+		if !grant.isGranted(args) {
+			continue
+		}
+
+		switch grant.Permission {
+		case ReadPermission:
+			if bucketReadActions.Contains(args.Action) {
+				return true
+			}
+		case WritePermission:
+			if bucketWriteActions.Contains(args.Action) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsObjectAllowed - checks given policy args is allowed by canned ACL set on object.
+func (acl CannedACL) IsObjectAllowed(args Args) bool {
+	for _, grant := range acl.Grants() {
+		if grant.isGranted(args) && grant.Permission == ReadPermission && objectReadActions.Contains(args.Action) {
+			return true
+		}
+	}
+
+	return false
+}
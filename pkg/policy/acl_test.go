@@ -0,0 +1,150 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCannedACL(t *testing.T) {
+	testCases := []struct {
+		s              string
+		expectedResult CannedACL
+		expectErr      bool
+	}{
+		{"private", PrivateACL, false},
+		{"public-read", PublicReadACL, false},
+		{"public-read-write", PublicReadWriteACL, false},
+		{"authenticated-read", AuthenticatedReadACL, false},
+		{"bucket-owner-full-control", BucketOwnerFullControlACL, false},
+		{"", "", true},
+		{"Public-Read", "", true},
+		{"log-delivery-write", "", true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := ParseCannedACL(testCase.s)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestCannedACLFromGrants(t *testing.T) {
+	testCases := []struct {
+		grants         []Grant
+		expectedResult CannedACL
+		expectedFound  bool
+	}{
+		{nil, PrivateACL, true},
+		{[]Grant{{AllUsersGroup, ReadPermission}}, PublicReadACL, true},
+		{[]Grant{{AllUsersGroup, WritePermission}, {AllUsersGroup, ReadPermission}}, PublicReadWriteACL, true},
+		{[]Grant{{AuthenticatedUsersGroup, ReadPermission}}, AuthenticatedReadACL, true},
+		{[]Grant{{AllUsersGroup, WritePermission}}, "", false},
+		{[]Grant{{AllUsersGroup, ReadPermission}, {AllUsersGroup, ReadPermission}}, "", false},
+		{[]Grant{{AuthenticatedUsersGroup, FullControlPermission}}, "", false},
+	}
+
+	for i, testCase := range testCases {
+		result, found := CannedACLFromGrants(testCase.grants)
+
+		if found != testCase.expectedFound {
+			t.Fatalf("case %v: found: expected: %v, got: %v", i+1, testCase.expectedFound, found)
+		}
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+
+	for _, acl := range []CannedACL{PrivateACL, PublicReadACL, PublicReadWriteACL, AuthenticatedReadACL} {
+		result, found := CannedACLFromGrants(acl.Grants())
+		if !found || !reflect.DeepEqual(result, acl) {
+			t.Fatalf("%v: expected round trip, got: %v, %v", acl, result, found)
+		}
+	}
+}
+
+func TestCannedACLIsBucketAllowed(t *testing.T) {
+	anonGetObject := Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}
+	anonListBucket := Args{Action: ListBucketAction, BucketName: "mybucket"}
+	anonPutObject := Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "myobject"}
+	userListBucket := Args{AccountName: "user", Action: ListBucketAction, BucketName: "mybucket"}
+	ownerPutBucketACL := Args{AccountName: "owner", Action: PutBucketACLAction, BucketName: "mybucket", IsOwner: true}
+
+	testCases := []struct {
+		acl            CannedACL
+		args           Args
+		expectedResult bool
+	}{
+		{PrivateACL, anonListBucket, false},
+		{PrivateACL, ownerPutBucketACL, false},
+		{PublicReadWriteACL, ownerPutBucketACL, false},
+		{PublicReadACL, anonListBucket, true},
+		{PublicReadACL, anonGetObject, false},
+		{PublicReadACL, anonPutObject, false},
+		{PublicReadWriteACL, anonListBucket, true},
+		{PublicReadWriteACL, anonPutObject, true},
+		{AuthenticatedReadACL, anonListBucket, false},
+		{AuthenticatedReadACL, userListBucket, true},
+		{BucketOwnerFullControlACL, anonListBucket, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.acl.IsBucketAllowed(testCase.args)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestCannedACLIsObjectAllowed(t *testing.T) {
+	anonGetObject := Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}
+	anonPutObject := Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "myobject"}
+	userGetObject := Args{AccountName: "user", Action: GetObjectAction, BucketName: "mybucket", ObjectName: "myobject"}
+
+	testCases := []struct {
+		acl            CannedACL
+		args           Args
+		expectedResult bool
+	}{
+		{PrivateACL, anonGetObject, false},
+		{PublicReadACL, anonGetObject, true},
+		{PublicReadACL, anonPutObject, false},
+		{PublicReadWriteACL, anonGetObject, true},
+		{PublicReadWriteACL, anonPutObject, false},
+		{AuthenticatedReadACL, anonGetObject, false},
+		{AuthenticatedReadACL, userGetObject, true},
+		{BucketOwnerFullControlACL, anonGetObject, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.acl.IsObjectAllowed(testCase.args)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
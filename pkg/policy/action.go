@@ -44,18 +44,28 @@ const (
 	// DeleteObjectAction - DeleteObject Rest API action.
 	DeleteObjectAction = "s3:DeleteObject"
 
+	// GetBucketACLAction - GetBucketAcl Rest API action.
+	GetBucketACLAction = "s3:GetBucketAcl"
+
 	// GetBucketLocationAction - GetBucketLocation Rest API action.
 	GetBucketLocationAction = "s3:GetBucketLocation"
 
 	// GetBucketNotificationAction - GetBucketNotification Rest API action.
 	GetBucketNotificationAction = "s3:GetBucketNotification"
 
+	// GetInventoryConfigurationAction - GetBucketInventoryConfiguration and
+	// ListBucketInventoryConfigurations Rest API action.
+	GetInventoryConfigurationAction = "s3:GetInventoryConfiguration"
+
 	// GetBucketPolicyAction - GetBucketPolicy Rest API action.
 	GetBucketPolicyAction = "s3:GetBucketPolicy"
 
 	// GetObjectAction - GetObject Rest API action.
 	GetObjectAction = "s3:GetObject"
 
+	// GetObjectACLAction - GetObjectAcl Rest API action.
+	GetObjectACLAction = "s3:GetObjectAcl"
+
 	// HeadBucketAction - HeadBucket Rest API action. This action is unused in minio.
 	HeadBucketAction = "s3:HeadBucket"
 
@@ -75,14 +85,24 @@ const (
 	// ListMultipartUploadPartsAction - ListParts Rest API action.
 	ListMultipartUploadPartsAction = "s3:ListMultipartUploadParts"
 
+	// PutBucketACLAction - PutBucketAcl Rest API action.
+	PutBucketACLAction = "s3:PutBucketAcl"
+
 	// PutBucketNotificationAction - PutObjectNotification Rest API action.
 	PutBucketNotificationAction = "s3:PutBucketNotification"
 
 	// PutBucketPolicyAction - PutBucketPolicy Rest API action.
 	PutBucketPolicyAction = "s3:PutBucketPolicy"
 
+	// PutInventoryConfigurationAction - PutBucketInventoryConfiguration and
+	// DeleteBucketInventoryConfiguration Rest API action.
+	PutInventoryConfigurationAction = "s3:PutInventoryConfiguration"
+
 	// PutObjectAction - PutObject Rest API action.
 	PutObjectAction = "s3:PutObject"
+
+	// PutObjectACLAction - PutObjectAcl Rest API action.
+	PutObjectACLAction = "s3:PutObjectAcl"
 )
 
 // isObjectAction - returns whether action is object type or not.
@@ -90,7 +110,9 @@ func (action Action) isObjectAction() bool {
 	switch action {
 	case AbortMultipartUploadAction, DeleteObjectAction, GetObjectAction:
 		fallthrough
-	case ListMultipartUploadPartsAction, PutObjectAction:
+	case GetObjectACLAction, ListMultipartUploadPartsAction:
+		fallthrough
+	case PutObjectAction, PutObjectACLAction:
 		return true
 	}
 
@@ -106,6 +128,8 @@ func (action Action) IsValid() bool {
 		fallthrough
 	case GetBucketNotificationAction, GetBucketPolicyAction, GetObjectAction:
 		fallthrough
+	case GetInventoryConfigurationAction, PutInventoryConfigurationAction:
+		fallthrough
 	case HeadBucketAction, ListAllMyBucketsAction, ListBucketAction:
 		fallthrough
 	case ListBucketMultipartUploadsAction, ListenBucketNotificationAction:
@@ -113,6 +137,8 @@ func (action Action) IsValid() bool {
 	case ListMultipartUploadPartsAction, PutBucketNotificationAction:
 		fallthrough
 	case PutBucketPolicyAction, PutObjectAction:
+		fallthrough
+	case GetBucketACLAction, PutBucketACLAction, GetObjectACLAction, PutObjectACLAction:
 		return true
 	}
 
@@ -178,6 +204,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	GetBucketACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetBucketLocationAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -193,6 +224,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	GetInventoryConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	GetObjectAction: condition.NewKeySet(
 		condition.S3XAmzServerSideEncryption,
 		condition.S3XAmzServerSideEncryptionAwsKMSKeyID,
@@ -201,6 +237,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	GetObjectACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	HeadBucketAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -234,6 +275,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	PutBucketACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	PutBucketNotificationAction: condition.NewKeySet(
 		condition.AWSReferer,
 		condition.AWSSourceIP,
@@ -244,6 +290,11 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSSourceIP,
 	),
 
+	PutInventoryConfigurationAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
+
 	PutObjectAction: condition.NewKeySet(
 		condition.S3XAmzCopySource,
 		condition.S3XAmzServerSideEncryption,
@@ -253,4 +304,9 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		condition.AWSReferer,
 		condition.AWSSourceIP,
 	),
+
+	PutObjectACLAction: condition.NewKeySet(
+		condition.AWSReferer,
+		condition.AWSSourceIP,
+	),
 }
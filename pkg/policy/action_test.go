@@ -32,7 +32,10 @@ func TestActionIsObjectAction(t *testing.T) {
 		{GetObjectAction, true},
 		{ListMultipartUploadPartsAction, true},
 		{PutObjectAction, true},
+		{GetObjectACLAction, true},
+		{PutObjectACLAction, true},
 		{CreateBucketAction, false},
+		{PutBucketACLAction, false},
 	}
 
 	for i, testCase := range testCases {
@@ -50,6 +53,9 @@ func TestActionIsValid(t *testing.T) {
 		expectedResult bool
 	}{
 		{AbortMultipartUploadAction, true},
+		{PutInventoryConfigurationAction, true},
+		{GetBucketACLAction, true},
+		{PutObjectACLAction, true},
 		{Action("foo"), false},
 	}
 
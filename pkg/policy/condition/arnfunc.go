@@ -0,0 +1,170 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/pkg/set"
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// arnSections - number of colon separated sections of an ARN.
+const arnSections = 6
+
+// matchARN - returns whether ARN matches the pattern. Each of the colon separated
+// sections is matched separately and may contain '*' and '?' wildcards.
+func matchARN(pattern, arn string) bool {
+	patternSections := strings.SplitN(pattern, ":", arnSections)
+	valueSections := strings.SplitN(arn, ":", arnSections)
+	if len(patternSections) != len(valueSections) {
+		return false
+	}
+
+	for i := range patternSections {
+		if !wildcard.Match(patternSections[i], valueSections[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// arnFunc - ARN comparison function. It checks whether value by Key in given values
+// map is an ARN matching condition values. ArnEquals and ArnLike behave alike.
+// For example,
+//   - if values = ["arn:aws:s3:::mybucket/*"], at evaluate() it returns whether
+//     ARN in value map for Key matches "arn:aws:s3:::mybucket/*".
+type arnFunc struct {
+	n      name
+	k      Key
+	values set.StringSet
+}
+
+// evaluate() - evaluates to check whether value by Key in given values matches
+// condition values.
+func (f arnFunc) evaluate(values map[string][]string) bool {
+	for _, v := range values[f.k.Name()] {
+		if !f.values.FuncMatch(matchARN, v).IsEmpty() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f arnFunc) key() Key {
+	return f.k
+}
+
+// name() - returns ARN condition name of this function.
+func (f arnFunc) name() name {
+	return f.n
+}
+
+func (f arnFunc) String() string {
+	return toStringLikeFuncString(f.n, f.k, f.values)
+}
+
+// toMap - returns map representation of this function.
+func (f arnFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values.ToSlice() {
+		values.Add(NewStringValue(value))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// arnNotFunc - ARN negated comparison function. It checks whether value by Key in
+// given values map does NOT match condition values. ArnNotEquals and ArnNotLike
+// behave alike.
+type arnNotFunc struct {
+	arnFunc
+}
+
+// evaluate() - evaluates to check whether value by Key in given values does NOT
+// match condition values.
+func (f arnNotFunc) evaluate(values map[string][]string) bool {
+	return !f.arnFunc.evaluate(values)
+}
+
+func validateARNValues(n name, values set.StringSet) error {
+	for _, s := range values.ToSlice() {
+		if !strings.HasPrefix(s, "arn:") || len(strings.SplitN(s, ":", arnSections)) != arnSections {
+			return fmt.Errorf("invalid ARN '%v' for %v condition", s, n)
+		}
+	}
+
+	return nil
+}
+
+// newARNFunc - returns new ARN function of given ARN condition name.
+func newARNFunc(n name, key Key, values ValueSet) (Function, error) {
+	valueStrings, err := valuesToStringSlice(n, values)
+	if err != nil {
+		return nil, err
+	}
+
+	sset := set.CreateStringSet(valueStrings...)
+	if err = validateARNValues(n, sset); err != nil {
+		return nil, err
+	}
+
+	if n == arnNotEquals || n == arnNotLike {
+		return &arnNotFunc{arnFunc{n, key, sset}}, nil
+	}
+
+	return &arnFunc{n, key, sset}, nil
+}
+
+// NewArnEqualsFunc - returns new ArnEquals function.
+func NewArnEqualsFunc(key Key, values ...string) (Function, error) {
+	return newARNFunc(arnEquals, key, toStringValueSet(values))
+}
+
+// NewArnNotEqualsFunc - returns new ArnNotEquals function.
+func NewArnNotEqualsFunc(key Key, values ...string) (Function, error) {
+	return newARNFunc(arnNotEquals, key, toStringValueSet(values))
+}
+
+// NewArnLikeFunc - returns new ArnLike function.
+func NewArnLikeFunc(key Key, values ...string) (Function, error) {
+	return newARNFunc(arnLike, key, toStringValueSet(values))
+}
+
+// NewArnNotLikeFunc - returns new ArnNotLike function.
+func NewArnNotLikeFunc(key Key, values ...string) (Function, error) {
+	return newARNFunc(arnNotLike, key, toStringValueSet(values))
+}
+
+func toStringValueSet(values []string) ValueSet {
+	valueSet := NewValueSet()
+	for _, value := range values {
+		valueSet.Add(NewStringValue(value))
+	}
+
+	return valueSet
+}
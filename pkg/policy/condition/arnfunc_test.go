@@ -0,0 +1,117 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchARN(t *testing.T) {
+	testCases := []struct {
+		pattern        string
+		arn            string
+		expectedResult bool
+	}{
+		{"arn:aws:s3:::mybucket", "arn:aws:s3:::mybucket", true},
+		{"arn:aws:s3:::mybucket/*", "arn:aws:s3:::mybucket/myobject", true},
+		{"arn:aws:s3:::mybucket/*", "arn:aws:s3:::yourbucket/myobject", false},
+		{"arn:aws:sns:*:123456789012:*", "arn:aws:sns:us-east-1:123456789012:mytopic", true},
+		{"arn:aws:sns:*:123456789012:*", "arn:aws:sns:us-east-1:210987654321:mytopic", false},
+		// Wildcard does not span sections.
+		{"arn:*:mybucket", "arn:aws:s3:::mybucket", false},
+		{"arn:aws:s3:::mybucket", "mybucket", false},
+	}
+
+	for i, testCase := range testCases {
+		result := matchARN(testCase.pattern, testCase.arn)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestARNFuncEvaluate(t *testing.T) {
+	case1Function, err := NewArnLikeFunc(AWSReferer, "arn:aws:s3:::mybucket/*")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewArnNotEqualsFunc(AWSReferer, "arn:aws:s3:::mybucket/*")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		function       Function
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{case1Function, map[string][]string{"Referer": {"arn:aws:s3:::mybucket/myobject"}}, true},
+		{case1Function, map[string][]string{"Referer": {"arn:aws:s3:::yourbucket/myobject"}}, false},
+		{case1Function, map[string][]string{}, false},
+		{case2Function, map[string][]string{"Referer": {"arn:aws:s3:::mybucket/myobject"}}, false},
+		{case2Function, map[string][]string{"Referer": {"arn:aws:s3:::yourbucket/myobject"}}, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewARNFunc(t *testing.T) {
+	testCases := []struct {
+		n              name
+		values         ValueSet
+		expectedResult name
+		expectErr      bool
+	}{
+		{arnEquals, NewValueSet(NewStringValue("arn:aws:s3:::mybucket")), arnEquals, false},
+		{arnLike, NewValueSet(NewStringValue("arn:aws:s3:::mybucket/*")), arnLike, false},
+		{arnNotEquals, NewValueSet(NewStringValue("arn:aws:s3:::mybucket")), arnNotEquals, false},
+		{arnNotLike, NewValueSet(NewStringValue("arn:aws:s3:::mybucket/*")), arnNotLike, false},
+		// Invalid ARN error.
+		{arnLike, NewValueSet(NewStringValue("mybucket/*")), "", true},
+		{arnLike, NewValueSet(NewStringValue("arn:aws:s3:mybucket")), "", true},
+		// Invalid value error.
+		{arnLike, NewValueSet(NewIntValue(7)), "", true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := newARNFunc(testCase.n, AWSReferer, testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if result.name() != testCase.expectedResult {
+				t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result.name())
+			}
+
+			if !reflect.DeepEqual(result.toMap(), map[Key]ValueSet{AWSReferer: testCase.values}) {
+				t.Fatalf("case %v: values: expected: %v, got: %v\n", i+1, testCase.values, result.toMap())
+			}
+		}
+	}
+}
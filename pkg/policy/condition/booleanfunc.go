@@ -0,0 +1,99 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// booleanFunc - Bool condition function. It checks whether boolean value by Key in
+// given values map is equal to condition value.
+// For example,
+//   - if Key = AWSSecureTransport and Value = true, at evaluate() it returns whether
+//     request is sent over TLS.
+type booleanFunc struct {
+	k     Key
+	value bool
+}
+
+// evaluate() - evaluates to check whether boolean value by Key in given values is
+// equal to condition value. Non-boolean values never match.
+func (f booleanFunc) evaluate(values map[string][]string) bool {
+	for _, s := range values[f.k.Name()] {
+		if b, err := strconv.ParseBool(s); err == nil && b == f.value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f booleanFunc) key() Key {
+	return f.k
+}
+
+// name() - returns "Bool" condition name.
+func (f booleanFunc) name() name {
+	return boolean
+}
+
+func (f booleanFunc) String() string {
+	return fmt.Sprintf("%v:%v:%v", boolean, f.k, f.value)
+}
+
+// toMap - returns map representation of this function.
+func (f booleanFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	return map[Key]ValueSet{
+		f.k: NewValueSet(NewBoolValue(f.value)),
+	}
+}
+
+func newBooleanFunc(key Key, values ValueSet) (Function, error) {
+	if len(values) != 1 {
+		return nil, fmt.Errorf("only one value is allowed for Bool condition")
+	}
+
+	var value bool
+	for v := range values {
+		switch v.GetType() {
+		case reflect.Bool:
+			value, _ = v.GetBool()
+		case reflect.String:
+			var err error
+			s, _ := v.GetString()
+			if value, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("value must be a boolean string for Bool condition")
+			}
+		default:
+			return nil, fmt.Errorf("value must be a boolean for Bool condition")
+		}
+	}
+
+	return NewBoolFunc(key, value)
+}
+
+// NewBoolFunc - returns new Bool function.
+func NewBoolFunc(key Key, value bool) (Function, error) {
+	return &booleanFunc{key, value}, nil
+}
@@ -0,0 +1,221 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+func toDateFuncString(n name, key Key, values []time.Time) string {
+	valueStrings := []string{}
+	for _, value := range values {
+		valueStrings = append(valueStrings, value.UTC().Format(time.RFC3339))
+	}
+	sort.Strings(valueStrings)
+
+	return fmt.Sprintf("%v:%v:%v", n, key, valueStrings)
+}
+
+// parseDate - parses date in RFC3339 format or seconds since Unix epoch.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%v'", s)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// compareDate - returns whether request date compares to condition date as given
+// date condition name requires.
+func compareDate(n name, requestValue, value time.Time) bool {
+	switch n {
+	case dateEquals:
+		return requestValue.Equal(value)
+	case dateLessThan:
+		return requestValue.Before(value)
+	case dateLessThanEquals:
+		return !requestValue.After(value)
+	case dateGreaterThan:
+		return requestValue.After(value)
+	case dateGreaterThanEquals:
+		return !requestValue.Before(value)
+	}
+
+	return false
+}
+
+// dateFunc - Date comparison function. It checks whether date value by Key in given
+// values map compares to any condition value as per condition name. Dates are either
+// in RFC3339 format or seconds since Unix epoch.
+// For example,
+//   - if n = DateGreaterThan and values = ["2018-10-01T00:00:00Z"], at evaluate()
+//     it returns whether date in value map for Key is after October 1st 2018.
+type dateFunc struct {
+	n      name
+	k      Key
+	values []time.Time
+}
+
+// evaluate() - evaluates to check whether date value by Key in given values compares
+// to any condition value. Invalid dates never match.
+func (f dateFunc) evaluate(values map[string][]string) bool {
+	for _, s := range values[f.k.Name()] {
+		requestValue, err := parseDate(s)
+		if err != nil {
+			continue
+		}
+
+		for _, value := range f.values {
+			if compareDate(f.n, requestValue, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f dateFunc) key() Key {
+	return f.k
+}
+
+// name() - returns date condition name of this function.
+func (f dateFunc) name() name {
+	return f.n
+}
+
+func (f dateFunc) String() string {
+	return toDateFuncString(f.n, f.k, f.values)
+}
+
+// toMap - returns map representation of this function.
+func (f dateFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values {
+		values.Add(NewStringValue(value.UTC().Format(time.RFC3339)))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// dateNotEqualsFunc - Date not equals function. It checks whether date value by Key
+// in given values map is NOT equal to any condition value.
+// For example,
+//   - if values = ["2018-10-01T00:00:00Z"], at evaluate() it returns whether date
+//     in value map for Key is NOT October 1st 2018.
+type dateNotEqualsFunc struct {
+	dateFunc
+}
+
+// evaluate() - evaluates to check whether date value by Key in given values is NOT
+// equal to any condition value.
+func (f dateNotEqualsFunc) evaluate(values map[string][]string) bool {
+	return !f.dateFunc.evaluate(values)
+}
+
+// name() - returns "DateNotEquals" condition name.
+func (f dateNotEqualsFunc) name() name {
+	return dateNotEquals
+}
+
+func (f dateNotEqualsFunc) String() string {
+	return toDateFuncString(dateNotEquals, f.dateFunc.k, f.dateFunc.values)
+}
+
+func valuesToDates(n name, values ValueSet) ([]time.Time, error) {
+	dates := []time.Time{}
+	for v := range values {
+		var t time.Time
+		switch v.GetType() {
+		case reflect.Int:
+			i, _ := v.GetInt()
+			t = time.Unix(int64(i), 0).UTC()
+		case reflect.String:
+			var err error
+			s, _ := v.GetString()
+			if t, err = parseDate(s); err != nil {
+				return nil, fmt.Errorf("value %v must be a RFC3339 date or epoch seconds for %v condition", s, n)
+			}
+		default:
+			return nil, fmt.Errorf("value %v must be a date for %v condition", v, n)
+		}
+
+		dates = append(dates, t)
+	}
+
+	return dates, nil
+}
+
+// newDateFunc - returns new date function of given date condition name.
+func newDateFunc(n name, key Key, values ValueSet) (Function, error) {
+	dates, err := valuesToDates(n, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == dateNotEquals {
+		return NewDateNotEqualsFunc(key, dates...)
+	}
+
+	return &dateFunc{n, key, dates}, nil
+}
+
+// NewDateEqualsFunc - returns new DateEquals function.
+func NewDateEqualsFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateFunc{dateEquals, key, values}, nil
+}
+
+// NewDateNotEqualsFunc - returns new DateNotEquals function.
+func NewDateNotEqualsFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateNotEqualsFunc{dateFunc{dateEquals, key, values}}, nil
+}
+
+// NewDateLessThanFunc - returns new DateLessThan function.
+func NewDateLessThanFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateFunc{dateLessThan, key, values}, nil
+}
+
+// NewDateLessThanEqualsFunc - returns new DateLessThanEquals function.
+func NewDateLessThanEqualsFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateFunc{dateLessThanEquals, key, values}, nil
+}
+
+// NewDateGreaterThanFunc - returns new DateGreaterThan function.
+func NewDateGreaterThanFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateFunc{dateGreaterThan, key, values}, nil
+}
+
+// NewDateGreaterThanEqualsFunc - returns new DateGreaterThanEquals function.
+func NewDateGreaterThanEqualsFunc(key Key, values ...time.Time) (Function, error) {
+	return &dateFunc{dateGreaterThanEquals, key, values}, nil
+}
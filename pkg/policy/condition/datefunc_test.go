@@ -0,0 +1,136 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDateFuncEvaluate(t *testing.T) {
+	testCases := []struct {
+		n              name
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{dateEquals, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, true},
+		{dateEquals, map[string][]string{"CurrentTime": {"2018-10-01T02:00:00+02:00"}}, true},
+		{dateEquals, map[string][]string{"CurrentTime": {"2018-10-02T00:00:00Z"}}, false},
+		{dateNotEquals, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, false},
+		{dateNotEquals, map[string][]string{"CurrentTime": {"2018-10-02T00:00:00Z"}}, true},
+		{dateLessThan, map[string][]string{"CurrentTime": {"2018-09-30T23:59:59Z"}}, true},
+		{dateLessThan, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, false},
+		{dateLessThanEquals, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, true},
+		{dateLessThanEquals, map[string][]string{"CurrentTime": {"2018-10-01T00:00:01Z"}}, false},
+		{dateGreaterThan, map[string][]string{"CurrentTime": {"2018-10-01T00:00:01Z"}}, true},
+		{dateGreaterThan, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, false},
+		{dateGreaterThanEquals, map[string][]string{"CurrentTime": {"2018-10-01T00:00:00Z"}}, true},
+		{dateGreaterThanEquals, map[string][]string{"CurrentTime": {"2018-09-30T23:59:59Z"}}, false},
+		// Epoch seconds are accepted.
+		{dateEquals, map[string][]string{"CurrentTime": {"1538352000"}}, true},
+		// Invalid and missing values never match.
+		{dateGreaterThan, map[string][]string{"CurrentTime": {"foo"}}, false},
+		{dateGreaterThan, map[string][]string{}, false},
+	}
+
+	for i, testCase := range testCases {
+		function, err := newDateFunc(testCase.n, AWSCurrentTime, NewValueSet(NewStringValue("2018-10-01T00:00:00Z")))
+		if err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+
+		result := function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestDateFuncToMap(t *testing.T) {
+	date := time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	case1Function, err := NewDateGreaterThanFunc(AWSCurrentTime, date)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case1Result := map[Key]ValueSet{
+		AWSCurrentTime: NewValueSet(NewStringValue("2018-10-01T00:00:00Z")),
+	}
+
+	testCases := []struct {
+		f              Function
+		expectedResult map[Key]ValueSet
+	}{
+		{case1Function, case1Result},
+		{&dateFunc{}, nil},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.f.toMap()
+
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewDateFunc(t *testing.T) {
+	date := time.Date(2018, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	case1Function, err := NewDateLessThanFunc(AWSCurrentTime, date)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewDateNotEqualsFunc(AWSCurrentTime, date)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		n              name
+		values         ValueSet
+		expectedResult Function
+		expectErr      bool
+	}{
+		{dateLessThan, NewValueSet(NewStringValue("2018-10-01T00:00:00Z")), case1Function, false},
+		{dateLessThan, NewValueSet(NewIntValue(1538352000)), case1Function, false},
+		{dateNotEquals, NewValueSet(NewStringValue("2018-10-01T00:00:00Z")), case2Function, false},
+		// Invalid date string error.
+		{dateLessThan, NewValueSet(NewStringValue("2018-10-01")), nil, true},
+		// Invalid value error.
+		{dateLessThan, NewValueSet(NewBoolValue(true)), nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := newDateFunc(testCase.n, AWSCurrentTime, testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if !reflect.DeepEqual(result, testCase.expectedResult) {
+				t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+			}
+		}
+	}
+}
@@ -119,38 +119,16 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 				return err
 			}
 
-			var f Function
-			switch n {
-			case stringEquals:
-				if f, err = newStringEqualsFunc(key, values); err != nil {
-					return err
-				}
-			case stringNotEquals:
-				if f, err = newStringNotEqualsFunc(key, values); err != nil {
-					return err
-				}
-			case stringLike:
-				if f, err = newStringLikeFunc(key, values); err != nil {
-					return err
-				}
-			case stringNotLike:
-				if f, err = newStringNotLikeFunc(key, values); err != nil {
-					return err
-				}
-			case ipAddress:
-				if f, err = newIPAddressFunc(key, values); err != nil {
-					return err
-				}
-			case notIPAddress:
-				if f, err = newNotIPAddressFunc(key, values); err != nil {
-					return err
-				}
-			case null:
-				if f, err = newNullFunc(key, values); err != nil {
+			qualifier, unqualifiedName := n.split()
+			f, err := newFunc(unqualifiedName, key, values)
+			if err != nil {
+				return err
+			}
+
+			if qualifier != "" {
+				if f, err = newQualifiedFunc(qualifier, f); err != nil {
 					return err
 				}
-			default:
-				return fmt.Errorf("%v is not handled", n)
 			}
 
 			funcs = append(funcs, f)
@@ -162,6 +140,40 @@ func (functions *Functions) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// newFunc - returns new function of given unqualified condition name.
+func newFunc(n name, key Key, values ValueSet) (Function, error) {
+	switch n {
+	case stringEquals:
+		return newStringEqualsFunc(key, values)
+	case stringNotEquals:
+		return newStringNotEqualsFunc(key, values)
+	case stringEqualsIgnoreCase:
+		return newStringEqualsIgnoreCaseFunc(key, values)
+	case stringNotEqualsIgnoreCase:
+		return newStringNotEqualsIgnoreCaseFunc(key, values)
+	case stringLike:
+		return newStringLikeFunc(key, values)
+	case stringNotLike:
+		return newStringNotLikeFunc(key, values)
+	case numericEquals, numericNotEquals, numericLessThan, numericLessThanEquals, numericGreaterThan, numericGreaterThanEquals:
+		return newNumericFunc(n, key, values)
+	case dateEquals, dateNotEquals, dateLessThan, dateLessThanEquals, dateGreaterThan, dateGreaterThanEquals:
+		return newDateFunc(n, key, values)
+	case boolean:
+		return newBooleanFunc(key, values)
+	case arnEquals, arnNotEquals, arnLike, arnNotLike:
+		return newARNFunc(n, key, values)
+	case ipAddress:
+		return newIPAddressFunc(key, values)
+	case notIPAddress:
+		return newNotIPAddressFunc(key, values)
+	case null:
+		return newNullFunc(key, values)
+	}
+
+	return nil, fmt.Errorf("%v is not handled", n)
+}
+
 // GobEncode - encodes Functions to gob data.
 func (functions Functions) GobEncode() ([]byte, error) {
 	return functions.MarshalJSON()
@@ -268,6 +268,63 @@ func TestFunctionsUnmarshalJSON(t *testing.T) {
 
 	case3Data := []byte(`{}`)
 
+	case4Data := []byte(`{
+    "NumericLessThan": {
+        "s3:max-keys": 100
+    },
+    "DateGreaterThan": {
+        "aws:CurrentTime": "2018-10-01T00:00:00Z"
+    },
+    "Bool": {
+        "aws:SecureTransport": "true"
+    },
+    "StringEqualsIgnoreCase": {
+        "aws:UserAgent": "Minio"
+    },
+    "ForAllValues:StringLike": {
+        "s3:prefix": "photos/*"
+    }
+}`)
+	func8, err := newNumericFunc(numericLessThan, S3MaxKeys, NewValueSet(NewIntValue(100)))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	func9, err := newDateFunc(dateGreaterThan, AWSCurrentTime, NewValueSet(NewStringValue("2018-10-01T00:00:00Z")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	func10, err := newBooleanFunc(AWSSecureTransport, NewValueSet(NewBoolValue(true)))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	func11, err := newStringEqualsIgnoreCaseFunc(AWSUserAgent, NewValueSet(NewStringValue("Minio")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	func12, err := newStringLikeFunc(S3Prefix, NewValueSet(NewStringValue("photos/*")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	if func12, err = NewForAllValuesFunc(func12); err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case5Data := []byte(`{
+    "ForAnyValue:Null": {
+        "s3:prefix": true
+    }
+}`)
+
+	case6Data := []byte(`{
+    "NumericLessThan": {
+        "s3:max-keys": "foo"
+    }
+}`)
+
 	testCases := []struct {
 		data           []byte
 		expectedResult Functions
@@ -278,6 +335,11 @@ func TestFunctionsUnmarshalJSON(t *testing.T) {
 		{case2Data, NewFunctions(func6), false},
 		// empty condition error.
 		{case3Data, nil, true},
+		{case4Data, NewFunctions(func8, func9, func10, func11, func12), false},
+		// qualified Null condition error.
+		{case5Data, nil, true},
+		// non-integer numeric condition error.
+		{case6Data, nil, true},
 	}
 
 	for i, testCase := range testCases {
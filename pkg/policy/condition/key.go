@@ -68,8 +68,27 @@ const (
 
 	// AWSSourceIP - key representing client's IP address (not intermittent proxies) of any API.
 	AWSSourceIP = "aws:SourceIp"
+
+	// AWSSecureTransport - key representing whether the request is sent over TLS of any API.
+	AWSSecureTransport = "aws:SecureTransport"
+
+	// AWSCurrentTime - key representing server time in RFC3339 format of any API.
+	AWSCurrentTime = "aws:CurrentTime"
+
+	// AWSEpochTime - key representing server time in seconds since Unix epoch of any API.
+	AWSEpochTime = "aws:EpochTime"
+
+	// AWSUserAgent - key representing User-Agent header of any API.
+	AWSUserAgent = "aws:UserAgent"
+
+	// AWSUsername - key representing account name of the requester of any API.
+	AWSUsername = "aws:username"
 )
 
+// CommonKeys - condition keys applicable to any API.
+var CommonKeys = NewKeySet(AWSReferer, AWSSourceIP, AWSSecureTransport, AWSCurrentTime,
+	AWSEpochTime, AWSUserAgent, AWSUsername)
+
 // IsValid - checks if key is valid or not.
 func (key Key) IsValid() bool {
 	switch key {
@@ -78,6 +97,8 @@ func (key Key) IsValid() bool {
 	case S3XAmzMetadataDirective, S3XAmzStorageClass, S3LocationConstraint, S3Prefix:
 		fallthrough
 	case S3Delimiter, S3MaxKeys, AWSReferer, AWSSourceIP:
+		fallthrough
+	case AWSSecureTransport, AWSCurrentTime, AWSEpochTime, AWSUserAgent, AWSUsername:
 		return true
 	}
 
@@ -38,6 +38,11 @@ func TestKeyIsValid(t *testing.T) {
 		{S3MaxKeys, true},
 		{AWSReferer, true},
 		{AWSSourceIP, true},
+		{AWSSecureTransport, true},
+		{AWSCurrentTime, true},
+		{AWSEpochTime, true},
+		{AWSUserAgent, true},
+		{AWSUsername, true},
 		{Key("foo"), false},
 	}
 
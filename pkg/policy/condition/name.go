@@ -19,25 +19,75 @@ package condition
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type name string
 
 const (
-	stringEquals    name = "StringEquals"
-	stringNotEquals      = "StringNotEquals"
-	stringLike           = "StringLike"
-	stringNotLike        = "StringNotLike"
-	ipAddress            = "IpAddress"
-	notIPAddress         = "NotIpAddress"
-	null                 = "Null"
+	stringEquals              name = "StringEquals"
+	stringNotEquals                = "StringNotEquals"
+	stringEqualsIgnoreCase         = "StringEqualsIgnoreCase"
+	stringNotEqualsIgnoreCase      = "StringNotEqualsIgnoreCase"
+	stringLike                     = "StringLike"
+	stringNotLike                  = "StringNotLike"
+	numericEquals                  = "NumericEquals"
+	numericNotEquals               = "NumericNotEquals"
+	numericLessThan                = "NumericLessThan"
+	numericLessThanEquals          = "NumericLessThanEquals"
+	numericGreaterThan             = "NumericGreaterThan"
+	numericGreaterThanEquals       = "NumericGreaterThanEquals"
+	dateEquals                     = "DateEquals"
+	dateNotEquals                  = "DateNotEquals"
+	dateLessThan                   = "DateLessThan"
+	dateLessThanEquals             = "DateLessThanEquals"
+	dateGreaterThan                = "DateGreaterThan"
+	dateGreaterThanEquals          = "DateGreaterThanEquals"
+	boolean                        = "Bool"
+	arnEquals                      = "ArnEquals"
+	arnNotEquals                   = "ArnNotEquals"
+	arnLike                        = "ArnLike"
+	arnNotLike                     = "ArnNotLike"
+	ipAddress                      = "IpAddress"
+	notIPAddress                   = "NotIpAddress"
+	null                           = "Null"
 )
 
+// Set operator qualifiers of a condition name.
+const (
+	forAnyValue  = "ForAnyValue:"
+	forAllValues = "ForAllValues:"
+)
+
+// split - returns set operator qualifier and unqualified name of this name.
+func (n name) split() (string, name) {
+	for _, qualifier := range []string{forAnyValue, forAllValues} {
+		if strings.HasPrefix(string(n), qualifier) {
+			return qualifier, name(strings.TrimPrefix(string(n), qualifier))
+		}
+	}
+
+	return "", n
+}
+
 // IsValid - checks if name is valid or not.
 func (n name) IsValid() bool {
+	qualifier, n := n.split()
+
 	switch n {
-	case stringEquals, stringNotEquals, stringLike, stringNotLike, ipAddress, notIPAddress, null:
+	case stringEquals, stringNotEquals, stringEqualsIgnoreCase, stringNotEqualsIgnoreCase:
+		fallthrough
+	case stringLike, stringNotLike, numericEquals, numericNotEquals, numericLessThan:
+		fallthrough
+	case numericLessThanEquals, numericGreaterThan, numericGreaterThanEquals, dateEquals:
+		fallthrough
+	case dateNotEquals, dateLessThan, dateLessThanEquals, dateGreaterThan, dateGreaterThanEquals:
+		fallthrough
+	case boolean, arnEquals, arnNotEquals, arnLike, arnNotLike, ipAddress, notIPAddress:
 		return true
+	case null:
+		// Null checks presence of a key, set operators are meaningless on it.
+		return qualifier == ""
 	}
 
 	return false
@@ -34,6 +34,15 @@ func TestNameIsValid(t *testing.T) {
 		{ipAddress, true},
 		{notIPAddress, true},
 		{null, true},
+		{stringEqualsIgnoreCase, true},
+		{numericLessThan, true},
+		{dateGreaterThan, true},
+		{boolean, true},
+		{arnLike, true},
+		{name("ForAnyValue:StringEquals"), true},
+		{name("ForAllValues:StringLike"), true},
+		{name("ForAllValues:Null"), false},
+		{name("ForAnyValue:foo"), false},
 		{name("foo"), false},
 	}
 
@@ -0,0 +1,204 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+func toNumericFuncString(n name, key Key, values []int) string {
+	valueStrings := []string{}
+	for _, value := range values {
+		valueStrings = append(valueStrings, strconv.Itoa(value))
+	}
+	sort.Strings(valueStrings)
+
+	return fmt.Sprintf("%v:%v:%v", n, key, valueStrings)
+}
+
+// compareNumeric - returns whether request value compares to condition value as
+// given numeric condition name requires.
+func compareNumeric(n name, requestValue, value int) bool {
+	switch n {
+	case numericEquals:
+		return requestValue == value
+	case numericLessThan:
+		return requestValue < value
+	case numericLessThanEquals:
+		return requestValue <= value
+	case numericGreaterThan:
+		return requestValue > value
+	case numericGreaterThanEquals:
+		return requestValue >= value
+	}
+
+	return false
+}
+
+// numericFunc - Numeric comparison function. It checks whether integer value by Key
+// in given values map compares to any condition value as per condition name.
+// For example,
+//   - if n = NumericLessThan and values = [1000], at evaluate() it returns whether
+//     integer in value map for Key is less than 1000.
+type numericFunc struct {
+	n      name
+	k      Key
+	values []int
+}
+
+// evaluate() - evaluates to check whether integer value by Key in given values
+// compares to any condition value. Non-integer values never match.
+func (f numericFunc) evaluate(values map[string][]string) bool {
+	for _, s := range values[f.k.Name()] {
+		requestValue, err := strconv.Atoi(s)
+		if err != nil {
+			continue
+		}
+
+		for _, value := range f.values {
+			if compareNumeric(f.n, requestValue, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// key() - returns condition key which is used by this condition function.
+func (f numericFunc) key() Key {
+	return f.k
+}
+
+// name() - returns numeric condition name of this function.
+func (f numericFunc) name() name {
+	return f.n
+}
+
+func (f numericFunc) String() string {
+	return toNumericFuncString(f.n, f.k, f.values)
+}
+
+// toMap - returns map representation of this function.
+func (f numericFunc) toMap() map[Key]ValueSet {
+	if !f.k.IsValid() {
+		return nil
+	}
+
+	values := NewValueSet()
+	for _, value := range f.values {
+		values.Add(NewIntValue(value))
+	}
+
+	return map[Key]ValueSet{
+		f.k: values,
+	}
+}
+
+// numericNotEqualsFunc - Numeric not equals function. It checks whether integer value
+// by Key in given values map is NOT equal to any condition value.
+// For example,
+//   - if values = [1000], at evaluate() it returns whether integer in value map
+//     for Key is NOT 1000.
+type numericNotEqualsFunc struct {
+	numericFunc
+}
+
+// evaluate() - evaluates to check whether integer value by Key in given values is
+// NOT equal to any condition value.
+func (f numericNotEqualsFunc) evaluate(values map[string][]string) bool {
+	return !f.numericFunc.evaluate(values)
+}
+
+// name() - returns "NumericNotEquals" condition name.
+func (f numericNotEqualsFunc) name() name {
+	return numericNotEquals
+}
+
+func (f numericNotEqualsFunc) String() string {
+	return toNumericFuncString(numericNotEquals, f.numericFunc.k, f.numericFunc.values)
+}
+
+func valuesToInts(n name, values ValueSet) ([]int, error) {
+	ints := []int{}
+	for v := range values {
+		var i int
+		switch v.GetType() {
+		case reflect.Int:
+			i, _ = v.GetInt()
+		case reflect.String:
+			var err error
+			s, _ := v.GetString()
+			if i, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("value %v must be an integer string for %v condition", s, n)
+			}
+		default:
+			return nil, fmt.Errorf("value %v must be an integer for %v condition", v, n)
+		}
+
+		ints = append(ints, i)
+	}
+
+	return ints, nil
+}
+
+// newNumericFunc - returns new numeric function of given numeric condition name.
+func newNumericFunc(n name, key Key, values ValueSet) (Function, error) {
+	ints, err := valuesToInts(n, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == numericNotEquals {
+		return NewNumericNotEqualsFunc(key, ints...)
+	}
+
+	return &numericFunc{n, key, ints}, nil
+}
+
+// NewNumericEqualsFunc - returns new NumericEquals function.
+func NewNumericEqualsFunc(key Key, values ...int) (Function, error) {
+	return &numericFunc{numericEquals, key, values}, nil
+}
+
+// NewNumericNotEqualsFunc - returns new NumericNotEquals function.
+func NewNumericNotEqualsFunc(key Key, values ...int) (Function, error) {
+	return &numericNotEqualsFunc{numericFunc{numericEquals, key, values}}, nil
+}
+
+// NewNumericLessThanFunc - returns new NumericLessThan function.
+func NewNumericLessThanFunc(key Key, values ...int) (Function, error) {
+	return &numericFunc{numericLessThan, key, values}, nil
+}
+
+// NewNumericLessThanEqualsFunc - returns new NumericLessThanEquals function.
+func NewNumericLessThanEqualsFunc(key Key, values ...int) (Function, error) {
+	return &numericFunc{numericLessThanEquals, key, values}, nil
+}
+
+// NewNumericGreaterThanFunc - returns new NumericGreaterThan function.
+func NewNumericGreaterThanFunc(key Key, values ...int) (Function, error) {
+	return &numericFunc{numericGreaterThan, key, values}, nil
+}
+
+// NewNumericGreaterThanEqualsFunc - returns new NumericGreaterThanEquals function.
+func NewNumericGreaterThanEqualsFunc(key Key, values ...int) (Function, error) {
+	return &numericFunc{numericGreaterThanEquals, key, values}, nil
+}
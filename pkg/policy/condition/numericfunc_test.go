@@ -0,0 +1,161 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNumericFuncEvaluate(t *testing.T) {
+	testCases := []struct {
+		n              name
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{numericEquals, map[string][]string{"max-keys": {"100"}}, true},
+		{numericEquals, map[string][]string{"max-keys": {"1000"}}, false},
+		{numericNotEquals, map[string][]string{"max-keys": {"100"}}, false},
+		{numericNotEquals, map[string][]string{"max-keys": {"1000"}}, true},
+		{numericLessThan, map[string][]string{"max-keys": {"99"}}, true},
+		{numericLessThan, map[string][]string{"max-keys": {"100"}}, false},
+		{numericLessThanEquals, map[string][]string{"max-keys": {"100"}}, true},
+		{numericLessThanEquals, map[string][]string{"max-keys": {"101"}}, false},
+		{numericGreaterThan, map[string][]string{"max-keys": {"101"}}, true},
+		{numericGreaterThan, map[string][]string{"max-keys": {"100"}}, false},
+		{numericGreaterThanEquals, map[string][]string{"max-keys": {"100"}}, true},
+		{numericGreaterThanEquals, map[string][]string{"max-keys": {"99"}}, false},
+		// Non-integer and missing values never match.
+		{numericLessThan, map[string][]string{"max-keys": {"foo"}}, false},
+		{numericLessThan, map[string][]string{}, false},
+		{numericNotEquals, map[string][]string{}, true},
+	}
+
+	for i, testCase := range testCases {
+		function, err := newNumericFunc(testCase.n, S3MaxKeys, NewValueSet(NewIntValue(100)))
+		if err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+
+		result := function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNumericFuncName(t *testing.T) {
+	testCases := []name{
+		numericEquals,
+		numericNotEquals,
+		numericLessThan,
+		numericLessThanEquals,
+		numericGreaterThan,
+		numericGreaterThanEquals,
+	}
+
+	for i, n := range testCases {
+		function, err := newNumericFunc(n, S3MaxKeys, NewValueSet(NewIntValue(100)))
+		if err != nil {
+			t.Fatalf("case %v: unexpected error. %v\n", i+1, err)
+		}
+
+		if result := function.name(); result != n {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, n, result)
+		}
+	}
+}
+
+func TestNumericFuncToMap(t *testing.T) {
+	case1Function, err := NewNumericLessThanFunc(S3MaxKeys, 100)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case1Result := map[Key]ValueSet{
+		S3MaxKeys: NewValueSet(NewIntValue(100)),
+	}
+
+	case2Function, err := NewNumericNotEqualsFunc(S3MaxKeys, 100, 200)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Result := map[Key]ValueSet{
+		S3MaxKeys: NewValueSet(NewIntValue(100), NewIntValue(200)),
+	}
+
+	testCases := []struct {
+		f              Function
+		expectedResult map[Key]ValueSet
+	}{
+		{case1Function, case1Result},
+		{case2Function, case2Result},
+		{&numericFunc{}, nil},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.f.toMap()
+
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewNumericFunc(t *testing.T) {
+	case1Function, err := NewNumericLessThanFunc(S3MaxKeys, 100)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewNumericNotEqualsFunc(S3MaxKeys, 100)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		n              name
+		values         ValueSet
+		expectedResult Function
+		expectErr      bool
+	}{
+		{numericLessThan, NewValueSet(NewIntValue(100)), case1Function, false},
+		{numericLessThan, NewValueSet(NewStringValue("100")), case1Function, false},
+		{numericNotEquals, NewValueSet(NewIntValue(100)), case2Function, false},
+		// Non-integer string error.
+		{numericLessThan, NewValueSet(NewStringValue("foo")), nil, true},
+		// Invalid value error.
+		{numericLessThan, NewValueSet(NewBoolValue(true)), nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result, err := newNumericFunc(testCase.n, S3MaxKeys, testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if !reflect.DeepEqual(result, testCase.expectedResult) {
+				t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import "fmt"
+
+// qualifiedFunc - set operator qualified function. It evaluates wrapped function
+// on each value by Key in given values separately.
+// For example,
+//   - ForAnyValue:StringEquals returns whether at least one value by Key is in
+//     condition values.
+//   - ForAllValues:StringEquals returns whether every value by Key is in
+//     condition values. It returns true if Key has no value.
+type qualifiedFunc struct {
+	qualifier string
+	function  Function
+}
+
+// evaluate() - evaluates wrapped function on each value by Key in given values.
+func (f qualifiedFunc) evaluate(values map[string][]string) bool {
+	keyName := f.function.key().Name()
+
+	for _, v := range values[keyName] {
+		result := f.function.evaluate(map[string][]string{keyName: {v}})
+
+		if f.qualifier == forAnyValue && result {
+			return true
+		}
+
+		if f.qualifier == forAllValues && !result {
+			return false
+		}
+	}
+
+	return f.qualifier == forAllValues
+}
+
+// key() - returns condition key which is used by wrapped function.
+func (f qualifiedFunc) key() Key {
+	return f.function.key()
+}
+
+// name() - returns qualified condition name of wrapped function.
+func (f qualifiedFunc) name() name {
+	return name(f.qualifier) + f.function.name()
+}
+
+func (f qualifiedFunc) String() string {
+	return fmt.Sprintf("%v%v", f.qualifier, f.function)
+}
+
+// toMap - returns map representation of wrapped function.
+func (f qualifiedFunc) toMap() map[Key]ValueSet {
+	return f.function.toMap()
+}
+
+func newQualifiedFunc(qualifier string, function Function) (Function, error) {
+	if _, ok := function.(*nullFunc); ok {
+		return nil, fmt.Errorf("%v qualifier is not allowed for %v condition", qualifier, null)
+	}
+
+	return &qualifiedFunc{qualifier, function}, nil
+}
+
+// NewForAnyValueFunc - returns ForAnyValue qualified function of given function.
+func NewForAnyValueFunc(function Function) (Function, error) {
+	return newQualifiedFunc(forAnyValue, function)
+}
+
+// NewForAllValuesFunc - returns ForAllValues qualified function of given function.
+func NewForAllValuesFunc(function Function) (Function, error) {
+	return newQualifiedFunc(forAllValues, function)
+}
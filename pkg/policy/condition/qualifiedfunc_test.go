@@ -0,0 +1,126 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"testing"
+)
+
+func TestQualifiedFuncEvaluate(t *testing.T) {
+	stringEqualsFunction, err := NewStringEqualsFunc(S3Prefix, "photos/", "videos/")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	stringNotEqualsFunction, err := NewStringNotEqualsFunc(S3Prefix, "photos/")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case1Function, err := NewForAnyValueFunc(stringEqualsFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewForAllValuesFunc(stringEqualsFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case3Function, err := NewForAnyValueFunc(stringNotEqualsFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case4Function, err := NewForAllValuesFunc(stringNotEqualsFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		function       Function
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{case1Function, map[string][]string{"prefix": {"photos/", "music/"}}, true},
+		{case1Function, map[string][]string{"prefix": {"music/"}}, false},
+		{case1Function, map[string][]string{}, false},
+		{case2Function, map[string][]string{"prefix": {"photos/", "videos/"}}, true},
+		{case2Function, map[string][]string{"prefix": {"photos/", "music/"}}, false},
+		{case2Function, map[string][]string{}, true},
+		{case3Function, map[string][]string{"prefix": {"photos/", "music/"}}, true},
+		{case3Function, map[string][]string{"prefix": {"photos/"}}, false},
+		{case4Function, map[string][]string{"prefix": {"photos/", "music/"}}, false},
+		{case4Function, map[string][]string{"prefix": {"videos/", "music/"}}, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestQualifiedFuncName(t *testing.T) {
+	stringLikeFunction, err := NewStringLikeFunc(S3Prefix, "photos/*")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case1Function, err := NewForAnyValueFunc(stringLikeFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewForAllValuesFunc(stringLikeFunction)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		function       Function
+		expectedResult name
+	}{
+		{case1Function, name("ForAnyValue:StringLike")},
+		{case2Function, name("ForAllValues:StringLike")},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.function.name()
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+
+		if !result.IsValid() {
+			t.Fatalf("case %v: expected valid name %v\n", i+1, result)
+		}
+	}
+}
+
+func TestNewQualifiedFunc(t *testing.T) {
+	nullFunction, err := NewNullFunc(S3Prefix, true)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	if _, err = NewForAnyValueFunc(nullFunction); err == nil {
+		t.Fatalf("expected error for qualified Null condition")
+	}
+}
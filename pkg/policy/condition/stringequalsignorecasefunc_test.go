@@ -0,0 +1,107 @@
+/*
+ * Minio Cloud Storage, (C) 2018 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringEqualsIgnoreCaseFuncEvaluate(t *testing.T) {
+	case1Function, err := newStringEqualsIgnoreCaseFunc(AWSUserAgent, NewValueSet(NewStringValue("Minio")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := newStringNotEqualsIgnoreCaseFunc(AWSUserAgent, NewValueSet(NewStringValue("Minio")))
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		function       Function
+		values         map[string][]string
+		expectedResult bool
+	}{
+		{case1Function, map[string][]string{"UserAgent": {"Minio"}}, true},
+		{case1Function, map[string][]string{"UserAgent": {"MINIO"}}, true},
+		{case1Function, map[string][]string{"UserAgent": {"curl", "minio"}}, true},
+		{case1Function, map[string][]string{"UserAgent": {"curl"}}, false},
+		{case1Function, map[string][]string{}, false},
+		{case2Function, map[string][]string{"UserAgent": {"MINIO"}}, false},
+		{case2Function, map[string][]string{"UserAgent": {"curl"}}, true},
+		{case2Function, map[string][]string{}, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.function.evaluate(testCase.values)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestStringEqualsIgnoreCaseFuncToMap(t *testing.T) {
+	case1Function, err := NewStringEqualsIgnoreCaseFunc(AWSUserAgent, "Minio")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	case2Function, err := NewStringNotEqualsIgnoreCaseFunc(AWSUserAgent, "Minio", "curl")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	testCases := []struct {
+		f              Function
+		expectedResult map[Key]ValueSet
+	}{
+		{case1Function, map[Key]ValueSet{AWSUserAgent: NewValueSet(NewStringValue("Minio"))}},
+		{case2Function, map[Key]ValueSet{AWSUserAgent: NewValueSet(NewStringValue("Minio"), NewStringValue("curl"))}},
+		{&stringEqualsIgnoreCaseFunc{}, nil},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.f.toMap()
+
+		if !reflect.DeepEqual(result, testCase.expectedResult) {
+			t.Fatalf("case %v: result: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestNewStringEqualsIgnoreCaseFunc(t *testing.T) {
+	testCases := []struct {
+		values    ValueSet
+		expectErr bool
+	}{
+		{NewValueSet(NewStringValue("Minio")), false},
+		{NewValueSet(NewStringValue("Minio"), NewStringValue("curl")), false},
+		// Invalid value error.
+		{NewValueSet(NewIntValue(7)), true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := newStringEqualsIgnoreCaseFunc(AWSUserAgent, testCase.values)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("case %v: error: expected: %v, got: %v\n", i+1, testCase.expectErr, expectErr)
+		}
+	}
+}
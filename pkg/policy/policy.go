@@ -42,17 +42,27 @@ type Policy struct {
 	Statements []Statement `json:"Statement"`
 }
 
-// IsAllowed - checks given policy args is allowed to continue the Rest API.
-func (policy Policy) IsAllowed(args Args) bool {
-	// Check all deny statements. If any one statement denies, return false.
+// IsDenied - checks whether any deny statement of the policy explicitly
+// denies given policy args.
+func (policy Policy) IsDenied(args Args) bool {
 	for _, statement := range policy.Statements {
 		if statement.Effect == Deny {
 			if !statement.IsAllowed(args) {
-				return false
+				return true
 			}
 		}
 	}
 
+	return false
+}
+
+// IsAllowed - checks given policy args is allowed to continue the Rest API.
+func (policy Policy) IsAllowed(args Args) bool {
+	// If any one deny statement matches, return false.
+	if policy.IsDenied(args) {
+		return false
+	}
+
 	// For owner, its allowed by default.
 	if args.IsOwner {
 		return true
@@ -184,6 +184,75 @@ func TestPolicyIsAllowed(t *testing.T) {
 	}
 }
 
+func TestPolicyIsDenied(t *testing.T) {
+	allowPolicy := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				Allow,
+				NewPrincipal("*"),
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "/*")),
+				condition.NewFunctions(),
+			)},
+	}
+
+	denyPolicy := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				Deny,
+				NewPrincipal("*"),
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "/private*")),
+				condition.NewFunctions(),
+			)},
+	}
+
+	getObjectArgs := Args{
+		Action:          GetObjectAction,
+		BucketName:      "mybucket",
+		ConditionValues: map[string][]string{},
+		ObjectName:      "myobject",
+	}
+
+	getPrivateObjectArgs := Args{
+		Action:          GetObjectAction,
+		BucketName:      "mybucket",
+		ConditionValues: map[string][]string{},
+		ObjectName:      "private/myobject",
+	}
+
+	ownerGetPrivateObjectArgs := Args{
+		AccountName:     "Q3AM3UQ867SPQQA43P2F",
+		Action:          GetObjectAction,
+		BucketName:      "mybucket",
+		ConditionValues: map[string][]string{},
+		IsOwner:         true,
+		ObjectName:      "private/myobject",
+	}
+
+	testCases := []struct {
+		policy         Policy
+		args           Args
+		expectedResult bool
+	}{
+		{allowPolicy, getObjectArgs, false},
+		{allowPolicy, getPrivateObjectArgs, false},
+		{denyPolicy, getObjectArgs, false},
+		{denyPolicy, getPrivateObjectArgs, true},
+		{denyPolicy, ownerGetPrivateObjectArgs, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.policy.IsDenied(testCase.args)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestPolicyIsEmpty(t *testing.T) {
 	case1Policy := Policy{
 		Version: DefaultVersion,
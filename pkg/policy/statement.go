@@ -94,7 +94,7 @@ func (statement Statement) isValid() error {
 		}
 
 		keys := statement.Conditions.Keys()
-		keyDiff := keys.Difference(actionConditionKeyMap[action])
+		keyDiff := keys.Difference(actionConditionKeyMap[action]).Difference(condition.CommonKeys)
 		if !keyDiff.IsEmpty() {
 			return fmt.Errorf("unsupported condition keys '%v' used for action '%v'", keyDiff, action)
 		}
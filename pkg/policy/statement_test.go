@@ -189,6 +189,11 @@ func TestStatementIsValid(t *testing.T) {
 		t.Fatalf("unexpected error. %v\n", err)
 	}
 
+	func3, err := condition.NewBoolFunc(condition.AWSSecureTransport, true)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
 	testCases := []struct {
 		statement Statement
 		expectErr bool
@@ -256,6 +261,14 @@ func TestStatementIsValid(t *testing.T) {
 			NewResourceSet(NewResource("mybucket", "myobject*")),
 			condition.NewFunctions(func1),
 		), false},
+		// Common condition key is supported for any action.
+		{NewStatement(
+			Allow,
+			NewPrincipal("*"),
+			NewActionSet(GetBucketLocationAction, GetObjectAction),
+			NewResourceSet(NewResource("mybucket", ""), NewResource("mybucket", "myobject*")),
+			condition.NewFunctions(func3),
+		), false},
 	}
 
 	for i, testCase := range testCases {